@@ -102,10 +102,34 @@ type KubernetesRoleStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault,
+	// or compared with it in observe mode.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// reconcileRequest is the value of the reconcile-at annotation that was last handled.
+	// +optional
+	ReconcileRequest string `json:"reconcileRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // KubernetesRole is the Schema for the kubernetesroles API
 type KubernetesRole struct {
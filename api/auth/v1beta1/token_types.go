@@ -106,10 +106,39 @@ type TokenStatus struct {
 
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 	Accessor   string             `json:"accessor,omitempty"`
+
+	// observedGeneration is the generation of the spec last applied to Vault,
+	// or compared with it in observe mode.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to
+	// Vault, the token ID left out.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// expireTime is when the token issued expires, unset for tokens without TTL.
+	// +optional
+	ExpireTime *metav1.Time `json:"expireTime,omitempty"`
+
+	// reconcileRequest is the value of the reconcile-at annotation that was last handled.
+	// +optional
+	ReconcileRequest string `json:"reconcileRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Token is the Schema for the tokens API
 type Token struct {
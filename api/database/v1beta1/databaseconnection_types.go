@@ -0,0 +1,153 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RootCredentialsSecretRef struct {
+	// name defines the name of a Secret in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+
+	// usernameKey defines the key of the Secret holding the username.
+	// +kubebuilder:default="username"
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// passwordKey defines the key of the Secret holding the password.
+	// +kubebuilder:default="password"
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// DatabaseConnectionSpec defines the desired state of DatabaseConnection
+type DatabaseConnectionSpec struct {
+	// mount defines the path the database secrets engine is mounted at.
+	// +kubebuilder:default="database"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// pluginName defines the name of the database plugin to use, e.g. postgresql-database-plugin.
+	// +required
+	PluginName string `json:"pluginName"`
+
+	// pluginVersion defines the semantic version of the plugin to use.
+	// +optional
+	PluginVersion string `json:"pluginVersion,omitempty"`
+
+	// connectionURL defines the connection string used to connect to the database. The
+	// {{username}} and {{password}} placeholders are replaced with the root credentials.
+	// +required
+	ConnectionURL string `json:"connectionURL"`
+
+	// rootCredentialsSecretRef defines the Secret holding the credentials Vault uses to
+	// connect to the database. They are only written to Vault when the Secret changes, so
+	// that a root rotation done by Vault is not undone.
+	// +required
+	RootCredentialsSecretRef RootCredentialsSecretRef `json:"rootCredentialsSecretRef"`
+
+	// allowedRoles defines the roles allowed to use this connection. "*" allows every role.
+	// +optional
+	AllowedRoles []string `json:"allowedRoles,omitempty"`
+
+	// verifyConnection if set, the connection is verified when it is written. Defaults to true.
+	// +optional
+	VerifyConnection *bool `json:"verifyConnection,omitempty"`
+
+	// rootRotationStatements defines the database statements executed to rotate the root
+	// credentials.
+	// +optional
+	RootRotationStatements []string `json:"rootRotationStatements,omitempty"`
+
+	// passwordPolicy defines the password policy used to generate passwords.
+	// +optional
+	PasswordPolicy string `json:"passwordPolicy,omitempty"`
+
+	// options defines additional plugin specific parameters of the connection, such as
+	// max_open_connections or username_template.
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// DatabaseConnectionStatus defines the observed state of DatabaseConnection.
+type DatabaseConnectionStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to
+	// Vault, the password left out.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// rootCredentialsHash is the checksum of the root credentials last written to Vault.
+	// +optional
+	RootCredentialsHash string `json:"rootCredentialsHash,omitempty"`
+
+	// rootRotationRequest is the value of the rotate-root annotation that was last handled.
+	// +optional
+	RootRotationRequest string `json:"rootRotationRequest,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DatabaseConnection is the Schema for the databaseconnections API
+type DatabaseConnection struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of DatabaseConnection
+	// +required
+	Spec DatabaseConnectionSpec `json:"spec"`
+
+	// status defines the observed state of DatabaseConnection
+	// +optional
+	Status DatabaseConnectionStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseConnectionList contains a list of DatabaseConnection
+type DatabaseConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseConnection{}, &DatabaseConnectionList{})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DatabaseCredentialsTarget struct {
+	// name defines the name of the Secret created in the namespace of the resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Name is immutable"
+	// +required
+	Name string `json:"name"`
+
+	// deletionPolicy defines whether the Secret is deleted with the resource. Only Secrets owned by the resource are deleted.
+	// The lease is revoked in both cases, so a retained Secret holds credentials that no longer work.
+	// +optional
+	// +kubebuilder:default="Delete"
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// DatabaseCredentialsSpec defines the desired state of DatabaseCredentials
+type DatabaseCredentialsSpec struct {
+	// mount defines the path the database secrets engine is mounted at.
+	// +kubebuilder:default="database"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// role defines the name of the role the credentials are requested from.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Role is immutable"
+	// +required
+	Role string `json:"role"`
+
+	// target defines the Secret the credentials are written to.
+	// +required
+	Target DatabaseCredentialsTarget `json:"target"`
+
+	// ttl defines the increment requested when renewing the lease. The role's default TTL is used when not set.
+	// The credentials are rotated once a renewal can no longer extend the lease by this much, i.e. before
+	// the role's max TTL is reached.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// DatabaseCredentialsStatus defines the observed state of DatabaseCredentials.
+type DatabaseCredentialsStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// leaseID is the ID of the lease of the current credentials.
+	// +optional
+	LeaseID string `json:"leaseID,omitempty"`
+
+	// previousLeaseID is the ID of the lease of the credentials being replaced
+	// in the Secret, revoked once it holds the current ones.
+	// +optional
+	PreviousLeaseID string `json:"previousLeaseID,omitempty"`
+
+	// leaseDuration is the duration of the lease in seconds, as of the last renewal.
+	// +optional
+	LeaseDuration int `json:"leaseDuration,omitempty"`
+
+	// renewable reports whether the lease can be renewed.
+	// +optional
+	Renewable bool `json:"renewable,omitempty"`
+
+	// lastRenewTime is the last time the lease was issued or renewed.
+	// +optional
+	LastRenewTime *metav1.Time `json:"lastRenewTime,omitempty"`
+
+	// expireTime is the time the lease expires unless renewed.
+	// +optional
+	ExpireTime *metav1.Time `json:"expireTime,omitempty"`
+
+	// username is the username of the current credentials.
+	// +optional
+	Username string `json:"username,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DatabaseCredentials is the Schema for the databasecredentials API
+type DatabaseCredentials struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of DatabaseCredentials
+	// +required
+	Spec DatabaseCredentialsSpec `json:"spec"`
+
+	// status defines the observed state of DatabaseCredentials
+	// +optional
+	Status DatabaseCredentialsStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseCredentialsList contains a list of DatabaseCredentials
+type DatabaseCredentialsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseCredentials `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseCredentials{}, &DatabaseCredentialsList{})
+}
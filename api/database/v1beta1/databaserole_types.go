@@ -0,0 +1,126 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseRoleSpec defines the desired state of DatabaseRole
+type DatabaseRoleSpec struct {
+	// mount defines the path the database secrets engine is mounted at.
+	// +kubebuilder:default="database"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// dbName defines the name of the database connection to use for this role.
+	// +required
+	DBName string `json:"dbName"`
+
+	// creationStatements defines the database statements executed to create and configure a user.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	CreationStatements []string `json:"creationStatements"`
+
+	// revocationStatements defines the database statements executed to revoke a user.
+	// +optional
+	RevocationStatements []string `json:"revocationStatements,omitempty"`
+
+	// rollbackStatements defines the database statements executed to rollback a create operation in the event of an error.
+	// +optional
+	RollbackStatements []string `json:"rollbackStatements,omitempty"`
+
+	// renewStatements defines the database statements executed to renew a user.
+	// +optional
+	RenewStatements []string `json:"renewStatements,omitempty"`
+
+	// defaultTTL defines the TTL of the leases, provided as "1h" or a number of seconds. The mount's default is used when not set.
+	// +optional
+	DefaultTTL string `json:"defaultTTL,omitempty"`
+
+	// maxTTL defines the maximum TTL of the leases, provided as "24h" or a number of seconds. The mount's maximum is used when not set.
+	// +optional
+	MaxTTL string `json:"maxTTL,omitempty"`
+
+	// credentialType defines the type of credential generated for the role.
+	// +kubebuilder:validation:Enum=password;rsa_private_key;client_certificate
+	// +kubebuilder:default="password"
+	// +optional
+	CredentialType string `json:"credentialType,omitempty"`
+
+	// credentialConfig defines the configuration of the credentialType.
+	// +optional
+	CredentialConfig map[string]string `json:"credentialConfig,omitempty"`
+}
+
+// DatabaseRoleStatus defines the observed state of DatabaseRole.
+type DatabaseRoleStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DatabaseRole is the Schema for the databaseroles API
+type DatabaseRole struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of DatabaseRole
+	// +required
+	Spec DatabaseRoleSpec `json:"spec"`
+
+	// status defines the observed state of DatabaseRole
+	// +optional
+	Status DatabaseRoleStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseRoleList contains a list of DatabaseRole
+type DatabaseRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseRole{}, &DatabaseRoleList{})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DatabaseStaticRoleTarget struct {
+	// name defines the name of the Secret created in the namespace of the resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Name is immutable"
+	// +required
+	Name string `json:"name"`
+
+	// deletionPolicy defines whether the Secret is deleted with the resource. Only Secrets owned by the resource are deleted.
+	// +optional
+	// +kubebuilder:default="Retain"
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// DatabaseStaticRoleSpec defines the desired state of DatabaseStaticRole
+// +kubebuilder:validation:XValidation:rule="has(self.rotationPeriod) != has(self.rotationSchedule)",message="Exactly one of rotationPeriod or rotationSchedule is required"
+// +kubebuilder:validation:XValidation:rule="!has(self.rotationWindow) || has(self.rotationSchedule)",message="rotationWindow requires rotationSchedule"
+type DatabaseStaticRoleSpec struct {
+	// mount defines the path the database secrets engine is mounted at.
+	// +kubebuilder:default="database"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// dbName defines the name of the database connection to use for this role.
+	// +required
+	DBName string `json:"dbName"`
+
+	// username defines the name of the existing database user whose password Vault manages.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Username is immutable"
+	// +required
+	Username string `json:"username"`
+
+	// rotationPeriod defines how often Vault rotates the password, provided as "24h" or a number of seconds.
+	// +optional
+	RotationPeriod string `json:"rotationPeriod,omitempty"`
+
+	// rotationSchedule defines a cron-style schedule, e.g. "0 0 * * SAT", at which Vault rotates the password.
+	// +optional
+	RotationSchedule string `json:"rotationSchedule,omitempty"`
+
+	// rotationWindow defines how long after the scheduled time a rotation may still happen, provided as "1h"
+	// or a number of seconds.
+	// +optional
+	RotationWindow string `json:"rotationWindow,omitempty"`
+
+	// rotationStatements defines the database statements executed to rotate the password.
+	// +optional
+	RotationStatements []string `json:"rotationStatements,omitempty"`
+
+	// credentialType defines the type of credential managed for the role.
+	// +kubebuilder:validation:Enum=password;rsa_private_key;client_certificate
+	// +kubebuilder:default="password"
+	// +optional
+	CredentialType string `json:"credentialType,omitempty"`
+
+	// target defines the Secret the current credentials are written to. It is updated after every
+	// rotation done by Vault.
+	// +required
+	Target DatabaseStaticRoleTarget `json:"target"`
+}
+
+// DatabaseStaticRoleStatus defines the observed state of DatabaseStaticRole.
+type DatabaseStaticRoleStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// lastVaultRotation is the time Vault last rotated the password.
+	// +optional
+	LastVaultRotation *metav1.Time `json:"lastVaultRotation,omitempty"`
+
+	// rotationRequest is the value of the rotate annotation that was last handled.
+	// +optional
+	RotationRequest string `json:"rotationRequest,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DatabaseStaticRole is the Schema for the databasestaticroles API
+type DatabaseStaticRole struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of DatabaseStaticRole
+	// +required
+	Spec DatabaseStaticRoleSpec `json:"spec"`
+
+	// status defines the observed state of DatabaseStaticRole
+	// +optional
+	Status DatabaseStaticRoleStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseStaticRoleList contains a list of DatabaseStaticRole
+type DatabaseStaticRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseStaticRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseStaticRole{}, &DatabaseStaticRoleList{})
+}
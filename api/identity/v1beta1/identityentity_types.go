@@ -0,0 +1,108 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IdentityEntityReference references an IdentityEntity.
+type IdentityEntityReference struct {
+	// name defines the name of the IdentityEntity in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// IdentityEntitySpec defines the desired state of IdentityEntity. The entity is named after the resource.
+type IdentityEntitySpec struct {
+	// policies defines the policies attached to the entity, in addition to those of the tokens of its aliases.
+	// +optional
+	Policies []string `json:"policies,omitempty"`
+
+	// metadata defines the metadata of the entity, usable in policy templates.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// disabled defines whether tokens of the entity are denied.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// IdentityEntityStatus defines the observed state of IdentityEntity.
+type IdentityEntityStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// entityID is the ID of the entity in Vault.
+	// +optional
+	EntityID string `json:"entityID,omitempty"`
+
+	// aliasIDs are the IDs of the aliases of the entity in Vault, including those not managed by the operator.
+	// +optional
+	AliasIDs []string `json:"aliasIDs,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IdentityEntity is the Schema for the identityentities API
+type IdentityEntity struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of IdentityEntity
+	// +required
+	Spec IdentityEntitySpec `json:"spec"`
+
+	// status defines the observed state of IdentityEntity
+	// +optional
+	Status IdentityEntityStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// IdentityEntityList contains a list of IdentityEntity
+type IdentityEntityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IdentityEntity `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IdentityEntity{}, &IdentityEntityList{})
+}
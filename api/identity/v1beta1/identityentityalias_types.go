@@ -0,0 +1,110 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuthReference references an Auth of the sys group.
+type AuthReference struct {
+	// name defines the name of the Auth in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// IdentityEntityAliasSpec defines the desired state of IdentityEntityAlias. An alias binds the identity an auth method
+// reports to an entity.
+type IdentityEntityAliasSpec struct {
+	// name defines the name of the alias, which must match what the auth method reports, e.g. the service account UID
+	// or "namespace/name" for the kubernetes auth method depending on its aliasNameSource.
+	// +required
+	Name string `json:"name"`
+
+	// entityRef defines the IdentityEntity the alias belongs to.
+	// +required
+	EntityRef IdentityEntityReference `json:"entityRef"`
+
+	// authRef defines the Auth whose accessor the alias is bound to.
+	// +required
+	AuthRef AuthReference `json:"authRef"`
+
+	// customMetadata defines the metadata of the alias.
+	// +optional
+	CustomMetadata map[string]string `json:"customMetadata,omitempty"`
+}
+
+// IdentityEntityAliasStatus defines the observed state of IdentityEntityAlias.
+type IdentityEntityAliasStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// aliasID is the ID of the alias in Vault.
+	// +optional
+	AliasID string `json:"aliasID,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IdentityEntityAlias is the Schema for the identityentityaliases API
+type IdentityEntityAlias struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of IdentityEntityAlias
+	// +required
+	Spec IdentityEntityAliasSpec `json:"spec"`
+
+	// status defines the observed state of IdentityEntityAlias
+	// +optional
+	Status IdentityEntityAliasStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// IdentityEntityAliasList contains a list of IdentityEntityAlias
+type IdentityEntityAliasList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IdentityEntityAlias `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IdentityEntityAlias{}, &IdentityEntityAliasList{})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IdentityGroupReference references an IdentityGroup.
+type IdentityGroupReference struct {
+	// name defines the name of the IdentityGroup in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// IdentityGroupSpec defines the desired state of IdentityGroup. The group is named after the resource.
+// +kubebuilder:validation:XValidation:rule="self.type == 'internal' || (!has(self.memberEntityRefs) && !has(self.memberGroupRefs))",message="Members of external groups are managed by their alias and cannot be set"
+type IdentityGroupSpec struct {
+	// type defines whether members are set explicitly (internal) or by the auth method of the group alias on login
+	// (external).
+	// +kubebuilder:validation:Enum=internal;external
+	// +kubebuilder:default="internal"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Type is immutable"
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// policies defines the policies granted to the members of the group.
+	// +optional
+	Policies []string `json:"policies,omitempty"`
+
+	// metadata defines the metadata of the group.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// memberEntityRefs defines the IdentityEntities member of an internal group.
+	// +optional
+	MemberEntityRefs []IdentityEntityReference `json:"memberEntityRefs,omitempty"`
+
+	// memberGroupRefs defines the IdentityGroups member of an internal group, which inherit its policies.
+	// +optional
+	MemberGroupRefs []IdentityGroupReference `json:"memberGroupRefs,omitempty"`
+}
+
+// IdentityGroupStatus defines the observed state of IdentityGroup.
+type IdentityGroupStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// groupID is the ID of the group in Vault.
+	// +optional
+	GroupID string `json:"groupID,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IdentityGroup is the Schema for the identitygroups API
+type IdentityGroup struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of IdentityGroup
+	// +required
+	Spec IdentityGroupSpec `json:"spec"`
+
+	// status defines the observed state of IdentityGroup
+	// +optional
+	Status IdentityGroupStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// IdentityGroupList contains a list of IdentityGroup
+type IdentityGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IdentityGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IdentityGroup{}, &IdentityGroupList{})
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IdentityGroupAliasSpec defines the desired state of IdentityGroupAlias. An alias binds a group reported by an auth
+// method, e.g. an LDAP group or an OIDC groups claim, to an external group.
+type IdentityGroupAliasSpec struct {
+	// name defines the name of the group as reported by the auth method.
+	// +required
+	Name string `json:"name"`
+
+	// groupRef defines the external IdentityGroup the alias belongs to.
+	// +required
+	GroupRef IdentityGroupReference `json:"groupRef"`
+
+	// authRef defines the Auth whose accessor the alias is bound to.
+	// +required
+	AuthRef AuthReference `json:"authRef"`
+}
+
+// IdentityGroupAliasStatus defines the observed state of IdentityGroupAlias.
+type IdentityGroupAliasStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// aliasID is the ID of the alias in Vault.
+	// +optional
+	AliasID string `json:"aliasID,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IdentityGroupAlias is the Schema for the identitygroupaliases API
+type IdentityGroupAlias struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of IdentityGroupAlias
+	// +required
+	Spec IdentityGroupAliasSpec `json:"spec"`
+
+	// status defines the observed state of IdentityGroupAlias
+	// +optional
+	Status IdentityGroupAliasStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// IdentityGroupAliasList contains a list of IdentityGroupAlias
+type IdentityGroupAliasList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IdentityGroupAlias `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IdentityGroupAlias{}, &IdentityGroupAliasList{})
+}
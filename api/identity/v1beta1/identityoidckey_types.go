@@ -0,0 +1,103 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IdentityOIDCKeySpec defines the desired state of IdentityOIDCKey. The key signs identity tokens and tokens of the
+// OIDC provider, and is named after the resource.
+type IdentityOIDCKeySpec struct {
+	// rotationPeriod defines how often a new key pair is generated, provided as "24h" or a number of seconds.
+	// +kubebuilder:default="24h"
+	// +optional
+	RotationPeriod string `json:"rotationPeriod,omitempty"`
+
+	// verificationTTL defines how long the public key of a rotated key pair remains published to verify tokens,
+	// provided as "24h" or a number of seconds.
+	// +kubebuilder:default="24h"
+	// +optional
+	VerificationTTL string `json:"verificationTTL,omitempty"`
+
+	// allowedClientIDs defines the client IDs of the roles and clients allowed to use the key, "*" allowing any.
+	// +optional
+	AllowedClientIDs []string `json:"allowedClientIDs,omitempty"`
+
+	// algorithm defines the signing algorithm of the key.
+	// +kubebuilder:validation:Enum=RS256;RS384;RS512;ES256;ES384;ES512;EdDSA
+	// +kubebuilder:default="RS256"
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// IdentityOIDCKeyStatus defines the observed state of IdentityOIDCKey.
+type IdentityOIDCKeyStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IdentityOIDCKey is the Schema for the identityoidckeys API
+type IdentityOIDCKey struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of IdentityOIDCKey
+	// +required
+	Spec IdentityOIDCKeySpec `json:"spec"`
+
+	// status defines the observed state of IdentityOIDCKey
+	// +optional
+	Status IdentityOIDCKeyStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// IdentityOIDCKeyList contains a list of IdentityOIDCKey
+type IdentityOIDCKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IdentityOIDCKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IdentityOIDCKey{}, &IdentityOIDCKeyList{})
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IdentityOIDCRoleSpec defines the desired state of IdentityOIDCRole. Entities read identity tokens from
+// identity/oidc/token/<name>, the role being named after the resource.
+type IdentityOIDCRoleSpec struct {
+	// key defines the name of the key signing the tokens, e.g. an IdentityOIDCKey or "default". The key must allow
+	// the client ID of the role.
+	// +required
+	Key string `json:"key"`
+
+	// template defines the JSON template of additional claims, e.g. {"groups": {{identity.entity.groups.names}}}.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// ttl defines the lifetime of the tokens, provided as "1h" or a number of seconds.
+	// +kubebuilder:default="24h"
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+}
+
+// IdentityOIDCRoleStatus defines the observed state of IdentityOIDCRole.
+type IdentityOIDCRoleStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// clientID is the client ID generated by Vault, used as the audience of the tokens.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IdentityOIDCRole is the Schema for the identityoidcroles API
+type IdentityOIDCRole struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of IdentityOIDCRole
+	// +required
+	Spec IdentityOIDCRoleSpec `json:"spec"`
+
+	// status defines the observed state of IdentityOIDCRole
+	// +optional
+	Status IdentityOIDCRoleStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// IdentityOIDCRoleList contains a list of IdentityOIDCRole
+type IdentityOIDCRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IdentityOIDCRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IdentityOIDCRole{}, &IdentityOIDCRoleList{})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OIDCClientReference references an OIDCClient.
+type OIDCClientReference struct {
+	// name defines the name of the OIDCClient in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+type OIDCClientTarget struct {
+	// name defines the name of the Secret holding client_id and client_secret, created in the namespace of the
+	// resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Name is immutable"
+	// +required
+	Name string `json:"name"`
+
+	// deletionPolicy defines whether the Secret is deleted with the resource. Only Secrets owned by the resource are deleted.
+	// +optional
+	// +kubebuilder:default="Retain"
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// OIDCClientSpec defines the desired state of OIDCClient. The client is named after the resource.
+type OIDCClientSpec struct {
+	// key defines the name of the key signing the ID tokens, e.g. an IdentityOIDCKey or "default". The key must allow
+	// the client ID of the client.
+	// +required
+	Key string `json:"key"`
+
+	// redirectURIs defines the URIs the provider may redirect to after authentication.
+	// +optional
+	RedirectURIs []string `json:"redirectURIs,omitempty"`
+
+	// assignments defines the names of the assignments of entities and groups allowed to authenticate, e.g.
+	// OIDCAssignments or "allow_all".
+	// +optional
+	Assignments []string `json:"assignments,omitempty"`
+
+	// clientType defines whether the client can keep a secret (confidential) or not (public, using PKCE).
+	// +kubebuilder:validation:Enum=confidential;public
+	// +kubebuilder:default="confidential"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ClientType is immutable"
+	// +optional
+	ClientType string `json:"clientType,omitempty"`
+
+	// idTokenTTL defines the lifetime of ID tokens, provided as "1h" or a number of seconds.
+	// +kubebuilder:default="24h"
+	// +optional
+	IDTokenTTL string `json:"idTokenTTL,omitempty"`
+
+	// accessTokenTTL defines the lifetime of access tokens, provided as "1h" or a number of seconds.
+	// +kubebuilder:default="24h"
+	// +optional
+	AccessTokenTTL string `json:"accessTokenTTL,omitempty"`
+
+	// target defines the Secret the client credentials are written to.
+	// +required
+	Target OIDCClientTarget `json:"target"`
+}
+
+// OIDCClientStatus defines the observed state of OIDCClient.
+type OIDCClientStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// clientID is the client ID generated by Vault.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// OIDCClient is the Schema for the oidcclients API
+type OIDCClient struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of OIDCClient
+	// +required
+	Spec OIDCClientSpec `json:"spec"`
+
+	// status defines the observed state of OIDCClient
+	// +optional
+	Status OIDCClientStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// OIDCClientList contains a list of OIDCClient
+type OIDCClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OIDCClient `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OIDCClient{}, &OIDCClientList{})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OIDCProviderSpec defines the desired state of OIDCProvider. The provider is named after the resource.
+type OIDCProviderSpec struct {
+	// issuer defines the scheme, host and port of the issuer URL, e.g. https://vault.example.com:8200. Defaults to
+	// the api_addr of Vault.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// allowedClientRefs defines the OIDCClients allowed to authenticate with the provider.
+	// +optional
+	AllowedClientRefs []OIDCClientReference `json:"allowedClientRefs,omitempty"`
+
+	// scopesSupported defines the names of the scopes available to clients, which must exist, e.g. OIDCScopes.
+	// +optional
+	ScopesSupported []string `json:"scopesSupported,omitempty"`
+}
+
+// OIDCProviderStatus defines the observed state of OIDCProvider.
+type OIDCProviderStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// issuer is the issuer URL of the provider, which serves the discovery document under
+	// /.well-known/openid-configuration.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// OIDCProvider is the Schema for the oidcproviders API
+type OIDCProvider struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of OIDCProvider
+	// +required
+	Spec OIDCProviderSpec `json:"spec"`
+
+	// status defines the observed state of OIDCProvider
+	// +optional
+	Status OIDCProviderStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// OIDCProviderList contains a list of OIDCProvider
+type OIDCProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OIDCProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OIDCProvider{}, &OIDCProviderList{})
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KVSecretSpec defines the desired state of KVSecret
+// +kubebuilder:validation:XValidation:rule="has(self.secretRef) || has(self.data)",message="One of secretRef or data is required"
+// +kubebuilder:validation:XValidation:rule="self.kvVersion == 2 || (!has(self.customMetadata) && !has(self.maxVersions) && !has(self.casRequired))",message="customMetadata, maxVersions and casRequired require kvVersion 2"
+type KVSecretSpec struct {
+	// mount defines the path the KV secrets engine is mounted at.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +required
+	Mount string `json:"mount"`
+
+	// path defines the path of the secret within the mount.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Path is immutable"
+	// +required
+	Path string `json:"path"`
+
+	// kvVersion defines the version of the KV secrets engine mounted at mount.
+	// +kubebuilder:validation:Enum=1;2
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="KVVersion is immutable"
+	// +kubebuilder:default=2
+	// +optional
+	KVVersion int `json:"kvVersion,omitempty"`
+
+	// secretRef defines a Secret in the same namespace whose data is written to Vault.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// data defines keys written to Vault, on top of the ones of secretRef. Values are Go templates
+	// rendered with the data of secretRef available as .Secret, e.g. `{{ .Secret.password }}`.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+
+	// customMetadata defines the custom metadata of the secret. Only supported by KV v2.
+	// +optional
+	CustomMetadata map[string]string `json:"customMetadata,omitempty"`
+
+	// maxVersions defines the number of versions kept for the secret. Only supported by KV v2.
+	// The mount's setting is used when not set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxVersions int `json:"maxVersions,omitempty"`
+
+	// casRequired if set, every write to the secret must use check-and-set. Only supported by KV v2.
+	// +optional
+	CASRequired bool `json:"casRequired,omitempty"`
+
+	// deletionPolicy defines what happens to the secret in Vault when the resource is deleted.
+	// SoftDelete deletes the latest version, which can be undeleted, Destroy permanently removes
+	// every version and the metadata, and Retain leaves the secret untouched. With KV v1, both
+	// SoftDelete and Destroy delete the secret.
+	// +kubebuilder:validation:Enum=SoftDelete;Destroy;Retain
+	// +kubebuilder:default="Retain"
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// KVSecretStatus defines the observed state of KVSecret.
+type KVSecretStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the keys and templates of the
+	// data last written to Vault and of the version of its source Secret, the
+	// values being secrets.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// version is the current version of the secret in Vault. Only reported by KV v2.
+	// +optional
+	Version int `json:"version,omitempty"`
+
+	// customMetadata is the custom metadata of the secret in Vault. Only reported by KV v2.
+	// +optional
+	CustomMetadata map[string]string `json:"customMetadata,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// KVSecret is the Schema for the kvsecrets API
+type KVSecret struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of KVSecret
+	// +required
+	Spec KVSecretSpec `json:"spec"`
+
+	// status defines the observed state of KVSecret
+	// +optional
+	Status KVSecretStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// KVSecretList contains a list of KVSecret
+type KVSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KVSecret `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KVSecret{}, &KVSecretList{})
+}
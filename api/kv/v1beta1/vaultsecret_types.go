@@ -0,0 +1,156 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type VaultSecretTarget struct {
+	// name defines the name of the Secret created in the namespace of the resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Name is immutable"
+	// +required
+	Name string `json:"name"`
+
+	// deletionPolicy defines whether the Secret is deleted with the resource. Only Secrets owned by the resource are deleted.
+	// +optional
+	// +kubebuilder:default="Retain"
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+type VaultSecretRolloutTarget struct {
+	// kind defines the kind of the workload to roll out.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	// +required
+	Kind string `json:"kind"`
+
+	// name defines the name of the workload, in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// VaultSecretSpec defines the desired state of VaultSecret
+// +kubebuilder:validation:XValidation:rule="self.kvVersion == 2 || !has(self.version)",message="version requires kvVersion 2"
+type VaultSecretSpec struct {
+	// mount defines the path the KV secrets engine is mounted at.
+	// +required
+	Mount string `json:"mount"`
+
+	// path defines the path of the secret within the mount.
+	// +required
+	Path string `json:"path"`
+
+	// kvVersion defines the version of the KV secrets engine mounted at mount.
+	// +kubebuilder:validation:Enum=1;2
+	// +kubebuilder:default=2
+	// +optional
+	KVVersion int `json:"kvVersion,omitempty"`
+
+	// version pins the version of the secret to read. The latest version is read when not set. Only supported by KV v2.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Version int `json:"version,omitempty"`
+
+	// target defines the Secret the data is written to.
+	// +required
+	Target VaultSecretTarget `json:"target"`
+
+	// keys maps keys of the target Secret to keys of the Vault secret. When neither keys nor
+	// templates are set, every key of the Vault secret is copied as is.
+	// +optional
+	Keys map[string]string `json:"keys,omitempty"`
+
+	// templates defines keys of the target Secret rendered from Go templates. The data of the
+	// Vault secret is available as .Secret, its version as .Version and its custom metadata
+	// as .CustomMetadata, e.g. `{{ .Secret.username }}:{{ .Secret.password }}`.
+	// +optional
+	Templates map[string]string `json:"templates,omitempty"`
+
+	// refreshInterval defines how often Vault is checked for changes. With KV v2, only the
+	// metadata is read unless the current version changed.
+	// +kubebuilder:default="5m"
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// rolloutTargets defines workloads whose pods are restarted when the data of the Secret
+	// changes, through a checksum annotation on their pod template.
+	// +optional
+	RolloutTargets []VaultSecretRolloutTarget `json:"rolloutTargets,omitempty"`
+}
+
+// VaultSecretStatus defines the observed state of VaultSecret.
+type VaultSecretStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// version is the version of the Vault secret written to the Secret. Only reported by KV v2.
+	// +optional
+	Version int `json:"version,omitempty"`
+
+	// dataHash is the checksum of the data written to the Secret, keyed with
+	// the UID of the Secret.
+	// +optional
+	DataHash string `json:"dataHash,omitempty"`
+
+	// lastRefreshTime is the last time the data was read from Vault.
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+
+	// observedGeneration is the generation of the spec the Secret was last written for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// VaultSecret is the Schema for the vaultsecrets API
+type VaultSecret struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of VaultSecret
+	// +required
+	Spec VaultSecretSpec `json:"spec"`
+
+	// status defines the observed state of VaultSecret
+	// +optional
+	Status VaultSecretStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// VaultSecretList contains a list of VaultSecret
+type VaultSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VaultSecret `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VaultSecret{}, &VaultSecretList{})
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PKICertificateTarget struct {
+	// name defines the name of the kubernetes.io/tls Secret created in the namespace of the resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Name is immutable"
+	// +required
+	Name string `json:"name"`
+
+	// deletionPolicy defines whether the Secret is deleted with the resource. Only Secrets owned by the resource are deleted.
+	// +optional
+	// +kubebuilder:default="Delete"
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// PKICertificateSpec defines the desired state of PKICertificate
+type PKICertificateSpec struct {
+	// mount defines the path the PKI secrets engine is mounted at.
+	// +kubebuilder:default="pki"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// role defines the name of the PKI role the certificate is issued against.
+	// +required
+	Role string `json:"role"`
+
+	// commonName defines the common name of the certificate.
+	// +required
+	CommonName string `json:"commonName"`
+
+	// altNames defines the DNS subject alternative names of the certificate.
+	// +optional
+	AltNames []string `json:"altNames,omitempty"`
+
+	// ipSANs defines the IP subject alternative names of the certificate.
+	// +optional
+	IPSANs []string `json:"ipSANs,omitempty"`
+
+	// uriSANs defines the URI subject alternative names of the certificate.
+	// +optional
+	URISANs []string `json:"uriSANs,omitempty"`
+
+	// ttl defines the requested lifetime of the certificate. The role's TTL is used when unset.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// renewalPercentage defines the share of the certificate lifetime, in percent, after which a new certificate
+	// is issued.
+	// +kubebuilder:default=67
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	RenewalPercentage int `json:"renewalPercentage,omitempty"`
+
+	// target defines the Secret the certificate, its private key and the issuing CA are written to.
+	// +required
+	Target PKICertificateTarget `json:"target"`
+}
+
+// PKICertificateStatus defines the observed state of PKICertificate.
+type PKICertificateStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// serialNumber is the serial number of the current certificate.
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// notBefore is the time the current certificate is valid from.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// notAfter is the time the current certificate expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// renewalTime is the time a new certificate will be issued.
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PKICertificate is the Schema for the pkicertificates API
+type PKICertificate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of PKICertificate
+	// +required
+	Spec PKICertificateSpec `json:"spec"`
+
+	// status defines the observed state of PKICertificate
+	// +optional
+	Status PKICertificateStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PKICertificateList contains a list of PKICertificate
+type PKICertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PKICertificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PKICertificate{}, &PKICertificateList{})
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PKIURLs struct {
+	// issuingCertificates defines the URLs of the issuing CA certificates, embedded in issued certificates.
+	// +optional
+	IssuingCertificates []string `json:"issuingCertificates,omitempty"`
+
+	// crlDistributionPoints defines the URLs of the CRL, embedded in issued certificates.
+	// +optional
+	CRLDistributionPoints []string `json:"crlDistributionPoints,omitempty"`
+
+	// ocspServers defines the URLs of the OCSP responders, embedded in issued certificates.
+	// +optional
+	OCSPServers []string `json:"ocspServers,omitempty"`
+
+	// enableTemplating defines whether the URLs may contain templates such as {{issuer_id}}.
+	// +optional
+	EnableTemplating bool `json:"enableTemplating,omitempty"`
+}
+
+type PKICRL struct {
+	// expiry defines how long the CRL is valid for, provided as "72h".
+	// +kubebuilder:default="72h"
+	// +optional
+	Expiry string `json:"expiry,omitempty"`
+
+	// disable defines whether CRL building is disabled.
+	// +optional
+	Disable bool `json:"disable,omitempty"`
+
+	// ocspDisable defines whether the OCSP responder is disabled.
+	// +optional
+	OCSPDisable bool `json:"ocspDisable,omitempty"`
+
+	// autoRebuild defines whether the CRL is rebuilt periodically rather than on every revocation.
+	// +optional
+	AutoRebuild bool `json:"autoRebuild,omitempty"`
+
+	// autoRebuildGracePeriod defines how long before its expiry the CRL is rebuilt, provided as "12h".
+	// +kubebuilder:default="12h"
+	// +optional
+	AutoRebuildGracePeriod string `json:"autoRebuildGracePeriod,omitempty"`
+
+	// enableDelta defines whether delta CRLs are built between complete CRLs. Requires autoRebuild.
+	// +optional
+	EnableDelta bool `json:"enableDelta,omitempty"`
+
+	// deltaRebuildInterval defines how often delta CRLs are rebuilt, provided as "15m".
+	// +kubebuilder:default="15m"
+	// +optional
+	DeltaRebuildInterval string `json:"deltaRebuildInterval,omitempty"`
+}
+
+type PKIAutoTidy struct {
+	// enabled defines whether tidy operations run periodically.
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// interval defines how often tidy operations run, provided as "12h" or a number of seconds.
+	// +kubebuilder:default="12h"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// tidyCertStore defines whether expired certificates are removed from storage.
+	// +optional
+	TidyCertStore bool `json:"tidyCertStore,omitempty"`
+
+	// tidyRevokedCerts defines whether expired revoked certificates are removed from the CRL and storage.
+	// +optional
+	TidyRevokedCerts bool `json:"tidyRevokedCerts,omitempty"`
+
+	// tidyExpiredIssuers defines whether expired issuers are removed.
+	// +optional
+	TidyExpiredIssuers bool `json:"tidyExpiredIssuers,omitempty"`
+
+	// safetyBuffer defines how long after their expiry certificates are kept, provided as "72h" or a number of
+	// seconds.
+	// +kubebuilder:default="72h"
+	// +optional
+	SafetyBuffer string `json:"safetyBuffer,omitempty"`
+}
+
+// PKIConfigSpec defines the desired state of PKIConfig. Sections left unset are not managed.
+type PKIConfigSpec struct {
+	// mount defines the path the PKI secrets engine is mounted at.
+	// +kubebuilder:default="pki"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// defaultIssuer defines the name or ID of the default issuer of the mount. The name of a PKIIssuer is the
+	// name of its issuer in Vault.
+	// +optional
+	DefaultIssuer string `json:"defaultIssuer,omitempty"`
+
+	// urls defines the URLs embedded in issued certificates, written to config/urls.
+	// +optional
+	URLs *PKIURLs `json:"urls,omitempty"`
+
+	// crl defines how the CRL is built, written to config/crl.
+	// +optional
+	CRL *PKICRL `json:"crl,omitempty"`
+
+	// autoTidy defines the periodic tidy operations, written to config/auto-tidy.
+	// +optional
+	AutoTidy *PKIAutoTidy `json:"autoTidy,omitempty"`
+}
+
+// PKIConfigStatus defines the observed state of PKIConfig.
+type PKIConfigStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PKIConfig is the Schema for the pkiconfigs API
+type PKIConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of PKIConfig
+	// +required
+	Spec PKIConfigSpec `json:"spec"`
+
+	// status defines the observed state of PKIConfig
+	// +optional
+	Status PKIConfigStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PKIConfigList contains a list of PKIConfig
+type PKIConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PKIConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PKIConfig{}, &PKIConfigList{})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PKIIssuerReference struct {
+	// name defines the name of the PKIIssuer in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// PKIIssuerSpec defines the desired state of PKIIssuer
+// +kubebuilder:validation:XValidation:rule="self.type == 'intermediate' ? has(self.signerRef) : !has(self.signerRef)",message="signerRef is required for intermediate issuers and not allowed for root issuers"
+type PKIIssuerSpec struct {
+	// mount defines the path the PKI secrets engine is mounted at.
+	// +kubebuilder:default="pki"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// type defines whether the issuer is a self-signed root or an intermediate signed by signerRef.
+	// +kubebuilder:validation:Enum=root;intermediate
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Type is immutable"
+	// +required
+	Type string `json:"type"`
+
+	// signerRef defines the PKIIssuer signing an intermediate issuer. It may live on another mount.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="SignerRef is immutable"
+	// +optional
+	SignerRef *PKIIssuerReference `json:"signerRef,omitempty"`
+
+	// commonName defines the common name of the CA certificate.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="CommonName is immutable"
+	// +required
+	CommonName string `json:"commonName"`
+
+	// organization defines the organization of the CA certificate subject.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Organization is immutable"
+	// +optional
+	Organization []string `json:"organization,omitempty"`
+
+	// keyType defines the type of the CA private key.
+	// +kubebuilder:validation:Enum=rsa;ec;ed25519
+	// +kubebuilder:default="rsa"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="KeyType is immutable"
+	// +optional
+	KeyType string `json:"keyType,omitempty"`
+
+	// keyBits defines the size of the CA private key. The default of the key type is used when unset.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="KeyBits is immutable"
+	// +optional
+	KeyBits int `json:"keyBits,omitempty"`
+
+	// ttl defines the lifetime of the CA certificate, provided as "87600h" or a number of seconds. The max lease
+	// TTL of the mount is used when unset.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="TTL is immutable"
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// leafNotAfterBehavior defines what happens when a certificate would outlive the issuer: err fails the
+	// request, truncate caps its lifetime and permit lets it outlive the issuer.
+	// +kubebuilder:validation:Enum=err;truncate;permit
+	// +kubebuilder:default="err"
+	// +optional
+	LeafNotAfterBehavior string `json:"leafNotAfterBehavior,omitempty"`
+
+	// deletionPolicy defines whether the issuer and its key are deleted from Vault with the resource.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default="Retain"
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// PKIIssuerStatus defines the observed state of PKIIssuer.
+type PKIIssuerStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// issuerID is the ID Vault assigned to the issuer.
+	// +optional
+	IssuerID string `json:"issuerID,omitempty"`
+
+	// keyID is the ID of the private key of the issuer.
+	// +optional
+	KeyID string `json:"keyID,omitempty"`
+
+	// serialNumber is the serial number of the CA certificate.
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// notAfter is the time the CA certificate expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// certificate is the PEM encoded CA certificate.
+	// +optional
+	Certificate string `json:"certificate,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PKIIssuer is the Schema for the pkiissuers API
+type PKIIssuer struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of PKIIssuer
+	// +required
+	Spec PKIIssuerSpec `json:"spec"`
+
+	// status defines the observed state of PKIIssuer
+	// +optional
+	Status PKIIssuerStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PKIIssuerList contains a list of PKIIssuer
+type PKIIssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PKIIssuer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PKIIssuer{}, &PKIIssuerList{})
+}
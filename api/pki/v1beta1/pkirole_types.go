@@ -0,0 +1,157 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PKIRoleSpec defines the desired state of PKIRole
+type PKIRoleSpec struct {
+	// mount defines the path the PKI secrets engine is mounted at.
+	// +kubebuilder:default="pki"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// issuerRef defines the name or ID of the issuer signing certificates for this role. The default issuer of the
+	// mount is used when empty.
+	// +optional
+	IssuerRef string `json:"issuerRef,omitempty"`
+
+	// allowedDomains defines the domains certificates may be issued for, as restricted by the allow* fields.
+	// +optional
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// allowBareDomains defines whether certificates may be issued for the allowed domains themselves.
+	// +optional
+	AllowBareDomains bool `json:"allowBareDomains,omitempty"`
+
+	// allowSubdomains defines whether certificates may be issued for subdomains of the allowed domains.
+	// +optional
+	AllowSubdomains bool `json:"allowSubdomains,omitempty"`
+
+	// allowGlobDomains defines whether the allowed domains may contain glob patterns, e.g. "ftp*.example.com".
+	// +optional
+	AllowGlobDomains bool `json:"allowGlobDomains,omitempty"`
+
+	// allowAnyName defines whether certificates may be issued for any name. Use with care.
+	// +optional
+	AllowAnyName bool `json:"allowAnyName,omitempty"`
+
+	// allowLocalhost defines whether certificates may be issued for localhost. Defaults to true.
+	// +optional
+	AllowLocalhost *bool `json:"allowLocalhost,omitempty"`
+
+	// allowIPSANs defines whether certificates may carry IP subject alternative names. Defaults to true.
+	// +optional
+	AllowIPSANs *bool `json:"allowIPSANs,omitempty"`
+
+	// serverFlag defines whether certificates may be used for server authentication. Defaults to true.
+	// +optional
+	ServerFlag *bool `json:"serverFlag,omitempty"`
+
+	// clientFlag defines whether certificates may be used for client authentication. Defaults to true.
+	// +optional
+	ClientFlag *bool `json:"clientFlag,omitempty"`
+
+	// keyType defines the type of the private keys generated for certificates.
+	// +kubebuilder:validation:Enum=rsa;ec;ed25519;any
+	// +kubebuilder:default="rsa"
+	// +optional
+	KeyType string `json:"keyType,omitempty"`
+
+	// keyBits defines the size of the private keys, e.g. 2048 for rsa or 256 for ec. The default of the key type is
+	// used when unset.
+	// +optional
+	KeyBits int `json:"keyBits,omitempty"`
+
+	// ttl defines the default lifetime of certificates, provided as "72h" or a number of seconds.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// maxTTL defines the maximum lifetime of certificates, provided as "720h" or a number of seconds.
+	// +optional
+	MaxTTL string `json:"maxTTL,omitempty"`
+
+	// keyUsage defines the key usages of certificates, e.g. DigitalSignature or KeyEncipherment. Defaults to
+	// DigitalSignature, KeyAgreement and KeyEncipherment.
+	// +optional
+	KeyUsage []string `json:"keyUsage,omitempty"`
+
+	// extKeyUsage defines extended key usages of certificates besides those set by serverFlag and clientFlag,
+	// e.g. CodeSigning.
+	// +optional
+	ExtKeyUsage []string `json:"extKeyUsage,omitempty"`
+}
+
+// PKIRoleStatus defines the observed state of PKIRole.
+type PKIRoleStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PKIRole is the Schema for the pkiroles API
+type PKIRole struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of PKIRole
+	// +required
+	Spec PKIRoleSpec `json:"spec"`
+
+	// status defines the observed state of PKIRole
+	// +optional
+	Status PKIRoleStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PKIRoleList contains a list of PKIRole
+type PKIRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PKIRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PKIRole{}, &PKIRoleList{})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SSHCAKeySecretRef references a Secret holding the key pair of an existing CA.
+type SSHCAKeySecretRef struct {
+	// name defines the name of a Secret in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+
+	// privateKeyKey defines the key of the Secret holding the private key.
+	// +kubebuilder:default="ssh-privatekey"
+	// +optional
+	PrivateKeyKey string `json:"privateKeyKey,omitempty"`
+
+	// publicKeyKey defines the key of the Secret holding the public key, in authorized_keys format.
+	// +kubebuilder:default="ssh-publickey"
+	// +optional
+	PublicKeyKey string `json:"publicKeyKey,omitempty"`
+}
+
+type SSHCAConfigTarget struct {
+	// name defines the name of the ConfigMap the public key of the CA is published to, in the namespace of the
+	// resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Name is immutable"
+	// +required
+	Name string `json:"name"`
+
+	// key defines the key of the ConfigMap holding the public key.
+	// +kubebuilder:default="trusted-user-ca-keys.pem"
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// deletionPolicy defines whether the ConfigMap is deleted with the resource. Only ConfigMaps owned by the
+	// resource are deleted.
+	// +optional
+	// +kubebuilder:default="Retain"
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// SSHCAConfigSpec defines the desired state of SSHCAConfig
+// +kubebuilder:validation:XValidation:rule="!has(self.keySecretRef) || !has(self.keyBits)",message="keyBits only applies to generated keys"
+type SSHCAConfigSpec struct {
+	// mount defines the path the SSH secrets engine is mounted at.
+	// +kubebuilder:default="ssh"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// keySecretRef references the key pair of an existing CA to import. Vault generates the CA when unset. The
+	// CA is replaced whenever the key pair changes.
+	// +optional
+	KeySecretRef *SSHCAKeySecretRef `json:"keySecretRef,omitempty"`
+
+	// keyType defines the type of the key Vault generates.
+	// +kubebuilder:validation:Enum=ssh-rsa;ecdsa-sha2-nistp256;ecdsa-sha2-nistp384;ecdsa-sha2-nistp521;ssh-ed25519
+	// +kubebuilder:default="ssh-rsa"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="KeyType is immutable"
+	// +optional
+	KeyType string `json:"keyType,omitempty"`
+
+	// keyBits defines the size of the key Vault generates. 0 uses the default of the key type.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="KeyBits is immutable"
+	// +optional
+	KeyBits int `json:"keyBits,omitempty"`
+
+	// target defines the ConfigMap the public key of the CA is published to, for hosts to trust it.
+	// +required
+	Target SSHCAConfigTarget `json:"target"`
+
+	// deletionPolicy defines whether the CA is deleted from Vault with the resource.
+	// +optional
+	// +kubebuilder:default="Retain"
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// SSHCAConfigStatus defines the observed state of SSHCAConfig.
+type SSHCAConfigStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to
+	// Vault, the private key left out.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// fingerprint is the SHA256 fingerprint of the public key of the CA.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SSHCAConfig is the Schema for the sshcaconfigs API
+type SSHCAConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of SSHCAConfig
+	// +required
+	Spec SSHCAConfigSpec `json:"spec"`
+
+	// status defines the observed state of SSHCAConfig
+	// +optional
+	Status SSHCAConfigStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// SSHCAConfigList contains a list of SSHCAConfig
+type SSHCAConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SSHCAConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SSHCAConfig{}, &SSHCAConfigList{})
+}
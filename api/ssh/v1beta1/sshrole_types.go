@@ -0,0 +1,152 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SSHRoleSpec defines the desired state of SSHRole. Roles sign keys with the CA of the mount.
+// +kubebuilder:validation:XValidation:rule="self.allowUserCertificates || self.allowHostCertificates",message="At least one of allowUserCertificates or allowHostCertificates is required"
+type SSHRoleSpec struct {
+	// mount defines the path the SSH secrets engine is mounted at.
+	// +kubebuilder:default="ssh"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// allowUserCertificates defines whether the role signs user certificates.
+	// +kubebuilder:default=true
+	// +optional
+	AllowUserCertificates bool `json:"allowUserCertificates"`
+
+	// allowHostCertificates defines whether the role signs host certificates.
+	// +optional
+	AllowHostCertificates bool `json:"allowHostCertificates,omitempty"`
+
+	// allowedUsers defines the users certificates can be signed for, "*" allowing any.
+	// +optional
+	AllowedUsers []string `json:"allowedUsers,omitempty"`
+
+	// allowedUsersTemplate defines whether allowedUsers may contain identity templates.
+	// +optional
+	AllowedUsersTemplate bool `json:"allowedUsersTemplate,omitempty"`
+
+	// defaultUser defines the user certificates are signed for when the request does not name one.
+	// +optional
+	DefaultUser string `json:"defaultUser,omitempty"`
+
+	// allowedDomains defines the domains host certificates can be signed for.
+	// +optional
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// allowSubdomains defines whether host certificates can be signed for subdomains of allowedDomains.
+	// +optional
+	AllowSubdomains bool `json:"allowSubdomains,omitempty"`
+
+	// allowBareDomains defines whether host certificates can be signed for allowedDomains themselves.
+	// +optional
+	AllowBareDomains bool `json:"allowBareDomains,omitempty"`
+
+	// allowedExtensions defines the extensions requests may set, "*" allowing any.
+	// +optional
+	AllowedExtensions []string `json:"allowedExtensions,omitempty"`
+
+	// defaultExtensions defines the extensions set when the request does not provide any, e.g. permit-pty.
+	// +optional
+	DefaultExtensions map[string]string `json:"defaultExtensions,omitempty"`
+
+	// allowedCriticalOptions defines the critical options requests may set, "*" allowing any.
+	// +optional
+	AllowedCriticalOptions []string `json:"allowedCriticalOptions,omitempty"`
+
+	// defaultCriticalOptions defines the critical options set when the request does not provide any, e.g.
+	// force-command.
+	// +optional
+	DefaultCriticalOptions map[string]string `json:"defaultCriticalOptions,omitempty"`
+
+	// ttl defines the default lifetime of the certificates, provided as "30m" or a number of seconds.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// maxTTL defines the maximum lifetime of the certificates, provided as "24h" or a number of seconds.
+	// +optional
+	MaxTTL string `json:"maxTTL,omitempty"`
+
+	// algorithmSigner defines the signature algorithm used with RSA CA keys.
+	// +kubebuilder:validation:Enum=default;ssh-rsa;rsa-sha2-256;rsa-sha2-512
+	// +kubebuilder:default="default"
+	// +optional
+	AlgorithmSigner string `json:"algorithmSigner,omitempty"`
+}
+
+// SSHRoleStatus defines the observed state of SSHRole.
+type SSHRoleStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SSHRole is the Schema for the sshroles API
+type SSHRole struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of SSHRole
+	// +required
+	Spec SSHRoleSpec `json:"spec"`
+
+	// status defines the observed state of SSHRole
+	// +optional
+	Status SSHRoleStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// SSHRoleList contains a list of SSHRole
+type SSHRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SSHRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SSHRole{}, &SSHRoleList{})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuditDeviceSpec defines the desired state of AuditDevice.
+//
+// Audit device options cannot be tuned in Vault. When the spec changes, the
+// operator enables a replacement device at "<path>-<generation>" before
+// disabling the previous one, so that audit logging never stops.
+type AuditDeviceSpec struct {
+	// type defines the type of the audit device.
+	// +kubebuilder:validation:Enum=file;syslog;socket
+	// +required
+	Type string `json:"type"`
+
+	// path defines where the audit device is enabled. Defaults to the name of the resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Path is immutable"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// description defines a human-friendly description of the audit device.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// local if set, the audit device is local to the cluster and is not replicated.
+	// +optional
+	Local bool `json:"local,omitempty"`
+
+	// options defines the type specific options of the audit device, such as `file_path` for file,
+	// `facility` and `tag` for syslog or `address` and `socket_type` for socket.
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// AuditDeviceStatus defines the observed state of AuditDevice.
+type AuditDeviceStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// path is where the audit device currently enabled for this resource lives in Vault.
+	Path string `json:"path,omitempty"`
+
+	// replacedPath is where the audit device replaced by the one at path lives
+	// in Vault, until it is disabled.
+	// +optional
+	ReplacedPath string `json:"replacedPath,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AuditDevice is the Schema for the auditdevices API
+type AuditDevice struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of AuditDevice
+	// +required
+	Spec AuditDeviceSpec `json:"spec"`
+
+	// status defines the observed state of AuditDevice
+	// +optional
+	Status AuditDeviceStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// AuditDeviceList contains a list of AuditDevice
+type AuditDeviceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AuditDevice `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AuditDevice{}, &AuditDeviceList{})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LeaseCountQuotaSpec defines the desired state of LeaseCountQuota. The quota is named after the resource.
+// +kubebuilder:validation:XValidation:rule="[has(self.path), has(self.authRef), has(self.kubernetesRoleRef)].filter(x, x).size() <= 1",message="At most one of path, authRef and kubernetesRoleRef may be set"
+type LeaseCountQuotaSpec struct {
+	QuotaScope `json:",inline"`
+
+	// maxLeases defines the maximum number of leases that can exist at once.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	MaxLeases int `json:"maxLeases"`
+}
+
+// LeaseCountQuotaStatus defines the observed state of LeaseCountQuota.
+type LeaseCountQuotaStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// LeaseCountQuota is the Schema for the leasecountquotas API
+type LeaseCountQuota struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of LeaseCountQuota
+	// +required
+	Spec LeaseCountQuotaSpec `json:"spec"`
+
+	// status defines the observed state of LeaseCountQuota
+	// +optional
+	Status LeaseCountQuotaStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// LeaseCountQuotaList contains a list of LeaseCountQuota
+type LeaseCountQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LeaseCountQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LeaseCountQuota{}, &LeaseCountQuotaList{})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PasswordPolicyRule requires characters of a charset in generated passwords.
+type PasswordPolicyRule struct {
+	// charset defines the characters the rule applies to, e.g. "abcdefghijklmnopqrstuvwxyz".
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Charset string `json:"charset"`
+
+	// minChars defines the minimum number of characters of the charset in generated passwords.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinChars int `json:"minChars,omitempty"`
+}
+
+// PasswordPolicySpec defines the desired state of PasswordPolicy. The policy is named after the resource and is
+// either rendered from length and rules, or provided as HCL.
+// +kubebuilder:validation:XValidation:rule="has(self.policy) ? !has(self.length) && !has(self.rules) : has(self.length) && has(self.rules)",message="Either policy or both length and rules must be set"
+type PasswordPolicySpec struct {
+	// length defines the length of generated passwords.
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Length int `json:"length,omitempty"`
+
+	// rules defines the charsets generated passwords are made of. Characters are drawn from all charsets once the
+	// minimum of each rule is met.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Rules []PasswordPolicyRule `json:"rules,omitempty"`
+
+	// policy defines the policy as HCL, for rules that cannot be expressed with length and rules.
+	// +optional
+	Policy string `json:"policy,omitempty"`
+}
+
+// PasswordPolicyStatus defines the observed state of PasswordPolicy.
+type PasswordPolicyStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PasswordPolicy is the Schema for the passwordpolicies API
+type PasswordPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of PasswordPolicy
+	// +required
+	Spec PasswordPolicySpec `json:"spec"`
+
+	// status defines the observed state of PasswordPolicy
+	// +optional
+	Status PasswordPolicyStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PasswordPolicyList contains a list of PasswordPolicy
+type PasswordPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PasswordPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PasswordPolicy{}, &PasswordPolicyList{})
+}
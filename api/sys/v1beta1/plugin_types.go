@@ -0,0 +1,136 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PluginSpec defines the desired state of Plugin. The plugin is registered in the catalog under the name of the
+// resource.
+type PluginSpec struct {
+	// type defines the type of the plugin.
+	// +kubebuilder:validation:Enum=auth;secret;database
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Type is immutable"
+	// +required
+	Type string `json:"type"`
+
+	// command defines the command used to execute the plugin, relative to the plugin directory of Vault.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Command string `json:"command"`
+
+	// sha256 defines the SHA256 sum of the plugin binary, hex encoded.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{64}$`
+	// +required
+	SHA256 string `json:"sha256"`
+
+	// version defines the semantic version of the plugin, e.g. "v1.2.0". Versions replaced here stay registered
+	// as long as a mount is configured with or still runs them, and until the resource is deleted for database
+	// plugins.
+	// +kubebuilder:validation:Pattern=`^v?[0-9]+\.[0-9]+\.[0-9]+([-+].*)?$`
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// args defines the arguments passed to the plugin command.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// env defines the environment variables of the plugin process, as "KEY=value".
+	// +optional
+	Env []string `json:"env,omitempty"`
+}
+
+// PluginVersionStatus is a version of the plugin registered in the catalog.
+type PluginVersionStatus struct {
+	// version is the registered version, empty for an unversioned plugin.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// running reports whether a mount runs this version. Vault does not report the plugins run by database
+	// connections.
+	Running bool `json:"running"`
+
+	// mounts are the paths of the mounts running this version.
+	// +optional
+	Mounts []string `json:"mounts,omitempty"`
+}
+
+// PluginStatus defines the observed state of Plugin.
+type PluginStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// versions are the versions of the plugin registered in the catalog.
+	// +optional
+	Versions []PluginVersionStatus `json:"versions,omitempty"`
+
+	// observedGeneration is the generation of the spec the plugin was last registered for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// reloadRequest is the value of the reload annotation that was last handled.
+	// +optional
+	ReloadRequest string `json:"reloadRequest,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Plugin is the Schema for the plugins API
+type Plugin struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of Plugin
+	// +required
+	Spec PluginSpec `json:"spec"`
+
+	// status defines the observed state of Plugin
+	// +optional
+	Status PluginStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// PluginList contains a list of Plugin
+type PluginList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Plugin `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Plugin{}, &PluginList{})
+}
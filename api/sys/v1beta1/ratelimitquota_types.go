@@ -0,0 +1,128 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuthReference references an Auth.
+type AuthReference struct {
+	// name defines the name of the Auth in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// KubernetesRoleReference references a KubernetesRole.
+type KubernetesRoleReference struct {
+	// name defines the name of the KubernetesRole in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// QuotaScope defines the requests a quota applies to. A quota without scope applies to every request.
+type QuotaScope struct {
+	// path defines the mount or the path under a mount the quota applies to, e.g. "secret/" or "secret/data/team".
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// authRef defines the Auth whose mount the quota applies to.
+	// +optional
+	AuthRef *AuthReference `json:"authRef,omitempty"`
+
+	// kubernetesRoleRef defines the KubernetesRole whose logins the quota applies to.
+	// +optional
+	KubernetesRoleRef *KubernetesRoleReference `json:"kubernetesRoleRef,omitempty"`
+}
+
+// RateLimitQuotaSpec defines the desired state of RateLimitQuota. The quota is named after the resource.
+// +kubebuilder:validation:XValidation:rule="[has(self.path), has(self.authRef), has(self.kubernetesRoleRef)].filter(x, x).size() <= 1",message="At most one of path, authRef and kubernetesRoleRef may be set"
+type RateLimitQuotaSpec struct {
+	QuotaScope `json:",inline"`
+
+	// rate defines the number of requests allowed per interval.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	Rate int `json:"rate"`
+
+	// interval defines the duration the rate applies to, provided as "1s" or a number of seconds.
+	// +kubebuilder:default="1s"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// blockInterval defines how long clients exceeding the rate are blocked, provided as "1m" or a number of
+	// seconds. Clients are not blocked by default.
+	// +optional
+	BlockInterval string `json:"blockInterval,omitempty"`
+}
+
+// RateLimitQuotaStatus defines the observed state of RateLimitQuota.
+type RateLimitQuotaStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RateLimitQuota is the Schema for the ratelimitquotas API
+type RateLimitQuota struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of RateLimitQuota
+	// +required
+	Spec RateLimitQuotaSpec `json:"spec"`
+
+	// status defines the observed state of RateLimitQuota
+	// +optional
+	Status RateLimitQuotaStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// RateLimitQuotaList contains a list of RateLimitQuota
+type RateLimitQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RateLimitQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RateLimitQuota{}, &RateLimitQuotaList{})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretEngineConfig defines the tunable configuration of a secrets engine mount.
+type SecretEngineConfig struct {
+	// defaultLeaseTTL defines the default lease duration, provided as "1h" or a number of seconds.
+	// +optional
+	DefaultLeaseTTL *string `json:"defaultLeaseTTL,omitempty"`
+
+	// maxLeaseTTL defines the maximum lease duration, provided as "1h" or a number of seconds.
+	// +optional
+	MaxLeaseTTL *string `json:"maxLeaseTTL,omitempty"`
+
+	// auditNonHmacRequestKeys defines the list of keys that will not be HMAC'd by audit devices in the request data object.
+	// +optional
+	AuditNonHMACRequestKeys []string `json:"auditNonHmacRequestKeys,omitempty"`
+
+	// auditNonHmacResponseKeys defines the list of keys that will not be HMAC'd by audit devices in the response data object.
+	// +optional
+	AuditNonHMACResponseKeys []string `json:"auditNonHmacResponseKeys,omitempty"`
+
+	// listingVisibility defines whether to show this mount in the UI-specific listing endpoint.
+	// +kubebuilder:validation:Enum=unauth;hidden
+	// +optional
+	ListingVisibility *string `json:"listingVisibility,omitempty"`
+
+	// passthroughRequestHeaders defines the list of headers to allow and pass from the request to the plugin.
+	// +optional
+	PassthroughRequestHeaders []string `json:"passthroughRequestHeaders,omitempty"`
+
+	// allowedResponseHeaders defines the list of headers to allow, allowing a plugin to include them in the response.
+	// +optional
+	AllowedResponseHeaders []string `json:"allowedResponseHeaders,omitempty"`
+
+	// pluginVersion defines the semantic version of the plugin to use.
+	// +optional
+	PluginVersion *string `json:"pluginVersion,omitempty"`
+
+	// identityTokenKey defines the key to use for signing plugin workload identity tokens.
+	// +optional
+	IdentityTokenKey *string `json:"identityTokenKey,omitempty"`
+}
+
+// SecretEngineSpec defines the desired state of SecretEngine
+type SecretEngineSpec struct {
+	// type defines the type of the secrets engine, such as kv, pki, transit or database.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Type is immutable"
+	// +required
+	Type string `json:"type"`
+
+	// path defines where the secrets engine is mounted. Defaults to the name of the resource.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Path is immutable"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// description defines a human-friendly description of the mount.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// options defines mount type specific options, such as `version: "2"` for kv.
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+
+	// local if set, the mount is local to the cluster and is not replicated.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Local is immutable"
+	// +optional
+	Local bool `json:"local,omitempty"`
+
+	// sealWrap if set, enables seal wrapping for the mount.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="SealWrap is immutable"
+	// +optional
+	SealWrap bool `json:"sealWrap,omitempty"`
+
+	// externalEntropyAccess if set, gives the mount access to Vault's external entropy source.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ExternalEntropyAccess is immutable"
+	// +optional
+	ExternalEntropyAccess bool `json:"externalEntropyAccess,omitempty"`
+
+	// config defines the tunable configuration of the mount.
+	// +optional
+	Config SecretEngineConfig `json:"config,omitempty"`
+}
+
+// SecretEngineStatus defines the observed state of SecretEngine.
+type SecretEngineStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	Accessor   string             `json:"accessor,omitempty"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretEngine is the Schema for the secretengines API
+type SecretEngine struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of SecretEngine
+	// +required
+	Spec SecretEngineSpec `json:"spec"`
+
+	// status defines the observed state of SecretEngine
+	// +optional
+	Status SecretEngineStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretEngineList contains a list of SecretEngine
+type SecretEngineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretEngine `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretEngine{}, &SecretEngineList{})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VaultNamespaceReference references a VaultNamespace.
+type VaultNamespaceReference struct {
+	// name defines the name of the VaultNamespace in the namespace of the resource.
+	// +required
+	Name string `json:"name"`
+}
+
+// VaultNamespaceSpec defines the desired state of VaultNamespace. The Vault namespace is named after the resource
+// and created under the root namespace unless a parent is set.
+// +kubebuilder:validation:XValidation:rule="!(has(self.parent) && has(self.parentRef))",message="At most one of parent and parentRef may be set"
+// +kubebuilder:validation:XValidation:rule="(has(self.parent) ? self.parent : ”) == (has(oldSelf.parent) ? oldSelf.parent : ”) && (has(self.parentRef) ? self.parentRef.name : ”) == (has(oldSelf.parentRef) ? oldSelf.parentRef.name : ”)",message="Parent is immutable"
+type VaultNamespaceSpec struct {
+	// parent defines the path of an existing Vault namespace to create the namespace in, e.g. "tenants".
+	// +optional
+	Parent string `json:"parent,omitempty"`
+
+	// parentRef defines the VaultNamespace to create the namespace in.
+	// +optional
+	ParentRef *VaultNamespaceReference `json:"parentRef,omitempty"`
+
+	// customMetadata defines custom metadata of the namespace.
+	// +optional
+	CustomMetadata map[string]string `json:"customMetadata,omitempty"`
+
+	// forceDeletion defines whether the namespace is deleted with the resource while it still contains secrets
+	// engines or auth methods, which Vault deletes along with it. Deletion is refused otherwise.
+	// +optional
+	ForceDeletion bool `json:"forceDeletion,omitempty"`
+}
+
+// VaultNamespaceStatus defines the observed state of VaultNamespace.
+type VaultNamespaceStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// id is the identifier of the namespace in Vault.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// path is the full path of the namespace, e.g. "tenants/team-a", to reference it from other resources.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// VaultNamespace is the Schema for the vaultnamespaces API
+type VaultNamespace struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of VaultNamespace
+	// +required
+	Spec VaultNamespaceSpec `json:"spec"`
+
+	// status defines the observed state of VaultNamespace
+	// +optional
+	Status VaultNamespaceStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// VaultNamespaceList contains a list of VaultNamespace
+type VaultNamespaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VaultNamespace `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VaultNamespace{}, &VaultNamespaceList{})
+}
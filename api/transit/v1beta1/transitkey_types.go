@@ -0,0 +1,136 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TransitKeySpec defines the desired state of TransitKey
+// +kubebuilder:validation:XValidation:rule="self.minEncryptionVersion == 0 || self.minEncryptionVersion >= self.minDecryptionVersion",message="minEncryptionVersion must be 0 or at least minDecryptionVersion"
+type TransitKeySpec struct {
+	// mount defines the path the transit secrets engine is mounted at.
+	// +kubebuilder:default="transit"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Mount is immutable"
+	// +optional
+	Mount string `json:"mount,omitempty"`
+
+	// type defines the type of the key.
+	// +kubebuilder:validation:Enum=aes128-gcm96;aes256-gcm96;chacha20-poly1305;ed25519;ecdsa-p256;ecdsa-p384;ecdsa-p521;rsa-2048;rsa-3072;rsa-4096;hmac
+	// +kubebuilder:default="aes256-gcm96"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Type is immutable"
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// exportable defines whether the key can be exported. It cannot be disabled once enabled.
+	// +kubebuilder:validation:XValidation:rule="!oldSelf || self",message="Exportable cannot be disabled once enabled"
+	// +optional
+	Exportable bool `json:"exportable,omitempty"`
+
+	// allowPlaintextBackup defines whether the key can be backed up in plaintext. It cannot be disabled once
+	// enabled.
+	// +kubebuilder:validation:XValidation:rule="!oldSelf || self",message="AllowPlaintextBackup cannot be disabled once enabled"
+	// +optional
+	AllowPlaintextBackup bool `json:"allowPlaintextBackup,omitempty"`
+
+	// deletionAllowed defines whether the key is deleted from Vault with the resource. The key is kept in Vault
+	// otherwise.
+	// +optional
+	DeletionAllowed bool `json:"deletionAllowed,omitempty"`
+
+	// autoRotatePeriod defines how often Vault rotates the key, provided as "720h" or a number of seconds.
+	// Vault requires at least an hour, "0" or unset disables automatic rotation.
+	// +optional
+	AutoRotatePeriod string `json:"autoRotatePeriod,omitempty"`
+
+	// minDecryptionVersion defines the oldest version of the key that can be used to decrypt.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MinDecryptionVersion int `json:"minDecryptionVersion,omitempty"`
+
+	// minEncryptionVersion defines the oldest version of the key that can be used to encrypt. 0 uses the
+	// latest version.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinEncryptionVersion int `json:"minEncryptionVersion,omitempty"`
+}
+
+// TransitKeyStatus defines the observed state of TransitKey.
+type TransitKeyStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// observedGeneration is the generation of the spec last applied to Vault.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// vaultPath is the path of the object in Vault.
+	// +optional
+	VaultPath string `json:"vaultPath,omitempty"`
+
+	// lastAppliedHash is the SHA-256 checksum of the payload last written to Vault.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// lastAppliedTime is the last time the payload was written to Vault.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// latestVersion is the latest version of the key.
+	// +optional
+	LatestVersion int `json:"latestVersion,omitempty"`
+
+	// rotationRequest is the value of the rotate annotation that was last handled.
+	// +optional
+	RotationRequest string `json:"rotationRequest,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=`.status.vaultPath`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// TransitKey is the Schema for the transitkeys API
+type TransitKey struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of TransitKey
+	// +required
+	Spec TransitKeySpec `json:"spec"`
+
+	// status defines the observed state of TransitKey
+	// +optional
+	Status TransitKeyStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// TransitKeyList contains a list of TransitKey
+type TransitKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TransitKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TransitKey{}, &TransitKeyList{})
+}
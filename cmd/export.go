@@ -0,0 +1,84 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/export"
+)
+
+// runExport prints the configuration of Vault as resources of the operator
+// and returns the exit code.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s export [flags]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Prints the policies, password policies, auth methods, secrets engines, audit devices and")
+		fmt.Fprintln(fs.Output(), "Kubernetes auth roles of Vault as resources of the operator.")
+		fmt.Fprintln(fs.Output(), "The Vault token is read from the VAULT_TOKEN environment variable.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	var vaultAddr string
+	var options export.Options
+	fs.StringVar(&vaultAddr, "vault-addr", "", "The address of the vault server, defaults to the VAULT_ADDR environment variable.")
+	fs.StringVar(&options.Namespace, "namespace", "", "The namespace of the resources, left unset when empty.")
+	fs.StringVar(&options.NamePrefix, "name-prefix", "", "The prefix of the names of the secrets engines and audit devices, "+
+		"the other resources being named after the object they manage in vault.")
+	fs.BoolVar(&options.Adopt, "adopt", false, "Annotate the resources with "+vault.AdoptAnnotation+
+		" for the operator to take over the existing objects rather than failing to create them.")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 1
+	}
+
+	config := vaultapi.DefaultConfig()
+	if vaultAddr != "" {
+		config.Address = vaultAddr
+	}
+	c, err := vaultapi.NewClient(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create vault client: %s\n", err)
+		return 1
+	}
+
+	objs, skipped, err := export.Export(context.Background(), vault.NewVaultClient(c), options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "skipped %s %s: %s\n", s.Kind, s.Path, s.Reason)
+	}
+
+	if err := export.Write(os.Stdout, objs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package main
 
 import (
-	"context"
 	"crypto/tls"
 	"flag"
 	"os"
@@ -34,15 +33,29 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	databasev1beta1 "hopopops/vault-operator/api/database/v1beta1"
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	kvv1beta1 "hopopops/vault-operator/api/kv/v1beta1"
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	sshv1beta1 "hopopops/vault-operator/api/ssh/v1beta1"
 	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	transitv1beta1 "hopopops/vault-operator/api/transit/v1beta1"
 	"hopopops/vault-operator/internal/connector/vault"
 	authcontroller "hopopops/vault-operator/internal/controller/auth"
+	databasecontroller "hopopops/vault-operator/internal/controller/database"
+	identitycontroller "hopopops/vault-operator/internal/controller/identity"
+	kvcontroller "hopopops/vault-operator/internal/controller/kv"
+	pkicontroller "hopopops/vault-operator/internal/controller/pki"
+	sshcontroller "hopopops/vault-operator/internal/controller/ssh"
 	syscontroller "hopopops/vault-operator/internal/controller/sys"
+	transitcontroller "hopopops/vault-operator/internal/controller/transit"
+	opmetrics "hopopops/vault-operator/internal/metrics"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -56,11 +69,26 @@ func init() {
 
 	utilruntime.Must(sysv1beta1.AddToScheme(scheme))
 	utilruntime.Must(authv1beta1.AddToScheme(scheme))
+	utilruntime.Must(kvv1beta1.AddToScheme(scheme))
+	utilruntime.Must(databasev1beta1.AddToScheme(scheme))
+	utilruntime.Must(pkiv1beta1.AddToScheme(scheme))
+	utilruntime.Must(transitv1beta1.AddToScheme(scheme))
+	utilruntime.Must(sshv1beta1.AddToScheme(scheme))
+	utilruntime.Must(identityv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "plan":
+			os.Exit(runPlan(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		}
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
@@ -92,6 +120,10 @@ func main() {
 	flag.StringVar(&vaultAuthPath, "vault-auth-endpoint", "kubernetes", "The endpoint of the kubernetes auth method.")
 	flag.StringVar(&vaultRole, "vault-role", "vault-operator", "The vault role to use.")
 	flag.StringVar(&vaultTokenPath, "vault-token-path", "/var/run/secrets/kubernetes.io/serviceaccount/token", "The path to the vault token.")
+	var vaultMode string
+	flag.StringVar(&vaultMode, "mode", string(vault.ModeEnforce), "Whether the operator writes to vault (enforce) "+
+		"or only reports the differences (observe). Resources can override it with the "+vault.ModeAnnotation+" annotation. "+
+		"Only Policy, Auth, KubernetesRole and Token resources can be observed, the others are flagged and left untouched.")
 
 	opts := zap.Options{
 		Development: true,
@@ -101,6 +133,12 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	mode, err := vault.ParseMode(vaultMode)
+	if err != nil {
+		setupLog.Error(err, "invalid mode")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -214,7 +252,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	v, _, err := vault.NewVaultKubernetesClient(context.Background(), &vault.Parameters{
+	ctx := ctrl.SetupSignalHandler()
+
+	v, token, err := vault.NewVaultKubernetesClient(ctx, &vault.Parameters{
 		Address:   vaultAddr,
 		AuthPath:  vaultAuthPath,
 		Role:      vaultRole,
@@ -223,39 +263,350 @@ func main() {
 		setupLog.Error(err, "unable to create vault kubernetes client")
 		os.Exit(1)
 	}
+	// Renewed on every replica, so that a new leader starts with a valid token
+	go v.PeriodicallyRenewLeases(ctx, token)
 
 	if err := (&syscontroller.PolicyReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Vault:  v.Client,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("policy-controller"),
+		Mode:     mode,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Policy")
 		os.Exit(1)
 	}
-	if err := (&authcontroller.KubernetesRoleReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Vault:  v.Client,
+	if err := (&syscontroller.AuthReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("auth-controller"),
+		Mode:     mode,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "KubernetesRole")
+		setupLog.Error(err, "unable to create controller", "controller", "Auth")
 		os.Exit(1)
 	}
-	if err := (&syscontroller.AuthReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Vault:  v.Client,
+	if err := (&authcontroller.KubernetesRoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("kubernetesrole-controller"),
+		Mode:     mode,
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Auth")
+		setupLog.Error(err, "unable to create controller", "controller", "KubernetesRole")
 		os.Exit(1)
 	}
 	if err := (&authcontroller.TokenReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Vault:  v.Client,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("token-controller"),
+		Mode:     mode,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Token")
 		os.Exit(1)
 	}
+
+	if err := (&syscontroller.PasswordPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("passwordpolicy-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PasswordPolicy")
+		os.Exit(1)
+	}
+	if err := (&syscontroller.RateLimitQuotaReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("ratelimitquota-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RateLimitQuota")
+		os.Exit(1)
+	}
+	if err := (&syscontroller.LeaseCountQuotaReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("leasecountquota-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LeaseCountQuota")
+		os.Exit(1)
+	}
+	if err := (&syscontroller.PluginReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("plugin-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Plugin")
+		os.Exit(1)
+	}
+	if err := (&syscontroller.VaultNamespaceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("vaultnamespace-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VaultNamespace")
+		os.Exit(1)
+	}
+	if err := (&syscontroller.SecretEngineReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("secretengine-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SecretEngine")
+		os.Exit(1)
+	}
+	if err := (&syscontroller.AuditDeviceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("auditdevice-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AuditDevice")
+		os.Exit(1)
+	}
+	if err := (&kvcontroller.KVSecretReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("kvsecret-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KVSecret")
+		os.Exit(1)
+	}
+	if err := (&kvcontroller.VaultSecretReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("vaultsecret-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VaultSecret")
+		os.Exit(1)
+	}
+	if err := (&databasecontroller.DatabaseConnectionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("databaseconnection-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseConnection")
+		os.Exit(1)
+	}
+	if err := (&databasecontroller.DatabaseRoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("databaserole-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseRole")
+		os.Exit(1)
+	}
+	if err := (&databasecontroller.DatabaseCredentialsReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("databasecredentials-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseCredentials")
+		os.Exit(1)
+	}
+	if err := (&databasecontroller.DatabaseStaticRoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("databasestaticrole-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseStaticRole")
+		os.Exit(1)
+	}
+	if err := (&pkicontroller.PKIRoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("pkirole-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PKIRole")
+		os.Exit(1)
+	}
+	if err := (&pkicontroller.PKICertificateReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("pkicertificate-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PKICertificate")
+		os.Exit(1)
+	}
+	if err := (&pkicontroller.PKIIssuerReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("pkiissuer-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PKIIssuer")
+		os.Exit(1)
+	}
+	if err := (&pkicontroller.PKIConfigReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("pkiconfig-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PKIConfig")
+		os.Exit(1)
+	}
+	if err := (&transitcontroller.TransitKeyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("transitkey-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TransitKey")
+		os.Exit(1)
+	}
+	if err := (&sshcontroller.SSHCAConfigReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("sshcaconfig-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SSHCAConfig")
+		os.Exit(1)
+	}
+	if err := (&sshcontroller.SSHRoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("sshrole-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SSHRole")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.IdentityEntityReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("identityentity-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IdentityEntity")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.IdentityEntityAliasReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("identityentityalias-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IdentityEntityAlias")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.IdentityGroupReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("identitygroup-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IdentityGroup")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.IdentityGroupAliasReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("identitygroupalias-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IdentityGroupAlias")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.IdentityOIDCKeyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("identityoidckey-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IdentityOIDCKey")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.IdentityOIDCRoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("identityoidcrole-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IdentityOIDCRole")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.OIDCScopeReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("oidcscope-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OIDCScope")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.OIDCAssignmentReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("oidcassignment-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OIDCAssignment")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.OIDCClientReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("oidcclient-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OIDCClient")
+		os.Exit(1)
+	}
+	if err := (&identitycontroller.OIDCProviderReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Vault:    v,
+		Recorder: mgr.GetEventRecorderFor("oidcprovider-controller"),
+		Mode:     mode,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OIDCProvider")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -274,6 +625,8 @@ func main() {
 		}
 	}
 
+	ctrlmetrics.Registry.MustRegister(opmetrics.NewResourcesCollector(mgr.GetCache(), mgr.GetScheme()))
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -284,7 +637,7 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	authcontroller "hopopops/vault-operator/internal/controller/auth"
+	syscontroller "hopopops/vault-operator/internal/controller/sys"
+	"hopopops/vault-operator/internal/plan"
+)
+
+// pathsFlag collects the paths of a repeated flag.
+type pathsFlag []string
+
+func (p *pathsFlag) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *pathsFlag) Set(v string) error {
+	*p = append(*p, v)
+	return nil
+}
+
+// runPlan prints the changes applying the manifests would make in Vault,
+// without writing to it, and returns the exit code.
+func runPlan(args []string) int {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s plan [flags] <file, directory or kustomization>...\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Prints the changes applying the resources would make in Vault, without writing to it.")
+		fmt.Fprintln(fs.Output(), "The Vault token is read from the VAULT_TOKEN environment variable.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	var vaultAddr, format, kustomize string
+	var previous pathsFlag
+	var detailedExitCode bool
+	fs.StringVar(&vaultAddr, "vault-addr", "", "The address of the vault server, defaults to the VAULT_ADDR environment variable.")
+	fs.StringVar(&format, "format", string(plan.FormatText), "The output format (text, json or markdown).")
+	fs.StringVar(&kustomize, "kustomize", "kustomize", "The kustomize binary used to build kustomizations.")
+	fs.Var(&previous, "previous", "The manifests currently applied, the resources missing from the new manifests being "+
+		"planned for deletion. Can be repeated.")
+	fs.BoolVar(&detailedExitCode, "detailed-exitcode", false, "Exit with 2 rather than 0 when the plan writes to vault.")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 1
+	}
+
+	f, err := plan.ParseFormat(format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	desired, err := plan.Load(scheme, kustomize, fs.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to load the manifests: %s\n", err)
+		return 1
+	}
+	var applied []client.Object
+	if len(previous) > 0 {
+		if applied, err = plan.Load(scheme, kustomize, previous...); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to load the previous manifests: %s\n", err)
+			return 1
+		}
+	}
+
+	config := vaultapi.DefaultConfig()
+	if vaultAddr != "" {
+		config.Address = vaultAddr
+	}
+	c, err := vaultapi.NewClient(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create vault client: %s\n", err)
+		return 1
+	}
+	v := vault.NewVaultClient(c)
+
+	// Only the reconcilers able to compare a resource with Vault without
+	// writing to it are planned, the other kinds are reported as skipped
+	planners := map[schema.GroupKind]plan.Planner{
+		{Group: sysv1beta1.GroupVersion.Group, Kind: "Policy"}:          &syscontroller.PolicyReconciler{Vault: v},
+		{Group: sysv1beta1.GroupVersion.Group, Kind: "Auth"}:            &syscontroller.AuthReconciler{Vault: v},
+		{Group: authv1beta1.GroupVersion.Group, Kind: "KubernetesRole"}: &authcontroller.KubernetesRoleReconciler{Vault: v},
+	}
+
+	p, err := plan.Build(context.Background(), planners, desired, applied)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := p.Write(os.Stdout, f); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if detailedExitCode && p.HasChanges() {
+		return 2
+	}
+	return 0
+}
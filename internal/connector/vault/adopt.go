@@ -0,0 +1,10 @@
+package vault
+
+// AdoptAnnotation makes the operator take over the object already existing
+// in Vault at the path of a resource, rather than failing to create it.
+const AdoptAnnotation = "vault.hopopops.com/adopt"
+
+// Adopts reports whether a resource takes over the object existing in Vault.
+func Adopts(annotations map[string]string) bool {
+	return annotations[AdoptAnnotation] == "true"
+}
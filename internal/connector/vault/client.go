@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	vaultapi "github.com/hashicorp/vault/api"
 	vaultauth "github.com/hashicorp/vault/api/auth/kubernetes"
+
+	"hopopops/vault-operator/internal/metrics"
 )
 
 type Vault struct {
@@ -62,7 +65,12 @@ func (v *Vault) PeriodicallyRenewLeases(
 	for {
 		renewed, err := v.renewLeases(ctx, currentAuthToken)
 		if err != nil {
-			log.Fatalf("renew error: %v", err) // simplified error handling
+			metrics.TokenRenewalFailures.Inc()
+			// A token that failed to renew is replaced by logging in again
+			if renewed&expiringAuthToken == 0 {
+				log.Fatalf("renew error: %v", err) // simplified error handling
+			}
+			log.Printf("auth token: renewal failed: %v", err)
 		}
 
 		if renewed&exitRequested != 0 {
@@ -129,6 +137,7 @@ func (v *Vault) renewLeases(ctx context.Context, authToken *vaultapi.Secret) (re
 		// renewal takes place and includes metadata about the renewal.
 		case info := <-authTokenWatcher.RenewCh():
 			log.Printf("auth token: successfully renewed; remaining duration: %ds", info.Secret.Auth.LeaseDuration)
+			metrics.SetTokenExpiry(info.RenewedAt.Add(time.Duration(info.Secret.Auth.LeaseDuration) * time.Second))
 		}
 	}
 }
@@ -149,13 +158,20 @@ func (v *Vault) login(ctx context.Context) (*vaultapi.Secret, error) {
 		return nil, fmt.Errorf("unable to initialize Kubernetes auth method: %w", err)
 	}
 
+	start := time.Now()
 	authInfo, err := v.Client.Auth().Login(ctx, kubernetesAuth)
+	observe("Login", start, &err)
 	if err != nil {
 		return nil, fmt.Errorf("unable to log in with Kubernetes auth: %w", err)
 	}
-	if authInfo == nil {
+	if authInfo == nil || authInfo.Auth == nil {
 		return nil, fmt.Errorf("no auth info was returned after login")
 	}
+	if authInfo.Auth.LeaseDuration > 0 {
+		metrics.SetTokenExpiry(time.Now().Add(time.Duration(authInfo.Auth.LeaseDuration) * time.Second))
+	} else {
+		metrics.SetTokenExpiry(time.Time{})
+	}
 
 	return authInfo, nil
 }
@@ -0,0 +1,399 @@
+package fake
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// defaultMaxLeaseTTL is the system wide lease TTL of Vault, used when neither
+// the role nor the mount set one.
+const defaultMaxLeaseTTL = 768 * time.Hour
+
+// database is the state of a database secrets engine mount. Connection
+// details include the password, which is never returned on reads.
+type database struct {
+	connections map[string]*vault.DatabaseConnection
+	roles       map[string]*vault.DatabaseRole
+	staticRoles map[string]*staticRole
+}
+
+// staticRole is a static role along with the password Vault manages for it.
+type staticRole struct {
+	vault.DatabaseStaticRole
+	password     string
+	lastRotation time.Time
+}
+
+// rotate sets a new password, the way Vault does when the rotation period
+// elapses or when asked to through rotate-role.
+func (r *staticRole) rotate(password string) {
+	r.password = password
+	r.lastRotation = time.Now()
+	r.LastVaultRotation = r.lastRotation.Format(time.RFC3339Nano)
+}
+
+// DatabaseStaticRole returns a static role of a database mount along with its
+// current password.
+func (s *Server) DatabaseStaticRole(mount, name string) (*vault.DatabaseStaticRole, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, ok := s.databases[normalize(mount)]
+	if !ok {
+		return nil, "", false
+	}
+	r, ok := db.staticRoles[name]
+	if !ok {
+		return nil, "", false
+	}
+	out := r.DatabaseStaticRole
+	return &out, r.password, true
+}
+
+// DatabaseConnection returns a connection of a database mount, including the
+// root password Vault keeps to itself.
+func (s *Server) DatabaseConnection(mount, name string) (*vault.DatabaseConnection, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, ok := s.databases[normalize(mount)]
+	if !ok {
+		return nil, false
+	}
+	c, ok := db.connections[name]
+	if !ok {
+		return nil, false
+	}
+	out := *c
+	out.ConnectionDetails = maps.Clone(c.ConnectionDetails)
+	return &out, true
+}
+
+// databaseMount returns the database mount p belongs to, if any.
+func (s *Server) databaseMount(p string) string {
+	for mount, m := range s.mounts {
+		if m.Type == "database" && strings.HasPrefix(p, mount+"/") {
+			return mount
+		}
+	}
+	return ""
+}
+
+func (s *Server) handleDatabase(w http.ResponseWriter, method, mount, p string, body map[string]interface{}) {
+	db := s.databases[mount]
+	if db == nil {
+		db = &database{
+			connections: map[string]*vault.DatabaseConnection{},
+			roles:       map[string]*vault.DatabaseRole{},
+			staticRoles: map[string]*staticRole{},
+		}
+		s.databases[mount] = db
+	}
+
+	op, name, _ := strings.Cut(p, "/")
+	switch {
+	case op == "config" && method == "LIST":
+		writeKeys(w, db.connections)
+	case op == "config" && method == http.MethodGet:
+		c, ok := db.connections[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		out := *c
+		out.ConnectionDetails = maps.Clone(c.ConnectionDetails)
+		delete(out.ConnectionDetails, "password")
+		delete(out.ConnectionDetails, "private_key")
+		writeData(w, toMap(out))
+	case op == "config" && (method == http.MethodPut || method == http.MethodPost):
+		c := &vault.DatabaseConnection{ConnectionDetails: map[string]interface{}{}, VerifyConnection: true}
+		if existing, ok := db.connections[name]; ok {
+			*c = *existing
+			c.ConnectionDetails = maps.Clone(existing.ConnectionDetails)
+		}
+		// Connection details are merged, so that updates can leave the
+		// password out.
+		for k, v := range body {
+			switch k {
+			case "plugin_name":
+				c.PluginName = fmt.Sprint(v)
+			case "plugin_version":
+				c.PluginVersion = fmt.Sprint(v)
+			case "allowed_roles":
+				c.AllowedRoles = toStrings(v)
+			case "root_rotation_statements":
+				c.RootRotationStatements = toStrings(v)
+			case "password_policy":
+				c.PasswordPolicy = fmt.Sprint(v)
+			case "verify_connection":
+				c.VerifyConnection = v == true
+			default:
+				c.ConnectionDetails[k] = v
+			}
+		}
+		if c.PluginName == "" {
+			writeErrors(w, http.StatusBadRequest, "empty plugin name")
+			return
+		}
+		db.connections[name] = c
+		w.WriteHeader(http.StatusNoContent)
+	case op == "config" && method == http.MethodDelete:
+		delete(db.connections, name)
+		w.WriteHeader(http.StatusNoContent)
+	case op == "reset" && (method == http.MethodPut || method == http.MethodPost):
+		if _, ok := db.connections[name]; !ok {
+			writeErrors(w, http.StatusBadRequest, "unable to reset connection")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case op == "rotate-root" && (method == http.MethodPut || method == http.MethodPost):
+		c, ok := db.connections[name]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, "unable to rotate root credentials: no configuration found")
+			return
+		}
+		c.ConnectionDetails["password"] = s.newPassword()
+		w.WriteHeader(http.StatusNoContent)
+	case op == "roles" && method == "LIST":
+		writeKeys(w, db.roles)
+	case op == "roles" && method == http.MethodGet:
+		r, ok := db.roles[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(r))
+	case op == "roles" && (method == http.MethodPut || method == http.MethodPost):
+		r := &vault.DatabaseRole{CredentialType: "password"}
+		if existing, ok := db.roles[name]; ok {
+			*r = *existing
+		}
+		for k, v := range body {
+			var err error
+			switch k {
+			case "db_name":
+				r.DBName = fmt.Sprint(v)
+			case "creation_statements":
+				r.CreationStatements = toStrings(v)
+			case "revocation_statements":
+				r.RevocationStatements = toStrings(v)
+			case "rollback_statements":
+				r.RollbackStatements = toStrings(v)
+			case "renew_statements":
+				r.RenewStatements = toStrings(v)
+			case "default_ttl":
+				r.DefaultTTL, err = vault.ParseTTL(fmt.Sprint(v))
+			case "max_ttl":
+				r.MaxTTL, err = vault.ParseTTL(fmt.Sprint(v))
+			case "credential_type":
+				r.CredentialType = fmt.Sprint(v)
+			case "credential_config":
+				r.CredentialConfig, _ = v.(map[string]interface{})
+			}
+			if err != nil {
+				writeErrors(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if r.DBName == "" {
+			writeErrors(w, http.StatusBadRequest, "empty database name attribute")
+			return
+		}
+		if len(r.CreationStatements) == 0 {
+			writeErrors(w, http.StatusBadRequest, "creation_statements are required")
+			return
+		}
+		db.roles[name] = r
+		w.WriteHeader(http.StatusNoContent)
+	case op == "roles" && method == http.MethodDelete:
+		delete(db.roles, name)
+		w.WriteHeader(http.StatusNoContent)
+	case op == "static-roles" && method == "LIST":
+		writeKeys(w, db.staticRoles)
+	case op == "static-roles" && method == http.MethodGet:
+		r, ok := db.staticRoles[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(r.DatabaseStaticRole))
+	case op == "static-roles" && (method == http.MethodPut || method == http.MethodPost):
+		r := &staticRole{DatabaseStaticRole: vault.DatabaseStaticRole{CredentialType: "password"}}
+		existing, exists := db.staticRoles[name]
+		if exists {
+			c := *existing
+			r = &c
+		}
+		if _, ok := body["rotation_period"]; ok && body["rotation_schedule"] != nil {
+			writeErrors(w, http.StatusBadRequest, "mutually exclusive fields rotation_period and rotation_schedule were both specified; only one of them can be provided")
+			return
+		}
+		for k, v := range body {
+			var err error
+			switch k {
+			case "db_name":
+				r.DBName = fmt.Sprint(v)
+			case "username":
+				if exists && fmt.Sprint(v) != existing.Username {
+					err = fmt.Errorf("cannot update static account username")
+				}
+				r.Username = fmt.Sprint(v)
+			case "rotation_period":
+				// Switching to a period drops the schedule and vice versa
+				r.RotationPeriod, err = vault.ParseTTL(fmt.Sprint(v))
+				r.RotationSchedule, r.RotationWindow = "", 0
+			case "rotation_schedule":
+				r.RotationSchedule = fmt.Sprint(v)
+				r.RotationPeriod = 0
+			case "rotation_window":
+				r.RotationWindow, err = vault.ParseTTL(fmt.Sprint(v))
+			case "rotation_statements":
+				r.RotationStatements = toStrings(v)
+			case "credential_type":
+				r.CredentialType = fmt.Sprint(v)
+			}
+			if err != nil {
+				writeErrors(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if r.Username == "" {
+			writeErrors(w, http.StatusBadRequest, "username is a required field to create a static account")
+			return
+		}
+		if (r.RotationPeriod == 0) == (r.RotationSchedule == "") {
+			writeErrors(w, http.StatusBadRequest, "must provide exactly one of rotation_schedule or rotation_period")
+			return
+		}
+		if _, ok := db.connections[r.DBName]; !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown database connection: %s", r.DBName))
+			return
+		}
+		// Vault takes over the account by rotating its password on creation
+		if !exists {
+			r.rotate(s.newPassword())
+		}
+		db.staticRoles[name] = r
+		w.WriteHeader(http.StatusNoContent)
+	case op == "static-roles" && method == http.MethodDelete:
+		delete(db.staticRoles, name)
+		w.WriteHeader(http.StatusNoContent)
+	case op == "static-creds" && method == http.MethodGet:
+		r, ok := db.staticRoles[name]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, "unknown role")
+			return
+		}
+		data := map[string]interface{}{
+			"username":            r.Username,
+			"password":            r.password,
+			"last_vault_rotation": r.LastVaultRotation,
+		}
+		if r.RotationPeriod > 0 {
+			period := time.Duration(r.RotationPeriod) * time.Second
+			if time.Since(r.lastRotation) >= period {
+				r.rotate(s.newPassword())
+				data["password"] = r.password
+				data["last_vault_rotation"] = r.LastVaultRotation
+			}
+			data["rotation_period"] = r.RotationPeriod
+			data["ttl"] = int((period - time.Since(r.lastRotation)).Seconds())
+		} else {
+			data["rotation_schedule"] = r.RotationSchedule
+		}
+		writeData(w, data)
+	case op == "rotate-role" && (method == http.MethodPut || method == http.MethodPost):
+		r, ok := db.staticRoles[name]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, "no static role found for role name")
+			return
+		}
+		r.rotate(s.newPassword())
+		w.WriteHeader(http.StatusNoContent)
+	case op == "creds" && method == http.MethodGet:
+		r, ok := db.roles[name]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown role: %s", name))
+			return
+		}
+		c, ok := db.connections[r.DBName]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown database connection: %s", r.DBName))
+			return
+		}
+		if !slices.Contains(c.AllowedRoles, "*") && !slices.Contains(c.AllowedRoles, name) {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("%q is not an allowed role", name))
+			return
+		}
+
+		password := s.newPassword()
+		id := fmt.Sprintf("%s/creds/%s/%08x", mount, name, s.counter)
+		ttl, maxTTL := s.leaseTTL(mount, r.DefaultTTL, r.MaxTTL)
+		l := s.issueLease(id, ttl, maxTTL)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"lease_id":       id,
+			"renewable":      l.renewable,
+			"lease_duration": l.ttl,
+			"data": map[string]interface{}{
+				"username": fmt.Sprintf("v-%s-%08x", name, s.counter),
+				"password": password,
+			},
+		})
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// newPassword generates a password unique to the server.
+func (s *Server) newPassword() string {
+	s.counter++
+	return fmt.Sprintf("A1a-%08x", s.counter)
+}
+
+// leaseTTL returns the TTL and max TTL of a lease, falling back to the
+// settings of the mount and then to the system defaults.
+func (s *Server) leaseTTL(mount string, ttl, maxTTL int) (time.Duration, time.Duration) {
+	m := s.mounts[mount]
+	if maxTTL == 0 && m != nil {
+		maxTTL = m.Config.MaxLeaseTTL
+	}
+	if ttl == 0 && m != nil {
+		ttl = m.Config.DefaultLeaseTTL
+	}
+
+	maxDuration := time.Duration(maxTTL) * time.Second
+	if maxDuration == 0 {
+		maxDuration = defaultMaxLeaseTTL
+	}
+	duration := time.Duration(ttl) * time.Second
+	if duration == 0 || duration > maxDuration {
+		duration = maxDuration
+	}
+	return duration, maxDuration
+}
+
+// toStrings accepts lists as JSON arrays or comma separated strings, the way
+// Vault does for most list parameters.
+func toStrings(v interface{}) []string {
+	switch value := v.(type) {
+	case []interface{}:
+		out := make([]string, 0, len(value))
+		for _, e := range value {
+			out = append(out, fmt.Sprint(e))
+		}
+		return out
+	case string:
+		if value == "" {
+			return nil
+		}
+		return strings.Split(value, ",")
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,529 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// IdentityEntity returns an entity by name, along with its aliases.
+func (s *Server) IdentityEntity(name string) (*vault.IdentityEntity, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entityByName(name)
+	if e == nil {
+		return nil, false
+	}
+	return s.entityWithAliases(e), true
+}
+
+// IdentityEntityAlias returns an entity alias by ID.
+func (s *Server) IdentityEntityAlias(id string) (*vault.IdentityEntityAlias, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.entityAliases[id]
+	if !ok {
+		return nil, false
+	}
+	out := *a
+	return &out, true
+}
+
+// IdentityGroup returns a group by name, along with its alias.
+func (s *Server) IdentityGroup(name string) (*vault.IdentityGroup, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.groupByName(name)
+	if g == nil {
+		return nil, false
+	}
+	return s.groupWithAlias(g), true
+}
+
+// IdentityGroupAlias returns a group alias by ID.
+func (s *Server) IdentityGroupAlias(id string) (*vault.IdentityGroupAlias, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.groupAliases[id]
+	if !ok {
+		return nil, false
+	}
+	out := *a
+	return &out, true
+}
+
+func (s *Server) entityByName(name string) *vault.IdentityEntity {
+	for _, e := range s.entities {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// entityWithAliases returns a copy of an entity with the aliases bound to it.
+func (s *Server) entityWithAliases(e *vault.IdentityEntity) *vault.IdentityEntity {
+	out := *e
+	out.Aliases = []vault.IdentityEntityAlias{}
+	for _, a := range s.entityAliases {
+		if a.CanonicalID == e.ID {
+			out.Aliases = append(out.Aliases, *a)
+		}
+	}
+	sort.Slice(out.Aliases, func(i, j int) bool { return out.Aliases[i].ID < out.Aliases[j].ID })
+	return &out
+}
+
+func (s *Server) deleteEntity(e *vault.IdentityEntity) {
+	for id, a := range s.entityAliases {
+		if a.CanonicalID == e.ID {
+			delete(s.entityAliases, id)
+		}
+	}
+	for _, g := range s.groups {
+		g.MemberEntityIDs = slices.DeleteFunc(g.MemberEntityIDs, func(id string) bool { return id == e.ID })
+	}
+	delete(s.entities, e.ID)
+}
+
+// isAuthAccessor reports whether accessor belongs to an enabled auth method.
+func (s *Server) isAuthAccessor(accessor string) bool {
+	for _, m := range s.auths {
+		if m.Accessor == accessor {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleIdentity(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	switch {
+	case p == "entity/name" && method == "LIST":
+		names := map[string]struct{}{}
+		for _, e := range s.entities {
+			names[e.Name] = struct{}{}
+		}
+		writeKeys(w, names)
+	case strings.HasPrefix(p, "entity/name/"):
+		s.handleEntityByName(w, method, strings.TrimPrefix(p, "entity/name/"), body)
+	case strings.HasPrefix(p, "entity/id/"):
+		e, ok := s.entities[strings.TrimPrefix(p, "entity/id/")]
+		switch {
+		case method == http.MethodGet && !ok:
+			writeErrors(w, http.StatusNotFound)
+		case method == http.MethodGet:
+			writeData(w, toMap(s.entityWithAliases(e)))
+		case method == http.MethodDelete:
+			if ok {
+				s.deleteEntity(e)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeErrors(w, http.StatusMethodNotAllowed)
+		}
+	case p == "entity-alias" && (method == http.MethodPut || method == http.MethodPost):
+		s.writeEntityAlias(w, nil, body)
+	case strings.HasPrefix(p, "entity-alias/id/"):
+		a, ok := s.entityAliases[strings.TrimPrefix(p, "entity-alias/id/")]
+		switch {
+		case !ok && method != http.MethodDelete:
+			writeErrors(w, http.StatusNotFound)
+		case method == http.MethodGet:
+			writeData(w, toMap(a))
+		case method == http.MethodPut || method == http.MethodPost:
+			s.writeEntityAlias(w, a, body)
+		case method == http.MethodDelete:
+			if ok {
+				delete(s.entityAliases, a.ID)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeErrors(w, http.StatusMethodNotAllowed)
+		}
+	case p == "lookup/entity" && (method == http.MethodPut || method == http.MethodPost):
+		s.lookupEntity(w, body)
+	case p == "group/name" && method == "LIST":
+		names := map[string]struct{}{}
+		for _, g := range s.groups {
+			names[g.Name] = struct{}{}
+		}
+		writeKeys(w, names)
+	case strings.HasPrefix(p, "group/name/"):
+		s.handleGroupByName(w, method, strings.TrimPrefix(p, "group/name/"), body)
+	case strings.HasPrefix(p, "group/id/"):
+		g, ok := s.groups[strings.TrimPrefix(p, "group/id/")]
+		switch {
+		case method == http.MethodGet && !ok:
+			writeErrors(w, http.StatusNotFound)
+		case method == http.MethodGet:
+			writeData(w, toMap(s.groupWithAlias(g)))
+		case method == http.MethodDelete:
+			if ok {
+				s.deleteGroup(g)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeErrors(w, http.StatusMethodNotAllowed)
+		}
+	case p == "group-alias" && (method == http.MethodPut || method == http.MethodPost):
+		s.writeGroupAlias(w, nil, body)
+	case strings.HasPrefix(p, "group-alias/id/"):
+		a, ok := s.groupAliases[strings.TrimPrefix(p, "group-alias/id/")]
+		switch {
+		case !ok && method != http.MethodDelete:
+			writeErrors(w, http.StatusNotFound)
+		case method == http.MethodGet:
+			writeData(w, toMap(a))
+		case method == http.MethodPut || method == http.MethodPost:
+			s.writeGroupAlias(w, a, body)
+		case method == http.MethodDelete:
+			if ok {
+				delete(s.groupAliases, a.ID)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeErrors(w, http.StatusMethodNotAllowed)
+		}
+	case strings.HasPrefix(p, "oidc/"):
+		s.handleOIDC(w, method, strings.TrimPrefix(p, "oidc/"), body)
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleEntityByName(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	e := s.entityByName(name)
+	switch method {
+	case http.MethodGet:
+		if e == nil {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(s.entityWithAliases(e)))
+	case http.MethodPut, http.MethodPost:
+		var in vault.IdentityEntity
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if e != nil {
+			// Update only the fields provided
+			if _, ok := body["policies"]; ok {
+				e.Policies = in.Policies
+			}
+			if _, ok := body["metadata"]; ok {
+				e.Metadata = in.Metadata
+			}
+			if _, ok := body["disabled"]; ok {
+				e.Disabled = in.Disabled
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		s.counter++
+		e = &vault.IdentityEntity{
+			ID:       fmt.Sprintf("%08x-2222-0000-0000-000000000000", s.counter),
+			Name:     name,
+			Policies: in.Policies,
+			Metadata: in.Metadata,
+			Disabled: in.Disabled,
+		}
+		s.entities[e.ID] = e
+		writeData(w, map[string]interface{}{"id": e.ID, "name": e.Name})
+	case http.MethodDelete:
+		if e != nil {
+			s.deleteEntity(e)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// writeEntityAlias creates an alias when current is nil, or updates it.
+func (s *Server) writeEntityAlias(w http.ResponseWriter, current *vault.IdentityEntityAlias, body map[string]interface{}) {
+	var in vault.IdentityEntityAlias
+	if err := fromMap(body, &in); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	a := vault.IdentityEntityAlias{}
+	if current != nil {
+		a = *current
+	}
+	if in.Name != "" {
+		a.Name = in.Name
+	}
+	if in.CanonicalID != "" {
+		a.CanonicalID = in.CanonicalID
+	}
+	if in.MountAccessor != "" {
+		a.MountAccessor = in.MountAccessor
+	}
+	if _, ok := body["custom_metadata"]; ok {
+		a.CustomMetadata = in.CustomMetadata
+	}
+
+	switch {
+	case a.Name == "":
+		writeErrors(w, http.StatusBadRequest, "missing alias name")
+		return
+	case !s.isAuthAccessor(a.MountAccessor):
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid mount accessor %q", a.MountAccessor))
+		return
+	case a.CanonicalID != "" && s.entities[a.CanonicalID] == nil:
+		writeErrors(w, http.StatusBadRequest, "invalid canonical ID")
+		return
+	}
+
+	for _, o := range s.entityAliases {
+		if o.ID == a.ID {
+			continue
+		}
+		if o.Name == a.Name && o.MountAccessor == a.MountAccessor {
+			writeErrors(w, http.StatusBadRequest, "alias with combination of mount accessor and name already exists")
+			return
+		}
+		if a.CanonicalID != "" && o.CanonicalID == a.CanonicalID && o.MountAccessor == a.MountAccessor {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("mount accessor %q has already been used by alias %q of the entity", a.MountAccessor, o.ID))
+			return
+		}
+	}
+
+	if current == nil {
+		s.counter++
+		a.ID = fmt.Sprintf("%08x-3333-0000-0000-000000000000", s.counter)
+		if a.CanonicalID == "" {
+			// Vault creates an entity for aliases created on their own
+			e := &vault.IdentityEntity{ID: fmt.Sprintf("%08x-2222-0000-0000-000000000000", s.counter), Name: "entity_" + a.ID[:8]}
+			s.entities[e.ID] = e
+			a.CanonicalID = e.ID
+		}
+	}
+	s.entityAliases[a.ID] = &a
+	writeData(w, map[string]interface{}{"id": a.ID, "canonical_id": a.CanonicalID})
+}
+
+// lookupEntity finds an entity by name, ID, or the name and mount accessor of
+// one of its aliases. Nothing is returned when there is no match.
+func (s *Server) lookupEntity(w http.ResponseWriter, body map[string]interface{}) {
+	var in struct {
+		Name               string `json:"name"`
+		ID                 string `json:"id"`
+		AliasName          string `json:"alias_name"`
+		AliasMountAccessor string `json:"alias_mount_accessor"`
+	}
+	if err := fromMap(body, &in); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var e *vault.IdentityEntity
+	switch {
+	case in.Name != "":
+		e = s.entityByName(in.Name)
+	case in.ID != "":
+		e = s.entities[in.ID]
+	case in.AliasName != "" && in.AliasMountAccessor != "":
+		for _, a := range s.entityAliases {
+			if a.Name == in.AliasName && a.MountAccessor == in.AliasMountAccessor {
+				e = s.entities[a.CanonicalID]
+			}
+		}
+	default:
+		writeErrors(w, http.StatusBadRequest, "one of name, id or alias_name and alias_mount_accessor is required")
+		return
+	}
+
+	if e == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeData(w, toMap(s.entityWithAliases(e)))
+}
+
+func (s *Server) groupByName(name string) *vault.IdentityGroup {
+	for _, g := range s.groups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// groupWithAlias returns a copy of a group with its alias, which is empty when
+// there is none.
+func (s *Server) groupWithAlias(g *vault.IdentityGroup) *vault.IdentityGroup {
+	out := *g
+	out.Alias = &vault.IdentityGroupAlias{}
+	for _, a := range s.groupAliases {
+		if a.CanonicalID == g.ID {
+			alias := *a
+			out.Alias = &alias
+		}
+	}
+	return &out
+}
+
+func (s *Server) deleteGroup(g *vault.IdentityGroup) {
+	for id, a := range s.groupAliases {
+		if a.CanonicalID == g.ID {
+			delete(s.groupAliases, id)
+		}
+	}
+	for _, o := range s.groups {
+		o.MemberGroupIDs = slices.DeleteFunc(o.MemberGroupIDs, func(id string) bool { return id == g.ID })
+	}
+	delete(s.groups, g.ID)
+}
+
+func (s *Server) handleGroupByName(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	g := s.groupByName(name)
+	switch method {
+	case http.MethodGet:
+		if g == nil {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(s.groupWithAlias(g)))
+	case http.MethodPut, http.MethodPost:
+		var in vault.IdentityGroup
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		created := g == nil
+		if created {
+			g = &vault.IdentityGroup{Name: name, Type: "internal"}
+		}
+		next := *g
+		if in.Type != "" {
+			if !created && in.Type != g.Type {
+				writeErrors(w, http.StatusBadRequest, "group type cannot be changed")
+				return
+			}
+			next.Type = in.Type
+		}
+		// Update only the fields provided
+		if _, ok := body["policies"]; ok {
+			next.Policies = in.Policies
+		}
+		if _, ok := body["metadata"]; ok {
+			next.Metadata = in.Metadata
+		}
+		if _, ok := body["member_entity_ids"]; ok {
+			next.MemberEntityIDs = in.MemberEntityIDs
+		}
+		if _, ok := body["member_group_ids"]; ok {
+			next.MemberGroupIDs = in.MemberGroupIDs
+		}
+
+		switch {
+		case next.Type != "internal" && next.Type != "external":
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid group type %q", next.Type))
+			return
+		case next.Type == "external" && (len(next.MemberEntityIDs) > 0 || len(next.MemberGroupIDs) > 0):
+			writeErrors(w, http.StatusBadRequest, "member entities and groups can't be set manually for external groups")
+			return
+		}
+		for _, id := range next.MemberEntityIDs {
+			if s.entities[id] == nil {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid member entity ID %q", id))
+				return
+			}
+		}
+		for _, id := range next.MemberGroupIDs {
+			if s.groups[id] == nil || id == g.ID {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid member group ID %q", id))
+				return
+			}
+		}
+
+		if !created {
+			*g = next
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		s.counter++
+		next.ID = fmt.Sprintf("%08x-4444-0000-0000-000000000000", s.counter)
+		s.groups[next.ID] = &next
+		writeData(w, map[string]interface{}{"id": next.ID, "name": next.Name})
+	case http.MethodDelete:
+		if g != nil {
+			s.deleteGroup(g)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// writeGroupAlias creates an alias when current is nil, or updates it.
+func (s *Server) writeGroupAlias(w http.ResponseWriter, current *vault.IdentityGroupAlias, body map[string]interface{}) {
+	var in vault.IdentityGroupAlias
+	if err := fromMap(body, &in); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	a := vault.IdentityGroupAlias{}
+	if current != nil {
+		a = *current
+	}
+	if in.Name != "" {
+		a.Name = in.Name
+	}
+	if in.CanonicalID != "" {
+		a.CanonicalID = in.CanonicalID
+	}
+	if in.MountAccessor != "" {
+		a.MountAccessor = in.MountAccessor
+	}
+
+	g := s.groups[a.CanonicalID]
+	switch {
+	case a.Name == "":
+		writeErrors(w, http.StatusBadRequest, "missing alias name")
+		return
+	case !s.isAuthAccessor(a.MountAccessor):
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid mount accessor %q", a.MountAccessor))
+		return
+	case g == nil:
+		writeErrors(w, http.StatusBadRequest, "invalid canonical ID")
+		return
+	case g.Type != "external":
+		writeErrors(w, http.StatusBadRequest, "alias can't be set on an internal group")
+		return
+	}
+
+	for _, o := range s.groupAliases {
+		if o.ID == a.ID {
+			continue
+		}
+		if o.Name == a.Name && o.MountAccessor == a.MountAccessor {
+			writeErrors(w, http.StatusBadRequest, "combination of mount and group alias name is already in use")
+			return
+		}
+		if o.CanonicalID == a.CanonicalID {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("group %q already has alias %q", g.Name, o.ID))
+			return
+		}
+	}
+
+	if current == nil {
+		s.counter++
+		a.ID = fmt.Sprintf("%08x-5555-0000-0000-000000000000", s.counter)
+	}
+	s.groupAliases[a.ID] = &a
+	writeData(w, map[string]interface{}{"id": a.ID, "canonical_id": a.CanonicalID})
+}
@@ -0,0 +1,275 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// kvSecret is a secret stored in a KV v2 mount.
+type kvSecret struct {
+	metadata vault.KVMetadata
+	versions map[int]map[string]interface{}
+}
+
+// KVData returns the data of the current version of a KV v2 secret along with
+// its version number. Deleted and destroyed versions are reported missing.
+func (s *Server) KVData(mount, p string) (map[string]interface{}, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, ok := s.kv[kvKey(mount, p)]
+	if !ok {
+		return nil, 0, false
+	}
+	v := secret.metadata.CurrentVersion
+	if !secret.isLive(v) {
+		return nil, v, false
+	}
+	return roundTrip(secret.versions[v]), v, true
+}
+
+// KVMetadata returns the metadata of a KV v2 secret.
+func (s *Server) KVMetadata(mount, p string) (*vault.KVMetadata, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secret, ok := s.kv[kvKey(mount, p)]
+	if !ok {
+		return nil, false
+	}
+	m := secret.metadataOutput()
+	return &m, true
+}
+
+// kvMount returns the KV v2 mount p belongs to, if any.
+func (s *Server) kvMount(p string) string {
+	for mount, m := range s.mounts {
+		if m.Type == "kv" && m.Options["version"] == "2" && strings.HasPrefix(p, mount+"/") {
+			return mount
+		}
+	}
+	return ""
+}
+
+func (s *Server) handleKV(w http.ResponseWriter, method, mount, p string, version string, body map[string]interface{}) {
+	op, secretPath, _ := strings.Cut(p, "/")
+	key := kvKey(mount, secretPath)
+	secret := s.kv[key]
+
+	switch {
+	case op == "data" && method == http.MethodGet:
+		if secret == nil || secret.metadata.CurrentVersion == 0 {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		v := secret.metadata.CurrentVersion
+		if version != "" && version != "0" {
+			n, err := strconv.Atoi(version)
+			if err != nil {
+				writeErrors(w, http.StatusBadRequest, "invalid version")
+				return
+			}
+			v = n
+		}
+		if _, ok := secret.metadata.Versions[strconv.Itoa(v)]; !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		out := map[string]interface{}{
+			"data":     secret.versions[v],
+			"metadata": secret.versionOutput(v),
+		}
+		if !secret.isLive(v) {
+			out["data"] = nil
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"data": out})
+			return
+		}
+		writeData(w, out)
+	case op == "data" && (method == http.MethodPut || method == http.MethodPost):
+		if secret == nil {
+			secret = &kvSecret{versions: map[int]map[string]interface{}{}}
+		}
+		options, _ := body["options"].(map[string]interface{})
+		cas, hasCAS := options["cas"].(float64)
+		if secret.metadata.CASRequired && !hasCAS {
+			writeErrors(w, http.StatusBadRequest, "check-and-set parameter required for this call")
+			return
+		}
+		if hasCAS && int(cas) != secret.metadata.CurrentVersion {
+			writeErrors(w, http.StatusBadRequest, "check-and-set parameter did not match the current version")
+			return
+		}
+
+		data, _ := body["data"].(map[string]interface{})
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		v := secret.metadata.CurrentVersion + 1
+		secret.versions[v] = data
+		secret.metadata.CurrentVersion = v
+		if secret.metadata.Versions == nil {
+			secret.metadata.Versions = map[string]vault.KVVersionMetadata{}
+		}
+		secret.metadata.Versions[strconv.Itoa(v)] = vault.KVVersionMetadata{CreatedTime: now()}
+		secret.trim()
+		s.kv[key] = secret
+
+		writeData(w, secret.versionOutput(v))
+	case op == "data" && method == http.MethodDelete:
+		if secret != nil {
+			secret.delete(secret.metadata.CurrentVersion)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case (op == "delete" || op == "undelete" || op == "destroy") && (method == http.MethodPut || method == http.MethodPost):
+		if secret == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		versions, _ := body["versions"].([]interface{})
+		for _, v := range versions {
+			n, _ := v.(float64)
+			switch op {
+			case "delete":
+				secret.delete(int(n))
+			case "undelete":
+				if vm, ok := secret.metadata.Versions[strconv.Itoa(int(n))]; ok && !vm.Destroyed {
+					vm.DeletionTime = ""
+					secret.metadata.Versions[strconv.Itoa(int(n))] = vm
+				}
+			case "destroy":
+				if vm, ok := secret.metadata.Versions[strconv.Itoa(int(n))]; ok {
+					vm.Destroyed = true
+					secret.metadata.Versions[strconv.Itoa(int(n))] = vm
+					delete(secret.versions, int(n))
+				}
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case op == "metadata" && method == "LIST":
+		keys := map[string]struct{}{}
+		prefix := kvKey(mount, secretPath)
+		if secretPath != "" {
+			prefix += "/"
+		}
+		for k := range s.kv {
+			rest, ok := strings.CutPrefix(k, prefix)
+			if !ok {
+				continue
+			}
+			if i := strings.Index(rest, "/"); i >= 0 {
+				rest = rest[:i+1]
+			}
+			keys[rest] = struct{}{}
+		}
+		if len(keys) == 0 {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeKeys(w, keys)
+	case op == "metadata" && method == http.MethodGet:
+		if secret == nil {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(secret.metadataOutput()))
+	case op == "metadata" && (method == http.MethodPut || method == http.MethodPost):
+		if secret == nil {
+			secret = &kvSecret{versions: map[int]map[string]interface{}{}}
+			s.kv[key] = secret
+		}
+		if v, ok := body["max_versions"].(float64); ok {
+			secret.metadata.MaxVersions = int(v)
+		}
+		if v, ok := body["cas_required"].(bool); ok {
+			secret.metadata.CASRequired = v
+		}
+		if v, ok := body["custom_metadata"].(map[string]interface{}); ok {
+			secret.metadata.CustomMetadata = map[string]string{}
+			for k, val := range v {
+				secret.metadata.CustomMetadata[k] = fmt.Sprint(val)
+			}
+		}
+		secret.trim()
+		w.WriteHeader(http.StatusNoContent)
+	case op == "metadata" && method == http.MethodDelete:
+		delete(s.kv, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (k *kvSecret) isLive(v int) bool {
+	vm, ok := k.metadata.Versions[strconv.Itoa(v)]
+	return ok && vm.DeletionTime == "" && !vm.Destroyed
+}
+
+func (k *kvSecret) delete(v int) {
+	if vm, ok := k.metadata.Versions[strconv.Itoa(v)]; ok && vm.DeletionTime == "" {
+		vm.DeletionTime = now()
+		k.metadata.Versions[strconv.Itoa(v)] = vm
+	}
+}
+
+// trim drops the versions exceeding max_versions, which defaults to 10.
+func (k *kvSecret) trim() {
+	limit := k.metadata.MaxVersions
+	if limit == 0 {
+		limit = 10
+	}
+
+	var versions []int
+	for v := range k.metadata.Versions {
+		n, _ := strconv.Atoi(v)
+		versions = append(versions, n)
+	}
+	sort.Ints(versions)
+	for len(versions) > limit {
+		delete(k.metadata.Versions, strconv.Itoa(versions[0]))
+		delete(k.versions, versions[0])
+		versions = versions[1:]
+	}
+	if len(versions) > 0 {
+		k.metadata.OldestVersion = versions[0]
+	}
+}
+
+func (k *kvSecret) metadataOutput() vault.KVMetadata {
+	m := k.metadata
+	m.Versions = map[string]vault.KVVersionMetadata{}
+	for v, vm := range k.metadata.Versions {
+		m.Versions[v] = vm
+	}
+	if k.metadata.CustomMetadata != nil {
+		m.CustomMetadata = map[string]string{}
+		for key, v := range k.metadata.CustomMetadata {
+			m.CustomMetadata[key] = v
+		}
+	}
+	return m
+}
+
+func (k *kvSecret) versionOutput(v int) map[string]interface{} {
+	vm := k.metadata.Versions[strconv.Itoa(v)]
+	return map[string]interface{}{
+		"version":         v,
+		"created_time":    vm.CreatedTime,
+		"deletion_time":   vm.DeletionTime,
+		"destroyed":       vm.Destroyed,
+		"custom_metadata": k.metadata.CustomMetadata,
+	}
+}
+
+func kvKey(mount, p string) string {
+	return normalize(mount) + "/" + normalize(p)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
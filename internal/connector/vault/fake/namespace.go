@@ -0,0 +1,197 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// namespace is a Vault Enterprise namespace along with the mounts enabled in
+// it.
+type namespace struct {
+	ID             string            `json:"id"`
+	Path           string            `json:"path"`
+	CustomMetadata map[string]string `json:"custom_metadata"`
+
+	auths  map[string]*vaultapi.MountOutput
+	mounts map[string]*vaultapi.MountOutput
+}
+
+// NamespaceMetadata returns the custom metadata of the namespace at the full
+// path, e.g. "tenants/team-a".
+func (s *Server) NamespaceMetadata(p string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns, ok := s.namespaces[normalize(p)]
+	if !ok {
+		return nil, false
+	}
+	metadata := map[string]string{}
+	for k, v := range ns.CustomMetadata {
+		metadata[k] = v
+	}
+	return metadata, true
+}
+
+// splitNamespace finds the namespace a request is made in, from the
+// X-Vault-Namespace header and the longest namespace path prefixing the
+// request path.
+func (s *Server) splitNamespace(header, p string) (*namespace, string) {
+	full := p
+	if header = strings.Trim(header, "/"); header != "" {
+		full = header + "/" + p
+	}
+
+	var found *namespace
+	for k, ns := range s.namespaces {
+		if strings.HasPrefix(full, k+"/") && (found == nil || len(k) > len(strings.TrimSuffix(found.Path, "/"))) {
+			found = ns
+		}
+	}
+	if found == nil {
+		return nil, p
+	}
+	return found, strings.TrimPrefix(full, found.Path)
+}
+
+// handleNamespaced serves the requests made in a namespace, limited to child
+// namespaces and mounts.
+func (s *Server) handleNamespaced(w http.ResponseWriter, method string, ns *namespace, p string, body map[string]interface{}) {
+	parent := strings.TrimSuffix(ns.Path, "/")
+	switch {
+	case p == "sys/namespaces" || strings.HasPrefix(p, "sys/namespaces/"):
+		s.handleNamespaces(w, method, parent, strings.TrimPrefix(strings.TrimPrefix(p, "sys/namespaces"), "/"), body)
+	case p == "sys/mounts" || strings.HasPrefix(p, "sys/mounts/"):
+		s.handleNamespaceMounts(w, method, ns.mounts, strings.TrimPrefix(strings.TrimPrefix(p, "sys/mounts"), "/"), body)
+	case p == "sys/auth" || strings.HasPrefix(p, "sys/auth/"):
+		s.handleNamespaceMounts(w, method, ns.auths, strings.TrimPrefix(strings.TrimPrefix(p, "sys/auth"), "/"), body)
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleNamespaces(w http.ResponseWriter, method, parent, p string, body map[string]interface{}) {
+	full := p
+	if parent != "" {
+		full = parent + "/" + p
+	}
+
+	if p == "" {
+		if method != "LIST" {
+			writeErrors(w, http.StatusMethodNotAllowed)
+			return
+		}
+		children := map[string]struct{}{}
+		for k := range s.namespaces {
+			if child, ok := strings.CutPrefix(k, strings.TrimPrefix(parent+"/", "/")); ok && !strings.Contains(child, "/") {
+				children[child+"/"] = struct{}{}
+			}
+		}
+		writeKeys(w, children)
+		return
+	}
+	if strings.Contains(p, "/") {
+		writeErrors(w, http.StatusBadRequest, "namespace name cannot contain a slash")
+		return
+	}
+
+	ns, exists := s.namespaces[full]
+	switch method {
+	case http.MethodGet:
+		if !exists {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(ns))
+	case http.MethodPut, http.MethodPost:
+		if exists {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("namespace %q already exists", full))
+			return
+		}
+		switch p {
+		case "root", "sys", "audit", "auth", "cubbyhole", "identity":
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("%q is a reserved path and cannot be used as a namespace name", p))
+			return
+		}
+		ns := &namespace{Path: full + "/", CustomMetadata: map[string]string{}}
+		if err := fromMap(body, ns); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.counter++
+		ns.ID = fmt.Sprintf("ns%05x", s.counter)
+		ns.mounts = map[string]*vaultapi.MountOutput{
+			"cubbyhole": {Type: "ns_cubbyhole"},
+			"identity":  {Type: "ns_identity"},
+			"sys":       {Type: "ns_system"},
+		}
+		ns.auths = map[string]*vaultapi.MountOutput{
+			"token": {Type: "ns_token"},
+		}
+		s.namespaces[full] = ns
+		writeData(w, toMap(ns))
+	case http.MethodPatch:
+		if !exists {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		metadata, _ := body["custom_metadata"].(map[string]interface{})
+		for k, v := range metadata {
+			if v == nil {
+				delete(ns.CustomMetadata, k)
+				continue
+			}
+			ns.CustomMetadata[k] = fmt.Sprint(v)
+		}
+		writeData(w, toMap(ns))
+	case http.MethodDelete:
+		for k := range s.namespaces {
+			if strings.HasPrefix(k, full+"/") {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("cannot delete namespace (%q) containing child namespaces", full))
+				return
+			}
+		}
+		delete(s.namespaces, full)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// handleNamespaceMounts lists, enables and disables the auth methods or
+// secrets engines of a namespace, which are not served.
+func (s *Server) handleNamespaceMounts(w http.ResponseWriter, method string, mounts map[string]*vaultapi.MountOutput, p string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		data := map[string]interface{}{}
+		for k, m := range mounts {
+			data[k+"/"] = toMap(m)
+		}
+		writeData(w, data)
+	case http.MethodPut, http.MethodPost:
+		if _, ok := mounts[p]; ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("path is already in use at %s/", p))
+			return
+		}
+		var in vaultapi.MountInput
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.counter++
+		mounts[p] = &vaultapi.MountOutput{
+			UUID:     fmt.Sprintf("%08x-0000-0000-0000-000000000000", s.counter),
+			Type:     in.Type,
+			Accessor: fmt.Sprintf("%s_%08x", in.Type, s.counter),
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(mounts, p)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,380 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// oidcAlgorithms are the signing algorithms of OIDC keys.
+var oidcAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// oidcStore holds the identity tokens and OIDC provider configuration.
+type oidcStore struct {
+	keys        map[string]*vault.IdentityOIDCKey
+	roles       map[string]*vault.IdentityOIDCRole
+	providers   map[string]*oidcProvider
+	clients     map[string]*vault.OIDCClient
+	scopes      map[string]*vault.OIDCScope
+	assignments map[string]*vault.OIDCAssignment
+}
+
+// oidcProvider is a provider along with the issuer it was configured with.
+type oidcProvider struct {
+	vault.OIDCProvider
+	base string
+}
+
+// newOIDCStore returns the store of a new server, with the key and the
+// assignment Vault ships with.
+func newOIDCStore() *oidcStore {
+	return &oidcStore{
+		keys: map[string]*vault.IdentityOIDCKey{
+			"default": {RotationPeriod: 86400, VerificationTTL: 86400, AllowedClientIDs: []string{"*"}, Algorithm: "RS256"},
+		},
+		roles:     map[string]*vault.IdentityOIDCRole{},
+		providers: map[string]*oidcProvider{},
+		clients:   map[string]*vault.OIDCClient{},
+		scopes:    map[string]*vault.OIDCScope{},
+		assignments: map[string]*vault.OIDCAssignment{
+			"allow_all": {EntityIDs: []string{"*"}, GroupIDs: []string{"*"}},
+		},
+	}
+}
+
+// OIDCClient returns an OIDC client, including its credentials.
+func (s *Server) OIDCClient(name string) (*vault.OIDCClient, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.oidc.clients[name]
+	if !ok {
+		return nil, false
+	}
+	out := *c
+	return &out, true
+}
+
+// decodeOIDC decodes body over out, so that fields not provided keep their
+// value. Durations are parsed into the fields of ttls.
+func decodeOIDC(body map[string]interface{}, out interface{}, ttls map[string]*int) error {
+	fields := roundTrip(body)
+	for field := range ttls {
+		delete(fields, field)
+	}
+	if err := fromMap(fields, out); err != nil {
+		return err
+	}
+	for field, ttl := range ttls {
+		if v, ok := body[field]; ok {
+			var err error
+			if *ttl, err = vault.ParseTTL(fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("invalid %s: %w", field, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleOIDC(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	kind, name, _ := strings.Cut(p, "/")
+	if name == "" {
+		if method != "LIST" {
+			writeErrors(w, http.StatusMethodNotAllowed)
+			return
+		}
+		switch kind {
+		case "key":
+			writeKeys(w, s.oidc.keys)
+		case "role":
+			writeKeys(w, s.oidc.roles)
+		case "provider":
+			writeKeys(w, s.oidc.providers)
+		case "client":
+			writeKeys(w, s.oidc.clients)
+		case "scope":
+			writeKeys(w, s.oidc.scopes)
+		case "assignment":
+			writeKeys(w, s.oidc.assignments)
+		default:
+			writeErrors(w, http.StatusNotFound)
+		}
+		return
+	}
+
+	switch kind {
+	case "key":
+		s.handleOIDCKey(w, method, name, body)
+	case "role":
+		s.handleOIDCRole(w, method, name, body)
+	case "provider":
+		s.handleOIDCProvider(w, method, name, body)
+	case "client":
+		s.handleOIDCClient(w, method, name, body)
+	case "scope":
+		s.handleOIDCScope(w, method, name, body)
+	case "assignment":
+		s.handleOIDCAssignment(w, method, name, body)
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleOIDCKey(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		k, ok := s.oidc.keys[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(k))
+	case http.MethodPut, http.MethodPost:
+		k := vault.IdentityOIDCKey{RotationPeriod: 86400, VerificationTTL: 86400, AllowedClientIDs: []string{}, Algorithm: "RS256"}
+		if current, ok := s.oidc.keys[name]; ok {
+			k = *current
+		}
+		if err := decodeOIDC(body, &k, map[string]*int{"rotation_period": &k.RotationPeriod, "verification_ttl": &k.VerificationTTL}); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !slices.Contains(oidcAlgorithms, k.Algorithm) {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown signing algorithm %q", k.Algorithm))
+			return
+		}
+		s.oidc.keys[name] = &k
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		var users []string
+		for role, r := range s.oidc.roles {
+			if r.Key == name {
+				users = append(users, role)
+			}
+		}
+		for client, c := range s.oidc.clients {
+			if c.Key == name {
+				users = append(users, client)
+			}
+		}
+		if len(users) > 0 {
+			slices.Sort(users)
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unable to delete key %q because it is currently referenced by these roles and clients: %s", name, strings.Join(users, ", ")))
+			return
+		}
+		delete(s.oidc.keys, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleOIDCRole(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		r, ok := s.oidc.roles[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(r))
+	case http.MethodPut, http.MethodPost:
+		r := vault.IdentityOIDCRole{TTL: 86400}
+		if current, ok := s.oidc.roles[name]; ok {
+			r = *current
+		}
+		if err := decodeOIDC(body, &r, map[string]*int{"ttl": &r.TTL}); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := s.oidc.keys[r.Key]; !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("key %q does not exist", r.Key))
+			return
+		}
+		if r.ClientID == "" {
+			s.counter++
+			r.ClientID = fmt.Sprintf("role%028x", s.counter)
+		}
+		s.oidc.roles[name] = &r
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.oidc.roles, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleOIDCProvider(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		p, ok := s.oidc.providers[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		out := p.OIDCProvider
+		out.Issuer = fmt.Sprintf("%s/v1/identity/oidc/provider/%s", p.base, name)
+		writeData(w, toMap(out))
+	case http.MethodPut, http.MethodPost:
+		p := oidcProvider{OIDCProvider: vault.OIDCProvider{AllowedClientIDs: []string{}, ScopesSupported: []string{}}, base: s.URL}
+		if current, ok := s.oidc.providers[name]; ok {
+			p = *current
+		}
+		p.Issuer = ""
+		if err := decodeOIDC(body, &p.OIDCProvider, nil); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if p.Issuer != "" {
+			u, err := url.Parse(p.Issuer)
+			if err != nil || u.Scheme == "" || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+				writeErrors(w, http.StatusBadRequest, "invalid issuer, which must include only a scheme, host, and optional port")
+				return
+			}
+			p.base = strings.TrimSuffix(p.Issuer, "/")
+		}
+		for _, scope := range p.ScopesSupported {
+			if _, ok := s.oidc.scopes[scope]; !ok {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("scope %q does not exist", scope))
+				return
+			}
+		}
+		s.oidc.providers[name] = &p
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.oidc.providers, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleOIDCClient(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		c, ok := s.oidc.clients[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(c))
+	case http.MethodPut, http.MethodPost:
+		current, exists := s.oidc.clients[name]
+		c := vault.OIDCClient{RedirectURIs: []string{}, Assignments: []string{}, ClientType: "confidential", IDTokenTTL: 86400, AccessTokenTTL: 86400}
+		if exists {
+			c = *current
+		}
+		if err := decodeOIDC(body, &c, map[string]*int{"id_token_ttl": &c.IDTokenTTL, "access_token_ttl": &c.AccessTokenTTL}); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		switch {
+		case exists && c.ClientType != current.ClientType:
+			writeErrors(w, http.StatusBadRequest, "client_type cannot be updated")
+			return
+		case c.ClientType != "confidential" && c.ClientType != "public":
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid client_type %q", c.ClientType))
+			return
+		}
+		if _, ok := s.oidc.keys[c.Key]; !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("key %q does not exist", c.Key))
+			return
+		}
+		for _, assignment := range c.Assignments {
+			if _, ok := s.oidc.assignments[assignment]; !ok {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("assignment %q does not exist", assignment))
+				return
+			}
+		}
+		if !exists {
+			s.counter++
+			c.ClientID = fmt.Sprintf("client%026x", s.counter)
+			if c.ClientType == "confidential" {
+				c.ClientSecret = fmt.Sprintf("hvo_secret_%032x", s.counter)
+			}
+		}
+		s.oidc.clients[name] = &c
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.oidc.clients, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleOIDCScope(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		sc, ok := s.oidc.scopes[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(sc))
+	case http.MethodPut, http.MethodPost:
+		if name == "openid" {
+			writeErrors(w, http.StatusBadRequest, `the "openid" scope name is reserved`)
+			return
+		}
+		sc := vault.OIDCScope{}
+		if current, ok := s.oidc.scopes[name]; ok {
+			sc = *current
+		}
+		if err := decodeOIDC(body, &sc, nil); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.oidc.scopes[name] = &sc
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		for provider, p := range s.oidc.providers {
+			if slices.Contains(p.ScopesSupported, name) {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unable to delete scope %q because it is currently referenced by provider %q", name, provider))
+				return
+			}
+		}
+		delete(s.oidc.scopes, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleOIDCAssignment(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		a, ok := s.oidc.assignments[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(a))
+	case http.MethodPut, http.MethodPost:
+		a := vault.OIDCAssignment{EntityIDs: []string{}, GroupIDs: []string{}}
+		if current, ok := s.oidc.assignments[name]; ok {
+			a = *current
+		}
+		if err := decodeOIDC(body, &a, nil); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.oidc.assignments[name] = &a
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		for client, c := range s.oidc.clients {
+			if slices.Contains(c.Assignments, name) {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unable to delete assignment %q because it is currently referenced by client %q", name, client))
+				return
+			}
+		}
+		delete(s.oidc.assignments, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
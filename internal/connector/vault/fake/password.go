@@ -0,0 +1,136 @@
+package fake
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	passwordLengthRe  = regexp.MustCompile(`(?m)^\s*length\s*=\s*(\d+)\s*$`)
+	passwordRuleRe    = regexp.MustCompile(`rule\s+"charset"\s*\{([^}]*)\}`)
+	passwordCharsetRe = regexp.MustCompile(`charset\s*=\s*("(?:[^"\\]|\\.)*")`)
+	passwordMinRe     = regexp.MustCompile(`min-chars\s*=\s*(\d+)`)
+)
+
+// passwordRule is a charset rule of a password policy.
+type passwordRule struct {
+	charset  string
+	minChars int
+}
+
+// parsePasswordPolicy understands the length and charset rules of password
+// policies, which is all the operator renders.
+func parsePasswordPolicy(policy string) (int, []passwordRule, error) {
+	m := passwordLengthRe.FindStringSubmatch(policy)
+	if m == nil {
+		return 0, nil, fmt.Errorf("length must be specified")
+	}
+	length, _ := strconv.Atoi(m[1])
+	if length < 4 || length > 100 {
+		return 0, nil, fmt.Errorf("length must be >= 4 and <= 100")
+	}
+
+	var rules []passwordRule
+	total := 0
+	for _, block := range passwordRuleRe.FindAllStringSubmatch(policy, -1) {
+		c := passwordCharsetRe.FindStringSubmatch(block[1])
+		if c == nil {
+			return 0, nil, fmt.Errorf("charset rule must specify a charset")
+		}
+		charset, err := strconv.Unquote(c[1])
+		if err != nil || charset == "" {
+			return 0, nil, fmt.Errorf("invalid charset %s", c[1])
+		}
+		rule := passwordRule{charset: charset}
+		if n := passwordMinRe.FindStringSubmatch(block[1]); n != nil {
+			rule.minChars, _ = strconv.Atoi(n[1])
+		}
+		total += rule.minChars
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return 0, nil, fmt.Errorf("no charset specified")
+	}
+	if total > length {
+		return 0, nil, fmt.Errorf("length %d is too short for the %d characters required by the rules", length, total)
+	}
+	return length, rules, nil
+}
+
+// generatePassword draws the minimum characters of each rule, then fills the
+// password from all charsets.
+func generatePassword(length int, rules []passwordRule) string {
+	var password []rune
+	var all []rune
+	for _, rule := range rules {
+		charset := []rune(rule.charset)
+		for range rule.minChars {
+			password = append(password, charset[rand.IntN(len(charset))])
+		}
+		all = append(all, charset...)
+	}
+	for len(password) < length {
+		password = append(password, all[rand.IntN(len(all))])
+	}
+	rand.Shuffle(len(password), func(i, j int) { password[i], password[j] = password[j], password[i] })
+	return string(password)
+}
+
+// PasswordPolicy returns the HCL of a password policy.
+func (s *Server) PasswordPolicy(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy, ok := s.passwordPolicies[name]
+	return policy, ok
+}
+
+func (s *Server) handlePasswordPolicy(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	if name, ok := strings.CutSuffix(p, "/generate"); ok {
+		if method != http.MethodGet {
+			writeErrors(w, http.StatusMethodNotAllowed)
+			return
+		}
+		policy, ok := s.passwordPolicies[name]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("policy %q does not exist", name))
+			return
+		}
+		length, rules, err := parsePasswordPolicy(policy)
+		if err != nil {
+			writeErrors(w, http.StatusInternalServerError, fmt.Sprintf("failed to generate password from policy: %s", err))
+			return
+		}
+		writeData(w, map[string]interface{}{"password": generatePassword(length, rules)})
+		return
+	}
+
+	switch method {
+	case "LIST":
+		writeKeys(w, s.passwordPolicies)
+	case http.MethodGet:
+		policy, ok := s.passwordPolicies[p]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, map[string]interface{}{"policy": policy})
+	case http.MethodPut, http.MethodPost:
+		policy, _ := body["policy"].(string)
+		if _, _, err := parsePasswordPolicy(policy); err != nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid password policy: %s", err))
+			return
+		}
+		s.passwordPolicies[p] = policy
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.passwordPolicies, p)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
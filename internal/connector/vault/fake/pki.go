@@ -0,0 +1,899 @@
+package fake
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"maps"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// pki is the state of a PKI secrets engine mount. Keys are kept apart from
+// issuers, as intermediate CSRs are generated before their issuer exists.
+type pki struct {
+	keys          map[string]crypto.Signer
+	issuers       map[string]*issuer
+	defaultIssuer string
+	roles         map[string]*vault.PKIRole
+	certs         map[string]*issuedCertificate
+	urls          vault.PKIURLs
+	crl           vault.PKICRL
+	autoTidy      vault.PKIAutoTidy
+}
+
+type issuer struct {
+	vault.PKIIssuer
+	cert *x509.Certificate
+}
+
+type issuedCertificate struct {
+	cert    *x509.Certificate
+	pem     string
+	revoked time.Time
+}
+
+// PKICertificate returns a certificate issued by a PKI mount by its serial
+// number, and whether it was revoked.
+func (s *Server) PKICertificate(mount, serial string) (*x509.Certificate, bool, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.pkis[normalize(mount)]
+	if !ok {
+		return nil, false, false
+	}
+	c, ok := e.certs[serial]
+	if !ok {
+		return nil, false, false
+	}
+	return c.cert, !c.revoked.IsZero(), true
+}
+
+// pkiMount returns the PKI mount p belongs to, if any.
+func (s *Server) pkiMount(p string) string {
+	for mount, m := range s.mounts {
+		if m.Type == "pki" && strings.HasPrefix(p, mount+"/") {
+			return mount
+		}
+	}
+	return ""
+}
+
+func (s *Server) handlePKI(w http.ResponseWriter, method, mount, p string, body map[string]interface{}) {
+	e := s.pkis[mount]
+	if e == nil {
+		e = &pki{
+			keys:    map[string]crypto.Signer{},
+			issuers: map[string]*issuer{},
+			roles:   map[string]*vault.PKIRole{},
+			certs:   map[string]*issuedCertificate{},
+			urls: vault.PKIURLs{
+				IssuingCertificates:   []string{},
+				CRLDistributionPoints: []string{},
+				OCSPServers:           []string{},
+			},
+			crl: vault.PKICRL{
+				Expiry:                 "72h",
+				AutoRebuildGracePeriod: "12h",
+				DeltaRebuildInterval:   "15m",
+			},
+			autoTidy: vault.PKIAutoTidy{
+				IntervalDuration: 43200,
+				SafetyBuffer:     259200,
+			},
+		}
+		s.pkis[mount] = e
+	}
+
+	op, name, _ := strings.Cut(p, "/")
+	switch {
+	case p == "root/generate/internal" && (method == http.MethodPut || method == http.MethodPost):
+		s.generateRoot(w, e, body)
+	case p == "intermediate/generate/internal" && (method == http.MethodPut || method == http.MethodPost):
+		s.generateIntermediate(w, e, body, false)
+	case p == "intermediate/generate/existing" && (method == http.MethodPut || method == http.MethodPost):
+		s.generateIntermediate(w, e, body, true)
+	case p == "intermediate/set-signed" && (method == http.MethodPut || method == http.MethodPost):
+		s.setSignedIntermediate(w, e, body)
+	case op == "issuers" && method == "LIST":
+		keyInfo := map[string]interface{}{}
+		for id, i := range e.issuers {
+			keyInfo[id] = map[string]interface{}{
+				"issuer_name": i.IssuerName,
+				"is_default":  id == e.defaultIssuer,
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"keys":     slices.Sorted(maps.Keys(e.issuers)),
+			"key_info": keyInfo,
+		}})
+	case op == "issuer" && strings.HasSuffix(name, "/sign-intermediate") && (method == http.MethodPut || method == http.MethodPost):
+		s.signIntermediate(w, e, mount, strings.TrimSuffix(name, "/sign-intermediate"), body)
+	case op == "issuer" && method == http.MethodGet:
+		i, err := e.issuer(name)
+		if err != nil {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(i.PKIIssuer))
+	case op == "issuer" && (method == http.MethodPut || method == http.MethodPost):
+		i, err := e.issuer(name)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if v, ok := body["issuer_name"].(string); ok {
+			for _, other := range e.issuers {
+				if v != "" && other != i && other.IssuerName == v {
+					writeErrors(w, http.StatusBadRequest, fmt.Sprintf("issuer name already in use: %s", v))
+					return
+				}
+			}
+			i.IssuerName = v
+		}
+		if v, ok := body["leaf_not_after_behavior"].(string); ok {
+			if !slices.Contains([]string{"err", "truncate", "permit"}, v) {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid leaf_not_after_behavior: %s", v))
+				return
+			}
+			i.LeafNotAfterBehavior = v
+		}
+		writeData(w, toMap(i.PKIIssuer))
+	case op == "issuer" && method == http.MethodDelete:
+		if i, err := e.issuer(name); err == nil {
+			delete(e.issuers, i.IssuerID)
+			if e.defaultIssuer == i.IssuerID {
+				e.defaultIssuer = ""
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case op == "key" && method == http.MethodGet:
+		if _, ok := e.keys[name]; !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, map[string]interface{}{"key_id": name})
+	case op == "key" && method == http.MethodDelete:
+		for _, i := range e.issuers {
+			if i.KeyID == name {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unable to delete key as it is in use by an issuer: %s", i.IssuerID))
+				return
+			}
+		}
+		delete(e.keys, name)
+		w.WriteHeader(http.StatusNoContent)
+	case p == "config/issuers" && method == http.MethodGet:
+		writeData(w, map[string]interface{}{"default": e.defaultIssuer})
+	case p == "config/issuers" && (method == http.MethodPut || method == http.MethodPost):
+		i, err := e.issuer(fmt.Sprint(body["default"]))
+		if err != nil || body["default"] == "default" {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unable to find PKI issuer for reference: %v", body["default"]))
+			return
+		}
+		e.defaultIssuer = i.IssuerID
+		writeData(w, map[string]interface{}{"default": e.defaultIssuer})
+	case p == "config/urls" && method == http.MethodGet:
+		writeData(w, toMap(e.urls))
+	case p == "config/urls" && (method == http.MethodPut || method == http.MethodPost):
+		urls := e.urls
+		if err := fromMap(roundTrip(body), &urls); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		e.urls = urls
+		w.WriteHeader(http.StatusNoContent)
+	case p == "config/crl" && method == http.MethodGet:
+		writeData(w, toMap(e.crl))
+	case p == "config/crl" && (method == http.MethodPut || method == http.MethodPost):
+		crl := e.crl
+		if err := fromMap(roundTrip(body), &crl); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if crl.EnableDelta && !crl.AutoRebuild {
+			writeErrors(w, http.StatusBadRequest, "delta CRLs require auto_rebuild")
+			return
+		}
+		e.crl = crl
+		writeData(w, toMap(e.crl))
+	case p == "config/auto-tidy" && method == http.MethodGet:
+		writeData(w, toMap(e.autoTidy))
+	case p == "config/auto-tidy" && (method == http.MethodPut || method == http.MethodPost):
+		autoTidy := e.autoTidy
+		for k, v := range body {
+			var err error
+			switch k {
+			case "enabled":
+				autoTidy.Enabled = v == true
+			case "interval_duration":
+				autoTidy.IntervalDuration, err = vault.ParseTTL(fmt.Sprint(v))
+			case "tidy_cert_store":
+				autoTidy.TidyCertStore = v == true
+			case "tidy_revoked_certs":
+				autoTidy.TidyRevokedCerts = v == true
+			case "tidy_expired_issuers":
+				autoTidy.TidyExpiredIssuers = v == true
+			case "safety_buffer":
+				autoTidy.SafetyBuffer, err = vault.ParseTTL(fmt.Sprint(v))
+			}
+			if err != nil {
+				writeErrors(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		e.autoTidy = autoTidy
+		writeData(w, toMap(e.autoTidy))
+	case op == "roles" && method == "LIST":
+		writeKeys(w, e.roles)
+	case op == "roles" && method == http.MethodGet:
+		r, ok := e.roles[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(r))
+	case op == "roles" && (method == http.MethodPut || method == http.MethodPost):
+		r, err := pkiRole(body)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		e.roles[name] = r
+		w.WriteHeader(http.StatusNoContent)
+	case op == "roles" && method == http.MethodDelete:
+		delete(e.roles, name)
+		w.WriteHeader(http.StatusNoContent)
+	case op == "issue" && (method == http.MethodPut || method == http.MethodPost):
+		s.issueCertificate(w, e, mount, name, body)
+	case p == "revoke" && (method == http.MethodPut || method == http.MethodPost):
+		c, ok := e.certs[fmt.Sprint(body["serial_number"])]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("certificate with serial %s not found", body["serial_number"]))
+			return
+		}
+		if c.revoked.IsZero() {
+			c.revoked = time.Now()
+		}
+		writeData(w, map[string]interface{}{
+			"revocation_time":         c.revoked.Unix(),
+			"revocation_time_rfc3339": c.revoked.Format(time.RFC3339Nano),
+		})
+	case op == "cert" && method == http.MethodGet:
+		c, ok := e.certs[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		var revocationTime int64
+		if !c.revoked.IsZero() {
+			revocationTime = c.revoked.Unix()
+		}
+		writeData(w, map[string]interface{}{
+			"certificate":     c.pem,
+			"revocation_time": revocationTime,
+		})
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+// pkiRole builds a role from a write request. Unlike database roles, PKI role
+// writes replace the role, resetting omitted fields to their defaults.
+func pkiRole(body map[string]interface{}) (*vault.PKIRole, error) {
+	r := &vault.PKIRole{
+		IssuerRef:      "default",
+		AllowLocalhost: true,
+		AllowIPSANs:    true,
+		ServerFlag:     true,
+		ClientFlag:     true,
+		KeyType:        "rsa",
+		KeyUsage:       vault.DefaultPKIKeyUsage,
+		AllowedDomains: []string{},
+		ExtKeyUsage:    []string{},
+	}
+	for k, v := range body {
+		var err error
+		switch k {
+		case "issuer_ref":
+			r.IssuerRef = fmt.Sprint(v)
+		case "allowed_domains":
+			r.AllowedDomains = toStrings(v)
+		case "allow_bare_domains":
+			r.AllowBareDomains = v == true
+		case "allow_subdomains":
+			r.AllowSubdomains = v == true
+		case "allow_glob_domains":
+			r.AllowGlobDomains = v == true
+		case "allow_any_name":
+			r.AllowAnyName = v == true
+		case "allow_localhost":
+			r.AllowLocalhost = v == true
+		case "allow_ip_sans":
+			r.AllowIPSANs = v == true
+		case "server_flag":
+			r.ServerFlag = v == true
+		case "client_flag":
+			r.ClientFlag = v == true
+		case "key_type":
+			r.KeyType = fmt.Sprint(v)
+		case "key_bits":
+			_, err = fmt.Sscan(fmt.Sprint(v), &r.KeyBits)
+		case "ttl":
+			r.TTL, err = vault.ParseTTL(fmt.Sprint(v))
+		case "max_ttl":
+			r.MaxTTL, err = vault.ParseTTL(fmt.Sprint(v))
+		case "key_usage":
+			r.KeyUsage = toStrings(v)
+		case "ext_key_usage":
+			r.ExtKeyUsage = toStrings(v)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !slices.Contains([]string{"rsa", "ec", "ed25519", "any"}, r.KeyType) {
+		return nil, fmt.Errorf("unknown key type %s", r.KeyType)
+	}
+	if r.KeyBits == 0 {
+		r.KeyBits = vault.DefaultPKIKeyBits(r.KeyType)
+	}
+	return r, nil
+}
+
+// generateRoot creates a self-signed CA. The first issuer of a mount becomes
+// its default issuer.
+func (s *Server) generateRoot(w http.ResponseWriter, e *pki, body map[string]interface{}) {
+	subject, err := caSubject(body)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ttl, err := vault.ParseTTL(fmt.Sprint(body["ttl"]))
+	if body["ttl"] == nil || err != nil || ttl == 0 {
+		ttl = int(defaultMaxLeaseTTL.Seconds())
+	}
+	keyID, key, err := s.generatePKIKey(e, body)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          s.nextSerial(),
+		Subject:               subject,
+		NotBefore:             now.Add(-30 * time.Second),
+		NotAfter:              now.Add(time.Duration(ttl) * time.Second),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, certPEM, err := signCertificate(template, template, key.Public(), key)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	name, _ := body["issuer_name"].(string)
+	i := s.addIssuer(e, cert, certPEM, keyID, name, nil)
+	serial := formatSerial(cert.SerialNumber)
+	e.certs[serial] = &issuedCertificate{cert: cert, pem: certPEM}
+
+	writeData(w, map[string]interface{}{
+		"certificate":   certPEM,
+		"issuing_ca":    certPEM,
+		"serial_number": serial,
+		"expiration":    cert.NotAfter.Unix(),
+		"issuer_id":     i.IssuerID,
+		"issuer_name":   i.IssuerName,
+		"key_id":        keyID,
+	})
+}
+
+// generateIntermediate creates a key, or reuses the one of key_ref, and
+// returns a CSR for it, to be signed by another issuer and imported through
+// set-signed.
+func (s *Server) generateIntermediate(w http.ResponseWriter, e *pki, body map[string]interface{}, existing bool) {
+	subject, err := caSubject(body)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var keyID string
+	var key crypto.Signer
+	if existing {
+		keyID, _ = body["key_ref"].(string)
+		if key = e.keys[keyID]; key == nil {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unable to find PKI key for reference: %s", keyID))
+			return
+		}
+	} else if keyID, key, err = s.generatePKIKey(e, body); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, key)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeData(w, map[string]interface{}{
+		"csr":    strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))),
+		"key_id": keyID,
+	})
+}
+
+// signIntermediate signs an intermediate CSR with the referenced issuer.
+func (s *Server) signIntermediate(w http.ResponseWriter, e *pki, mount, ref string, body map[string]interface{}) {
+	i, err := e.issuer(ref)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	csrPEM, _ := body["csr"].(string)
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil {
+		writeErrors(w, http.StatusBadRequest, "certificate request could not be parsed")
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ttl := 0
+	if body["ttl"] != nil {
+		if ttl, err = vault.ParseTTL(fmt.Sprint(body["ttl"])); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	duration, _ := s.leaseTTL(mount, ttl, 0)
+
+	subject := csr.Subject
+	if commonName, _ := body["common_name"].(string); commonName != "" {
+		subject.CommonName = commonName
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          s.nextSerial(),
+		Subject:               subject,
+		NotBefore:             now.Add(-30 * time.Second),
+		NotAfter:              now.Add(duration),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if template.NotAfter, err = i.notAfter(template.NotAfter); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cert, certPEM, err := signCertificate(template, i.cert, csr.PublicKey, e.keys[i.KeyID])
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	serial := formatSerial(cert.SerialNumber)
+	e.certs[serial] = &issuedCertificate{cert: cert, pem: certPEM}
+	writeData(w, map[string]interface{}{
+		"certificate":   certPEM,
+		"issuing_ca":    i.Certificate,
+		"ca_chain":      i.CAChain,
+		"serial_number": serial,
+		"expiration":    cert.NotAfter.Unix(),
+	})
+}
+
+// setSignedIntermediate imports a signed intermediate certificate and its
+// chain, pairing it with the key its CSR was generated with.
+func (s *Server) setSignedIntermediate(w http.ResponseWriter, e *pki, body map[string]interface{}) {
+	bundle, _ := body["certificate"].(string)
+	var chain []string
+	var cert *x509.Certificate
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if cert == nil {
+			c, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				writeErrors(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			cert = c
+		}
+		chain = append(chain, strings.TrimSpace(string(pem.EncodeToMemory(block))))
+	}
+	if cert == nil {
+		writeErrors(w, http.StatusBadRequest, "no certificate found in the bundle")
+		return
+	}
+
+	for _, i := range e.issuers {
+		if i.cert.Equal(cert) {
+			writeData(w, map[string]interface{}{
+				"imported_issuers": []string{},
+				"existing_issuers": []string{i.IssuerID},
+				"mapping":          map[string]interface{}{i.IssuerID: i.KeyID},
+			})
+			return
+		}
+	}
+
+	keyID := ""
+	for id, key := range e.keys {
+		if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); ok && pub.Equal(cert.PublicKey) {
+			keyID = id
+		}
+	}
+	if keyID == "" {
+		writeErrors(w, http.StatusBadRequest, "no matching key found for the signed certificate")
+		return
+	}
+
+	i := s.addIssuer(e, cert, chain[0], keyID, "", chain[1:])
+	writeData(w, map[string]interface{}{
+		"imported_issuers": []string{i.IssuerID},
+		"imported_keys":    []string{},
+		"mapping":          map[string]interface{}{i.IssuerID: keyID},
+	})
+}
+
+// caSubject returns the subject of a CA from a generate request.
+func caSubject(body map[string]interface{}) (pkix.Name, error) {
+	commonName, _ := body["common_name"].(string)
+	if commonName == "" {
+		return pkix.Name{}, fmt.Errorf("the common_name field is required")
+	}
+	subject := pkix.Name{CommonName: commonName}
+	if body["organization"] != nil {
+		subject.Organization = toStrings(body["organization"])
+	}
+	return subject, nil
+}
+
+// generatePKIKey generates the key of a CA and stores it under a new ID.
+func (s *Server) generatePKIKey(e *pki, body map[string]interface{}) (string, crypto.Signer, error) {
+	// Vault defaults to rsa, ec keeps tests fast
+	keyType, _ := body["key_type"].(string)
+	if keyType == "" {
+		keyType = "ec"
+	}
+	bits := 0
+	if body["key_bits"] != nil {
+		if _, err := fmt.Sscan(fmt.Sprint(body["key_bits"]), &bits); err != nil {
+			return "", nil, err
+		}
+	}
+
+	key, err := generateKey(keyType, bits)
+	if err != nil {
+		return "", nil, err
+	}
+	s.counter++
+	id := fmt.Sprintf("%08x-1111-0000-0000-000000000000", s.counter)
+	e.keys[id] = key
+	return id, key, nil
+}
+
+// addIssuer stores a CA certificate as a new issuer. Its chain is made of the
+// certificate followed by the given parent certificates.
+func (s *Server) addIssuer(e *pki, cert *x509.Certificate, certPEM, keyID, name string, parents []string) *issuer {
+	s.counter++
+	i := &issuer{
+		PKIIssuer: vault.PKIIssuer{
+			IssuerID:             fmt.Sprintf("%08x-0000-0000-0000-000000000000", s.counter),
+			IssuerName:           name,
+			KeyID:                keyID,
+			Certificate:          certPEM,
+			CAChain:              append([]string{certPEM}, parents...),
+			LeafNotAfterBehavior: "err",
+		},
+		cert: cert,
+	}
+	e.issuers[i.IssuerID] = i
+	if e.defaultIssuer == "" {
+		e.defaultIssuer = i.IssuerID
+	}
+	return i
+}
+
+// notAfter applies the leaf_not_after_behavior of the issuer to the expiry of
+// a certificate it signs.
+func (i *issuer) notAfter(notAfter time.Time) (time.Time, error) {
+	// Certificates only carry seconds
+	notAfter = notAfter.Truncate(time.Second)
+	if !notAfter.After(i.cert.NotAfter) {
+		return notAfter, nil
+	}
+	switch i.LeafNotAfterBehavior {
+	case "truncate":
+		return i.cert.NotAfter, nil
+	case "permit":
+		return notAfter, nil
+	default:
+		return time.Time{}, fmt.Errorf("cannot satisfy request, as TTL would result in notAfter of %s that is beyond the expiration of the CA certificate at %s", notAfter.UTC().Format(time.RFC3339Nano), i.cert.NotAfter.UTC().Format(time.RFC3339Nano))
+	}
+}
+
+// issuer resolves an issuer reference, which is "default", a name or an ID.
+func (e *pki) issuer(ref string) (*issuer, error) {
+	if ref == "" || ref == "default" {
+		i, ok := e.issuers[e.defaultIssuer]
+		if !ok {
+			return nil, fmt.Errorf("no default issuer currently configured")
+		}
+		return i, nil
+	}
+	for _, i := range e.issuers {
+		if i.IssuerID == ref || i.IssuerName == ref {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find PKI issuer for reference: %s", ref)
+}
+
+func (s *Server) issueCertificate(w http.ResponseWriter, e *pki, mount, role string, body map[string]interface{}) {
+	r, ok := e.roles[role]
+	if !ok {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown role: %s", role))
+		return
+	}
+	i, err := e.issuer(r.IssuerRef)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	commonName, _ := body["common_name"].(string)
+	if commonName == "" {
+		writeErrors(w, http.StatusBadRequest, "the common_name field is required")
+		return
+	}
+	if !allowsName(r, commonName) {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("common name %s not allowed by this role", commonName))
+		return
+	}
+	dnsNames := []string{commonName}
+	if body["alt_names"] != nil {
+		for _, name := range toStrings(body["alt_names"]) {
+			if !allowsName(r, name) {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("subject alternate name %s not allowed by this role", name))
+				return
+			}
+			if !slices.Contains(dnsNames, name) {
+				dnsNames = append(dnsNames, name)
+			}
+		}
+	}
+	var ips []net.IP
+	if body["ip_sans"] != nil {
+		for _, v := range toStrings(body["ip_sans"]) {
+			ip := net.ParseIP(v)
+			if ip == nil || !r.AllowIPSANs {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("IP Subject Alternative Names are not allowed in this role, but was provided %s", v))
+				return
+			}
+			ips = append(ips, ip)
+		}
+	}
+	var uris []*url.URL
+	if body["uri_sans"] != nil {
+		for _, v := range toStrings(body["uri_sans"]) {
+			u, err := url.Parse(v)
+			if err != nil {
+				writeErrors(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			uris = append(uris, u)
+		}
+	}
+
+	ttl := r.TTL
+	if body["ttl"] != nil {
+		if ttl, err = vault.ParseTTL(fmt.Sprint(body["ttl"])); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	duration, _ := s.leaseTTL(mount, ttl, r.MaxTTL)
+
+	if r.KeyType == "any" {
+		writeErrors(w, http.StatusBadRequest, "role key type \"any\" not allowed for issuing certificates, only signing")
+		return
+	}
+	key, err := generateKey(r.KeyType, r.KeyBits)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: s.nextSerial(),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+		URIs:         uris,
+		NotBefore:    now.Add(-30 * time.Second),
+		NotAfter:     now.Add(duration),
+		KeyUsage:     keyUsage(r.KeyUsage),
+	}
+	if template.NotAfter, err = i.notAfter(template.NotAfter); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if r.ServerFlag {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	}
+	if r.ClientFlag {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	}
+
+	cert, certPEM, err := signCertificate(template, i.cert, key.Public(), e.keys[i.KeyID])
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	keyPEM, keyType, err := encodeKey(key)
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	serial := formatSerial(cert.SerialNumber)
+	e.certs[serial] = &issuedCertificate{cert: cert, pem: certPEM}
+
+	writeData(w, map[string]interface{}{
+		"certificate":      certPEM,
+		"issuing_ca":       i.Certificate,
+		"ca_chain":         i.CAChain,
+		"private_key":      keyPEM,
+		"private_key_type": keyType,
+		"serial_number":    serial,
+		"expiration":       cert.NotAfter.Unix(),
+	})
+}
+
+// allowsName reports whether the role allows certificates for name.
+func allowsName(r *vault.PKIRole, name string) bool {
+	if r.AllowAnyName {
+		return true
+	}
+	if r.AllowLocalhost && name == "localhost" {
+		return true
+	}
+	for _, domain := range r.AllowedDomains {
+		switch {
+		case r.AllowBareDomains && name == domain:
+			return true
+		case r.AllowSubdomains && strings.HasSuffix(name, "."+domain):
+			return true
+		case r.AllowGlobDomains && strings.Contains(domain, "*"):
+			if matched, _ := path.Match(domain, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyUsage converts key usage names, e.g. DigitalSignature, to their x509
+// flags.
+func keyUsage(names []string) x509.KeyUsage {
+	flags := map[string]x509.KeyUsage{
+		"DigitalSignature":  x509.KeyUsageDigitalSignature,
+		"ContentCommitment": x509.KeyUsageContentCommitment,
+		"KeyEncipherment":   x509.KeyUsageKeyEncipherment,
+		"DataEncipherment":  x509.KeyUsageDataEncipherment,
+		"KeyAgreement":      x509.KeyUsageKeyAgreement,
+		"CertSign":          x509.KeyUsageCertSign,
+		"CRLSign":           x509.KeyUsageCRLSign,
+		"EncipherOnly":      x509.KeyUsageEncipherOnly,
+		"DecipherOnly":      x509.KeyUsageDecipherOnly,
+	}
+	var usage x509.KeyUsage
+	for _, name := range names {
+		usage |= flags[name]
+	}
+	return usage
+}
+
+func generateKey(keyType string, bits int) (crypto.Signer, error) {
+	switch keyType {
+	case "rsa":
+		if bits == 0 {
+			bits = 2048
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	case "ec":
+		curve := elliptic.P256()
+		switch bits {
+		case 224:
+			curve = elliptic.P224()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unknown key type %s", keyType)
+	}
+}
+
+// encodeKey returns the PEM encoding of a private key in the default "der"
+// format of Vault, along with its type.
+func encodeKey(key crypto.Signer) (string, string, error) {
+	var block *pem.Block
+	var keyType string
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		block, keyType = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}, "rsa"
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return "", "", err
+		}
+		block, keyType = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}, "ec"
+	default:
+		der, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return "", "", err
+		}
+		block, keyType = &pem.Block{Type: "PRIVATE KEY", Bytes: der}, "ed25519"
+	}
+	return strings.TrimSpace(string(pem.EncodeToMemory(block))), keyType, nil
+}
+
+func signCertificate(template, parent *x509.Certificate, pub crypto.PublicKey, signer crypto.Signer) (*x509.Certificate, string, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	if err != nil {
+		return nil, "", err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, "", err
+	}
+	return cert, strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))), nil
+}
+
+func (s *Server) nextSerial() *big.Int {
+	s.counter++
+	return big.NewInt(int64(s.counter)<<32 | time.Now().UnixNano()&0xffffffff)
+}
+
+// formatSerial formats a serial number the way Vault does, as colon separated
+// hex bytes.
+func formatSerial(serial *big.Int) string {
+	b := serial.Bytes()
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02x", v)
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,213 @@
+package fake
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+var pluginVersionRe = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+([-+].*)?$`)
+
+// plugin is a plugin registered in the catalog.
+type plugin struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Version string   `json:"version"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Env     []string `json:"env"`
+	SHA256  string   `json:"sha256"`
+}
+
+// Plugin returns a plugin registered in the catalog, version being empty for
+// an unversioned plugin.
+func (s *Server) Plugin(typ, name, version string) (*plugin, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.plugins[typ+"/"+name+"/"+version]
+	if !ok {
+		return nil, false
+	}
+	out := *p
+	return &out, true
+}
+
+// pluginType returns the catalog type of the plugins run by auth methods or
+// secrets engines.
+func pluginType(auth bool) string {
+	if auth {
+		return "auth"
+	}
+	return "secret"
+}
+
+// checkPluginVersion fails when a mount is pinned to a version of a plugin
+// that is not registered.
+func (s *Server) checkPluginVersion(typ, name, version string) error {
+	if version == "" {
+		return nil
+	}
+	if _, ok := s.plugins[typ+"/"+name+"/"+version]; !ok {
+		return fmt.Errorf("plugin %q (version %q) not found in the catalog", name, version)
+	}
+	return nil
+}
+
+// runPlugin makes the mount run its configured version of the plugin, the way
+// Vault does when the mount is enabled or its plugin reloaded.
+func (s *Server) runPlugin(m *vaultapi.MountOutput, typ string) {
+	m.RunningVersion = m.PluginVersion
+	m.RunningSha256 = ""
+	if p, ok := s.plugins[typ+"/"+m.Type+"/"+m.PluginVersion]; ok {
+		m.RunningSha256 = p.SHA256
+	}
+}
+
+// pluginMounts returns the mounts configured with or running a version of a
+// plugin.
+func (s *Server) pluginMounts(typ, name, version string) []string {
+	mounts := s.mounts
+	prefix := ""
+	if typ == "auth" {
+		mounts = s.auths
+		prefix = "auth/"
+	}
+
+	var paths []string
+	for k, m := range mounts {
+		if m.Type == name && (m.PluginVersion == version || m.RunningVersion == version) {
+			paths = append(paths, prefix+k+"/")
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (s *Server) handlePlugins(w http.ResponseWriter, method, p, version string, body map[string]interface{}) {
+	switch {
+	case p == "catalog":
+		if method != http.MethodGet {
+			writeErrors(w, http.StatusMethodNotAllowed)
+			return
+		}
+		data := map[string]interface{}{}
+		var detailed []map[string]interface{}
+		for _, pl := range s.plugins {
+			names, _ := data[pl.Type].([]string)
+			data[pl.Type] = append(names, pl.Name)
+			detailed = append(detailed, map[string]interface{}{
+				"name":    pl.Name,
+				"type":    pl.Type,
+				"version": pl.Version,
+				"sha256":  pl.SHA256,
+				"builtin": false,
+			})
+		}
+		sort.Slice(detailed, func(i, j int) bool {
+			return fmt.Sprint(detailed[i]["type"], detailed[i]["name"], detailed[i]["version"]) <
+				fmt.Sprint(detailed[j]["type"], detailed[j]["name"], detailed[j]["version"])
+		})
+		data["detailed"] = detailed
+		writeData(w, data)
+	case strings.HasPrefix(p, "catalog/"):
+		s.handlePluginCatalog(w, method, strings.TrimPrefix(p, "catalog/"), version, body)
+	case p == "reload/backend":
+		if method != http.MethodPut && method != http.MethodPost {
+			writeErrors(w, http.StatusMethodNotAllowed)
+			return
+		}
+		name, _ := body["plugin"].(string)
+		if name == "" {
+			writeErrors(w, http.StatusBadRequest, "plugin or mounts must be provided")
+			return
+		}
+		for _, m := range s.auths {
+			if m.Type == name {
+				s.runPlugin(m, "auth")
+			}
+		}
+		for _, m := range s.mounts {
+			if m.Type == name {
+				s.runPlugin(m, "secret")
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handlePluginCatalog(w http.ResponseWriter, method, p, version string, body map[string]interface{}) {
+	typ, name, _ := strings.Cut(p, "/")
+	if typ != "auth" && typ != "secret" && typ != "database" {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown plugin type %q", typ))
+		return
+	}
+	if name == "" {
+		if method != "LIST" {
+			writeErrors(w, http.StatusMethodNotAllowed)
+			return
+		}
+		names := map[string]struct{}{}
+		for _, pl := range s.plugins {
+			if pl.Type == typ {
+				names[pl.Name] = struct{}{}
+			}
+		}
+		writeKeys(w, names)
+		return
+	}
+
+	switch method {
+	case http.MethodGet:
+		pl, ok := s.plugins[typ+"/"+name+"/"+version]
+		if !ok {
+			writeErrors(w, http.StatusNotFound, fmt.Sprintf("no plugin found with name %q", name))
+			return
+		}
+		writeData(w, map[string]interface{}{
+			"name":    pl.Name,
+			"command": pl.Command,
+			"args":    pl.Args,
+			"sha256":  pl.SHA256,
+			"version": pl.Version,
+			"builtin": false,
+		})
+	case http.MethodPut, http.MethodPost:
+		pl := plugin{Name: name, Type: typ}
+		if err := fromMap(body, &pl); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pl.Name, pl.Type = name, typ
+		if sum, err := hex.DecodeString(pl.SHA256); err != nil || len(sum) != 32 {
+			writeErrors(w, http.StatusBadRequest, "Could not decode SHA-256 value from Hex")
+			return
+		}
+		if pl.Command == "" || strings.HasPrefix(pl.Command, "/") || strings.Contains(pl.Command, "..") {
+			writeErrors(w, http.StatusBadRequest, "invalid command")
+			return
+		}
+		if pl.Version != "" && !pluginVersionRe.MatchString(pl.Version) {
+			writeErrors(w, http.StatusBadRequest, "version is not a valid semantic version")
+			return
+		}
+		s.plugins[typ+"/"+name+"/"+pl.Version] = &pl
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if mounts := s.pluginMounts(typ, name, version); len(mounts) > 0 {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("plugin %q (version %q) is in use by %s", name, version, strings.Join(mounts, ", ")))
+			return
+		}
+		delete(s.plugins, typ+"/"+name+"/"+version)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
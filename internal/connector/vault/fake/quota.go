@@ -0,0 +1,143 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// quota is a rate limit or lease count quota.
+type quota struct {
+	Name          string  `json:"name"`
+	Type          string  `json:"type"`
+	Path          string  `json:"path"`
+	Role          string  `json:"role"`
+	Rate          float64 `json:"rate,omitempty"`
+	Interval      int     `json:"interval,omitempty"`
+	BlockInterval int     `json:"block_interval,omitempty"`
+	MaxLeases     int     `json:"max_leases,omitempty"`
+}
+
+// Quota returns a quota of the given type, "rate-limit" or "lease-count".
+func (s *Server) Quota(typ, name string) (*quota, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.quotas[typ+"/"+name]
+	if !ok {
+		return nil, false
+	}
+	out := *q
+	return &out, true
+}
+
+// quotaPath returns the path of a quota the way Vault stores it, with a
+// trailing slash after the mount, and whether it is an auth mount.
+func (s *Server) quotaPath(p string) (string, bool, error) {
+	if p == "" {
+		return "", false, nil
+	}
+	if rest, ok := strings.CutPrefix(p, "auth/"); ok {
+		mount, sub, _ := strings.Cut(rest, "/")
+		if _, ok := s.auths[mount]; ok {
+			return "auth/" + mount + "/" + sub, sub == "", nil
+		}
+	} else {
+		mount, sub, _ := strings.Cut(p, "/")
+		if _, ok := s.mounts[mount]; ok {
+			return mount + "/" + sub, false, nil
+		}
+	}
+	return "", false, fmt.Errorf("invalid mount path %q", p)
+}
+
+func (s *Server) handleQuota(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	typ, name, _ := strings.Cut(p, "/")
+	if typ != "rate-limit" && typ != "lease-count" {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	if name == "" {
+		if method != "LIST" {
+			writeErrors(w, http.StatusMethodNotAllowed)
+			return
+		}
+		names := map[string]struct{}{}
+		for _, q := range s.quotas {
+			if q.Type == typ {
+				names[q.Name] = struct{}{}
+			}
+		}
+		writeKeys(w, names)
+		return
+	}
+
+	key := typ + "/" + name
+	switch method {
+	case http.MethodGet:
+		q, ok := s.quotas[key]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(q))
+	case http.MethodPut, http.MethodPost:
+		q := quota{Name: name, Type: typ, Interval: 1}
+		if current, ok := s.quotas[key]; ok {
+			q = *current
+		}
+		fields := roundTrip(body)
+		delete(fields, "interval")
+		delete(fields, "block_interval")
+		if err := fromMap(fields, &q); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for field, ttl := range map[string]*int{"interval": &q.Interval, "block_interval": &q.BlockInterval} {
+			if v, ok := body[field]; ok {
+				var err error
+				if *ttl, err = vault.ParseTTL(fmt.Sprint(v)); err != nil {
+					writeErrors(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %s", field, err))
+					return
+				}
+			}
+		}
+
+		path, isAuthMount, err := s.quotaPath(q.Path)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		q.Path = path
+		switch {
+		case q.Role != "" && !isAuthMount:
+			writeErrors(w, http.StatusBadRequest, "role can only be set on quotas of auth mounts")
+			return
+		case typ == "rate-limit" && q.Rate <= 0:
+			writeErrors(w, http.StatusBadRequest, "'rate' is invalid")
+			return
+		case typ == "rate-limit" && q.Interval <= 0:
+			writeErrors(w, http.StatusBadRequest, "'interval' is invalid")
+			return
+		case typ == "lease-count" && q.MaxLeases <= 0:
+			writeErrors(w, http.StatusBadRequest, "'max_leases' is invalid")
+			return
+		}
+		for k, other := range s.quotas {
+			if k != key && other.Type == typ && other.Path == q.Path && other.Role == q.Role {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("quota %q already exists for the same path and role", other.Name))
+				return
+			}
+		}
+
+		s.quotas[key] = &q
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.quotas, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,891 @@
+// Package fake provides an in-memory stand-in for the subset of the Vault HTTP
+// API the operator talks to, so that reconcilers can be exercised offline.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// Fault makes the server answer matching requests with an error status.
+type Fault struct {
+	// Method restricts the fault to one HTTP method ("LIST" for list
+	// requests). Empty matches every method.
+	Method string
+	// Path is matched as a prefix of the request path, without "/v1/".
+	Path string
+	// Status is the HTTP status code returned, defaults to 500.
+	Status int
+	// Times is the number of requests to fail, 0 fails until cleared.
+	Times int
+}
+
+// Request records a call received by the server.
+type Request struct {
+	Method string
+	Path   string
+}
+
+// Server is an in-memory Vault server. It supports ACL policies under
+// sys/policies/acl, password policies under sys/policies/password, rate limit
+// and lease count quotas under sys/quotas, the plugin catalog and plugin
+// reloads under sys/plugins, namespaces under sys/namespaces, with the mounts
+// enabled in them, auth methods under sys/auth, secrets engines under
+// sys/mounts, audit devices under sys/audit, lease renewal and revocation under
+// sys/leases, token creation, lookup and revocation under auth/token, entities, groups,
+// their aliases and the OIDC provider under identity, KV v2 secrets in kv
+// mounts with version 2, connections, roles and credentials in database mounts,
+// issuers, roles and certificates in pki mounts, keys in transit mounts, the CA
+// and roles in ssh mounts, and a generic key/value store for every other
+// logical path (auth/<mount>/role/<name>, KV v1 secrets, ...).
+type Server struct {
+	*httptest.Server
+
+	mu               sync.Mutex
+	counter          int
+	policies         map[string]string
+	passwordPolicies map[string]string
+	quotas           map[string]*quota
+	plugins          map[string]*plugin
+	namespaces       map[string]*namespace
+	auths            map[string]*vaultapi.MountOutput
+	mounts           map[string]*vaultapi.MountOutput
+	audits           map[string]*vaultapi.Audit
+	kv               map[string]*kvSecret
+	databases        map[string]*database
+	pkis             map[string]*pki
+	transits         map[string]map[string]*transitKey
+	sshs             map[string]*sshEngine
+	leases           map[string]*lease
+	tokens           map[string]*vaultapi.SecretAuth
+	entities         map[string]*vault.IdentityEntity
+	entityAliases    map[string]*vault.IdentityEntityAlias
+	groups           map[string]*vault.IdentityGroup
+	groupAliases     map[string]*vault.IdentityGroupAlias
+	oidc             *oidcStore
+	data             map[string]map[string]interface{}
+	faults           []*Fault
+	requests         []Request
+}
+
+// NewServer starts a new fake Vault server. Callers should Close it when done.
+func NewServer() *Server {
+	s := &Server{}
+	s.reset()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a Vault client connected to the server.
+func (s *Server) Client() *vault.Vault {
+	config := vaultapi.DefaultConfig()
+	config.Address = s.URL
+	// Retries would hide injected faults.
+	config.MaxRetries = 0
+
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		panic(fmt.Sprintf("unable to initialize fake vault client: %v", err))
+	}
+	client.SetToken("root")
+
+	return vault.NewVaultClient(client)
+}
+
+// Reset drops all stored state, faults and recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reset()
+}
+
+func (s *Server) reset() {
+	s.policies = map[string]string{}
+	s.passwordPolicies = map[string]string{}
+	s.quotas = map[string]*quota{}
+	s.plugins = map[string]*plugin{}
+	s.namespaces = map[string]*namespace{}
+	s.auths = map[string]*vaultapi.MountOutput{}
+	s.mounts = map[string]*vaultapi.MountOutput{}
+	s.audits = map[string]*vaultapi.Audit{}
+	s.kv = map[string]*kvSecret{}
+	s.databases = map[string]*database{}
+	s.pkis = map[string]*pki{}
+	s.transits = map[string]map[string]*transitKey{}
+	s.sshs = map[string]*sshEngine{}
+	s.leases = map[string]*lease{}
+	s.tokens = map[string]*vaultapi.SecretAuth{}
+	s.entities = map[string]*vault.IdentityEntity{}
+	s.entityAliases = map[string]*vault.IdentityEntityAlias{}
+	s.groups = map[string]*vault.IdentityGroup{}
+	s.groupAliases = map[string]*vault.IdentityGroupAlias{}
+	s.oidc = newOIDCStore()
+	s.data = map[string]map[string]interface{}{}
+	s.faults = nil
+	s.requests = nil
+}
+
+// InjectFault registers a fault for subsequent requests.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Status == 0 {
+		f.Status = http.StatusInternalServerError
+	}
+	f.Path = normalize(f.Path)
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every registered fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Policy returns the rules of an ACL policy.
+func (s *Server) Policy(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules, ok := s.policies[name]
+	return rules, ok
+}
+
+// SetPolicy writes an ACL policy behind the operator's back.
+func (s *Server) SetPolicy(name, rules string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policies[name] = rules
+}
+
+// AuthMount returns the auth method enabled at path.
+func (s *Server) AuthMount(p string) (*vaultapi.MountOutput, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.auths[normalize(p)]
+	if !ok {
+		return nil, false
+	}
+	c := *m
+	return &c, true
+}
+
+// Mount returns the secrets engine mounted at path.
+func (s *Server) Mount(p string) (*vaultapi.MountOutput, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.mounts[normalize(p)]
+	if !ok {
+		return nil, false
+	}
+	c := *m
+	return &c, true
+}
+
+// AuditDevice returns the audit device enabled at path.
+func (s *Server) AuditDevice(p string) (*vaultapi.Audit, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.audits[normalize(p)]
+	if !ok {
+		return nil, false
+	}
+	c := *a
+	return &c, true
+}
+
+// Lease returns the expiration time of a lease. Revoked and expired leases are
+// reported missing.
+func (s *Server) Lease(id string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := s.liveLease(id)
+	if l == nil {
+		return time.Time{}, false
+	}
+	return l.expireTime, true
+}
+
+// Token returns the token issued with the given accessor.
+func (s *Server) Token(accessor string) (*vaultapi.SecretAuth, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[accessor]
+	if !ok {
+		return nil, false
+	}
+	c := *t
+	return &c, true
+}
+
+// Data returns the data stored at a logical path.
+func (s *Server) Data(p string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.data[normalize(p)]
+	if !ok {
+		return nil, false
+	}
+	return roundTrip(d), true
+}
+
+// SetData writes data at a logical path behind the operator's back.
+func (s *Server) SetData(p string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[normalize(p)] = roundTrip(data)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := normalize(strings.TrimPrefix(r.URL.Path, "/v1/"))
+	method := r.Method
+	if method == http.MethodGet && r.URL.Query().Get("list") == "true" {
+		method = "LIST"
+	}
+	s.requests = append(s.requests, Request{Method: method, Path: p})
+
+	if f := s.matchFault(method, p); f != nil {
+		writeErrors(w, f.Status, fmt.Sprintf("injected fault for %s %s", method, p))
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Body != nil && (method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err.Error() != "EOF" {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("failed to parse JSON input: %v", err))
+			return
+		}
+	}
+
+	if ns, rest := s.splitNamespace(r.Header.Get("X-Vault-Namespace"), p); ns != nil {
+		s.handleNamespaced(w, method, ns, rest, body)
+		return
+	}
+
+	switch {
+	case p == "sys/namespaces" || strings.HasPrefix(p, "sys/namespaces/"):
+		s.handleNamespaces(w, method, "", strings.TrimPrefix(strings.TrimPrefix(p, "sys/namespaces"), "/"), body)
+	case p == "sys/policies/acl" || strings.HasPrefix(p, "sys/policies/acl/"):
+		s.handlePolicy(w, method, strings.TrimPrefix(strings.TrimPrefix(p, "sys/policies/acl"), "/"), body)
+	case p == "sys/policies/password" || strings.HasPrefix(p, "sys/policies/password/"):
+		s.handlePasswordPolicy(w, method, strings.TrimPrefix(strings.TrimPrefix(p, "sys/policies/password"), "/"), body)
+	case strings.HasPrefix(p, "sys/quotas/"):
+		s.handleQuota(w, method, strings.TrimPrefix(p, "sys/quotas/"), body)
+	case strings.HasPrefix(p, "sys/plugins/"):
+		s.handlePlugins(w, method, strings.TrimPrefix(p, "sys/plugins/"), r.URL.Query().Get("version"), body)
+	case strings.HasPrefix(p, "sys/mounts/auth/"):
+		s.handleGetAuth(w, method, strings.TrimPrefix(p, "sys/mounts/auth/"))
+	case p == "sys/mounts" || strings.HasPrefix(p, "sys/mounts/"):
+		s.handleMounts(w, method, strings.TrimPrefix(strings.TrimPrefix(p, "sys/mounts"), "/"), body)
+	case p == "sys/auth" || strings.HasPrefix(p, "sys/auth/"):
+		s.handleAuth(w, method, strings.TrimPrefix(strings.TrimPrefix(p, "sys/auth"), "/"), body)
+	case p == "sys/audit" || strings.HasPrefix(p, "sys/audit/"):
+		s.handleAudit(w, method, strings.TrimPrefix(strings.TrimPrefix(p, "sys/audit"), "/"), body)
+	case strings.HasPrefix(p, "sys/leases/"):
+		s.handleLeases(w, method, strings.TrimPrefix(p, "sys/leases/"), body)
+	case strings.HasPrefix(p, "auth/token/"):
+		s.handleToken(w, method, strings.TrimPrefix(p, "auth/token/"), body)
+	case strings.HasPrefix(p, "identity/"):
+		s.handleIdentity(w, method, strings.TrimPrefix(p, "identity/"), body)
+	case s.kvMount(p) != "":
+		mount := s.kvMount(p)
+		s.handleKV(w, method, mount, strings.TrimPrefix(p, mount+"/"), r.URL.Query().Get("version"), body)
+	case s.databaseMount(p) != "":
+		mount := s.databaseMount(p)
+		s.handleDatabase(w, method, mount, strings.TrimPrefix(p, mount+"/"), body)
+	case s.pkiMount(p) != "":
+		mount := s.pkiMount(p)
+		s.handlePKI(w, method, mount, strings.TrimPrefix(p, mount+"/"), body)
+	case s.transitMount(p) != "":
+		mount := s.transitMount(p)
+		s.handleTransit(w, method, mount, strings.TrimPrefix(p, mount+"/"), body)
+	case s.sshMount(p) != "":
+		mount := s.sshMount(p)
+		s.handleSSH(w, method, mount, strings.TrimPrefix(p, mount+"/"), body)
+	default:
+		s.handleLogical(w, method, p, body)
+	}
+}
+
+func (s *Server) matchFault(method, p string) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if p != f.Path && !strings.HasPrefix(p, f.Path+"/") && f.Path != "" {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) handlePolicy(w http.ResponseWriter, method, name string, body map[string]interface{}) {
+	switch method {
+	case "LIST":
+		writeKeys(w, s.policies)
+	case http.MethodGet:
+		rules, ok := s.policies[name]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, map[string]interface{}{"name": name, "policy": rules})
+	case http.MethodPut, http.MethodPost:
+		rules, _ := body["policy"].(string)
+		s.policies[name] = rules
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.policies, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleGetAuth(w http.ResponseWriter, method, p string) {
+	if method != http.MethodGet {
+		writeErrors(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	m, ok := s.auths[p]
+	if !ok {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("No auth engine at auth/%s/", p))
+		return
+	}
+	writeData(w, toMap(m))
+}
+
+func (s *Server) handleAuth(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		mounts := map[string]interface{}{}
+		for k, m := range s.auths {
+			mounts[k+"/"] = toMap(m)
+		}
+		writeData(w, mounts)
+	case http.MethodPut, http.MethodPost:
+		if _, ok := s.auths[p]; ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("path is already in use at %s/", p))
+			return
+		}
+
+		var in vaultapi.MountInput
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := s.checkPluginVersion("auth", in.Type, in.Config.PluginVersion); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.counter++
+		m := &vaultapi.MountOutput{
+			UUID:                  fmt.Sprintf("%08x-0000-0000-0000-000000000000", s.counter),
+			Type:                  in.Type,
+			Description:           in.Description,
+			Accessor:              fmt.Sprintf("auth_%s_%08x", in.Type, s.counter),
+			Options:               in.Options,
+			Local:                 in.Local,
+			SealWrap:              in.SealWrap,
+			ExternalEntropyAccess: in.ExternalEntropyAccess,
+			PluginVersion:         in.Config.PluginVersion,
+		}
+		s.runPlugin(m, "auth")
+		s.auths[p] = m
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.auths, p)
+		for k := range s.data {
+			if strings.HasPrefix(k, "auth/"+p+"/") {
+				delete(s.data, k)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleMounts(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	if mount, ok := strings.CutSuffix(p, "/tune"); ok {
+		s.handleTune(w, method, mount, body)
+		return
+	}
+
+	switch method {
+	case http.MethodGet:
+		if p == "" {
+			mounts := map[string]interface{}{}
+			for k, m := range s.mounts {
+				mounts[k+"/"] = toMap(m)
+			}
+			writeData(w, mounts)
+			return
+		}
+
+		m, ok := s.mounts[p]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("No secret engine mount at %s/", p))
+			return
+		}
+		writeData(w, toMap(m))
+	case http.MethodPut, http.MethodPost:
+		if _, ok := s.mounts[p]; ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("path is already in use at %s/", p))
+			return
+		}
+
+		var in vaultapi.MountInput
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.counter++
+		m := &vaultapi.MountOutput{
+			UUID:                  fmt.Sprintf("%08x-0000-0000-0000-000000000000", s.counter),
+			Type:                  in.Type,
+			Description:           in.Description,
+			Accessor:              fmt.Sprintf("%s_%08x", in.Type, s.counter),
+			Options:               in.Options,
+			Local:                 in.Local,
+			SealWrap:              in.SealWrap,
+			ExternalEntropyAccess: in.ExternalEntropyAccess,
+		}
+		if err := s.checkPluginVersion("secret", in.Type, in.Config.PluginVersion); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := tune(m, in.Config); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.runPlugin(m, "secret")
+		s.mounts[p] = m
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.mounts, p)
+		for k := range s.data {
+			if strings.HasPrefix(k, p+"/") {
+				delete(s.data, k)
+			}
+		}
+		for k := range s.kv {
+			if strings.HasPrefix(k, p+"/") {
+				delete(s.kv, k)
+			}
+		}
+		delete(s.databases, p)
+		delete(s.pkis, p)
+		delete(s.transits, p)
+		delete(s.sshs, p)
+		for k := range s.leases {
+			if strings.HasPrefix(k, p+"/") {
+				delete(s.leases, k)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleTune(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	m, ok := s.mounts[p]
+	if !ok {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("cannot tune '%s/': no mount entry found", p))
+		return
+	}
+
+	switch method {
+	case http.MethodGet:
+		writeData(w, toMap(m.Config))
+	case http.MethodPut, http.MethodPost:
+		var in vaultapi.MountConfigInput
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// The new version only runs once the plugin is reloaded
+		if err := s.checkPluginVersion("secret", m.Type, in.PluginVersion); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := tune(m, in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// tune applies the settings present in config to the mount, the way
+// sys/mounts/<path>/tune leaves unset settings untouched.
+func tune(m *vaultapi.MountOutput, config vaultapi.MountConfigInput) error {
+	if config.DefaultLeaseTTL != "" {
+		ttl, err := vault.ParseTTL(config.DefaultLeaseTTL)
+		if err != nil {
+			return err
+		}
+		m.Config.DefaultLeaseTTL = ttl
+	}
+	if config.MaxLeaseTTL != "" {
+		ttl, err := vault.ParseTTL(config.MaxLeaseTTL)
+		if err != nil {
+			return err
+		}
+		m.Config.MaxLeaseTTL = ttl
+	}
+	if config.Description != nil {
+		m.Description = *config.Description
+	}
+	if config.Options != nil && m.Options == nil {
+		m.Options = map[string]string{}
+	}
+	for k, v := range config.Options {
+		m.Options[k] = v
+	}
+	if config.AuditNonHMACRequestKeys != nil {
+		m.Config.AuditNonHMACRequestKeys = config.AuditNonHMACRequestKeys
+	}
+	if config.AuditNonHMACResponseKeys != nil {
+		m.Config.AuditNonHMACResponseKeys = config.AuditNonHMACResponseKeys
+	}
+	if config.ListingVisibility != "" {
+		m.Config.ListingVisibility = config.ListingVisibility
+	}
+	if config.PassthroughRequestHeaders != nil {
+		m.Config.PassthroughRequestHeaders = config.PassthroughRequestHeaders
+	}
+	if config.AllowedResponseHeaders != nil {
+		m.Config.AllowedResponseHeaders = config.AllowedResponseHeaders
+	}
+	if config.PluginVersion != "" {
+		m.PluginVersion = config.PluginVersion
+	}
+	if config.IdentityTokenKey != "" {
+		m.Config.IdentityTokenKey = config.IdentityTokenKey
+	}
+	return nil
+}
+
+func (s *Server) handleAudit(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	switch method {
+	case http.MethodGet:
+		devices := map[string]interface{}{}
+		for k, a := range s.audits {
+			devices[k+"/"] = toMap(a)
+		}
+		writeData(w, devices)
+	case http.MethodPut, http.MethodPost:
+		if _, ok := s.audits[p]; ok {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("path already in use at %s/", p))
+			return
+		}
+
+		var in vaultapi.EnableAuditOptions
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.audits[p] = &vaultapi.Audit{
+			Type:        in.Type,
+			Description: in.Description,
+			Options:     in.Options,
+			Local:       in.Local,
+			Path:        p + "/",
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.audits, p)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// lease is a lease on a secret issued by a secrets engine.
+type lease struct {
+	ttl           int
+	renewable     bool
+	issueTime     time.Time
+	expireTime    time.Time
+	maxExpireTime time.Time
+}
+
+func (s *Server) issueLease(id string, ttl, maxTTL time.Duration) *lease {
+	now := time.Now()
+	l := &lease{
+		ttl:           int(ttl.Seconds()),
+		renewable:     true,
+		issueTime:     now,
+		expireTime:    now.Add(ttl),
+		maxExpireTime: now.Add(maxTTL),
+	}
+	s.leases[id] = l
+	return l
+}
+
+func (s *Server) liveLease(id string) *lease {
+	l, ok := s.leases[id]
+	if !ok || !time.Now().Before(l.expireTime) {
+		return nil
+	}
+	return l
+}
+
+func (s *Server) handleLeases(w http.ResponseWriter, method, op string, body map[string]interface{}) {
+	if method != http.MethodPost && method != http.MethodPut {
+		writeErrors(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, _ := body["lease_id"].(string)
+	switch op {
+	case "renew":
+		l := s.liveLease(id)
+		if l == nil {
+			writeErrors(w, http.StatusBadRequest, "lease not found")
+			return
+		}
+		if !l.renewable {
+			writeErrors(w, http.StatusBadRequest, "lease is not renewable")
+			return
+		}
+
+		// Renewals cannot extend the lease past its max TTL
+		now := time.Now()
+		increment, _ := body["increment"].(float64)
+		duration := time.Duration(increment) * time.Second
+		if duration == 0 {
+			duration = time.Duration(l.ttl) * time.Second
+		}
+		if remaining := l.maxExpireTime.Sub(now).Truncate(time.Second); duration > remaining {
+			duration = remaining
+		}
+		l.expireTime = now.Add(duration)
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"lease_id":       id,
+			"renewable":      l.renewable,
+			"lease_duration": int(duration.Seconds()),
+		})
+	case "lookup":
+		l := s.liveLease(id)
+		if l == nil {
+			writeErrors(w, http.StatusBadRequest, "invalid lease")
+			return
+		}
+		writeData(w, map[string]interface{}{
+			"id":          id,
+			"issue_time":  l.issueTime.Format(time.RFC3339Nano),
+			"expire_time": l.expireTime.Format(time.RFC3339Nano),
+			"renewable":   l.renewable,
+			"ttl":         int(time.Until(l.expireTime).Seconds()),
+		})
+	case "revoke":
+		delete(s.leases, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, method, op string, body map[string]interface{}) {
+	if method != http.MethodPost && method != http.MethodPut {
+		writeErrors(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch op {
+	case "create", "create-orphan":
+		var in vaultapi.TokenCreateRequest
+		if err := fromMap(body, &in); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.counter++
+		policies := in.Policies
+		if !in.NoDefaultPolicy {
+			policies = append([]string{"default"}, policies...)
+		}
+		auth := &vaultapi.SecretAuth{
+			ClientToken:   fmt.Sprintf("hvs.fake%08x", s.counter),
+			Accessor:      fmt.Sprintf("accessor%08x", s.counter),
+			Policies:      policies,
+			TokenPolicies: policies,
+			Metadata:      in.Metadata,
+			Orphan:        in.NoParent || op == "create-orphan",
+			Renewable:     in.Renewable == nil || *in.Renewable,
+		}
+		// Only TTLs in Go duration syntax are supported
+		if ttl, err := time.ParseDuration(in.TTL); err == nil {
+			auth.LeaseDuration = int(ttl.Seconds())
+		}
+		if in.ID != "" {
+			auth.ClientToken = in.ID
+		}
+		s.tokens[auth.Accessor] = auth
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"auth": toMap(auth)})
+	case "lookup-accessor":
+		accessor, _ := body["accessor"].(string)
+		t, ok := s.tokens[accessor]
+		if !ok {
+			writeErrors(w, http.StatusBadRequest, "invalid accessor")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"accessor": t.Accessor,
+			"policies": t.Policies,
+			"meta":     t.Metadata,
+			"orphan":   t.Orphan,
+		}})
+	case "revoke-accessor":
+		accessor, _ := body["accessor"].(string)
+		if _, ok := s.tokens[accessor]; !ok {
+			writeErrors(w, http.StatusBadRequest, "invalid accessor")
+			return
+		}
+		delete(s.tokens, accessor)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleLogical(w http.ResponseWriter, method, p string, body map[string]interface{}) {
+	switch method {
+	case "LIST":
+		keys := map[string]struct{}{}
+		for k := range s.data {
+			if !strings.HasPrefix(k, p+"/") {
+				continue
+			}
+			rest := strings.TrimPrefix(k, p+"/")
+			if i := strings.Index(rest, "/"); i >= 0 {
+				rest = rest[:i+1]
+			}
+			keys[rest] = struct{}{}
+		}
+		if len(keys) == 0 {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeKeys(w, keys)
+	case http.MethodGet:
+		d, ok := s.data[p]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, d)
+	case http.MethodPut, http.MethodPost:
+		if body == nil {
+			body = map[string]interface{}{}
+		}
+		s.data[p] = body
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(s.data, p)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func normalize(p string) string {
+	return strings.Trim(path.Clean("/"+p), "/")
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeData(w http.ResponseWriter, data map[string]interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+func writeErrors(w http.ResponseWriter, status int, errs ...string) {
+	if errs == nil {
+		errs = []string{}
+	}
+	writeJSON(w, status, map[string]interface{}{"errors": errs})
+}
+
+func writeKeys[V any](w http.ResponseWriter, m map[string]V) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	writeData(w, map[string]interface{}{"keys": keys})
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	var m map[string]interface{}
+	_ = fromMap(v, &m)
+	return m
+}
+
+func fromMap(in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func roundTrip(in map[string]interface{}) map[string]interface{} {
+	return toMap(in)
+}
@@ -0,0 +1,656 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+var _ = Describe("Fake Vault Server", func() {
+	var (
+		server *Server
+		client *vault.Vault
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		server = NewServer()
+		client = server.Client()
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should store ACL policies", func() {
+		rules, err := client.GetPolicy(ctx, "missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules).To(BeEmpty())
+
+		Expect(client.PutPolicy(ctx, "app", `path "*" {}`)).To(Succeed())
+		rules, err = client.GetPolicy(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules).To(Equal(`path "*" {}`))
+
+		Expect(client.DeletePolicy(ctx, "app")).To(Succeed())
+		_, ok := server.Policy("app")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should validate password policies and generate passwords", func() {
+		_, err := client.Write(ctx, "sys/policies/password/short", map[string]interface{}{"policy": "length = 4\nrule \"charset\" {\n  charset = \"ab\"\n  min-chars = 5\n}\n"})
+		Expect(err).To(HaveOccurred())
+
+		_, err = client.Write(ctx, "sys/policies/password/digits", map[string]interface{}{"policy": "length = 12\nrule \"charset\" {\n  charset = \"0123456789\"\n}\n"})
+		Expect(err).NotTo(HaveOccurred())
+		s, err := client.Read(ctx, "sys/policies/password/digits/generate")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["password"]).To(MatchRegexp(`^[0-9]{12}$`))
+
+		_, err = client.Delete(ctx, "sys/policies/password/digits")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok := server.PasswordPolicy("digits")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should enable and disable auth methods", func() {
+		Expect(client.EnableAuth(ctx, "kubernetes/", &vaultapi.EnableAuthOptions{Type: "kubernetes"})).To(Succeed())
+		Expect(client.EnableAuth(ctx, "kubernetes/", &vaultapi.EnableAuthOptions{Type: "kubernetes"})).NotTo(Succeed())
+
+		mount, err := client.GetAuth(ctx, "kubernetes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mount.Type).To(Equal("kubernetes"))
+		Expect(mount.Accessor).NotTo(BeEmpty())
+
+		_, err = client.Write(ctx, "auth/kubernetes/role/app", map[string]interface{}{"token_ttl": 60})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(client.DisableAuth(ctx, "kubernetes/")).To(Succeed())
+		_, err = client.GetAuth(ctx, "kubernetes")
+		Expect(err).To(HaveOccurred())
+		_, ok := server.Data("auth/kubernetes/role/app")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should enable and disable audit devices", func() {
+		options := &vaultapi.EnableAuditOptions{Type: "file", Options: map[string]string{"file_path": "stdout"}}
+		Expect(client.EnableAudit(ctx, "file", options)).To(Succeed())
+		Expect(client.EnableAudit(ctx, "file", options)).NotTo(Succeed())
+
+		devices, err := client.ListAudit(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(devices).To(HaveKey("file/"))
+		Expect(devices["file/"].Type).To(Equal("file"))
+		Expect(devices["file/"].Options).To(HaveKeyWithValue("file_path", "stdout"))
+
+		Expect(client.DisableAudit(ctx, "file")).To(Succeed())
+		_, ok := server.AuditDevice("file")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should issue, look up and revoke tokens", func() {
+		secret, err := client.CreateToken(ctx, &vaultapi.TokenCreateRequest{Policies: []string{"app"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Auth.ClientToken).NotTo(BeEmpty())
+		Expect(secret.Auth.Policies).To(ConsistOf("default", "app"))
+
+		_, ok := server.Token(secret.Auth.Accessor)
+		Expect(ok).To(BeTrue())
+
+		lookup, err := client.LookupAccessor(ctx, secret.Auth.Accessor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookup.Data).To(HaveKeyWithValue("accessor", secret.Auth.Accessor))
+
+		Expect(client.RevokeAccessor(ctx, secret.Auth.Accessor)).To(Succeed())
+		Expect(client.RevokeAccessor(ctx, secret.Auth.Accessor)).NotTo(Succeed())
+		_, err = client.LookupAccessor(ctx, secret.Auth.Accessor)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read, write, list and delete logical paths", func() {
+		secret, err := client.Read(ctx, "/auth/kubernetes/role/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret).To(BeNil())
+
+		_, err = client.Write(ctx, "/auth/kubernetes/role/app", map[string]interface{}{"token_policies": []string{"app"}})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "auth/kubernetes/role/other", map[string]interface{}{})
+		Expect(err).NotTo(HaveOccurred())
+
+		secret, err = client.Read(ctx, "auth/kubernetes/role/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("token_policies", ConsistOf("app")))
+
+		secret, err = client.Client.Logical().ListWithContext(ctx, "auth/kubernetes/role")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data).To(HaveKeyWithValue("keys", ConsistOf("app", "other")))
+
+		_, err = client.Delete(ctx, "auth/kubernetes/role/app")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok := server.Data("auth/kubernetes/role/app")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should version secrets in KV v2 mounts", func() {
+		Expect(client.Mount(ctx, "secret", &vaultapi.MountInput{Type: "kv", Options: map[string]string{"version": "2"}})).To(Succeed())
+
+		_, err := client.Write(ctx, "secret/metadata/app", map[string]interface{}{"cas_required": true, "max_versions": 2})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "secret/data/app", map[string]interface{}{"data": map[string]interface{}{"user": "a"}})
+		Expect(err).To(HaveOccurred())
+
+		for i, user := range []string{"a", "b", "c"} {
+			secret, err := client.Write(ctx, "secret/data/app", map[string]interface{}{
+				"data":    map[string]interface{}{"user": user},
+				"options": map[string]interface{}{"cas": i},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Data).To(HaveKeyWithValue("version", json.Number(strconv.Itoa(i+1))))
+		}
+
+		secret, err := client.Read(ctx, "secret/data/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data["data"]).To(HaveKeyWithValue("user", "c"))
+
+		metadata, ok := server.KVMetadata("secret", "app")
+		Expect(ok).To(BeTrue())
+		Expect(metadata.CurrentVersion).To(Equal(3))
+		Expect(metadata.OldestVersion).To(Equal(2))
+
+		_, err = client.Delete(ctx, "secret/data/app")
+		Expect(err).NotTo(HaveOccurred())
+		_, _, ok = server.KVData("secret", "app")
+		Expect(ok).To(BeFalse())
+
+		_, err = client.Delete(ctx, "secret/metadata/app")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok = server.KVMetadata("secret", "app")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should issue, renew and revoke database credentials", func() {
+		Expect(client.Mount(ctx, "database", &vaultapi.MountInput{Type: "database"})).To(Succeed())
+
+		_, err := client.Write(ctx, "database/config/app", map[string]interface{}{
+			"plugin_name":   "postgresql-database-plugin",
+			"username":      "root",
+			"password":      "secret",
+			"allowed_roles": "app",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		secret, err := client.Read(ctx, "database/config/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data["connection_details"]).To(HaveKeyWithValue("username", "root"))
+		Expect(secret.Data["connection_details"]).NotTo(HaveKey("password"))
+
+		_, err = client.Read(ctx, "database/creds/app")
+		Expect(err).To(HaveOccurred())
+
+		_, err = client.Write(ctx, "database/roles/app", map[string]interface{}{
+			"db_name":             "app",
+			"creation_statements": []string{"CREATE ROLE"},
+			"default_ttl":         "1h",
+			"max_ttl":             "90m",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		creds, err := client.Read(ctx, "database/creds/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.LeaseDuration).To(Equal(3600))
+		Expect(creds.Data).To(HaveKey("username"))
+		_, ok := server.Lease(creds.LeaseID)
+		Expect(ok).To(BeTrue())
+
+		By("capping renewals at the max TTL")
+		renewed, err := client.RenewLease(ctx, creds.LeaseID, 7200)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renewed.LeaseDuration).To(BeNumerically("~", 5400, 1))
+
+		Expect(client.RevokeLease(ctx, creds.LeaseID)).To(Succeed())
+		_, ok = server.Lease(creds.LeaseID)
+		Expect(ok).To(BeFalse())
+		_, err = client.RenewLease(ctx, creds.LeaseID, 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should issue and revoke PKI certificates", func() {
+		Expect(client.Mount(ctx, "pki", &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+		_, err := client.Write(ctx, "pki/roles/svc", map[string]interface{}{
+			"allowed_domains":  []string{"svc.cluster.local"},
+			"allow_subdomains": true,
+			"key_type":         "ec",
+			"ttl":              "1h",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("requiring an issuer")
+		_, err = client.Write(ctx, "pki/issue/svc", map[string]interface{}{"common_name": "app.svc.cluster.local"})
+		Expect(err).To(HaveOccurred())
+
+		_, err = client.Write(ctx, "pki/root/generate/internal", map[string]interface{}{"common_name": "Root CA"})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("enforcing the allowed domains")
+		_, err = client.Write(ctx, "pki/issue/svc", map[string]interface{}{"common_name": "app.example.com"})
+		Expect(err).To(HaveOccurred())
+
+		s, err := client.Write(ctx, "pki/issue/svc", map[string]interface{}{
+			"common_name": "app.svc.cluster.local",
+			"alt_names":   "app.default.svc.cluster.local",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKey("private_key"))
+		serial := s.Data["serial_number"].(string)
+		cert, revoked, ok := server.PKICertificate("pki", serial)
+		Expect(ok).To(BeTrue())
+		Expect(revoked).To(BeFalse())
+		Expect(cert.DNSNames).To(ConsistOf("app.svc.cluster.local", "app.default.svc.cluster.local"))
+		Expect(cert.NotAfter.Sub(cert.NotBefore)).To(BeNumerically("~", time.Hour+30*time.Second, time.Second))
+
+		_, err = client.Write(ctx, "pki/revoke", map[string]interface{}{"serial_number": serial})
+		Expect(err).NotTo(HaveOccurred())
+		_, revoked, _ = server.PKICertificate("pki", serial)
+		Expect(revoked).To(BeTrue())
+	})
+
+	It("should sign and import PKI intermediates", func() {
+		Expect(client.Mount(ctx, "pki", &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+		Expect(client.Mount(ctx, "pki-int", &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+		s, err := client.Write(ctx, "pki/root/generate/internal", map[string]interface{}{"common_name": "Root CA", "issuer_name": "root"})
+		Expect(err).NotTo(HaveOccurred())
+		rootID := s.Data["issuer_id"].(string)
+
+		s, err = client.Write(ctx, "pki-int/intermediate/generate/internal", map[string]interface{}{"common_name": "Intermediate CA"})
+		Expect(err).NotTo(HaveOccurred())
+		keyID := s.Data["key_id"].(string)
+
+		By("generating the CSR again for the same key")
+		s, err = client.Write(ctx, "pki-int/intermediate/generate/existing", map[string]interface{}{"common_name": "Intermediate CA", "key_ref": keyID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKeyWithValue("key_id", keyID))
+
+		s, err = client.Write(ctx, "pki/issuer/root/sign-intermediate", map[string]interface{}{"csr": s.Data["csr"], "common_name": "Intermediate CA"})
+		Expect(err).NotTo(HaveOccurred())
+		chain := s.Data["ca_chain"].([]interface{})
+		Expect(chain).To(HaveLen(1))
+		bundle := s.Data["certificate"].(string) + "\n" + chain[0].(string)
+
+		s, err = client.Write(ctx, "pki-int/intermediate/set-signed", map[string]interface{}{"certificate": bundle})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["mapping"]).To(HaveLen(1))
+		Expect(s.Data["mapping"]).To(ContainElement(keyID))
+
+		By("listing the imported issuers")
+		s, err = client.List(ctx, "pki-int/issuers")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["keys"]).To(HaveLen(1))
+
+		By("refusing to delete a key in use")
+		_, err = client.Delete(ctx, "pki-int/key/"+keyID)
+		Expect(err).To(HaveOccurred())
+
+		s, err = client.Read(ctx, "pki/issuer/"+rootID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKeyWithValue("issuer_name", "root"))
+	})
+
+	It("should create, rotate and delete transit keys", func() {
+		Expect(client.Mount(ctx, "transit", &vaultapi.MountInput{Type: "transit"})).To(Succeed())
+		_, err := client.Write(ctx, "transit/keys/app", map[string]interface{}{"type": "ed25519", "exportable": true})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("refusing to disable export")
+		_, err = client.Write(ctx, "transit/keys/app/config", map[string]interface{}{"exportable": false})
+		Expect(err).To(HaveOccurred())
+
+		s, err := client.Write(ctx, "transit/keys/app/rotate", map[string]interface{}{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKeyWithValue("latest_version", json.Number("2")))
+
+		By("requiring existing versions")
+		_, err = client.Write(ctx, "transit/keys/app/config", map[string]interface{}{"min_decryption_version": 3})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "transit/keys/app/config", map[string]interface{}{"min_decryption_version": 2, "auto_rotate_period": "24h"})
+		Expect(err).NotTo(HaveOccurred())
+		key, ok := server.TransitKey("transit", "app")
+		Expect(ok).To(BeTrue())
+		Expect(key.Type).To(Equal("ed25519"))
+		Expect(key.MinDecryptionVersion).To(Equal(2))
+		Expect(key.AutoRotatePeriod).To(Equal(86400))
+
+		By("refusing deletion unless allowed")
+		_, err = client.Delete(ctx, "transit/keys/app")
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "transit/keys/app/config", map[string]interface{}{"deletion_allowed": true})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Delete(ctx, "transit/keys/app")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok = server.TransitKey("transit", "app")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should configure the SSH CA and roles", func() {
+		Expect(client.Mount(ctx, "ssh", &vaultapi.MountInput{Type: "ssh"})).To(Succeed())
+		_, err := client.Read(ctx, "ssh/config/ca")
+		Expect(err).To(HaveOccurred())
+
+		s, err := client.Write(ctx, "ssh/config/ca", map[string]interface{}{"key_type": "ssh-ed25519"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["public_key"]).To(HavePrefix("ssh-ed25519 "))
+		publicKey, ok := server.SSHCAPublicKey("ssh")
+		Expect(ok).To(BeTrue())
+		Expect(s.Data).To(HaveKeyWithValue("public_key", publicKey))
+
+		By("refusing to overwrite the CA")
+		_, err = client.Write(ctx, "ssh/config/ca", map[string]interface{}{})
+		Expect(err).To(HaveOccurred())
+
+		By("requiring certificates to be allowed")
+		_, err = client.Write(ctx, "ssh/roles/ops", map[string]interface{}{"key_type": "ca"})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "ssh/roles/ops", map[string]interface{}{
+			"key_type":                "ca",
+			"allow_user_certificates": true,
+			"allowed_users":           "ubuntu",
+			"ttl":                     "1h",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		s, err = client.Read(ctx, "ssh/roles/ops")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKeyWithValue("ttl", json.Number("3600")))
+
+		_, err = client.Delete(ctx, "ssh/config/ca")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok = server.SSHCAPublicKey("ssh")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should manage entities and entity aliases", func() {
+		Expect(client.EnableAuth(ctx, "kubernetes/", &vaultapi.EnableAuthOptions{Type: "kubernetes"})).To(Succeed())
+		mount, err := client.GetAuth(ctx, "kubernetes")
+		Expect(err).NotTo(HaveOccurred())
+
+		s, err := client.Write(ctx, "identity/entity/name/app", map[string]interface{}{"policies": []string{"app"}})
+		Expect(err).NotTo(HaveOccurred())
+		entityID := s.Data["id"].(string)
+
+		By("refusing unknown mount accessors")
+		_, err = client.Write(ctx, "identity/entity-alias", map[string]interface{}{"name": "default/app", "canonical_id": entityID, "mount_accessor": "missing"})
+		Expect(err).To(HaveOccurred())
+
+		s, err = client.Write(ctx, "identity/entity-alias", map[string]interface{}{"name": "default/app", "canonical_id": entityID, "mount_accessor": mount.Accessor})
+		Expect(err).NotTo(HaveOccurred())
+		aliasID := s.Data["id"].(string)
+
+		By("refusing duplicate aliases")
+		_, err = client.Write(ctx, "identity/entity-alias", map[string]interface{}{"name": "default/app", "canonical_id": entityID, "mount_accessor": mount.Accessor})
+		Expect(err).To(HaveOccurred())
+
+		s, err = client.Write(ctx, "identity/lookup/entity", map[string]interface{}{"alias_name": "default/app", "alias_mount_accessor": mount.Accessor})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKeyWithValue("name", "app"))
+		entity, ok := server.IdentityEntity("app")
+		Expect(ok).To(BeTrue())
+		Expect(entity.Policies).To(Equal([]string{"app"}))
+		Expect(entity.Aliases).To(HaveLen(1))
+		Expect(entity.Aliases[0].ID).To(Equal(aliasID))
+
+		By("deleting aliases along with their entity")
+		_, err = client.Delete(ctx, "identity/entity/name/app")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok = server.IdentityEntityAlias(aliasID)
+		Expect(ok).To(BeFalse())
+		s, err = client.Write(ctx, "identity/lookup/entity", map[string]interface{}{"alias_name": "default/app", "alias_mount_accessor": mount.Accessor})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(BeNil())
+	})
+
+	It("should manage groups and group aliases", func() {
+		Expect(client.EnableAuth(ctx, "ldap/", &vaultapi.EnableAuthOptions{Type: "ldap"})).To(Succeed())
+		mount, err := client.GetAuth(ctx, "ldap")
+		Expect(err).NotTo(HaveOccurred())
+		s, err := client.Write(ctx, "identity/entity/name/alice", map[string]interface{}{})
+		Expect(err).NotTo(HaveOccurred())
+		entityID := s.Data["id"].(string)
+
+		s, err = client.Write(ctx, "identity/group/name/devs", map[string]interface{}{"member_entity_ids": []string{entityID}})
+		Expect(err).NotTo(HaveOccurred())
+		internalID := s.Data["id"].(string)
+
+		By("refusing aliases on internal groups")
+		_, err = client.Write(ctx, "identity/group-alias", map[string]interface{}{"name": "devs", "canonical_id": internalID, "mount_accessor": mount.Accessor})
+		Expect(err).To(HaveOccurred())
+
+		By("refusing members on external groups")
+		_, err = client.Write(ctx, "identity/group/name/ldap-devs", map[string]interface{}{"type": "external", "member_entity_ids": []string{entityID}})
+		Expect(err).To(HaveOccurred())
+		s, err = client.Write(ctx, "identity/group/name/ldap-devs", map[string]interface{}{"type": "external", "policies": []string{"devs"}})
+		Expect(err).NotTo(HaveOccurred())
+		externalID := s.Data["id"].(string)
+
+		s, err = client.Write(ctx, "identity/group-alias", map[string]interface{}{"name": "cn=devs", "canonical_id": externalID, "mount_accessor": mount.Accessor})
+		Expect(err).NotTo(HaveOccurred())
+		aliasID := s.Data["id"].(string)
+		group, ok := server.IdentityGroup("ldap-devs")
+		Expect(ok).To(BeTrue())
+		Expect(group.Alias.ID).To(Equal(aliasID))
+
+		By("refusing a second alias on the group")
+		_, err = client.Write(ctx, "identity/group-alias", map[string]interface{}{"name": "cn=ops", "canonical_id": externalID, "mount_accessor": mount.Accessor})
+		Expect(err).To(HaveOccurred())
+
+		By("refusing to change the type")
+		_, err = client.Write(ctx, "identity/group/name/devs", map[string]interface{}{"type": "external"})
+		Expect(err).To(HaveOccurred())
+
+		By("dropping deleted members")
+		_, err = client.Delete(ctx, "identity/entity/name/alice")
+		Expect(err).NotTo(HaveOccurred())
+		group, ok = server.IdentityGroup("devs")
+		Expect(ok).To(BeTrue())
+		Expect(group.MemberEntityIDs).To(BeEmpty())
+
+		_, err = client.Delete(ctx, "identity/group/name/ldap-devs")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok = server.IdentityGroupAlias(aliasID)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should manage the OIDC provider", func() {
+		By("refusing clients with unknown keys or assignments")
+		_, err := client.Write(ctx, "identity/oidc/client/app", map[string]interface{}{"key": "signing"})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "identity/oidc/key/signing", map[string]interface{}{"rotation_period": "1h", "allowed_client_ids": []string{"*"}})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "identity/oidc/client/app", map[string]interface{}{"key": "signing", "assignments": []string{"devs"}})
+		Expect(err).To(HaveOccurred())
+
+		_, err = client.Write(ctx, "identity/oidc/client/app", map[string]interface{}{"key": "signing", "assignments": []string{"allow_all"}, "id_token_ttl": "1h"})
+		Expect(err).NotTo(HaveOccurred())
+		app, ok := server.OIDCClient("app")
+		Expect(ok).To(BeTrue())
+		Expect(app.ClientID).NotTo(BeEmpty())
+		Expect(app.ClientSecret).NotTo(BeEmpty())
+		Expect(app.IDTokenTTL).To(Equal(3600))
+
+		By("generating no secret for public clients")
+		_, err = client.Write(ctx, "identity/oidc/client/cli", map[string]interface{}{"key": "signing", "client_type": "public"})
+		Expect(err).NotTo(HaveOccurred())
+		cli, ok := server.OIDCClient("cli")
+		Expect(ok).To(BeTrue())
+		Expect(cli.ClientSecret).To(BeEmpty())
+
+		By("refusing to change the client type")
+		_, err = client.Write(ctx, "identity/oidc/client/cli", map[string]interface{}{"client_type": "confidential"})
+		Expect(err).To(HaveOccurred())
+
+		By("reporting the issuer of providers")
+		_, err = client.Write(ctx, "identity/oidc/provider/main", map[string]interface{}{"scopes_supported": []string{"groups"}})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "identity/oidc/scope/openid", map[string]interface{}{})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "identity/oidc/scope/groups", map[string]interface{}{"template": `{"groups": {{identity.entity.groups.names}}}`})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "identity/oidc/provider/main", map[string]interface{}{"issuer": "https://vault.example.com:8200", "scopes_supported": []string{"groups"}, "allowed_client_ids": []string{app.ClientID}})
+		Expect(err).NotTo(HaveOccurred())
+		s, err := client.Read(ctx, "identity/oidc/provider/main")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["issuer"]).To(Equal("https://vault.example.com:8200/v1/identity/oidc/provider/main"))
+
+		By("refusing to delete what is in use")
+		_, err = client.Delete(ctx, "identity/oidc/key/signing")
+		Expect(err).To(HaveOccurred())
+		_, err = client.Delete(ctx, "identity/oidc/scope/groups")
+		Expect(err).To(HaveOccurred())
+		_, err = client.Delete(ctx, "identity/oidc/assignment/allow_all")
+		Expect(err).To(HaveOccurred())
+
+		_, err = client.Delete(ctx, "identity/oidc/client/app")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Delete(ctx, "identity/oidc/client/cli")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Delete(ctx, "identity/oidc/key/signing")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should manage quotas", func() {
+		Expect(client.Mount(ctx, "secret", &vaultapi.MountInput{Type: "kv"})).To(Succeed())
+		Expect(client.EnableAuth(ctx, "kubernetes/", &vaultapi.EnableAuthOptions{Type: "kubernetes"})).To(Succeed())
+
+		_, err := client.Write(ctx, "sys/quotas/rate-limit/global", map[string]interface{}{"rate": 100, "interval": "1m"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "sys/quotas/rate-limit/app", map[string]interface{}{"path": "auth/kubernetes", "role": "app", "rate": 10})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "sys/quotas/lease-count/secret", map[string]interface{}{"path": "secret", "max_leases": 50})
+		Expect(err).NotTo(HaveOccurred())
+
+		q, ok := server.Quota("rate-limit", "global")
+		Expect(ok).To(BeTrue())
+		Expect(q.Interval).To(Equal(60))
+		s, err := client.Read(ctx, "sys/quotas/rate-limit/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["path"]).To(Equal("auth/kubernetes/"))
+		Expect(s.Data["role"]).To(Equal("app"))
+
+		By("rejecting invalid quotas")
+		_, err = client.Write(ctx, "sys/quotas/rate-limit/dup", map[string]interface{}{"rate": 5})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "sys/quotas/rate-limit/role", map[string]interface{}{"path": "secret", "role": "app", "rate": 5})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "sys/quotas/lease-count/missing", map[string]interface{}{"path": "missing", "max_leases": 5})
+		Expect(err).To(HaveOccurred())
+
+		_, err = client.Delete(ctx, "sys/quotas/lease-count/secret")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok = server.Quota("lease-count", "secret")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should manage the plugin catalog and reload plugins", func() {
+		sum := strings.Repeat("ab", 32)
+		_, err := client.Write(ctx, "sys/plugins/catalog/secret/custom", map[string]interface{}{"command": "custom-v1", "sha256": sum, "version": "v1.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "sys/plugins/catalog/secret/custom", map[string]interface{}{"command": "custom-v2", "sha256": sum, "version": "v2.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("rejecting invalid registrations and unknown versions")
+		_, err = client.Write(ctx, "sys/plugins/catalog/secret/custom", map[string]interface{}{"command": "custom", "sha256": "nothex"})
+		Expect(err).To(HaveOccurred())
+		_, err = client.Write(ctx, "sys/plugins/catalog/secret/custom", map[string]interface{}{"command": "custom", "sha256": sum, "version": "latest"})
+		Expect(err).To(HaveOccurred())
+		Expect(client.Mount(ctx, "custom", &vaultapi.MountInput{Type: "custom", Config: vaultapi.MountConfigInput{PluginVersion: "v3.0.0"}})).NotTo(Succeed())
+
+		s, err := client.ReadWithData(ctx, "sys/plugins/catalog/secret/custom", map[string][]string{"version": {"v2.0.0"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["command"]).To(Equal("custom-v2"))
+
+		By("running the tuned version once reloaded")
+		Expect(client.Mount(ctx, "custom", &vaultapi.MountInput{Type: "custom", Config: vaultapi.MountConfigInput{PluginVersion: "v1.0.0"}})).To(Succeed())
+		Expect(client.TuneMount(ctx, "custom", vaultapi.MountConfigInput{PluginVersion: "v2.0.0"})).To(Succeed())
+		m, _ := server.Mount("custom")
+		Expect(m.RunningVersion).To(Equal("v1.0.0"))
+		_, err = client.Write(ctx, "sys/plugins/reload/backend", map[string]interface{}{"plugin": "custom"})
+		Expect(err).NotTo(HaveOccurred())
+		m, _ = server.Mount("custom")
+		Expect(m.RunningVersion).To(Equal("v2.0.0"))
+
+		By("refusing to deregister what is in use")
+		_, err = client.DeleteWithData(ctx, "sys/plugins/catalog/secret/custom", map[string][]string{"version": {"v2.0.0"}})
+		Expect(err).To(HaveOccurred())
+		_, err = client.DeleteWithData(ctx, "sys/plugins/catalog/secret/custom", map[string][]string{"version": {"v1.0.0"}})
+		Expect(err).NotTo(HaveOccurred())
+		_, ok := server.Plugin("secret", "custom", "v1.0.0")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should manage nested namespaces and their mounts", func() {
+		_, err := client.Write(ctx, "sys/namespaces/tenants", map[string]interface{}{"custom_metadata": map[string]string{"owner": "platform"}})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "tenants/sys/namespaces/team-a", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Write(ctx, "sys/namespaces/sys", nil)
+		Expect(err).To(HaveOccurred())
+
+		s, err := client.Read(ctx, "tenants/sys/namespaces/team-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["path"]).To(Equal("tenants/team-a/"))
+		s, err = client.List(ctx, "sys/namespaces")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data["keys"]).To(ConsistOf("tenants/"))
+
+		By("patching the custom metadata")
+		_, err = client.JSONMergePatch(ctx, "sys/namespaces/tenants", map[string]interface{}{"custom_metadata": map[string]interface{}{"owner": nil, "tier": "gold"}})
+		Expect(err).NotTo(HaveOccurred())
+		metadata, _ := server.NamespaceMetadata("tenants")
+		Expect(metadata).To(Equal(map[string]string{"tier": "gold"}))
+
+		By("enabling mounts within a namespace")
+		_, err = client.Write(ctx, "tenants/team-a/sys/mounts/secret", map[string]interface{}{"type": "kv"})
+		Expect(err).NotTo(HaveOccurred())
+		s, err = client.Read(ctx, "tenants/team-a/sys/mounts")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Data).To(HaveKey("secret/"))
+		_, ok := server.Mount("secret")
+		Expect(ok).To(BeFalse())
+
+		By("refusing to delete namespaces with children")
+		_, err = client.Delete(ctx, "sys/namespaces/tenants")
+		Expect(err).To(HaveOccurred())
+		_, err = client.Delete(ctx, "tenants/sys/namespaces/team-a")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Delete(ctx, "sys/namespaces/tenants")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should inject faults", func() {
+		server.InjectFault(Fault{Method: http.MethodPut, Path: "sys/policies/acl/app", Status: http.StatusForbidden, Times: 1})
+
+		err := client.PutPolicy(ctx, "app", "")
+		Expect(err).To(HaveOccurred())
+		var respErr *vaultapi.ResponseError
+		Expect(err).To(BeAssignableToTypeOf(respErr))
+		Expect(err.(*vaultapi.ResponseError).StatusCode).To(Equal(http.StatusForbidden))
+
+		Expect(client.PutPolicy(ctx, "app", "")).To(Succeed())
+
+		server.InjectFault(Fault{Path: "sys"})
+		_, err = client.GetPolicy(ctx, "app")
+		Expect(err).To(HaveOccurred())
+		server.ClearFaults()
+		_, err = client.GetPolicy(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(server.Requests()).To(ContainElement(Request{Method: http.MethodPut, Path: "sys/policies/acl/app"}))
+	})
+})
@@ -0,0 +1,171 @@
+package fake
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// sshEngine is the state of an SSH secrets engine mount.
+type sshEngine struct {
+	// publicKey is the public key of the CA in authorized_keys format, empty
+	// until the CA is configured.
+	publicKey string
+	roles     map[string]*vault.SSHRole
+}
+
+// SSHCAPublicKey returns the public key of the CA of an SSH mount.
+func (s *Server) SSHCAPublicKey(mount string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.sshs[normalize(mount)]
+	if !ok || e.publicKey == "" {
+		return "", false
+	}
+	return e.publicKey, true
+}
+
+// sshMount returns the SSH mount p belongs to, if any.
+func (s *Server) sshMount(p string) string {
+	for mount, m := range s.mounts {
+		if m.Type == "ssh" && strings.HasPrefix(p, mount+"/") {
+			return mount
+		}
+	}
+	return ""
+}
+
+func (s *Server) handleSSH(w http.ResponseWriter, method, mount, p string, body map[string]interface{}) {
+	e := s.sshs[mount]
+	if e == nil {
+		e = &sshEngine{roles: map[string]*vault.SSHRole{}}
+		s.sshs[mount] = e
+	}
+
+	switch {
+	case p == "config/ca" && method == http.MethodGet:
+		if e.publicKey == "" {
+			writeErrors(w, http.StatusBadRequest, "keys haven't been configured yet")
+			return
+		}
+		writeData(w, map[string]interface{}{"public_key": e.publicKey})
+	case p == "config/ca" && (method == http.MethodPut || method == http.MethodPost):
+		if e.publicKey != "" {
+			writeErrors(w, http.StatusBadRequest, "keys are already configured; delete them before reconfiguring")
+			return
+		}
+		publicKey, err := sshCAKey(body)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		e.publicKey = publicKey
+		writeData(w, map[string]interface{}{"public_key": e.publicKey})
+	case p == "config/ca" && method == http.MethodDelete:
+		e.publicKey = ""
+		w.WriteHeader(http.StatusNoContent)
+	case p == "roles" && method == "LIST":
+		writeKeys(w, e.roles)
+	case strings.HasPrefix(p, "roles/") && method == http.MethodGet:
+		r, ok := e.roles[strings.TrimPrefix(p, "roles/")]
+		if !ok {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, toMap(r))
+	case strings.HasPrefix(p, "roles/") && (method == http.MethodPut || method == http.MethodPost):
+		r := &vault.SSHRole{AlgorithmSigner: "default"}
+		fields := roundTrip(body)
+		delete(fields, "ttl")
+		delete(fields, "max_ttl")
+		if err := fromMap(fields, r); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for field, ttl := range map[string]*int{"ttl": &r.TTL, "max_ttl": &r.MaxTTL} {
+			if v, ok := body[field]; ok {
+				var err error
+				if *ttl, err = vault.ParseTTL(fmt.Sprint(v)); err != nil {
+					writeErrors(w, http.StatusBadRequest, err.Error())
+					return
+				}
+			}
+		}
+		if r.KeyType != "ca" && r.KeyType != "otp" {
+			writeErrors(w, http.StatusBadRequest, "missing or invalid key_type")
+			return
+		}
+		if r.KeyType == "ca" && !r.AllowUserCertificates && !r.AllowHostCertificates {
+			writeErrors(w, http.StatusBadRequest, "either allow_user_certificates or allow_host_certificates must be set")
+			return
+		}
+		e.roles[strings.TrimPrefix(p, "roles/")] = r
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasPrefix(p, "roles/") && method == http.MethodDelete:
+		delete(e.roles, strings.TrimPrefix(p, "roles/"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErrors(w, http.StatusNotFound)
+	}
+}
+
+// sshCAKey returns the public key of the CA configured through
+// <mount>/config/ca, either generated or imported.
+func sshCAKey(body map[string]interface{}) (string, error) {
+	if privateKey, _ := body["private_key"].(string); privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse private_key: %w", err)
+		}
+		publicKey, _ := body["public_key"].(string)
+		parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse public_key: %w", err)
+		}
+		if string(parsed.Marshal()) != string(signer.PublicKey().Marshal()) {
+			return "", fmt.Errorf("public_key does not match private_key")
+		}
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(parsed))), nil
+	}
+
+	if body["generate_signing_key"] == false {
+		return "", fmt.Errorf("missing private_key")
+	}
+
+	var key crypto.Signer
+	var err error
+	switch keyType, _ := body["key_type"].(string); keyType {
+	case "", "ssh-rsa", "rsa":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ecdsa-sha2-nistp256":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ecdsa-sha2-nistp384":
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ecdsa-sha2-nistp521":
+		key, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case "ssh-ed25519", "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return "", fmt.Errorf("unknown key_type %s", keyType)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	publicKey, err := ssh.NewPublicKey(key.Public())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))), nil
+}
@@ -0,0 +1,207 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// transitKeyTypes are the key types the transit secrets engine supports.
+var transitKeyTypes = []string{
+	"aes128-gcm96", "aes256-gcm96", "chacha20-poly1305", "ed25519", "ecdsa-p256", "ecdsa-p384", "ecdsa-p521",
+	"rsa-2048", "rsa-3072", "rsa-4096", "hmac",
+}
+
+// transitKey is a key of a transit mount along with the creation time of its
+// versions.
+type transitKey struct {
+	vault.TransitKey
+	versions map[string]int64
+}
+
+func (k *transitKey) rotate() {
+	k.LatestVersion++
+	k.versions[strconv.Itoa(k.LatestVersion)] = time.Now().Unix()
+}
+
+// TransitKey returns a key of a transit mount.
+func (s *Server) TransitKey(mount, name string) (*vault.TransitKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.transits[normalize(mount)][name]
+	if !ok {
+		return nil, false
+	}
+	out := k.TransitKey
+	return &out, true
+}
+
+// transitMount returns the transit mount p belongs to, if any.
+func (s *Server) transitMount(p string) string {
+	for mount, m := range s.mounts {
+		if m.Type == "transit" && strings.HasPrefix(p, mount+"/") {
+			return mount
+		}
+	}
+	return ""
+}
+
+func (s *Server) handleTransit(w http.ResponseWriter, method, mount, p string, body map[string]interface{}) {
+	keys := s.transits[mount]
+	if keys == nil {
+		keys = map[string]*transitKey{}
+		s.transits[mount] = keys
+	}
+
+	if p == "keys" && method == "LIST" {
+		writeKeys(w, keys)
+		return
+	}
+
+	name, op, _ := strings.Cut(strings.TrimPrefix(p, "keys/"), "/")
+	if !strings.HasPrefix(p, "keys/") || name == "" {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	k := keys[name]
+
+	switch {
+	case op == "" && method == http.MethodGet:
+		if k == nil {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		data := toMap(k.TransitKey)
+		data["name"] = name
+		data["keys"] = k.versions
+		writeData(w, data)
+	case op == "" && (method == http.MethodPut || method == http.MethodPost):
+		// Creating an existing key is a no-op
+		if k != nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		k = &transitKey{
+			TransitKey: vault.TransitKey{Type: "aes256-gcm96", MinDecryptionVersion: 1},
+			versions:   map[string]int64{},
+		}
+		if v, ok := body["type"].(string); ok {
+			if !slices.Contains(transitKeyTypes, v) {
+				writeErrors(w, http.StatusBadRequest, fmt.Sprintf("unknown key type %s", v))
+				return
+			}
+			k.Type = v
+		}
+		k.Exportable = body["exportable"] == true
+		k.AllowPlaintextBackup = body["allow_plaintext_backup"] == true
+		if err := k.setAutoRotatePeriod(body); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		k.rotate()
+		keys[name] = k
+		w.WriteHeader(http.StatusNoContent)
+	case op == "" && method == http.MethodDelete:
+		if k == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !k.DeletionAllowed {
+			writeErrors(w, http.StatusBadRequest, "deletion is not allowed for this key")
+			return
+		}
+		delete(keys, name)
+		w.WriteHeader(http.StatusNoContent)
+	case op == "config" && (method == http.MethodPut || method == http.MethodPost):
+		if k == nil {
+			writeErrors(w, http.StatusBadRequest, "no existing key named "+name+" could be found")
+			return
+		}
+		updated := *k
+		if err := updated.configure(body); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		*k = updated
+		data := toMap(k.TransitKey)
+		data["name"] = name
+		writeData(w, data)
+	case op == "rotate" && (method == http.MethodPut || method == http.MethodPost):
+		if k == nil {
+			writeErrors(w, http.StatusBadRequest, "no existing key named "+name+" could be found")
+			return
+		}
+		k.rotate()
+		data := toMap(k.TransitKey)
+		data["name"] = name
+		writeData(w, data)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// configure applies the parameters of <mount>/keys/<name>/config.
+func (k *transitKey) configure(body map[string]interface{}) error {
+	for field, v := range body {
+		switch field {
+		case "exportable":
+			if k.Exportable && v != true {
+				return fmt.Errorf("export cannot be disabled once enabled")
+			}
+			k.Exportable = v == true
+		case "allow_plaintext_backup":
+			if k.AllowPlaintextBackup && v != true {
+				return fmt.Errorf("plaintext backup cannot be disabled once enabled")
+			}
+			k.AllowPlaintextBackup = v == true
+		case "deletion_allowed":
+			k.DeletionAllowed = v == true
+		case "min_decryption_version", "min_encryption_version":
+			n, err := strconv.Atoi(fmt.Sprint(v))
+			if err != nil {
+				return err
+			}
+			if field == "min_decryption_version" {
+				k.MinDecryptionVersion = max(n, 1)
+			} else {
+				k.MinEncryptionVersion = n
+			}
+		}
+	}
+	if err := k.setAutoRotatePeriod(body); err != nil {
+		return err
+	}
+
+	if k.MinDecryptionVersion > k.LatestVersion {
+		return fmt.Errorf("cannot set min decryption version of %d, latest key version is %d", k.MinDecryptionVersion, k.LatestVersion)
+	}
+	if k.MinEncryptionVersion > k.LatestVersion {
+		return fmt.Errorf("cannot set min encryption version of %d, latest key version is %d", k.MinEncryptionVersion, k.LatestVersion)
+	}
+	if k.MinEncryptionVersion != 0 && k.MinEncryptionVersion < k.MinDecryptionVersion {
+		return fmt.Errorf("min decryption version should not be less then min available version")
+	}
+	return nil
+}
+
+func (k *transitKey) setAutoRotatePeriod(body map[string]interface{}) error {
+	v, ok := body["auto_rotate_period"]
+	if !ok {
+		return nil
+	}
+	period, err := vault.ParseTTL(fmt.Sprint(v))
+	if err != nil {
+		return err
+	}
+	if period != 0 && period < 3600 {
+		return fmt.Errorf("auto rotate period must be 0 to disable or at least an hour")
+	}
+	k.AutoRotatePeriod = period
+	return nil
+}
@@ -0,0 +1,176 @@
+package vault
+
+import (
+	"context"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// Interface is the subset of the Vault API the reconcilers depend on. It is
+// implemented by *Vault against a live server, and can be pointed at the
+// in-memory server from the fake package in tests.
+type Interface interface {
+	GetPolicy(ctx context.Context, name string) (string, error)
+	PutPolicy(ctx context.Context, name, rules string) error
+	DeletePolicy(ctx context.Context, name string) error
+
+	GetAuth(ctx context.Context, path string) (*vaultapi.MountOutput, error)
+	EnableAuth(ctx context.Context, path string, options *vaultapi.EnableAuthOptions) error
+	DisableAuth(ctx context.Context, path string) error
+
+	GetMount(ctx context.Context, path string) (*vaultapi.MountOutput, error)
+	Mount(ctx context.Context, path string, options *vaultapi.MountInput) error
+	TuneMount(ctx context.Context, path string, config vaultapi.MountConfigInput) error
+	Unmount(ctx context.Context, path string) error
+
+	ListAudit(ctx context.Context) (map[string]*vaultapi.Audit, error)
+	EnableAudit(ctx context.Context, path string, options *vaultapi.EnableAuditOptions) error
+	DisableAudit(ctx context.Context, path string) error
+
+	CreateToken(ctx context.Context, request *vaultapi.TokenCreateRequest) (*vaultapi.Secret, error)
+	LookupAccessor(ctx context.Context, accessor string) (*vaultapi.Secret, error)
+	RevokeAccessor(ctx context.Context, accessor string) error
+	RenewLease(ctx context.Context, leaseID string, increment int) (*vaultapi.Secret, error)
+	RevokeLease(ctx context.Context, leaseID string) error
+
+	Read(ctx context.Context, path string) (*vaultapi.Secret, error)
+	ReadWithData(ctx context.Context, path string, data map[string][]string) (*vaultapi.Secret, error)
+	List(ctx context.Context, path string) (*vaultapi.Secret, error)
+	Write(ctx context.Context, path string, data map[string]interface{}) (*vaultapi.Secret, error)
+	JSONMergePatch(ctx context.Context, path string, data map[string]interface{}) (*vaultapi.Secret, error)
+	Delete(ctx context.Context, path string) (*vaultapi.Secret, error)
+	DeleteWithData(ctx context.Context, path string, data map[string][]string) (*vaultapi.Secret, error)
+}
+
+var _ Interface = &Vault{}
+
+// NewVaultClient wraps an already configured and authenticated Vault API client.
+func NewVaultClient(client *vaultapi.Client) *Vault {
+	return &Vault{Client: client}
+}
+
+func (v *Vault) GetPolicy(ctx context.Context, name string) (rules string, err error) {
+	defer observe("GetPolicy", time.Now(), &err)
+	return v.Client.Sys().GetPolicyWithContext(ctx, name)
+}
+
+func (v *Vault) PutPolicy(ctx context.Context, name, rules string) (err error) {
+	defer observe("PutPolicy", time.Now(), &err)
+	return v.Client.Sys().PutPolicyWithContext(ctx, name, rules)
+}
+
+func (v *Vault) DeletePolicy(ctx context.Context, name string) (err error) {
+	defer observe("DeletePolicy", time.Now(), &err)
+	return v.Client.Sys().DeletePolicyWithContext(ctx, name)
+}
+
+func (v *Vault) GetAuth(ctx context.Context, path string) (mount *vaultapi.MountOutput, err error) {
+	defer observe("GetAuth", time.Now(), &err)
+	return v.Client.Sys().GetAuthWithContext(ctx, path)
+}
+
+func (v *Vault) EnableAuth(ctx context.Context, path string, options *vaultapi.EnableAuthOptions) (err error) {
+	defer observe("EnableAuth", time.Now(), &err)
+	return v.Client.Sys().EnableAuthWithOptionsWithContext(ctx, path, options)
+}
+
+func (v *Vault) DisableAuth(ctx context.Context, path string) (err error) {
+	defer observe("DisableAuth", time.Now(), &err)
+	return v.Client.Sys().DisableAuthWithContext(ctx, path)
+}
+
+func (v *Vault) GetMount(ctx context.Context, path string) (mount *vaultapi.MountOutput, err error) {
+	defer observe("GetMount", time.Now(), &err)
+	return v.Client.Sys().GetMountWithContext(ctx, path)
+}
+
+func (v *Vault) Mount(ctx context.Context, path string, options *vaultapi.MountInput) (err error) {
+	defer observe("Mount", time.Now(), &err)
+	return v.Client.Sys().MountWithContext(ctx, path, options)
+}
+
+func (v *Vault) TuneMount(ctx context.Context, path string, config vaultapi.MountConfigInput) (err error) {
+	defer observe("TuneMount", time.Now(), &err)
+	return v.Client.Sys().TuneMountWithContext(ctx, path, config)
+}
+
+func (v *Vault) Unmount(ctx context.Context, path string) (err error) {
+	defer observe("Unmount", time.Now(), &err)
+	return v.Client.Sys().UnmountWithContext(ctx, path)
+}
+
+func (v *Vault) ListAudit(ctx context.Context) (devices map[string]*vaultapi.Audit, err error) {
+	defer observe("ListAudit", time.Now(), &err)
+	return v.Client.Sys().ListAuditWithContext(ctx)
+}
+
+func (v *Vault) EnableAudit(ctx context.Context, path string, options *vaultapi.EnableAuditOptions) (err error) {
+	defer observe("EnableAudit", time.Now(), &err)
+	return v.Client.Sys().EnableAuditWithOptionsWithContext(ctx, path, options)
+}
+
+func (v *Vault) DisableAudit(ctx context.Context, path string) (err error) {
+	defer observe("DisableAudit", time.Now(), &err)
+	return v.Client.Sys().DisableAuditWithContext(ctx, path)
+}
+
+func (v *Vault) CreateToken(ctx context.Context, request *vaultapi.TokenCreateRequest) (secret *vaultapi.Secret, err error) {
+	defer observe("CreateToken", time.Now(), &err)
+	return v.Client.Auth().Token().CreateWithContext(ctx, request)
+}
+
+func (v *Vault) LookupAccessor(ctx context.Context, accessor string) (secret *vaultapi.Secret, err error) {
+	defer observe("LookupAccessor", time.Now(), &err)
+	return v.Client.Auth().Token().LookupAccessorWithContext(ctx, accessor)
+}
+
+func (v *Vault) RevokeAccessor(ctx context.Context, accessor string) (err error) {
+	defer observe("RevokeAccessor", time.Now(), &err)
+	return v.Client.Auth().Token().RevokeAccessorWithContext(ctx, accessor)
+}
+
+func (v *Vault) RenewLease(ctx context.Context, leaseID string, increment int) (secret *vaultapi.Secret, err error) {
+	defer observe("RenewLease", time.Now(), &err)
+	return v.Client.Sys().RenewWithContext(ctx, leaseID, increment)
+}
+
+func (v *Vault) RevokeLease(ctx context.Context, leaseID string) (err error) {
+	defer observe("RevokeLease", time.Now(), &err)
+	return v.Client.Sys().RevokeWithContext(ctx, leaseID)
+}
+
+func (v *Vault) Read(ctx context.Context, path string) (secret *vaultapi.Secret, err error) {
+	defer observe("Read", time.Now(), &err)
+	return v.Client.Logical().ReadWithContext(ctx, path)
+}
+
+func (v *Vault) ReadWithData(ctx context.Context, path string, data map[string][]string) (secret *vaultapi.Secret, err error) {
+	defer observe("ReadWithData", time.Now(), &err)
+	return v.Client.Logical().ReadWithDataWithContext(ctx, path, data)
+}
+
+func (v *Vault) List(ctx context.Context, path string) (secret *vaultapi.Secret, err error) {
+	defer observe("List", time.Now(), &err)
+	return v.Client.Logical().ListWithContext(ctx, path)
+}
+
+func (v *Vault) Write(ctx context.Context, path string, data map[string]interface{}) (secret *vaultapi.Secret, err error) {
+	defer observe("Write", time.Now(), &err)
+	return v.Client.Logical().WriteWithContext(ctx, path, data)
+}
+
+func (v *Vault) JSONMergePatch(ctx context.Context, path string, data map[string]interface{}) (secret *vaultapi.Secret, err error) {
+	defer observe("JSONMergePatch", time.Now(), &err)
+	return v.Client.Logical().JSONMergePatch(ctx, path, data)
+}
+
+func (v *Vault) Delete(ctx context.Context, path string) (secret *vaultapi.Secret, err error) {
+	defer observe("Delete", time.Now(), &err)
+	return v.Client.Logical().DeleteWithContext(ctx, path)
+}
+
+func (v *Vault) DeleteWithData(ctx context.Context, path string, data map[string][]string) (secret *vaultapi.Secret, err error) {
+	defer observe("DeleteWithData", time.Now(), &err)
+	return v.Client.Logical().DeleteWithDataWithContext(ctx, path, data)
+}
@@ -0,0 +1,32 @@
+package vault
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"hopopops/vault-operator/internal/metrics"
+)
+
+// observe records the latency and error of an operation of Interface started
+// at start, once it returned err. The code is the HTTP status code Vault
+// answered a failed operation with, "ok" when it succeeded and "error" when
+// no response was received. Being recorded around the Vault API client, the
+// metrics leave its transport as configured, for unix sockets and TLS alike.
+func observe(operation string, start time.Time, err *error) {
+	code := "ok"
+	if *err != nil {
+		code = "error"
+		var respErr *vaultapi.ResponseError
+		if errors.As(*err, &respErr) {
+			code = strconv.Itoa(respErr.StatusCode)
+		}
+	}
+
+	metrics.VaultRequestDuration.WithLabelValues(operation, code).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		metrics.VaultRequestErrors.WithLabelValues(operation, code).Inc()
+	}
+}
@@ -0,0 +1,85 @@
+package vault_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+	"hopopops/vault-operator/internal/metrics"
+)
+
+var _ = Describe("Vault request metrics", func() {
+	ctx := context.Background()
+
+	// observations returns the number of requests recorded for an operation
+	// and a status code.
+	observations := func(operation, code string) uint64 {
+		m := &dto.Metric{}
+		observer := metrics.VaultRequestDuration.WithLabelValues(operation, code)
+		Expect(observer.(prometheus.Metric).Write(m)).To(Succeed())
+		return m.GetHistogram().GetSampleCount()
+	}
+
+	failures := func(operation, code string) float64 {
+		return testutil.ToFloat64(metrics.VaultRequestErrors.WithLabelValues(operation, code))
+	}
+
+	It("should record the operations by status code", func() {
+		server := fake.NewServer()
+		client := server.Client()
+
+		succeeded, failed := observations("PutPolicy", "ok"), failures("Write", "500")
+
+		Expect(client.PutPolicy(ctx, "app", `path "*" {}`)).To(Succeed())
+		server.InjectFault(fake.Fault{Method: http.MethodPut, Path: "secret/app", Status: http.StatusInternalServerError})
+		_, err := client.Write(ctx, "secret/app", map[string]interface{}{"key": "value"})
+		Expect(err).To(HaveOccurred())
+
+		Expect(observations("PutPolicy", "ok")).To(Equal(succeeded + 1))
+		Expect(failures("Write", "500")).To(Equal(failed + 1))
+
+		By("Recording the requests left unanswered")
+		server.Close()
+		unanswered := failures("Read", "error")
+		_, err = client.Read(ctx, "secret/app")
+		Expect(err).To(HaveOccurred())
+		Expect(failures("Read", "error")).To(Equal(unanswered + 1))
+	})
+
+	It("should leave the transport of the Vault API client as configured", func() {
+		socket := filepath.Join(GinkgoT().TempDir(), "vault.sock")
+		listener, err := net.Listen("unix", socket)
+		Expect(err).NotTo(HaveOccurred())
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"policy":"path \"*\" {}"}}`))
+		})}
+		go func() { _ = server.Serve(listener) }()
+		DeferCleanup(server.Close)
+
+		config := vaultapi.DefaultConfig()
+		config.Address = "unix://" + socket
+		apiClient, err := vaultapi.NewClient(config)
+		Expect(err).NotTo(HaveOccurred())
+		client := vault.NewVaultClient(apiClient)
+		apiClient.SetMaxIdleConnections(1)
+		apiClient.SetDisableKeepAlives(true)
+
+		succeeded := observations("GetPolicy", "ok")
+		rules, err := client.GetPolicy(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules).To(Equal(`path "*" {}`))
+		Expect(observations("GetPolicy", "ok")).To(Equal(succeeded + 1))
+	})
+})
@@ -0,0 +1,166 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"hopopops/vault-operator/internal/events"
+)
+
+// Mode defines whether the operator writes to Vault.
+type Mode string
+
+const (
+	// ModeEnforce makes Vault match the resources.
+	ModeEnforce Mode = "enforce"
+	// ModeObserve only reports how Vault differs from the resources, without
+	// writing to it.
+	ModeObserve Mode = "observe"
+)
+
+// ModeAnnotation overrides the mode of the operator for a resource.
+const ModeAnnotation = "vault.hopopops.com/mode"
+
+// ParseMode parses the mode of the operator.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeEnforce, ModeObserve:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid mode %q, must be %q or %q", s, ModeEnforce, ModeObserve)
+	}
+}
+
+// ResourceMode returns the mode a resource is reconciled in, from its
+// annotations and the mode of the operator, which is enforce when unset.
+func ResourceMode(mode Mode, annotations map[string]string) Mode {
+	if m, err := ParseMode(annotations[ModeAnnotation]); err == nil {
+		return m
+	}
+	if mode == "" {
+		return ModeEnforce
+	}
+	return mode
+}
+
+// UnsupportedModeReason is the reason of the Configured condition of a
+// resource to be observed while its kind only supports enforce mode.
+const UnsupportedModeReason = "UnsupportedMode"
+
+// SkipUnsupportedMode reports whether a resource of a kind that only supports
+// enforce mode is to be observed, in which case it must not be reconciled.
+// Its Configured condition is set to report it, along with a Warning event,
+// and once it is deleted its finalizer is removed, leaving Vault untouched.
+func SkipUnsupportedMode(ctx context.Context, c client.Client, recorder record.EventRecorder, obj client.Object, conditions *[]metav1.Condition, mode Mode, finalizer string) (bool, error) {
+	if ResourceMode(mode, obj.GetAnnotations()) != ModeObserve {
+		return false, nil
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		if controllerutil.RemoveFinalizer(obj, finalizer) {
+			return true, c.Update(ctx, obj)
+		}
+		return true, nil
+	}
+
+	message := "Observe mode is not supported by this kind of resource, Vault is left untouched"
+	if SetCondition(conditions, obj.GetGeneration(), metav1.Condition{Type: configuredCondition, Status: metav1.ConditionFalse, Reason: UnsupportedModeReason, Message: message}) {
+		events.UnsupportedMode(recorder, obj, UnsupportedModeReason, message)
+		return true, c.Status().Update(ctx, obj)
+	}
+	return true, nil
+}
+
+// Diff describes the fields of current that differ from desired, one per
+// line, both being compared through their JSON representation. Multi-line
+// strings are compared line by line.
+func Diff(current, desired interface{}) string {
+	c, d := diffFields(current), diffFields(desired)
+
+	keys := map[string]struct{}{}
+	for k := range c {
+		keys[k] = struct{}{}
+	}
+	for k := range d {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	for _, k := range sorted {
+		cv, dv := c[k], d[k]
+		cj, _ := json.Marshal(cv)
+		dj, _ := json.Marshal(dv)
+		if string(cj) == string(dj) {
+			continue
+		}
+
+		cs, cok := cv.(string)
+		ds, dok := dv.(string)
+		if cok && dok && (strings.Contains(cs, "\n") || strings.Contains(ds, "\n")) {
+			fmt.Fprintf(&b, "%s:\n", k)
+			for _, line := range diffLines(strings.Split(cs, "\n"), strings.Split(ds, "\n")) {
+				fmt.Fprintf(&b, "  %s\n", line)
+			}
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s -> %s\n", k, cj, dj)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// diffFields returns the fields of a value, nil being empty.
+func diffFields(v interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if b, err := json.Marshal(v); err == nil {
+		_ = json.Unmarshal(b, &fields)
+	}
+	return fields
+}
+
+// diffLines returns the lines removed from a, prefixed with "-", and added
+// in b, prefixed with "+", along their longest common subsequence.
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+	return lines
+}
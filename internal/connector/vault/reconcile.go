@@ -0,0 +1,94 @@
+package vault
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PausedAnnotation stops the operator from reconciling a resource when set
+// to "true", Vault being left untouched, even when the resource is deleted,
+// until it is removed.
+const PausedAnnotation = "vault.hopopops.com/paused"
+
+// ReconcileAtAnnotation requests a resource to be compared with Vault and
+// pushed to it again each time its value changes, usually to the current
+// time, for instance after fixing Vault by hand.
+const ReconcileAtAnnotation = "vault.hopopops.com/reconcile-at"
+
+// PausedCondition is the type of the condition reporting a paused resource.
+const PausedCondition = "Paused"
+
+// ReadyCondition is the type of the condition summarizing a resource for
+// GitOps tools and kubectl, mirroring its Configured condition, or its InSync
+// condition in observe mode, whichever was set last.
+const ReadyCondition = "Ready"
+
+// Types of the conditions mirrored by the Ready condition.
+const (
+	configuredCondition = "Configured"
+	inSyncCondition     = "InSync"
+)
+
+// Paused reports whether the reconciliation of a resource is paused.
+func Paused(annotations map[string]string) bool {
+	return annotations[PausedAnnotation] == "true"
+}
+
+// ReconcileRequested returns the value of the reconcile-at annotation, and
+// whether it differs from the last one handled, as recorded in the
+// reconcileRequest status field of the resource.
+func ReconcileRequested(annotations map[string]string, lastHandled string) (string, bool) {
+	requestedAt, ok := annotations[ReconcileAtAnnotation]
+	return requestedAt, ok && requestedAt != lastHandled
+}
+
+// SetPausedCondition sets the Paused condition of a paused resource, and
+// removes it otherwise, reporting whether the conditions changed.
+func SetPausedCondition(conditions *[]metav1.Condition, generation int64, paused bool) bool {
+	if !paused {
+		return meta.RemoveStatusCondition(conditions, PausedCondition)
+	}
+	return SetCondition(conditions, generation, metav1.Condition{Type: PausedCondition, Status: metav1.ConditionTrue, Reason: "Paused", Message: fmt.Sprintf("Reconciliation is paused by the %s annotation", PausedAnnotation)})
+}
+
+// SetCondition sets a condition of a resource, evaluated at its generation,
+// along with the Ready condition when it mirrors it, reporting whether the
+// conditions changed.
+func SetCondition(conditions *[]metav1.Condition, generation int64, condition metav1.Condition) bool {
+	condition.ObservedGeneration = generation
+	changed := meta.SetStatusCondition(conditions, condition)
+	if condition.Type == configuredCondition || condition.Type == inSyncCondition {
+		condition.Type = ReadyCondition
+		changed = meta.SetStatusCondition(conditions, condition) || changed
+	}
+	return changed
+}
+
+// Checksum returns the SHA-256 checksum of the JSON encoding of a payload
+// written to Vault, recorded in the status of its resource in place of the
+// payload. Maps are encoded with sorted keys, so the checksum only changes with
+// the payload. Being unkeyed, it would let the readers of the status check
+// guesses of a secret, so the fields holding secrets are left out of the
+// payload first, as Redacted does.
+func Checksum(payload interface{}) string {
+	// Payloads are sent to Vault as JSON, so they always encode
+	b, _ := json.Marshal(payload)
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// Redacted returns a copy of a payload written to Vault without the fields
+// holding secrets, to be checksummed in its place.
+func Redacted(payload map[string]interface{}, keys ...string) map[string]interface{} {
+	redacted := maps.Clone(payload)
+	for _, k := range keys {
+		delete(redacted, k)
+	}
+	return redacted
+}
@@ -0,0 +1,14 @@
+package vault_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestVault(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Vault Suite")
+}
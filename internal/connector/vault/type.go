@@ -1,8 +1,23 @@
 package vault
 
 import (
-	"hopopops/vault-operator/api/auth/v1beta1"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"k8s.io/utils/ptr"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	databasev1beta1 "hopopops/vault-operator/api/database/v1beta1"
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	kvv1beta1 "hopopops/vault-operator/api/kv/v1beta1"
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	sshv1beta1 "hopopops/vault-operator/api/ssh/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	transitv1beta1 "hopopops/vault-operator/api/transit/v1beta1"
 )
 
 type Policy struct {
@@ -10,6 +25,118 @@ type Policy struct {
 	Policy string `json:"policy"`
 }
 
+// PasswordPolicy is a password policy as returned by
+// sys/policies/password/<name>.
+type PasswordPolicy struct {
+	Policy string `json:"policy"`
+}
+
+// IsDifferentFromSpec reports whether the policy differs from the document
+// rendered from the spec.
+func (p *PasswordPolicy) IsDifferentFromSpec(s *sysv1beta1.PasswordPolicySpec) bool {
+	return p.Policy != PasswordPolicyDocument(s)
+}
+
+// PasswordPolicyDocument returns the HCL of a password policy, either as
+// provided or rendered from the length and rules of the spec.
+func PasswordPolicyDocument(s *sysv1beta1.PasswordPolicySpec) string {
+	if s.Policy != "" {
+		return s.Policy
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "length = %d\n", s.Length)
+	for _, rule := range s.Rules {
+		fmt.Fprintf(&b, "\nrule \"charset\" {\n  charset = %s\n", strconv.Quote(rule.Charset))
+		if rule.MinChars > 0 {
+			fmt.Fprintf(&b, "  min-chars = %d\n", rule.MinChars)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// RateLimitQuota is a quota as returned by sys/quotas/rate-limit/<name>.
+type RateLimitQuota struct {
+	Path          string  `json:"path"`
+	Role          string  `json:"role"`
+	Rate          float64 `json:"rate"`
+	Interval      int     `json:"interval"`
+	BlockInterval int     `json:"block_interval"`
+}
+
+// IsDifferentFromSpec reports whether the quota differs from the spec, the
+// path and role being resolved from the scope.
+func (q *RateLimitQuota) IsDifferentFromSpec(s *sysv1beta1.RateLimitQuotaSpec, path, role string) bool {
+	return isDifferentQuotaPath(q.Path, path) ||
+		q.Role != role ||
+		q.Rate != float64(s.Rate) ||
+		isDifferentTTL(q.Interval, &s.Interval) ||
+		isDifferentTTL(q.BlockInterval, &s.BlockInterval)
+}
+
+// LeaseCountQuota is a quota as returned by sys/quotas/lease-count/<name>.
+type LeaseCountQuota struct {
+	Path      string `json:"path"`
+	Role      string `json:"role"`
+	MaxLeases int    `json:"max_leases"`
+}
+
+// IsDifferentFromSpec reports whether the quota differs from the spec, the
+// path and role being resolved from the scope.
+func (q *LeaseCountQuota) IsDifferentFromSpec(s *sysv1beta1.LeaseCountQuotaSpec, path, role string) bool {
+	return isDifferentQuotaPath(q.Path, path) ||
+		q.Role != role ||
+		q.MaxLeases != s.MaxLeases
+}
+
+// isDifferentQuotaPath compares quota paths, Vault adding a trailing slash to
+// mount paths.
+func isDifferentQuotaPath(actual, desired string) bool {
+	return strings.TrimSuffix(actual, "/") != strings.TrimSuffix(desired, "/")
+}
+
+// Plugin is a plugin as returned by sys/plugins/catalog/<type>/<name>. The
+// environment is not returned by Vault.
+type Plugin struct {
+	Name    string   `json:"name"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	SHA256  string   `json:"sha256"`
+	Version string   `json:"version"`
+	Builtin bool     `json:"builtin"`
+}
+
+// IsDifferentFromSpec reports whether the registered plugin differs from the
+// spec.
+func (p *Plugin) IsDifferentFromSpec(s *sysv1beta1.PluginSpec) bool {
+	return p.Command != s.Command ||
+		p.SHA256 != s.SHA256 ||
+		isDifferentList(p.Args, s.Args)
+}
+
+// PluginCatalog is the catalog as returned by sys/plugins/catalog.
+type PluginCatalog struct {
+	Detailed []struct {
+		Name    string `json:"name"`
+		Type    string `json:"type"`
+		Version string `json:"version"`
+		Builtin bool   `json:"builtin"`
+	} `json:"detailed"`
+}
+
+// VaultNamespace is a namespace as returned by sys/namespaces/<path>.
+type VaultNamespace struct {
+	ID             string            `json:"id"`
+	Path           string            `json:"path"`
+	CustomMetadata map[string]string `json:"custom_metadata"`
+}
+
+// IsDifferentFromSpec reports whether the namespace differs from the spec.
+func (n *VaultNamespace) IsDifferentFromSpec(s *sysv1beta1.VaultNamespaceSpec) bool {
+	return isDifferentMap(n.CustomMetadata, s.CustomMetadata)
+}
+
 type KubernetesRole struct {
 	BoundServiceAccountNames      []string `json:"bound_service_account_names"`
 	BoundServiceAccountNamespaces []string `json:"bound_service_account_namespaces,omitempty"`
@@ -26,7 +153,7 @@ type KubernetesRole struct {
 	TokenType                     string   `json:"token_type,omitempty"`
 }
 
-func (k *KubernetesRole) IsDifferentFromSpec(s *v1beta1.KubernetesRoleSpec) bool {
+func (k *KubernetesRole) IsDifferentFromSpec(s *authv1beta1.KubernetesRoleSpec) bool {
 	return !reflect.DeepEqual(k.BoundServiceAccountNames, s.BoundServiceAccountNames) ||
 		!reflect.DeepEqual(k.BoundServiceAccountNamespaces, s.BoundServiceAccountNamespaces) ||
 		!reflect.DeepEqual(k.TokenPolicies, s.TokenPolicies) ||
@@ -41,3 +168,713 @@ func (k *KubernetesRole) IsDifferentFromSpec(s *v1beta1.KubernetesRoleSpec) bool
 		k.TokenPeriod != s.TokenPeriod ||
 		k.TokenType != s.TokenType
 }
+
+// ToSpec returns the spec of a resource managing the role in the auth method
+// at authPath, mapping the fields back from their Vault names.
+func (k *KubernetesRole) ToSpec(authPath string) authv1beta1.KubernetesRoleSpec {
+	return authv1beta1.KubernetesRoleSpec{
+		BoundServiceAccountNames:      k.BoundServiceAccountNames,
+		BoundServiceAccountNamespaces: k.BoundServiceAccountNamespaces,
+		Audience:                      k.Audience,
+		AliasNameSource:               k.AliasNameSource,
+		TokenTTL:                      k.TokenTTL,
+		TokenMaxTTL:                   k.TokenMaxTTL,
+		TokenPolicies:                 k.TokenPolicies,
+		TokenBoundCIDRs:               k.TokenBoundCIDRs,
+		TokenExplicitMaxTTL:           k.TokenExplicitMaxTTL,
+		TokenNoDefaultPolicy:          k.TokenNoDefaultPolicy,
+		TokenNumUses:                  k.TokenNumUses,
+		TokenPeriod:                   k.TokenPeriod,
+		TokenType:                     k.TokenType,
+		AuthPath:                      authPath,
+	}
+}
+
+// SecretEngine is a secrets engine mount as returned by sys/mounts/<path>.
+type SecretEngine vaultapi.MountOutput
+
+// IsDifferentFromSpec reports whether the tunable settings of the mount differ
+// from the spec. Settings left unset in the spec are not compared.
+func (m *SecretEngine) IsDifferentFromSpec(s *sysv1beta1.SecretEngineSpec) bool {
+	for k, v := range s.Options {
+		if m.Options[k] != v {
+			return true
+		}
+	}
+
+	return m.Description != s.Description ||
+		isDifferentTTL(m.Config.DefaultLeaseTTL, s.Config.DefaultLeaseTTL) ||
+		isDifferentTTL(m.Config.MaxLeaseTTL, s.Config.MaxLeaseTTL) ||
+		isDifferentList(m.Config.AuditNonHMACRequestKeys, s.Config.AuditNonHMACRequestKeys) ||
+		isDifferentList(m.Config.AuditNonHMACResponseKeys, s.Config.AuditNonHMACResponseKeys) ||
+		isDifferentList(m.Config.PassthroughRequestHeaders, s.Config.PassthroughRequestHeaders) ||
+		isDifferentList(m.Config.AllowedResponseHeaders, s.Config.AllowedResponseHeaders) ||
+		isDifferentString(m.Config.ListingVisibility, s.Config.ListingVisibility) ||
+		isDifferentString(m.PluginVersion, s.Config.PluginVersion) ||
+		isDifferentString(m.Config.IdentityTokenKey, s.Config.IdentityTokenKey)
+}
+
+// ToSpec returns the spec of a resource managing the mount at the path. Unset
+// settings are left out of the spec, for Vault to keep its defaults.
+func (m *SecretEngine) ToSpec(path string) sysv1beta1.SecretEngineSpec {
+	s := sysv1beta1.SecretEngineSpec{
+		Type:                  m.Type,
+		Path:                  path,
+		Description:           m.Description,
+		Options:               m.Options,
+		Local:                 m.Local,
+		SealWrap:              m.SealWrap,
+		ExternalEntropyAccess: m.ExternalEntropyAccess,
+		Config: sysv1beta1.SecretEngineConfig{
+			AuditNonHMACRequestKeys:   m.Config.AuditNonHMACRequestKeys,
+			AuditNonHMACResponseKeys:  m.Config.AuditNonHMACResponseKeys,
+			PassthroughRequestHeaders: m.Config.PassthroughRequestHeaders,
+			AllowedResponseHeaders:    m.Config.AllowedResponseHeaders,
+		},
+	}
+	if m.Config.DefaultLeaseTTL != 0 {
+		s.Config.DefaultLeaseTTL = ptr.To(fmt.Sprintf("%ds", m.Config.DefaultLeaseTTL))
+	}
+	if m.Config.MaxLeaseTTL != 0 {
+		s.Config.MaxLeaseTTL = ptr.To(fmt.Sprintf("%ds", m.Config.MaxLeaseTTL))
+	}
+	if m.Config.ListingVisibility != "" {
+		s.Config.ListingVisibility = ptr.To(m.Config.ListingVisibility)
+	}
+	if m.PluginVersion != "" {
+		s.Config.PluginVersion = ptr.To(m.PluginVersion)
+	}
+	if m.Config.IdentityTokenKey != "" {
+		s.Config.IdentityTokenKey = ptr.To(m.Config.IdentityTokenKey)
+	}
+	return s
+}
+
+// AuditDevice is an audit device as returned by sys/audit.
+type AuditDevice vaultapi.Audit
+
+// IsDifferentFromSpec reports whether the audit device differs from the spec.
+// Audit devices cannot be tuned, so any difference requires a replacement.
+func (a *AuditDevice) IsDifferentFromSpec(s *sysv1beta1.AuditDeviceSpec) bool {
+	return a.Type != s.Type ||
+		a.Description != s.Description ||
+		a.Local != s.Local ||
+		!(len(a.Options) == 0 && len(s.Options) == 0) && !reflect.DeepEqual(a.Options, s.Options)
+}
+
+// ToSpec returns the spec of a resource managing the audit device at the
+// path.
+func (a *AuditDevice) ToSpec(path string) sysv1beta1.AuditDeviceSpec {
+	return sysv1beta1.AuditDeviceSpec{
+		Type:        a.Type,
+		Path:        path,
+		Description: a.Description,
+		Local:       a.Local,
+		Options:     a.Options,
+	}
+}
+
+// KVVersionMetadata describes one version of a KV v2 secret.
+type KVVersionMetadata struct {
+	CreatedTime  string `json:"created_time"`
+	DeletionTime string `json:"deletion_time"`
+	Destroyed    bool   `json:"destroyed"`
+}
+
+// KVMetadata is the metadata of a KV v2 secret as returned by
+// <mount>/metadata/<path>.
+type KVMetadata struct {
+	CurrentVersion int                          `json:"current_version"`
+	OldestVersion  int                          `json:"oldest_version"`
+	MaxVersions    int                          `json:"max_versions"`
+	CASRequired    bool                         `json:"cas_required"`
+	CustomMetadata map[string]string            `json:"custom_metadata"`
+	Versions       map[string]KVVersionMetadata `json:"versions,omitempty"`
+}
+
+// IsDifferentFromSpec reports whether the settings of the secret differ from
+// the spec.
+func (m *KVMetadata) IsDifferentFromSpec(s *kvv1beta1.KVSecretSpec) bool {
+	return m.MaxVersions != s.MaxVersions ||
+		m.CASRequired != s.CASRequired ||
+		!(len(m.CustomMetadata) == 0 && len(s.CustomMetadata) == 0) && !reflect.DeepEqual(m.CustomMetadata, s.CustomMetadata)
+}
+
+// KVData is the data of a KV secret.
+type KVData map[string]interface{}
+
+// IsDifferentFrom reports whether the data differs from the desired values.
+func (d KVData) IsDifferentFrom(desired map[string]string) bool {
+	if len(d) != len(desired) {
+		return true
+	}
+	for k, v := range desired {
+		actual, ok := d[k]
+		if !ok || fmt.Sprint(actual) != v {
+			return true
+		}
+	}
+	return false
+}
+
+// DatabaseConnection is a connection of the database secrets engine as
+// returned by <mount>/config/<name>. Vault never returns the password.
+type DatabaseConnection struct {
+	PluginName             string                 `json:"plugin_name"`
+	PluginVersion          string                 `json:"plugin_version"`
+	ConnectionDetails      map[string]interface{} `json:"connection_details"`
+	AllowedRoles           []string               `json:"allowed_roles"`
+	RootRotationStatements []string               `json:"root_credentials_rotate_statements"`
+	PasswordPolicy         string                 `json:"password_policy"`
+	VerifyConnection       bool                   `json:"verify_connection"`
+}
+
+// IsDifferentFromSpec reports whether the connection differs from the spec and
+// the given root username.
+func (c *DatabaseConnection) IsDifferentFromSpec(s *databasev1beta1.DatabaseConnectionSpec, username string) bool {
+	for k, v := range s.Options {
+		if fmt.Sprint(c.ConnectionDetails[k]) != v {
+			return true
+		}
+	}
+
+	return c.PluginName != s.PluginName ||
+		c.PluginVersion != s.PluginVersion ||
+		fmt.Sprint(c.ConnectionDetails["connection_url"]) != s.ConnectionURL ||
+		fmt.Sprint(c.ConnectionDetails["username"]) != username ||
+		!(len(c.AllowedRoles) == 0 && len(s.AllowedRoles) == 0) && !reflect.DeepEqual(c.AllowedRoles, s.AllowedRoles) ||
+		!(len(c.RootRotationStatements) == 0 && len(s.RootRotationStatements) == 0) && !reflect.DeepEqual(c.RootRotationStatements, s.RootRotationStatements) ||
+		c.PasswordPolicy != s.PasswordPolicy
+}
+
+// DatabaseRole is a role of the database secrets engine as returned by
+// <mount>/roles/<name>.
+type DatabaseRole struct {
+	DBName               string                 `json:"db_name"`
+	CreationStatements   []string               `json:"creation_statements"`
+	RevocationStatements []string               `json:"revocation_statements"`
+	RollbackStatements   []string               `json:"rollback_statements"`
+	RenewStatements      []string               `json:"renew_statements"`
+	DefaultTTL           int                    `json:"default_ttl"`
+	MaxTTL               int                    `json:"max_ttl"`
+	CredentialType       string                 `json:"credential_type"`
+	CredentialConfig     map[string]interface{} `json:"credential_config"`
+}
+
+// IsDifferentFromSpec reports whether the role differs from the spec.
+func (r *DatabaseRole) IsDifferentFromSpec(s *databasev1beta1.DatabaseRoleSpec) bool {
+	return r.DBName != s.DBName ||
+		!reflect.DeepEqual(r.CreationStatements, s.CreationStatements) ||
+		!(len(r.RevocationStatements) == 0 && len(s.RevocationStatements) == 0) && !reflect.DeepEqual(r.RevocationStatements, s.RevocationStatements) ||
+		!(len(r.RollbackStatements) == 0 && len(s.RollbackStatements) == 0) && !reflect.DeepEqual(r.RollbackStatements, s.RollbackStatements) ||
+		!(len(r.RenewStatements) == 0 && len(s.RenewStatements) == 0) && !reflect.DeepEqual(r.RenewStatements, s.RenewStatements) ||
+		isDifferentTTL(r.DefaultTTL, &s.DefaultTTL) ||
+		isDifferentTTL(r.MaxTTL, &s.MaxTTL) ||
+		r.CredentialType != s.CredentialType ||
+		KVData(r.CredentialConfig).IsDifferentFrom(s.CredentialConfig)
+}
+
+// DatabaseStaticRole is a static role of the database secrets engine as
+// returned by <mount>/static-roles/<name>.
+type DatabaseStaticRole struct {
+	DBName             string   `json:"db_name"`
+	Username           string   `json:"username"`
+	RotationPeriod     int      `json:"rotation_period,omitempty"`
+	RotationSchedule   string   `json:"rotation_schedule,omitempty"`
+	RotationWindow     int      `json:"rotation_window,omitempty"`
+	RotationStatements []string `json:"rotation_statements"`
+	CredentialType     string   `json:"credential_type"`
+	LastVaultRotation  string   `json:"last_vault_rotation,omitempty"`
+}
+
+// IsDifferentFromSpec reports whether the static role differs from the spec.
+func (r *DatabaseStaticRole) IsDifferentFromSpec(s *databasev1beta1.DatabaseStaticRoleSpec) bool {
+	return r.DBName != s.DBName ||
+		r.Username != s.Username ||
+		isDifferentTTL(r.RotationPeriod, &s.RotationPeriod) ||
+		r.RotationSchedule != s.RotationSchedule ||
+		isDifferentTTL(r.RotationWindow, &s.RotationWindow) ||
+		!(len(r.RotationStatements) == 0 && len(s.RotationStatements) == 0) && !reflect.DeepEqual(r.RotationStatements, s.RotationStatements) ||
+		r.CredentialType != s.CredentialType
+}
+
+// PKIRole is a role of the PKI secrets engine as returned by
+// <mount>/roles/<name>.
+type PKIRole struct {
+	IssuerRef        string   `json:"issuer_ref"`
+	AllowedDomains   []string `json:"allowed_domains"`
+	AllowBareDomains bool     `json:"allow_bare_domains"`
+	AllowSubdomains  bool     `json:"allow_subdomains"`
+	AllowGlobDomains bool     `json:"allow_glob_domains"`
+	AllowAnyName     bool     `json:"allow_any_name"`
+	AllowLocalhost   bool     `json:"allow_localhost"`
+	AllowIPSANs      bool     `json:"allow_ip_sans"`
+	ServerFlag       bool     `json:"server_flag"`
+	ClientFlag       bool     `json:"client_flag"`
+	KeyType          string   `json:"key_type"`
+	KeyBits          int      `json:"key_bits"`
+	TTL              int      `json:"ttl"`
+	MaxTTL           int      `json:"max_ttl"`
+	KeyUsage         []string `json:"key_usage"`
+	ExtKeyUsage      []string `json:"ext_key_usage"`
+}
+
+// DefaultPKIKeyUsage is the key usage Vault sets on roles that do not specify
+// one.
+var DefaultPKIKeyUsage = []string{"DigitalSignature", "KeyAgreement", "KeyEncipherment"}
+
+// DefaultPKIKeyBits returns the key size Vault stores for a key type when none
+// is provided.
+func DefaultPKIKeyBits(keyType string) int {
+	switch keyType {
+	case "rsa":
+		return 2048
+	case "ec":
+		return 256
+	default:
+		return 0
+	}
+}
+
+// IsDifferentFromSpec reports whether the role differs from the spec.
+func (r *PKIRole) IsDifferentFromSpec(s *pkiv1beta1.PKIRoleSpec) bool {
+	issuerRef := s.IssuerRef
+	if issuerRef == "" {
+		issuerRef = "default"
+	}
+	keyBits := s.KeyBits
+	if keyBits == 0 {
+		keyBits = DefaultPKIKeyBits(s.KeyType)
+	}
+	keyUsage := s.KeyUsage
+	if keyUsage == nil {
+		keyUsage = DefaultPKIKeyUsage
+	}
+
+	return r.IssuerRef != issuerRef ||
+		isDifferentList(r.AllowedDomains, emptyIfNil(s.AllowedDomains)) ||
+		r.AllowBareDomains != s.AllowBareDomains ||
+		r.AllowSubdomains != s.AllowSubdomains ||
+		r.AllowGlobDomains != s.AllowGlobDomains ||
+		r.AllowAnyName != s.AllowAnyName ||
+		r.AllowLocalhost != boolOrDefault(s.AllowLocalhost, true) ||
+		r.AllowIPSANs != boolOrDefault(s.AllowIPSANs, true) ||
+		r.ServerFlag != boolOrDefault(s.ServerFlag, true) ||
+		r.ClientFlag != boolOrDefault(s.ClientFlag, true) ||
+		r.KeyType != s.KeyType ||
+		r.KeyBits != keyBits ||
+		isDifferentTTL(r.TTL, &s.TTL) ||
+		isDifferentTTL(r.MaxTTL, &s.MaxTTL) ||
+		isDifferentList(r.KeyUsage, keyUsage) ||
+		isDifferentList(r.ExtKeyUsage, emptyIfNil(s.ExtKeyUsage))
+}
+
+// PKIIssuer is an issuer of the PKI secrets engine as returned by
+// <mount>/issuer/<ref>.
+type PKIIssuer struct {
+	IssuerID             string   `json:"issuer_id"`
+	IssuerName           string   `json:"issuer_name"`
+	KeyID                string   `json:"key_id"`
+	Certificate          string   `json:"certificate"`
+	CAChain              []string `json:"ca_chain"`
+	LeafNotAfterBehavior string   `json:"leaf_not_after_behavior"`
+}
+
+// IsDifferentFromSpec reports whether the settings of the issuer that can
+// change after its creation differ from the spec.
+func (i *PKIIssuer) IsDifferentFromSpec(s *pkiv1beta1.PKIIssuerSpec) bool {
+	return i.LeafNotAfterBehavior != s.LeafNotAfterBehavior
+}
+
+// PKIURLs is the URL configuration of the PKI secrets engine as returned by
+// <mount>/config/urls.
+type PKIURLs struct {
+	IssuingCertificates   []string `json:"issuing_certificates"`
+	CRLDistributionPoints []string `json:"crl_distribution_points"`
+	OCSPServers           []string `json:"ocsp_servers"`
+	EnableTemplating      bool     `json:"enable_templating"`
+}
+
+// IsDifferentFromSpec reports whether the URLs differ from the spec.
+func (u *PKIURLs) IsDifferentFromSpec(s *pkiv1beta1.PKIURLs) bool {
+	return isDifferentList(u.IssuingCertificates, emptyIfNil(s.IssuingCertificates)) ||
+		isDifferentList(u.CRLDistributionPoints, emptyIfNil(s.CRLDistributionPoints)) ||
+		isDifferentList(u.OCSPServers, emptyIfNil(s.OCSPServers)) ||
+		u.EnableTemplating != s.EnableTemplating
+}
+
+// PKICRL is the CRL configuration of the PKI secrets engine as returned by
+// <mount>/config/crl. Durations are returned as provided, e.g. "72h".
+type PKICRL struct {
+	Expiry                 string `json:"expiry"`
+	Disable                bool   `json:"disable"`
+	OCSPDisable            bool   `json:"ocsp_disable"`
+	AutoRebuild            bool   `json:"auto_rebuild"`
+	AutoRebuildGracePeriod string `json:"auto_rebuild_grace_period"`
+	EnableDelta            bool   `json:"enable_delta"`
+	DeltaRebuildInterval   string `json:"delta_rebuild_interval"`
+}
+
+// IsDifferentFromSpec reports whether the CRL configuration differs from the
+// spec.
+func (c *PKICRL) IsDifferentFromSpec(s *pkiv1beta1.PKICRL) bool {
+	return isDifferentDuration(c.Expiry, s.Expiry) ||
+		c.Disable != s.Disable ||
+		c.OCSPDisable != s.OCSPDisable ||
+		c.AutoRebuild != s.AutoRebuild ||
+		isDifferentDuration(c.AutoRebuildGracePeriod, s.AutoRebuildGracePeriod) ||
+		c.EnableDelta != s.EnableDelta ||
+		isDifferentDuration(c.DeltaRebuildInterval, s.DeltaRebuildInterval)
+}
+
+// PKIAutoTidy is the auto-tidy configuration of the PKI secrets engine as
+// returned by <mount>/config/auto-tidy.
+type PKIAutoTidy struct {
+	Enabled            bool `json:"enabled"`
+	IntervalDuration   int  `json:"interval_duration"`
+	TidyCertStore      bool `json:"tidy_cert_store"`
+	TidyRevokedCerts   bool `json:"tidy_revoked_certs"`
+	TidyExpiredIssuers bool `json:"tidy_expired_issuers"`
+	SafetyBuffer       int  `json:"safety_buffer"`
+}
+
+// IsDifferentFromSpec reports whether the auto-tidy configuration differs
+// from the spec.
+func (t *PKIAutoTidy) IsDifferentFromSpec(s *pkiv1beta1.PKIAutoTidy) bool {
+	return t.Enabled != s.Enabled ||
+		isDifferentTTL(t.IntervalDuration, &s.Interval) ||
+		t.TidyCertStore != s.TidyCertStore ||
+		t.TidyRevokedCerts != s.TidyRevokedCerts ||
+		t.TidyExpiredIssuers != s.TidyExpiredIssuers ||
+		isDifferentTTL(t.SafetyBuffer, &s.SafetyBuffer)
+}
+
+// SSHRole is a role of the SSH secrets engine as returned by
+// <mount>/roles/<name>. Vault returns lists as comma separated strings.
+type SSHRole struct {
+	KeyType                string            `json:"key_type"`
+	AllowUserCertificates  bool              `json:"allow_user_certificates"`
+	AllowHostCertificates  bool              `json:"allow_host_certificates"`
+	AllowedUsers           string            `json:"allowed_users"`
+	AllowedUsersTemplate   bool              `json:"allowed_users_template"`
+	DefaultUser            string            `json:"default_user"`
+	AllowedDomains         string            `json:"allowed_domains"`
+	AllowSubdomains        bool              `json:"allow_subdomains"`
+	AllowBareDomains       bool              `json:"allow_bare_domains"`
+	AllowedExtensions      string            `json:"allowed_extensions"`
+	DefaultExtensions      map[string]string `json:"default_extensions"`
+	AllowedCriticalOptions string            `json:"allowed_critical_options"`
+	DefaultCriticalOptions map[string]string `json:"default_critical_options"`
+	TTL                    int               `json:"ttl"`
+	MaxTTL                 int               `json:"max_ttl"`
+	AlgorithmSigner        string            `json:"algorithm_signer"`
+}
+
+// IsDifferentFromSpec reports whether the role differs from the spec.
+func (r *SSHRole) IsDifferentFromSpec(s *sshv1beta1.SSHRoleSpec) bool {
+	return r.KeyType != "ca" ||
+		r.AllowUserCertificates != s.AllowUserCertificates ||
+		r.AllowHostCertificates != s.AllowHostCertificates ||
+		isDifferentList(SplitList(r.AllowedUsers), emptyIfNil(s.AllowedUsers)) ||
+		r.AllowedUsersTemplate != s.AllowedUsersTemplate ||
+		r.DefaultUser != s.DefaultUser ||
+		isDifferentList(SplitList(r.AllowedDomains), emptyIfNil(s.AllowedDomains)) ||
+		r.AllowSubdomains != s.AllowSubdomains ||
+		r.AllowBareDomains != s.AllowBareDomains ||
+		isDifferentList(SplitList(r.AllowedExtensions), emptyIfNil(s.AllowedExtensions)) ||
+		isDifferentMap(r.DefaultExtensions, s.DefaultExtensions) ||
+		isDifferentList(SplitList(r.AllowedCriticalOptions), emptyIfNil(s.AllowedCriticalOptions)) ||
+		isDifferentMap(r.DefaultCriticalOptions, s.DefaultCriticalOptions) ||
+		isDifferentTTL(r.TTL, &s.TTL) ||
+		isDifferentTTL(r.MaxTTL, &s.MaxTTL) ||
+		r.AlgorithmSigner != s.AlgorithmSigner
+}
+
+// TransitKey is a key of the transit secrets engine as returned by
+// <mount>/keys/<name>.
+type TransitKey struct {
+	Type                 string `json:"type"`
+	Exportable           bool   `json:"exportable"`
+	AllowPlaintextBackup bool   `json:"allow_plaintext_backup"`
+	DeletionAllowed      bool   `json:"deletion_allowed"`
+	AutoRotatePeriod     int    `json:"auto_rotate_period"`
+	MinDecryptionVersion int    `json:"min_decryption_version"`
+	MinEncryptionVersion int    `json:"min_encryption_version"`
+	LatestVersion        int    `json:"latest_version"`
+}
+
+// IsDifferentFromSpec reports whether the configuration of the key differs
+// from the spec. The type cannot be changed and is not compared.
+func (k *TransitKey) IsDifferentFromSpec(s *transitv1beta1.TransitKeySpec) bool {
+	return k.Exportable != s.Exportable ||
+		k.AllowPlaintextBackup != s.AllowPlaintextBackup ||
+		k.DeletionAllowed != s.DeletionAllowed ||
+		isDifferentTTL(k.AutoRotatePeriod, &s.AutoRotatePeriod) ||
+		k.MinDecryptionVersion != max(s.MinDecryptionVersion, 1) ||
+		k.MinEncryptionVersion != s.MinEncryptionVersion
+}
+
+// IdentityEntity is an entity as returned by identity/entity/name/<name>.
+type IdentityEntity struct {
+	ID       string                `json:"id"`
+	Name     string                `json:"name"`
+	Policies []string              `json:"policies"`
+	Metadata map[string]string     `json:"metadata"`
+	Disabled bool                  `json:"disabled"`
+	Aliases  []IdentityEntityAlias `json:"aliases"`
+}
+
+// IsDifferentFromSpec reports whether the entity differs from the spec.
+func (e *IdentityEntity) IsDifferentFromSpec(s *identityv1beta1.IdentityEntitySpec) bool {
+	return isDifferentList(emptyIfNil(e.Policies), emptyIfNil(s.Policies)) ||
+		isDifferentMap(e.Metadata, s.Metadata) ||
+		e.Disabled != s.Disabled
+}
+
+// IdentityEntityAlias is an entity alias as returned by
+// identity/entity-alias/id/<id>.
+type IdentityEntityAlias struct {
+	ID             string            `json:"id"`
+	CanonicalID    string            `json:"canonical_id"`
+	Name           string            `json:"name"`
+	MountAccessor  string            `json:"mount_accessor"`
+	CustomMetadata map[string]string `json:"custom_metadata"`
+}
+
+// IsDifferentFromSpec reports whether the alias differs from the spec, the
+// entity and the accessor of the auth method being resolved from references.
+func (a *IdentityEntityAlias) IsDifferentFromSpec(s *identityv1beta1.IdentityEntityAliasSpec, canonicalID, mountAccessor string) bool {
+	return a.Name != s.Name ||
+		a.CanonicalID != canonicalID ||
+		a.MountAccessor != mountAccessor ||
+		isDifferentMap(a.CustomMetadata, s.CustomMetadata)
+}
+
+// IdentityGroup is a group as returned by identity/group/name/<name>.
+type IdentityGroup struct {
+	ID              string              `json:"id"`
+	Name            string              `json:"name"`
+	Type            string              `json:"type"`
+	Policies        []string            `json:"policies"`
+	Metadata        map[string]string   `json:"metadata"`
+	MemberEntityIDs []string            `json:"member_entity_ids"`
+	MemberGroupIDs  []string            `json:"member_group_ids"`
+	Alias           *IdentityGroupAlias `json:"alias"`
+}
+
+// IsDifferentFromSpec reports whether the group differs from the spec, the
+// IDs of the members being resolved from references. Members of external
+// groups are managed by Vault and not compared.
+func (g *IdentityGroup) IsDifferentFromSpec(s *identityv1beta1.IdentityGroupSpec, memberEntityIDs, memberGroupIDs []string) bool {
+	if isDifferentList(emptyIfNil(g.Policies), emptyIfNil(s.Policies)) ||
+		isDifferentMap(g.Metadata, s.Metadata) {
+		return true
+	}
+	if g.Type == "external" {
+		return false
+	}
+	return isDifferentList(emptyIfNil(g.MemberEntityIDs), emptyIfNil(memberEntityIDs)) ||
+		isDifferentList(emptyIfNil(g.MemberGroupIDs), emptyIfNil(memberGroupIDs))
+}
+
+// IdentityGroupAlias is a group alias as returned by
+// identity/group-alias/id/<id>.
+type IdentityGroupAlias struct {
+	ID            string `json:"id"`
+	CanonicalID   string `json:"canonical_id"`
+	Name          string `json:"name"`
+	MountAccessor string `json:"mount_accessor"`
+}
+
+// IsDifferentFromSpec reports whether the alias differs from the spec, the
+// group and the accessor of the auth method being resolved from references.
+func (a *IdentityGroupAlias) IsDifferentFromSpec(s *identityv1beta1.IdentityGroupAliasSpec, canonicalID, mountAccessor string) bool {
+	return a.Name != s.Name ||
+		a.CanonicalID != canonicalID ||
+		a.MountAccessor != mountAccessor
+}
+
+// IdentityOIDCKey is a key as returned by identity/oidc/key/<name>.
+type IdentityOIDCKey struct {
+	RotationPeriod   int      `json:"rotation_period"`
+	VerificationTTL  int      `json:"verification_ttl"`
+	AllowedClientIDs []string `json:"allowed_client_ids"`
+	Algorithm        string   `json:"algorithm"`
+}
+
+// IsDifferentFromSpec reports whether the key differs from the spec.
+func (k *IdentityOIDCKey) IsDifferentFromSpec(s *identityv1beta1.IdentityOIDCKeySpec) bool {
+	return isDifferentTTL(k.RotationPeriod, &s.RotationPeriod) ||
+		isDifferentTTL(k.VerificationTTL, &s.VerificationTTL) ||
+		isDifferentList(emptyIfNil(k.AllowedClientIDs), emptyIfNil(s.AllowedClientIDs)) ||
+		k.Algorithm != s.Algorithm
+}
+
+// IdentityOIDCRole is a role as returned by identity/oidc/role/<name>.
+type IdentityOIDCRole struct {
+	Key      string `json:"key"`
+	Template string `json:"template"`
+	TTL      int    `json:"ttl"`
+	ClientID string `json:"client_id"`
+}
+
+// IsDifferentFromSpec reports whether the role differs from the spec.
+func (r *IdentityOIDCRole) IsDifferentFromSpec(s *identityv1beta1.IdentityOIDCRoleSpec) bool {
+	return r.Key != s.Key ||
+		r.Template != s.Template ||
+		isDifferentTTL(r.TTL, &s.TTL)
+}
+
+// OIDCProvider is a provider as returned by identity/oidc/provider/<name>.
+// The issuer includes the path of the provider.
+type OIDCProvider struct {
+	Issuer           string   `json:"issuer"`
+	AllowedClientIDs []string `json:"allowed_client_ids"`
+	ScopesSupported  []string `json:"scopes_supported"`
+}
+
+// IsDifferentFromSpec reports whether the provider differs from the spec, the
+// client IDs being resolved from references.
+func (p *OIDCProvider) IsDifferentFromSpec(s *identityv1beta1.OIDCProviderSpec, allowedClientIDs []string) bool {
+	return (s.Issuer != "" && !strings.HasPrefix(p.Issuer, strings.TrimSuffix(s.Issuer, "/")+"/v1/")) ||
+		isDifferentList(emptyIfNil(p.AllowedClientIDs), emptyIfNil(allowedClientIDs)) ||
+		isDifferentList(emptyIfNil(p.ScopesSupported), emptyIfNil(s.ScopesSupported))
+}
+
+// OIDCClient is a client as returned by identity/oidc/client/<name>.
+type OIDCClient struct {
+	Key            string   `json:"key"`
+	RedirectURIs   []string `json:"redirect_uris"`
+	Assignments    []string `json:"assignments"`
+	ClientType     string   `json:"client_type"`
+	IDTokenTTL     int      `json:"id_token_ttl"`
+	AccessTokenTTL int      `json:"access_token_ttl"`
+	ClientID       string   `json:"client_id"`
+	ClientSecret   string   `json:"client_secret"`
+}
+
+// IsDifferentFromSpec reports whether the client differs from the spec. The
+// client type cannot be changed and is not compared.
+func (c *OIDCClient) IsDifferentFromSpec(s *identityv1beta1.OIDCClientSpec) bool {
+	return c.Key != s.Key ||
+		isDifferentList(emptyIfNil(c.RedirectURIs), emptyIfNil(s.RedirectURIs)) ||
+		isDifferentList(emptyIfNil(c.Assignments), emptyIfNil(s.Assignments)) ||
+		isDifferentTTL(c.IDTokenTTL, &s.IDTokenTTL) ||
+		isDifferentTTL(c.AccessTokenTTL, &s.AccessTokenTTL)
+}
+
+// OIDCScope is a scope as returned by identity/oidc/scope/<name>.
+type OIDCScope struct {
+	Template    string `json:"template"`
+	Description string `json:"description"`
+}
+
+// IsDifferentFromSpec reports whether the scope differs from the spec.
+func (o *OIDCScope) IsDifferentFromSpec(s *identityv1beta1.OIDCScopeSpec) bool {
+	return o.Template != s.Template ||
+		o.Description != s.Description
+}
+
+// OIDCAssignment is an assignment as returned by
+// identity/oidc/assignment/<name>.
+type OIDCAssignment struct {
+	EntityIDs []string `json:"entity_ids"`
+	GroupIDs  []string `json:"group_ids"`
+}
+
+// IsDifferentFromSpec reports whether the assignment differs from the IDs of
+// the entities and groups resolved from references.
+func (a *OIDCAssignment) IsDifferentFromSpec(entityIDs, groupIDs []string) bool {
+	return isDifferentList(emptyIfNil(a.EntityIDs), emptyIfNil(entityIDs)) ||
+		isDifferentList(emptyIfNil(a.GroupIDs), emptyIfNil(groupIDs))
+}
+
+// ParseTTL converts a duration provided as "1h", "30d" or a number of seconds
+// to the number of seconds Vault reports it as.
+func ParseTTL(ttl string) (int, error) {
+	if ttl == "" {
+		return 0, nil
+	}
+
+	if seconds, err := strconv.Atoi(ttl); err == nil {
+		return seconds, nil
+	}
+
+	if days, ok := strings.CutSuffix(ttl, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return n * 24 * 60 * 60, nil
+	}
+
+	d, err := time.ParseDuration(ttl)
+	if err != nil {
+		return 0, err
+	}
+	return int(d.Seconds()), nil
+}
+
+func isDifferentTTL(actual int, desired *string) bool {
+	if desired == nil {
+		return false
+	}
+
+	seconds, err := ParseTTL(*desired)
+	// Let Vault report malformed values
+	return err != nil || seconds != actual
+}
+
+// isDifferentDuration compares durations Vault returns as provided, so that
+// "1h" and "60m" are equal.
+func isDifferentDuration(actual, desired string) bool {
+	a, err := ParseTTL(actual)
+	if err != nil {
+		return actual != desired
+	}
+	d, err := ParseTTL(desired)
+	return err != nil || a != d
+}
+
+func isDifferentList(actual, desired []string) bool {
+	if desired == nil {
+		return false
+	}
+
+	return !(len(actual) == 0 && len(desired) == 0) && !reflect.DeepEqual(actual, desired)
+}
+
+// isDifferentMap compares maps, an empty map being equal to nil.
+func isDifferentMap(actual, desired map[string]string) bool {
+	return !(len(actual) == 0 && len(desired) == 0) && !reflect.DeepEqual(actual, desired)
+}
+
+// SplitList splits a comma separated list the way Vault returns some of them.
+func SplitList(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+	values := strings.Split(list, ",")
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+func emptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func boolOrDefault(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
+
+func isDifferentString(actual string, desired *string) bool {
+	return desired != nil && actual != *desired
+}
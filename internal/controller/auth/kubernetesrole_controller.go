@@ -21,19 +21,20 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	vaultapi "github.com/hashicorp/vault/api"
-
 	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
 	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+	"hopopops/vault-operator/internal/plan"
 )
 
 const (
@@ -43,22 +44,33 @@ const (
 // Definitions to manage status conditions
 const (
 	typeConfiguredRole = "Configured"
+	typeInSyncRole     = "InSync"
 )
 
 // KubernetesRoleReconciler reconciles a KubernetesRole object
 type KubernetesRoleReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	Vault  *vaultapi.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
 }
 
 // +kubebuilder:rbac:groups=auth.toolkit.vault.hopopops.com,resources=kubernetesroles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=auth.toolkit.vault.hopopops.com,resources=kubernetesroles/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=auth.toolkit.vault.hopopops.com,resources=kubernetesroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
+// In observe mode, the role is only compared with the spec and the finalizer
+// leaves Vault untouched.
+//
+// A paused resource is not reconciled, and its finalizer leaves Vault
+// untouched. A new reconcile-at annotation pushes the role even when Vault
+// already matches it.
+//
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
 func (r *KubernetesRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -75,8 +87,22 @@ func (r *KubernetesRoleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	observe := vault.ResourceMode(r.Mode, role.Annotations) == vault.ModeObserve
+	paused := vault.Paused(role.Annotations)
+
+	if vault.SetPausedCondition(&role.Status.Conditions, role.Generation, paused) {
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update KubernetesRole status")
+			return ctrl.Result{}, err
+		}
+	}
+	if paused && role.ObjectMeta.DeletionTimestamp.IsZero() {
+		log.Info("KubernetesRole reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
 	if len(role.Status.Conditions) == 0 {
-		meta.SetStatusCondition(&role.Status.Conditions, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
 		if err := r.Status().Update(ctx, role); err != nil {
 			log.Error(err, "Failed to update KubernetesRole status")
 			return ctrl.Result{}, err
@@ -89,7 +115,7 @@ func (r *KubernetesRoleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	if role.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !controllerutil.ContainsFinalizer(role, roleFinalizer) {
+		if !controllerutil.ContainsFinalizer(role, roleFinalizer) && !observe {
 			// Initialize finalizer
 			controllerutil.AddFinalizer(role, roleFinalizer)
 			if err := r.Update(ctx, role); err != nil {
@@ -105,9 +131,12 @@ func (r *KubernetesRoleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	} else {
 		if controllerutil.ContainsFinalizer(role, roleFinalizer) {
 			// Delete managed resources for this KubernetesRole
-			if err := r.deleteVaultKubernetesRole(ctx, role); err != nil {
-				log.Error(err, "Failed to delete KubernetesRole")
-				return ctrl.Result{}, err
+			if !observe && !paused {
+				if err := r.deleteVaultKubernetesRole(ctx, role); err != nil {
+					log.Error(err, "Failed to delete KubernetesRole")
+					return ctrl.Result{}, err
+				}
+				events.Deleted(r.Recorder, role, kubernetesRolePath(role))
 			}
 
 			controllerutil.RemoveFinalizer(role, roleFinalizer)
@@ -123,18 +152,25 @@ func (r *KubernetesRoleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Create or update
 	if kr, err := r.fetchVaultKubernetesRole(ctx, role); err != nil {
 		log.Error(err, "Failed to fetch KubernetesRole")
-		meta.SetStatusCondition(&role.Status.Conditions, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch kubernetes auth engine role from Vault"})
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch kubernetes auth engine role from Vault"})
+		events.Failed(r.Recorder, role, "FailedToFetch", "Failed to fetch kubernetes auth engine role from Vault", kubernetesRolePath(role), err)
 		if err := r.Status().Update(ctx, role); err != nil {
 			log.Error(err, "Failed to update KubernetesRole status")
 			return ctrl.Result{}, err
 		}
 
 		return ctrl.Result{}, err
+	} else if observe {
+		return r.observeVaultKubernetesRole(ctx, role, kr)
 	} else {
-		if kr == nil || kr.IsDifferentFromSpec(&role.Spec) {
+		status := role.Status.DeepCopy()
+		message := "Kubernetes auth engine role in Vault matches the spec"
+		requestedAt, forced := vault.ReconcileRequested(role.Annotations, role.Status.ReconcileRequest)
+		if forced || kr == nil || kr.IsDifferentFromSpec(&role.Spec) {
 			if err := r.updateVaultKubernetesRole(ctx, role); err != nil {
 				log.Error(err, "Failed to update KubernetesRole")
-				meta.SetStatusCondition(&role.Status.Conditions, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push kubernetes auth engine role to Vault"})
+				vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push kubernetes auth engine role to Vault"})
+				events.Failed(r.Recorder, role, "FailedToUpdate", "Failed to push kubernetes auth engine role to Vault", kubernetesRolePath(role), err)
 				if err := r.Status().Update(ctx, role); err != nil {
 					log.Error(err, "Failed to update KubernetesRole status")
 					return ctrl.Result{}, err
@@ -143,7 +179,21 @@ func (r *KubernetesRoleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				return ctrl.Result{}, err
 			}
 
-			meta.SetStatusCondition(&role.Status.Conditions, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed kubernetes auth engine role to Vault"})
+			if kr != nil && !kr.IsDifferentFromSpec(&role.Spec) {
+				events.Reapplied(r.Recorder, role, kubernetesRolePath(role))
+			} else {
+				events.Pushed(r.Recorder, role, role.Status.Conditions, kr != nil, "KubernetesRole", kubernetesRolePath(role))
+			}
+
+			role.Status.ReconcileRequest = requestedAt
+			message = "Successfully pushed kubernetes auth engine role to Vault"
+		}
+
+		// The generation is applied even when Vault already matched it
+		role.Status.ObservedGeneration = role.Generation
+		role.Status.VaultPath = kubernetesRolePath(role)
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredRole, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+		if !equality.Semantic.DeepEqual(status, &role.Status) {
 			if err := r.Status().Update(ctx, role); err != nil {
 				log.Error(err, "Failed to update KubernetesRole status")
 				return ctrl.Result{}, err
@@ -154,13 +204,66 @@ func (r *KubernetesRoleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ctrl.Result{}, nil
 }
 
+// observeVaultKubernetesRole reports whether the role in Vault matches the
+// spec.
+func (r *KubernetesRoleReconciler) observeVaultKubernetesRole(ctx context.Context, role *authv1beta1.KubernetesRole, kr *vault.KubernetesRole) (ctrl.Result, error) {
+	condition := metav1.Condition{Type: typeInSyncRole, Status: metav1.ConditionTrue, Reason: "InSync", Message: "Kubernetes auth engine role in Vault matches the spec"}
+	if kr == nil {
+		condition = metav1.Condition{Type: typeInSyncRole, Status: metav1.ConditionFalse, Reason: "OutOfSync", Message: fmt.Sprintf("Kubernetes auth engine role %s does not exist in Vault", role.Name)}
+	} else if kr.IsDifferentFromSpec(&role.Spec) {
+		condition = metav1.Condition{Type: typeInSyncRole, Status: metav1.ConditionFalse, Reason: "OutOfSync", Message: "Kubernetes auth engine role in Vault differs from the spec:\n" + vault.Diff(kr, desiredVaultKubernetesRole(role))}
+	}
+
+	vault.SetCondition(&role.Status.Conditions, role.Generation, condition)
+	role.Status.ObservedGeneration = role.Generation
+	role.Status.VaultPath = kubernetesRolePath(role)
+	role.Status.ReconcileRequest = role.Annotations[vault.ReconcileAtAnnotation]
+	if err := r.Status().Update(ctx, role); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update KubernetesRole status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Plan returns the change applying the role, or deleting it, makes in Vault.
+func (r *KubernetesRoleReconciler) Plan(ctx context.Context, obj client.Object, deleted bool) (*plan.Change, error) {
+	role, ok := obj.(*authv1beta1.KubernetesRole)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+
+	kr, err := r.fetchVaultKubernetesRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &plan.Change{Path: kubernetesRolePath(role), Action: plan.ActionNone}
+	switch {
+	case deleted:
+		if kr != nil {
+			change.Action = plan.ActionDelete
+		}
+	case kr == nil:
+		change.Action, change.Diff = plan.ActionCreate, vault.Diff(&vault.KubernetesRole{}, desiredVaultKubernetesRole(role))
+	case kr.IsDifferentFromSpec(&role.Spec):
+		change.Action, change.Diff = plan.ActionUpdate, vault.Diff(kr, desiredVaultKubernetesRole(role))
+	}
+	return change, nil
+}
+
+// kubernetesRolePath returns the path of the role in Vault.
+func kubernetesRolePath(role *authv1beta1.KubernetesRole) string {
+	return fmt.Sprintf("auth/%s/role/%s", role.Spec.AuthPath, role.Name)
+}
+
 func (r *KubernetesRoleReconciler) deleteVaultKubernetesRole(ctx context.Context, role *authv1beta1.KubernetesRole) error {
-	_, err := r.Vault.Logical().DeleteWithContext(ctx, fmt.Sprintf("/auth/%s/role/%s", role.Spec.AuthPath, role.Name))
+	_, err := r.Vault.Delete(ctx, "/"+kubernetesRolePath(role))
 	return err
 }
 
 func (r *KubernetesRoleReconciler) fetchVaultKubernetesRole(ctx context.Context, role *authv1beta1.KubernetesRole) (*vault.KubernetesRole, error) {
-	s, err := r.Vault.Logical().ReadWithContext(ctx, fmt.Sprintf("/auth/%s/role/%s", role.Spec.AuthPath, role.Name))
+	s, err := r.Vault.Read(ctx, "/"+kubernetesRolePath(role))
 	if err != nil {
 		// TODO: "not found" should not be an error
 		return nil, err
@@ -183,8 +286,9 @@ func (r *KubernetesRoleReconciler) fetchVaultKubernetesRole(ctx context.Context,
 	return &kr, nil
 }
 
-func (r *KubernetesRoleReconciler) updateVaultKubernetesRole(ctx context.Context, role *authv1beta1.KubernetesRole) error {
-	jsonBytes, err := json.Marshal(&vault.KubernetesRole{
+// desiredVaultKubernetesRole returns the role as written to Vault.
+func desiredVaultKubernetesRole(role *authv1beta1.KubernetesRole) *vault.KubernetesRole {
+	return &vault.KubernetesRole{
 		BoundServiceAccountNames:      role.Spec.BoundServiceAccountNames,
 		BoundServiceAccountNamespaces: role.Spec.BoundServiceAccountNamespaces,
 		Audience:                      role.Spec.Audience,
@@ -198,7 +302,11 @@ func (r *KubernetesRoleReconciler) updateVaultKubernetesRole(ctx context.Context
 		TokenNumUses:                  role.Spec.TokenNumUses,
 		TokenPeriod:                   role.Spec.TokenPeriod,
 		TokenType:                     role.Spec.TokenType,
-	})
+	}
+}
+
+func (r *KubernetesRoleReconciler) updateVaultKubernetesRole(ctx context.Context, role *authv1beta1.KubernetesRole) error {
+	jsonBytes, err := json.Marshal(desiredVaultKubernetesRole(role))
 	if err != nil {
 		return err
 	}
@@ -208,8 +316,14 @@ func (r *KubernetesRoleReconciler) updateVaultKubernetesRole(ctx context.Context
 		return err
 	}
 
-	_, err = r.Vault.Logical().WriteWithContext(ctx, fmt.Sprintf("/auth/%s/role/%s", role.Spec.AuthPath, role.Name), m)
-	return err
+	if _, err := r.Vault.Write(ctx, "/"+kubernetesRolePath(role), m); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	role.Status.LastAppliedHash = vault.Checksum(m)
+	role.Status.LastAppliedTime = &now
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
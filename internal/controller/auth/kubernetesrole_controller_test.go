@@ -18,67 +18,244 @@ package auth
 
 import (
 	"context"
+	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
 )
 
 var _ = Describe("KubernetesRole Controller", func() {
 	Context("When reconciling a resource", func() {
-		const resourceName = "test-resource"
+		const resourceName = "test-role"
+		const rolePath = "auth/kubernetes/role/" + resourceName
 
 		ctx := context.Background()
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
+		}
+
+		var controllerReconciler *KubernetesRoleReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
 		}
-		kubernetesrole := &authv1beta1.KubernetesRole{}
 
 		BeforeEach(func() {
+			vaultServer.Reset()
+			controllerReconciler = &KubernetesRoleReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
 			By("creating the custom resource for the Kind KubernetesRole")
-			err := k8sClient.Get(ctx, typeNamespacedName, kubernetesrole)
+			role := &authv1beta1.KubernetesRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, role)
 			if err != nil && errors.IsNotFound(err) {
 				resource := &authv1beta1.KubernetesRole{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: authv1beta1.KubernetesRoleSpec{
+						BoundServiceAccountNames:      []string{"app"},
+						BoundServiceAccountNamespaces: []string{"default"},
+						TokenPolicies:                 []string{"read-only"},
+						TokenTTL:                      3600,
+						AliasNameSource:               "serviceaccount_uid",
+						AuthPath:                      "kubernetes",
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &authv1beta1.KubernetesRole{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance KubernetesRole")
+			vaultServer.ClearFaults()
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
 		})
-		It("should successfully reconcile the resource", func() {
+
+		It("should write the role to Vault", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &KubernetesRoleReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+			Expect(reconcileOnce()).To(Succeed())
+
+			data, ok := vaultServer.Data(rolePath)
+			Expect(ok).To(BeTrue())
+			Expect(data).To(HaveKeyWithValue("bound_service_account_names", ConsistOf("app")))
+			Expect(data).To(HaveKeyWithValue("token_policies", ConsistOf("read-only")))
+			Expect(data).To(HaveKeyWithValue("alias_name_source", "serviceaccount_uid"))
+			Expect(data).To(HaveKeyWithValue("token_ttl", BeNumerically("==", 3600)))
+
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			Expect(role.Finalizers).To(ContainElement(roleFinalizer))
+			Expect(meta.IsStatusConditionTrue(role.Status.Conditions, typeConfiguredRole)).To(BeTrue())
+		})
+
+		It("should not rewrite a role that is in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == rolePath {
+					writes++
+				}
 			}
+			Expect(writes).To(Equal(1))
+		})
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: typeNamespacedName,
-			})
-			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+		It("should update the role when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Adding a policy to the role")
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			role.Spec.TokenPolicies = append(role.Spec.TokenPolicies, "read-write")
+			Expect(k8sClient.Update(ctx, role)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			data, _ := vaultServer.Data(rolePath)
+			Expect(data).To(HaveKeyWithValue("token_policies", ConsistOf("read-only", "read-write")))
+		})
+
+		It("should correct drift made directly in Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Widening the role behind the operator's back")
+			data, _ := vaultServer.Data(rolePath)
+			data["bound_service_account_namespaces"] = []string{"*"}
+			vaultServer.SetData(rolePath, data)
+
+			Expect(reconcileOnce()).To(Succeed())
+			data, _ = vaultServer.Data(rolePath)
+			Expect(data).To(HaveKeyWithValue("bound_service_account_namespaces", ConsistOf("default")))
+		})
+
+		It("should report a failure to fetch the role", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodGet, Path: rolePath, Status: http.StatusServiceUnavailable})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			condition := meta.FindStatusCondition(role.Status.Conditions, typeConfiguredRole)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the role from Vault when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, role)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Data(rolePath)
+			Expect(ok).To(BeFalse())
+			err := k8sClient.Get(ctx, typeNamespacedName, role)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should only report drift when annotated with the observe mode", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Switching the role to observe mode")
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			role.Annotations = map[string]string{vault.ModeAnnotation: string(vault.ModeObserve)}
+			Expect(k8sClient.Update(ctx, role)).To(Succeed())
+
+			By("Widening the role behind the operator's back")
+			data, _ := vaultServer.Data(rolePath)
+			data["bound_service_account_namespaces"] = []string{"*"}
+			vaultServer.SetData(rolePath, data)
+
+			Expect(reconcileOnce()).To(Succeed())
+			data, _ = vaultServer.Data(rolePath)
+			Expect(data).To(HaveKeyWithValue("bound_service_account_namespaces", ConsistOf("*")))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			condition := meta.FindStatusCondition(role.Status.Conditions, typeInSyncRole)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("OutOfSync"))
+			Expect(condition.Message).To(ContainSubstring(`bound_service_account_namespaces: ["*"] -> ["default"]`))
+
+			By("Leaving the role in Vault when the resource is deleted")
+			Expect(k8sClient.Delete(ctx, role)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Data(rolePath)
+			Expect(ok).To(BeTrue())
+			err := k8sClient.Get(ctx, typeNamespacedName, role)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not write the role while paused", func() {
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			role.Annotations = map[string]string{vault.PausedAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, role)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Data(rolePath)
+			Expect(ok).To(BeFalse())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(role.Status.Conditions, vault.PausedCondition)).To(BeTrue())
+		})
+
+		It("should write the role again once per reconcile request", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := func() int {
+				n := 0
+				for _, r := range vaultServer.Requests() {
+					if r.Method == http.MethodPut && r.Path == rolePath {
+						n++
+					}
+				}
+				return n
+			}
+			Expect(writes()).To(Equal(1))
+
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			role.Annotations = map[string]string{vault.ReconcileAtAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, role)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(writes()).To(Equal(2))
 		})
 	})
 })
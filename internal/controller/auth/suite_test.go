@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -45,6 +46,9 @@ var (
 	testEnv   *envtest.Environment
 	cfg       *rest.Config
 	k8sClient client.Client
+
+	// vaultServer is an in-memory Vault shared by the reconcilers under test.
+	vaultServer *fake.Server
 )
 
 func TestControllers(t *testing.T) {
@@ -64,6 +68,9 @@ var _ = BeforeSuite(func() {
 
 	// +kubebuilder:scaffold:scheme
 
+	By("starting the fake vault server")
+	vaultServer = fake.NewServer()
+
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
@@ -88,6 +95,7 @@ var _ = BeforeSuite(func() {
 var _ = AfterSuite(func() {
 	By("tearing down the test environment")
 	cancel()
+	vaultServer.Close()
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
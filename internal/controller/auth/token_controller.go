@@ -18,14 +18,18 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -34,27 +38,42 @@ import (
 	vaultapi "github.com/hashicorp/vault/api"
 
 	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
 )
 
 const (
 	tokenFinalizer = "token.auth.toolkit.vault.hopopops.com/finalizer"
 )
 
+// tokenCreatePath is the path tokens are created at in Vault.
+const tokenCreatePath = "auth/token/create"
+
+// tokenAccessorPath returns the path of the accessor of a token in Vault,
+// which identifies it without giving access to it.
+func tokenAccessorPath(accessor string) string {
+	return "auth/token/accessors/" + accessor
+}
+
 // Definitions to manage status conditions
 const (
 	typeConfiguredToken = "Configured"
+	typeInSyncToken     = "InSync"
 )
 
 // TokenReconciler reconciles a Token object
 type TokenReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	Vault  *vaultapi.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
 }
 
 // +kubebuilder:rbac:groups=auth.toolkit.vault.hopopops.com,resources=tokens,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=auth.toolkit.vault.hopopops.com,resources=tokens/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=auth.toolkit.vault.hopopops.com,resources=tokens/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -64,6 +83,13 @@ type TokenReconciler struct {
 // perform operations to make the cluster state reflect the state specified by
 // the user.
 //
+// In observe mode, no token is created and the finalizer neither revokes the
+// token nor deletes the Secret.
+//
+// A paused resource is not reconciled, and its finalizer neither revokes the
+// token nor deletes the Secret. A new reconcile-at annotation issues a new
+// token when the token has been revoked or the Secret deleted.
+//
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
 func (r *TokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -80,19 +106,33 @@ func (r *TokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
+	observe := vault.ResourceMode(r.Mode, token.Annotations) == vault.ModeObserve
+	paused := vault.Paused(token.Annotations)
+
+	if vault.SetPausedCondition(&token.Status.Conditions, token.Generation, paused) {
+		if err := r.Status().Update(ctx, token); err != nil {
+			log.Error(err, "Failed to update Token status")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Token Deletion
 	isTokenMarkedToBeDeleted := token.GetDeletionTimestamp() != nil
 	if isTokenMarkedToBeDeleted {
 		if controllerutil.ContainsFinalizer(token, tokenFinalizer) {
-			if token.Spec.Target.DeletionPolicy == "Delete" {
+			if token.Spec.Target.DeletionPolicy == "Delete" && !observe && !paused {
 				if err := r.deleteK8sSecret(ctx, token); err != nil {
 					log.Error(err, "Failed to delete Secret")
 					return ctrl.Result{}, err
 				}
 
-				if err := r.Vault.Auth().Token().RevokeAccessorWithContext(ctx, token.Status.Accessor); err != nil {
-					log.Error(err, "Failed to delete accessor")
-					return ctrl.Result{}, err
+				// The accessor is only known once the token has been created
+				if token.Status.Accessor != "" {
+					if err := r.Vault.RevokeAccessor(ctx, token.Status.Accessor); err != nil {
+						log.Error(err, "Failed to delete accessor")
+						return ctrl.Result{}, err
+					}
+					events.Revoked(r.Recorder, token, "token", tokenAccessorPath(token.Status.Accessor))
 				}
 			}
 
@@ -105,16 +145,44 @@ func (r *TokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, nil
 	}
 
+	if paused {
+		log.Info("Token reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
 	// Token Initialization
-	if !controllerutil.ContainsFinalizer(token, tokenFinalizer) {
+	if !controllerutil.ContainsFinalizer(token, tokenFinalizer) && !observe {
 		controllerutil.AddFinalizer(token, tokenFinalizer)
-		meta.SetStatusCondition(&token.Status.Conditions, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		vault.SetCondition(&token.Status.Conditions, token.Generation, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
 		if err := r.Update(ctx, token); err != nil {
 			log.Error(err, "Failed to initialize Token status")
 			return ctrl.Result{}, err
 		}
 	}
 
+	if observe {
+		return r.observeVaultToken(ctx, token)
+	}
+
+	status := token.Status.DeepCopy()
+
+	// A reconcile request checks the token is still valid and stored in the
+	// Secret, a new one being issued otherwise
+	requestedAt, forced := vault.ReconcileRequested(token.Annotations, token.Status.ReconcileRequest)
+	if forced && token.Status.Accessor != "" {
+		valid, err := r.checkVaultToken(ctx, token)
+		if err != nil {
+			log.Error(err, "Failed to check Token")
+			return ctrl.Result{}, err
+		}
+
+		if !valid {
+			log.Info("Token has been revoked or its Secret deleted, issuing a new one")
+			token.Status.Accessor = ""
+		}
+	}
+
+	message := "Token is valid in Vault"
 	if token.Status.Accessor == "" {
 		tcr := &vaultapi.TokenCreateRequest{
 			ID:              token.Spec.ID,
@@ -132,9 +200,10 @@ func (r *TokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 			EntityAlias:     token.Spec.EntityAlias,
 		}
 
-		if t, err := r.Vault.Auth().Token().CreateWithContext(ctx, tcr); err != nil {
+		if t, err := r.Vault.CreateToken(ctx, tcr); err != nil {
 			log.Error(err, "Failed to create Token")
-			meta.SetStatusCondition(&token.Status.Conditions, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: "Failed to create token engine in Vault"})
+			vault.SetCondition(&token.Status.Conditions, token.Generation, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: "Failed to create token engine in Vault"})
+			events.Failed(r.Recorder, token, "FailedToCreate", "Failed to create token engine in Vault", tokenCreatePath, err)
 			if err := r.Status().Update(ctx, token); err != nil {
 				log.Error(err, "Failed to update Token status")
 				return ctrl.Result{}, err
@@ -144,7 +213,8 @@ func (r *TokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		} else {
 			if err := r.createK8sSecret(ctx, token, t.Auth.ClientToken); err != nil {
 				log.Error(err, "Failed to create k8s secret")
-				meta.SetStatusCondition(&token.Status.Conditions, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to create k8s secret %s", token.Spec.Target.Name)})
+				vault.SetCondition(&token.Status.Conditions, token.Generation, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to create k8s secret %s", token.Spec.Target.Name)})
+				events.Failed(r.Recorder, token, "FailedToCreate", fmt.Sprintf("Failed to create k8s secret %s", token.Spec.Target.Name), "", err)
 				if err := r.Status().Update(ctx, token); err != nil {
 					log.Error(err, "Failed to update Token status")
 					return ctrl.Result{}, err
@@ -152,18 +222,88 @@ func (r *TokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 				return ctrl.Result{}, err
 			}
 
+			events.Pushed(r.Recorder, token, token.Status.Conditions, false, "Token", tokenAccessorPath(t.Auth.Accessor))
+			now := metav1.Now()
 			token.Status.Accessor = t.Auth.Accessor
-			meta.SetStatusCondition(&token.Status.Conditions, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully created token engine in Vault"})
-			if err := r.Status().Update(ctx, token); err != nil {
-				log.Error(err, "Failed to update Token status")
-				return ctrl.Result{}, err
+			applied := *tcr
+			applied.ID = ""
+			token.Status.LastAppliedHash = vault.Checksum(applied)
+			token.Status.LastAppliedTime = &now
+			token.Status.ExpireTime = nil
+			if t.Auth.LeaseDuration > 0 {
+				expireTime := metav1.NewTime(time.Now().Add(time.Duration(t.Auth.LeaseDuration) * time.Second))
+				token.Status.ExpireTime = &expireTime
 			}
+			message = "Successfully created token engine in Vault"
+		}
+	}
+
+	// The generation is applied once the token is issued, as it is never
+	// updated
+	token.Status.ObservedGeneration = token.Generation
+	token.Status.VaultPath = tokenAccessorPath(token.Status.Accessor)
+	token.Status.ReconcileRequest = requestedAt
+	vault.SetCondition(&token.Status.Conditions, token.Generation, metav1.Condition{Type: typeConfiguredToken, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &token.Status) {
+		if err := r.Status().Update(ctx, token); err != nil {
+			log.Error(err, "Failed to update Token status")
+			return ctrl.Result{}, err
 		}
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// observeVaultToken reports whether the token has been created, tokens never
+// being updated.
+func (r *TokenReconciler) observeVaultToken(ctx context.Context, token *authv1beta1.Token) (ctrl.Result, error) {
+	condition := metav1.Condition{Type: typeInSyncToken, Status: metav1.ConditionTrue, Reason: "InSync", Message: "Token has been created in Vault"}
+	if token.Status.Accessor == "" {
+		condition = metav1.Condition{Type: typeInSyncToken, Status: metav1.ConditionFalse, Reason: "OutOfSync", Message: fmt.Sprintf("Token has not been created in Vault, nor Secret %s", token.Spec.Target.Name)}
+	}
+
+	vault.SetCondition(&token.Status.Conditions, token.Generation, condition)
+	token.Status.ObservedGeneration = token.Generation
+	if token.Status.Accessor != "" {
+		token.Status.VaultPath = tokenAccessorPath(token.Status.Accessor)
+	}
+	token.Status.ReconcileRequest = token.Annotations[vault.ReconcileAtAnnotation]
+	if err := r.Status().Update(ctx, token); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update Token status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// checkVaultToken reports whether the token is still valid in Vault and stored
+// in the Secret. A valid token missing from the Secret is revoked, as it
+// cannot be recovered.
+func (r *TokenReconciler) checkVaultToken(ctx context.Context, token *authv1beta1.Token) (bool, error) {
+	if _, err := r.Vault.LookupAccessor(ctx, token.Status.Accessor); err != nil {
+		// Vault rejects the accessors of revoked or expired tokens as invalid
+		var respErr *vaultapi.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusBadRequest {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to look up accessor: %w", err)
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: token.Spec.Target.Name, Namespace: token.Namespace}, secret)
+	if err != nil && apierrors.IsNotFound(err) {
+		if err := r.Vault.RevokeAccessor(ctx, token.Status.Accessor); err != nil {
+			return false, fmt.Errorf("failed to revoke accessor: %w", err)
+		}
+		events.Revoked(r.Recorder, token, "token", tokenAccessorPath(token.Status.Accessor))
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	return true, nil
+}
+
 func (r *TokenReconciler) deleteK8sSecret(ctx context.Context, token *authv1beta1.Token) error {
 	secret := &corev1.Secret{}
 
@@ -191,6 +331,8 @@ func (r *TokenReconciler) deleteK8sSecret(ctx context.Context, token *authv1beta
 	return nil
 }
 
+// createK8sSecret stores the token in the target Secret, an existing Secret
+// only being updated when controlled by the resource.
 func (r *TokenReconciler) createK8sSecret(ctx context.Context, token *authv1beta1.Token, data string) error {
 	log := logf.FromContext(ctx)
 
@@ -227,6 +369,15 @@ func (r *TokenReconciler) createK8sSecret(ctx context.Context, token *authv1beta
 		return err
 	}
 
+	if metav1.IsControlledBy(existingSecret, token) {
+		existingSecret.Data = secret.Data
+		if err := r.Update(ctx, existingSecret); err != nil {
+			return err
+		}
+		log.Info("Updated secret", "secret", secret.Name)
+		return nil
+	}
+
 	return apierrors.NewAlreadyExists(corev1.Resource("secrets"), secret.Name)
 }
 
@@ -18,32 +18,63 @@ package auth
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
 )
 
 var _ = Describe("Token Controller", func() {
 	Context("When reconciling a resource", func() {
-		const resourceName = "test-resource"
+		const resourceName = "test-token"
+		const secretName = "test-token-secret"
 
 		ctx := context.Background()
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
+		}
+		secretNamespacedName := types.NamespacedName{
+			Name:      secretName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *TokenReconciler
+		var recorder *record.FakeRecorder
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
 		}
-		token := &authv1beta1.Token{}
 
 		BeforeEach(func() {
+			vaultServer.Reset()
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &TokenReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Vault:    vaultServer.Client(),
+				Recorder: recorder,
+			}
+
 			By("creating the custom resource for the Kind Token")
+			token := &authv1beta1.Token{}
 			err := k8sClient.Get(ctx, typeNamespacedName, token)
 			if err != nil && errors.IsNotFound(err) {
 				resource := &authv1beta1.Token{
@@ -51,34 +82,205 @@ var _ = Describe("Token Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: authv1beta1.TokenSpec{
+						Target: authv1beta1.TokenTarget{
+							Name:           secretName,
+							DeletionPolicy: "Delete",
+						},
+						Policies:  []string{"read-only"},
+						TTL:       "1h",
+						Renewable: true,
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
+			vaultServer.ClearFaults()
+
 			resource := &authv1beta1.Token{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
-			Expect(err).NotTo(HaveOccurred())
+			if err == nil {
+				By("Cleanup the specific resource instance Token")
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			} else {
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			}
 
-			By("Cleanup the specific resource instance Token")
-			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			// envtest runs no garbage collector, owned secrets must be removed by hand
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, secretNamespacedName, secret); err == nil {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			}
 		})
-		It("should successfully reconcile the resource", func() {
+
+		It("should create a token and store it in the target secret", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &TokenReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+			Expect(reconcileOnce()).To(Succeed())
+
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			Expect(token.Status.Accessor).NotTo(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(token.Status.Conditions, typeConfiguredToken)).To(BeTrue())
+			Expect(token.Status.ExpireTime).NotTo(BeNil())
+			Expect(token.Status.ExpireTime.Time).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+
+			issued, ok := vaultServer.Token(token.Status.Accessor)
+			Expect(ok).To(BeTrue())
+			Expect(issued.Policies).To(ContainElement("read-only"))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKeyWithValue("token", []byte(issued.ClientToken)))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+			Expect(secret.OwnerReferences[0].UID).To(Equal(token.UID))
+			Expect(recorder.Events).To(Receive(Equal("Normal Created Created auth/token/accessors/" + token.Status.Accessor + " in Vault")))
+		})
+
+		It("should not issue a second token once created", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			created := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Path == "auth/token/create" {
+					created++
+				}
 			}
+			Expect(created).To(Equal(1))
+		})
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: typeNamespacedName,
-			})
-			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+		It("should report a failure to create the token", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPost, Path: "auth/token/create", Status: http.StatusForbidden})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			Expect(token.Status.Accessor).To(BeEmpty())
+			condition := meta.FindStatusCondition(token.Status.Conditions, typeConfiguredToken)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+			Expect(recorder.Events).To(Receive(Equal("Warning FailedToCreate Failed to create token engine in Vault at auth/token/create: permission denied (HTTP 403)")))
+
+			err := k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should revoke the token and delete the secret when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			accessor := token.Status.Accessor
+			Expect(k8sClient.Delete(ctx, token)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Token(accessor)
+			Expect(ok).To(BeFalse())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Revoked Revoked token auth/token/accessors/" + accessor + " in Vault")))
+			err := k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = k8sClient.Get(ctx, typeNamespacedName, token)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not create the token in observe mode", func() {
+			controllerReconciler.Mode = vault.ModeObserve
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(vaultServer.Requests()).To(BeEmpty())
+			err := k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			Expect(token.Finalizers).NotTo(ContainElement(tokenFinalizer))
+			condition := meta.FindStatusCondition(token.Status.Conditions, typeInSyncToken)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("OutOfSync"))
+		})
+
+		It("should keep the token and the secret when deleted in observe mode", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			accessor := token.Status.Accessor
+			Expect(k8sClient.Delete(ctx, token)).To(Succeed())
+
+			controllerReconciler.Mode = vault.ModeObserve
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Token(accessor)
+			Expect(ok).To(BeTrue())
+			Expect(k8sClient.Get(ctx, secretNamespacedName, &corev1.Secret{})).To(Succeed())
+			err := k8sClient.Get(ctx, typeNamespacedName, token)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not create the token while paused", func() {
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			token.Annotations = map[string]string{vault.PausedAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, token)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(vaultServer.Requests()).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			Expect(token.Status.Accessor).To(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(token.Status.Conditions, vault.PausedCondition)).To(BeTrue())
+		})
+
+		It("should issue a new token on a reconcile request once revoked", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			revoked := token.Status.Accessor
+
+			By("Revoking the token behind the operator's back")
+			Expect(vaultServer.Client().RevokeAccessor(ctx, revoked)).To(Succeed())
+
+			token.Annotations = map[string]string{vault.ReconcileAtAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, token)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			Expect(token.Status.Accessor).NotTo(Equal(revoked))
+			Expect(token.Status.ReconcileRequest).To(Equal("1"))
+			issued, ok := vaultServer.Token(token.Status.Accessor)
+			Expect(ok).To(BeTrue())
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKeyWithValue("token", []byte(issued.ClientToken)))
+		})
+
+		It("should keep the token valid on a reconcile request", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			token := &authv1beta1.Token{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			accessor := token.Status.Accessor
+
+			token.Annotations = map[string]string{vault.ReconcileAtAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, token)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, token)).To(Succeed())
+			Expect(token.Status.Accessor).To(Equal(accessor))
+			Expect(token.Status.ReconcileRequest).To(Equal("1"))
 		})
 	})
 })
@@ -0,0 +1,368 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	databasev1beta1 "hopopops/vault-operator/api/database/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	databaseConnectionFinalizer = "databaseconnection.database.toolkit.vault.hopopops.com/finalizer"
+
+	// rotateRootAnnotation requests a rotation of the root password whenever
+	// its value changes. The password is then only known to Vault.
+	rotateRootAnnotation = "database.toolkit.vault.hopopops.com/rotate-root"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredDatabaseConnection = "Configured"
+)
+
+// DatabaseConnectionReconciler reconciles a DatabaseConnection object
+type DatabaseConnectionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databaseconnections,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databaseconnections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databaseconnections/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *DatabaseConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the DatabaseConnection instance
+	connection := &databasev1beta1.DatabaseConnection{}
+	if err := r.Get(ctx, req.NamespacedName, connection); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("DatabaseConnection resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get DatabaseConnection")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, connection, &connection.Status.Conditions, r.Mode, databaseConnectionFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag DatabaseConnection in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// DatabaseConnection Deletion
+	isConnectionMarkedToBeDeleted := connection.GetDeletionTimestamp() != nil
+	if isConnectionMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(connection, databaseConnectionFinalizer) {
+			if err := r.deleteVaultDatabaseConnection(ctx, connection); err != nil {
+				log.Error(err, "Failed to delete DatabaseConnection")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, connection, databaseConnectionPath(connection))
+
+			controllerutil.RemoveFinalizer(connection, databaseConnectionFinalizer)
+			if err := r.Update(ctx, connection); err != nil {
+				log.Error(err, "Failed to remove finalizer from DatabaseConnection")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// DatabaseConnection Initialization
+	if !controllerutil.ContainsFinalizer(connection, databaseConnectionFinalizer) {
+		controllerutil.AddFinalizer(connection, databaseConnectionFinalizer)
+		vault.SetCondition(&connection.Status.Conditions, connection.Generation, metav1.Condition{Type: typeConfiguredDatabaseConnection, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, connection); err != nil {
+			log.Error(err, "Failed to initialize DatabaseConnection status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	username, password, secretUID, err := r.rootCredentials(ctx, connection)
+	if err != nil {
+		log.Error(err, "Failed to get root credentials")
+		vault.SetCondition(&connection.Status.Conditions, connection.Generation, metav1.Condition{Type: typeConfiguredDatabaseConnection, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to read root credentials: %s", err)})
+		events.Failed(r.Recorder, connection, "FailedToFetch", "Failed to read root credentials", databaseConnectionPath(connection), err)
+		if err := r.Status().Update(ctx, connection); err != nil {
+			log.Error(err, "Failed to update DatabaseConnection status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+	hash := hashCredentials(secretUID, username, password)
+
+	// Create or update
+	current, err := r.fetchVaultDatabaseConnection(ctx, connection)
+	if err != nil {
+		log.Error(err, "Failed to fetch DatabaseConnection")
+		vault.SetCondition(&connection.Status.Conditions, connection.Generation, metav1.Condition{Type: typeConfiguredDatabaseConnection, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch database connection from Vault"})
+		events.Failed(r.Recorder, connection, "FailedToFetch", "Failed to fetch database connection from Vault", databaseConnectionPath(connection), err)
+		if err := r.Status().Update(ctx, connection); err != nil {
+			log.Error(err, "Failed to update DatabaseConnection status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Vault does not return the password, changes are detected on the Secret
+	status := connection.Status.DeepCopy()
+	message := "Database connection in Vault matches the spec"
+	credentialsChanged := connection.Status.RootCredentialsHash != hash
+	if current == nil || credentialsChanged || current.IsDifferentFromSpec(&connection.Spec, username) {
+		// The password is left out of updates that do not change it, so that
+		// Vault keeps its own after a root rotation
+		if current != nil && !credentialsChanged {
+			password = ""
+		}
+
+		if err := r.updateVaultDatabaseConnection(ctx, connection, username, password); err != nil {
+			log.Error(err, "Failed to update DatabaseConnection")
+			vault.SetCondition(&connection.Status.Conditions, connection.Generation, metav1.Condition{Type: typeConfiguredDatabaseConnection, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push database connection to Vault"})
+			events.Failed(r.Recorder, connection, "FailedToUpdate", "Failed to push database connection to Vault", databaseConnectionPath(connection), err)
+			if err := r.Status().Update(ctx, connection); err != nil {
+				log.Error(err, "Failed to update DatabaseConnection status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, connection, connection.Status.Conditions, current != nil, "DatabaseConnection", databaseConnectionPath(connection))
+
+		connection.Status.RootCredentialsHash = hash
+		message = "Successfully pushed database connection to Vault"
+	}
+
+	connection.Status.ObservedGeneration = connection.Generation
+	connection.Status.VaultPath = databaseConnectionPath(connection)
+	vault.SetCondition(&connection.Status.Conditions, connection.Generation, metav1.Condition{Type: typeConfiguredDatabaseConnection, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &connection.Status) {
+		if err := r.Status().Update(ctx, connection); err != nil {
+			log.Error(err, "Failed to update DatabaseConnection status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// On-demand root rotation
+	if request := connection.Annotations[rotateRootAnnotation]; request != "" && request != connection.Status.RootRotationRequest {
+		if err := r.rotateVaultDatabaseConnectionRoot(ctx, connection); err != nil {
+			log.Error(err, "Failed to rotate DatabaseConnection root credentials")
+			vault.SetCondition(&connection.Status.Conditions, connection.Generation, metav1.Condition{Type: typeConfiguredDatabaseConnection, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to rotate the root credentials of the database connection"})
+			events.Failed(r.Recorder, connection, "FailedToUpdate", "Failed to rotate the root credentials of the database connection", fmt.Sprintf("%s/rotate-root/%s", connection.Spec.Mount, connection.Name), err)
+			if err := r.Status().Update(ctx, connection); err != nil {
+				log.Error(err, "Failed to update DatabaseConnection status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Rotated DatabaseConnection root credentials", "request", request)
+		events.Rotated(r.Recorder, connection, fmt.Sprintf("%s/rotate-root/%s", connection.Spec.Mount, connection.Name))
+
+		connection.Status.RootRotationRequest = request
+		vault.SetCondition(&connection.Status.Conditions, connection.Generation, metav1.Condition{Type: typeConfiguredDatabaseConnection, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully rotated the root credentials of the database connection"})
+		if err := r.Status().Update(ctx, connection); err != nil {
+			log.Error(err, "Failed to update DatabaseConnection status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func databaseConnectionPath(connection *databasev1beta1.DatabaseConnection) string {
+	return fmt.Sprintf("%s/config/%s", connection.Spec.Mount, connection.Name)
+}
+
+// rootCredentials returns the username and password of the root credentials
+// Secret, along with its UID.
+func (r *DatabaseConnectionReconciler) rootCredentials(ctx context.Context, connection *databasev1beta1.DatabaseConnection) (string, string, types.UID, error) {
+	ref := connection.Spec.RootCredentialsSecretRef
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: connection.Namespace}, secret); err != nil {
+		return "", "", "", fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+	}
+
+	username, ok := secret.Data[ref.UsernameKey]
+	if !ok {
+		return "", "", "", fmt.Errorf("key %s not found in secret %s", ref.UsernameKey, ref.Name)
+	}
+	password, ok := secret.Data[ref.PasswordKey]
+	if !ok {
+		return "", "", "", fmt.Errorf("key %s not found in secret %s", ref.PasswordKey, ref.Name)
+	}
+
+	return string(username), string(password), secret.UID, nil
+}
+
+// hashCredentials returns a checksum of the credentials, so that changes can be
+// detected without storing them. It is an HMAC keyed with the UID of their
+// Secret, which the readers of the status cannot check guesses against
+// without reading the Secret itself. A recreated Secret writes the
+// credentials to Vault again.
+func hashCredentials(key types.UID, username, password string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(username))
+	h.Write([]byte{0})
+	h.Write([]byte(password))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (r *DatabaseConnectionReconciler) deleteVaultDatabaseConnection(ctx context.Context, connection *databasev1beta1.DatabaseConnection) error {
+	_, err := r.Vault.Delete(ctx, databaseConnectionPath(connection))
+	return err
+}
+
+func (r *DatabaseConnectionReconciler) rotateVaultDatabaseConnectionRoot(ctx context.Context, connection *databasev1beta1.DatabaseConnection) error {
+	_, err := r.Vault.Write(ctx, fmt.Sprintf("%s/rotate-root/%s", connection.Spec.Mount, connection.Name), nil)
+	return err
+}
+
+func (r *DatabaseConnectionReconciler) fetchVaultDatabaseConnection(ctx context.Context, connection *databasev1beta1.DatabaseConnection) (*vault.DatabaseConnection, error) {
+	s, err := r.Vault.Read(ctx, databaseConnectionPath(connection))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var c vault.DatabaseConnection
+	if err := json.Unmarshal(jsonBytes, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// updateVaultDatabaseConnection writes the connection to Vault. The password is
+// only sent when not empty.
+func (r *DatabaseConnectionReconciler) updateVaultDatabaseConnection(ctx context.Context, connection *databasev1beta1.DatabaseConnection, username, password string) error {
+	m := map[string]interface{}{}
+	for k, v := range connection.Spec.Options {
+		m[k] = v
+	}
+
+	allowedRoles := connection.Spec.AllowedRoles
+	if allowedRoles == nil {
+		// An empty list clears the roles allowed previously
+		allowedRoles = []string{}
+	}
+	rootRotationStatements := connection.Spec.RootRotationStatements
+	if rootRotationStatements == nil {
+		rootRotationStatements = []string{}
+	}
+	verifyConnection := true
+	if connection.Spec.VerifyConnection != nil {
+		verifyConnection = *connection.Spec.VerifyConnection
+	}
+
+	m["plugin_name"] = connection.Spec.PluginName
+	m["plugin_version"] = connection.Spec.PluginVersion
+	m["connection_url"] = connection.Spec.ConnectionURL
+	m["username"] = username
+	m["allowed_roles"] = allowedRoles
+	m["root_rotation_statements"] = rootRotationStatements
+	m["password_policy"] = connection.Spec.PasswordPolicy
+	m["verify_connection"] = verifyConnection
+	if password != "" {
+		m["password"] = password
+	}
+
+	if _, err := r.Vault.Write(ctx, databaseConnectionPath(connection), m); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	connection.Status.LastAppliedHash = vault.Checksum(vault.Redacted(m, "password"))
+	connection.Status.LastAppliedTime = &now
+	return nil
+}
+
+// findDatabaseConnectionsForSecret returns the DatabaseConnections that use the
+// Secret as root credentials.
+func (r *DatabaseConnectionReconciler) findDatabaseConnectionsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	connections := &databasev1beta1.DatabaseConnectionList{}
+	if err := r.List(ctx, connections, client.InNamespace(secret.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list DatabaseConnections")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, c := range connections.Items {
+		if c.Spec.RootCredentialsSecretRef.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: c.Name, Namespace: c.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databasev1beta1.DatabaseConnection{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.findDatabaseConnectionsForSecret)).
+		Named("database-databaseconnection").
+		Complete(r)
+}
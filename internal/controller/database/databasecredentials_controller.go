@@ -0,0 +1,361 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	databasev1beta1 "hopopops/vault-operator/api/database/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	databaseCredentialsFinalizer = "databasecredentials.database.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredDatabaseCredentials = "Configured"
+)
+
+// DatabaseCredentialsReconciler reconciles a DatabaseCredentials object
+type DatabaseCredentialsReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databasecredentials,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databasecredentials/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databasecredentials/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Credentials are requested once and their lease is renewed when two thirds of
+// its duration have elapsed, the way LifetimeWatcher renews the operator's own
+// token. New credentials are requested when a renewal can no longer extend the
+// lease by a full period, before the role's max TTL is reached, or when the
+// lease was revoked behind the operator's back.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *DatabaseCredentialsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the DatabaseCredentials instance
+	credentials := &databasev1beta1.DatabaseCredentials{}
+	if err := r.Get(ctx, req.NamespacedName, credentials); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("DatabaseCredentials resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get DatabaseCredentials")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, credentials, &credentials.Status.Conditions, r.Mode, databaseCredentialsFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag DatabaseCredentials in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// DatabaseCredentials Deletion
+	isCredentialsMarkedToBeDeleted := credentials.GetDeletionTimestamp() != nil
+	if isCredentialsMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(credentials, databaseCredentialsFinalizer) {
+			for _, lease := range []string{credentials.Status.PreviousLeaseID, credentials.Status.LeaseID} {
+				if lease == "" {
+					continue
+				}
+				if err := r.Vault.RevokeLease(ctx, lease); err != nil {
+					log.Error(err, "Failed to revoke lease", "lease", lease)
+					return ctrl.Result{}, err
+				}
+				events.Revoked(r.Recorder, credentials, "lease", lease)
+			}
+
+			if credentials.Spec.Target.DeletionPolicy == "Delete" {
+				if err := deleteK8sSecret(ctx, r.Client, credentials, credentials.Spec.Target.Name); err != nil {
+					log.Error(err, "Failed to delete Secret")
+					return ctrl.Result{}, err
+				}
+			} else {
+				if err := releaseK8sSecret(ctx, r.Client, credentials, credentials.Spec.Target.Name); err != nil {
+					log.Error(err, "Failed to release Secret")
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(credentials, databaseCredentialsFinalizer)
+			if err := r.Update(ctx, credentials); err != nil {
+				log.Error(err, "Failed to remove finalizer from DatabaseCredentials")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// DatabaseCredentials Initialization
+	if !controllerutil.ContainsFinalizer(credentials, databaseCredentialsFinalizer) {
+		controllerutil.AddFinalizer(credentials, databaseCredentialsFinalizer)
+		vault.SetCondition(&credentials.Status.Conditions, credentials.Generation, metav1.Condition{Type: typeConfiguredDatabaseCredentials, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, credentials); err != nil {
+			log.Error(err, "Failed to initialize DatabaseCredentials status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	existing, err := fetchK8sSecret(ctx, r.Client, credentials.Namespace, credentials.Spec.Target.Name)
+	if err != nil {
+		log.Error(err, "Failed to get Secret")
+		return ctrl.Result{}, err
+	}
+
+	// Finish a rotation interrupted once the Secret was written, the previous
+	// lease being left to revoke
+	if credentials.Status.PreviousLeaseID != "" && holdsCredentials(existing, credentials) {
+		r.revokePreviousLease(ctx, credentials)
+		if err := r.Status().Update(ctx, credentials); err != nil {
+			log.Error(err, "Failed to update DatabaseCredentials status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// A rotation interrupted before the Secret was written is started over
+	rotate := credentials.Status.LeaseID == "" || existing == nil || credentials.Status.PreviousLeaseID != ""
+	if !rotate {
+		renewAt := renewTime(credentials)
+		if now := time.Now(); now.Before(renewAt) {
+			return ctrl.Result{RequeueAfter: renewAt.Sub(now)}, nil
+		}
+
+		s, err := r.Vault.RenewLease(ctx, credentials.Status.LeaseID, renewIncrement(credentials))
+		var responseError *vaultapi.ResponseError
+		switch {
+		case errors.As(err, &responseError) && responseError.StatusCode == http.StatusBadRequest:
+			log.Info("Lease can no longer be renewed, rotating credentials", "lease", credentials.Status.LeaseID, "reason", err.Error())
+			rotate = true
+		case err != nil:
+			log.Error(err, "Failed to renew lease")
+			vault.SetCondition(&credentials.Status.Conditions, credentials.Generation, metav1.Condition{Type: typeConfiguredDatabaseCredentials, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to renew the lease of the database credentials"})
+			events.Failed(r.Recorder, credentials, "FailedToUpdate", "Failed to renew the lease of the database credentials", databaseCredentialsPath(credentials), err)
+			if err := r.Status().Update(ctx, credentials); err != nil {
+				log.Error(err, "Failed to update DatabaseCredentials status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		case !s.Renewable || s.LeaseDuration < expectedLeaseDuration(credentials):
+			log.Info("Lease is close to its max TTL, rotating credentials", "lease", credentials.Status.LeaseID)
+			rotate = true
+		default:
+			setLease(credentials, s)
+			credentials.Status.ObservedGeneration = credentials.Generation
+			credentials.Status.VaultPath = databaseCredentialsPath(credentials)
+			vault.SetCondition(&credentials.Status.Conditions, credentials.Generation, metav1.Condition{Type: typeConfiguredDatabaseCredentials, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully renewed the lease of the database credentials"})
+			if err := r.Status().Update(ctx, credentials); err != nil {
+				log.Error(err, "Failed to update DatabaseCredentials status")
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	if rotate {
+		if err := r.rotateCredentials(ctx, credentials, existing); err != nil {
+			log.Error(err, "Failed to rotate database credentials")
+			vault.SetCondition(&credentials.Status.Conditions, credentials.Generation, metav1.Condition{Type: typeConfiguredDatabaseCredentials, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to deliver database credentials to k8s secret %s", credentials.Spec.Target.Name)})
+			events.Failed(r.Recorder, credentials, "FailedToCreate", fmt.Sprintf("Failed to deliver database credentials to k8s secret %s", credentials.Spec.Target.Name), databaseCredentialsPath(credentials), err)
+			if err := r.Status().Update(ctx, credentials); err != nil {
+				log.Error(err, "Failed to update DatabaseCredentials status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		credentials.Status.ObservedGeneration = credentials.Generation
+		credentials.Status.VaultPath = databaseCredentialsPath(credentials)
+		vault.SetCondition(&credentials.Status.Conditions, credentials.Generation, metav1.Condition{Type: typeConfiguredDatabaseCredentials, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully delivered database credentials"})
+		if err := r.Status().Update(ctx, credentials); err != nil {
+			log.Error(err, "Failed to update DatabaseCredentials status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: time.Until(renewTime(credentials))}, nil
+}
+
+func databaseCredentialsPath(credentials *databasev1beta1.DatabaseCredentials) string {
+	return fmt.Sprintf("%s/creds/%s", credentials.Spec.Mount, credentials.Spec.Role)
+}
+
+// renewTime returns the time the lease should be renewed at, once two thirds of
+// its duration have elapsed.
+//
+// Leases are renewed by requeued reconciliations rather than by the
+// LifetimeWatcher loop of renewLeases. That loop blocks a goroutine per
+// secret and only keeps the lease in memory, so every lease would be lost
+// track of on restarts and leader changes. The renewal time derived from the
+// status survives both, and only the leader acts on it.
+func renewTime(credentials *databasev1beta1.DatabaseCredentials) time.Time {
+	if credentials.Status.LastRenewTime == nil {
+		return time.Now()
+	}
+	return credentials.Status.LastRenewTime.Add(time.Duration(credentials.Status.LeaseDuration) * time.Second * 2 / 3)
+}
+
+// renewIncrement returns the increment requested on renewals in seconds. Zero
+// lets Vault use the role's default TTL.
+func renewIncrement(credentials *databasev1beta1.DatabaseCredentials) int {
+	if credentials.Spec.TTL == nil {
+		return 0
+	}
+	return int(credentials.Spec.TTL.Seconds())
+}
+
+// expectedLeaseDuration returns the lease duration a renewal grants unless it is
+// capped by the max TTL.
+func expectedLeaseDuration(credentials *databasev1beta1.DatabaseCredentials) int {
+	if increment := renewIncrement(credentials); increment > 0 {
+		return increment
+	}
+	return credentials.Status.LeaseDuration
+}
+
+func setLease(credentials *databasev1beta1.DatabaseCredentials, s *vaultapi.Secret) {
+	now := metav1.Now()
+	expireTime := metav1.NewTime(now.Add(time.Duration(s.LeaseDuration) * time.Second))
+
+	credentials.Status.LeaseID = s.LeaseID
+	credentials.Status.LeaseDuration = s.LeaseDuration
+	credentials.Status.Renewable = s.Renewable
+	credentials.Status.LastRenewTime = &now
+	credentials.Status.ExpireTime = &expireTime
+}
+
+// rotateCredentials requests new credentials, writes them to the target Secret
+// and only then revokes the previous lease, so that consumers are not left
+// without working credentials. The new lease is saved in the status before the
+// Secret is written, so that it is never lost track of, and a status read from
+// a stale cache fails to be saved instead of rotating the credentials twice.
+func (r *DatabaseCredentialsReconciler) rotateCredentials(ctx context.Context, credentials *databasev1beta1.DatabaseCredentials, existing *corev1.Secret) error {
+	log := logf.FromContext(ctx)
+
+	if existing != nil && !metav1.IsControlledBy(existing, credentials) {
+		return apierrors.NewAlreadyExists(corev1.Resource("secrets"), existing.Name)
+	}
+
+	// The lease of a rotation interrupted before the Secret was written was
+	// never delivered, the Secret still holding the previous one
+	previous := credentials.Status.LeaseID
+	if credentials.Status.PreviousLeaseID != "" {
+		if previous != "" {
+			if err := r.Vault.RevokeLease(ctx, previous); err != nil {
+				return err
+			}
+			events.Revoked(r.Recorder, credentials, "lease", previous)
+		}
+		previous = credentials.Status.PreviousLeaseID
+	}
+
+	s, err := r.Vault.Read(ctx, databaseCredentialsPath(credentials))
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("no credentials returned by %s", databaseCredentialsPath(credentials))
+	}
+
+	data, err := secretData(s.Data)
+	if err != nil {
+		return err
+	}
+
+	setLease(credentials, s)
+	credentials.Status.PreviousLeaseID = previous
+	credentials.Status.Username = string(data["username"])
+	if err := r.Status().Update(ctx, credentials); err != nil {
+		// Do not leave unused credentials behind
+		if err := r.Vault.RevokeLease(ctx, s.LeaseID); err != nil {
+			log.Error(err, "Failed to revoke unused lease", "lease", s.LeaseID)
+		}
+		return err
+	}
+
+	if err := applyK8sSecret(ctx, r.Client, r.Scheme, credentials, credentials.Spec.Target.Name, existing, data); err != nil {
+		return err
+	}
+	events.Pushed(r.Recorder, credentials, credentials.Status.Conditions, false, "DatabaseCredentials", s.LeaseID)
+
+	r.revokePreviousLease(ctx, credentials)
+	return nil
+}
+
+// revokePreviousLease revokes the lease of the credentials replaced in the
+// Secret, which expires on its own if it cannot be revoked, and forgets it.
+func (r *DatabaseCredentialsReconciler) revokePreviousLease(ctx context.Context, credentials *databasev1beta1.DatabaseCredentials) {
+	if previous := credentials.Status.PreviousLeaseID; previous != "" {
+		if err := r.Vault.RevokeLease(ctx, previous); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to revoke previous lease", "lease", previous)
+		} else {
+			events.Revoked(r.Recorder, credentials, "lease", previous)
+		}
+	}
+	credentials.Status.PreviousLeaseID = ""
+}
+
+// holdsCredentials reports whether the Secret holds the current credentials,
+// database usernames being unique to a lease.
+func holdsCredentials(secret *corev1.Secret, credentials *databasev1beta1.DatabaseCredentials) bool {
+	return secret != nil && credentials.Status.Username != "" && string(secret.Data["username"]) == credentials.Status.Username
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseCredentialsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databasev1beta1.DatabaseCredentials{}).
+		Owns(&corev1.Secret{}).
+		Named("database-databasecredentials").
+		Complete(r)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	databasev1beta1 "hopopops/vault-operator/api/database/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	databaseRoleFinalizer = "databaserole.database.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredDatabaseRole = "Configured"
+)
+
+// DatabaseRoleReconciler reconciles a DatabaseRole object
+type DatabaseRoleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databaseroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databaseroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databaseroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *DatabaseRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the DatabaseRole instance
+	role := &databasev1beta1.DatabaseRole{}
+	if err := r.Get(ctx, req.NamespacedName, role); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("DatabaseRole resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get DatabaseRole")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, role, &role.Status.Conditions, r.Mode, databaseRoleFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag DatabaseRole in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// DatabaseRole Deletion
+	isRoleMarkedToBeDeleted := role.GetDeletionTimestamp() != nil
+	if isRoleMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(role, databaseRoleFinalizer) {
+			if err := r.deleteVaultDatabaseRole(ctx, role); err != nil {
+				log.Error(err, "Failed to delete DatabaseRole")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, role, databaseRolePath(role))
+
+			controllerutil.RemoveFinalizer(role, databaseRoleFinalizer)
+			if err := r.Update(ctx, role); err != nil {
+				log.Error(err, "Failed to remove finalizer from DatabaseRole")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// DatabaseRole Initialization
+	if !controllerutil.ContainsFinalizer(role, databaseRoleFinalizer) {
+		controllerutil.AddFinalizer(role, databaseRoleFinalizer)
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseRole, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, role); err != nil {
+			log.Error(err, "Failed to initialize DatabaseRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultDatabaseRole(ctx, role)
+	if err != nil {
+		log.Error(err, "Failed to fetch DatabaseRole")
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseRole, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch database role from Vault"})
+		events.Failed(r.Recorder, role, "FailedToFetch", "Failed to fetch database role from Vault", databaseRolePath(role), err)
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update DatabaseRole status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	status := role.Status.DeepCopy()
+	message := "Database role in Vault matches the spec"
+	if current == nil || current.IsDifferentFromSpec(&role.Spec) {
+		if err := r.updateVaultDatabaseRole(ctx, role); err != nil {
+			log.Error(err, "Failed to update DatabaseRole")
+			vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push database role to Vault"})
+			events.Failed(r.Recorder, role, "FailedToUpdate", "Failed to push database role to Vault", databaseRolePath(role), err)
+			if err := r.Status().Update(ctx, role); err != nil {
+				log.Error(err, "Failed to update DatabaseRole status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, role, role.Status.Conditions, current != nil, "DatabaseRole", databaseRolePath(role))
+		message = "Successfully pushed database role to Vault"
+	}
+
+	role.Status.ObservedGeneration = role.Generation
+	role.Status.VaultPath = databaseRolePath(role)
+	vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseRole, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &role.Status) {
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update DatabaseRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func databaseRolePath(role *databasev1beta1.DatabaseRole) string {
+	return fmt.Sprintf("%s/roles/%s", role.Spec.Mount, role.Name)
+}
+
+func (r *DatabaseRoleReconciler) deleteVaultDatabaseRole(ctx context.Context, role *databasev1beta1.DatabaseRole) error {
+	_, err := r.Vault.Delete(ctx, databaseRolePath(role))
+	return err
+}
+
+func (r *DatabaseRoleReconciler) fetchVaultDatabaseRole(ctx context.Context, role *databasev1beta1.DatabaseRole) (*vault.DatabaseRole, error) {
+	s, err := r.Vault.Read(ctx, databaseRolePath(role))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var dr vault.DatabaseRole
+	if err := json.Unmarshal(jsonBytes, &dr); err != nil {
+		return nil, err
+	}
+
+	return &dr, nil
+}
+
+func (r *DatabaseRoleReconciler) updateVaultDatabaseRole(ctx context.Context, role *databasev1beta1.DatabaseRole) error {
+	credentialConfig := map[string]interface{}{}
+	for k, v := range role.Spec.CredentialConfig {
+		credentialConfig[k] = v
+	}
+
+	data := map[string]interface{}{
+		"db_name":               role.Spec.DBName,
+		"creation_statements":   role.Spec.CreationStatements,
+		"revocation_statements": emptyIfNil(role.Spec.RevocationStatements),
+		"rollback_statements":   emptyIfNil(role.Spec.RollbackStatements),
+		"renew_statements":      emptyIfNil(role.Spec.RenewStatements),
+		"default_ttl":           ttlOrZero(role.Spec.DefaultTTL),
+		"max_ttl":               ttlOrZero(role.Spec.MaxTTL),
+		"credential_type":       role.Spec.CredentialType,
+		"credential_config":     credentialConfig,
+	}
+	if _, err := r.Vault.Write(ctx, databaseRolePath(role), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	role.Status.LastAppliedHash = vault.Checksum(data)
+	role.Status.LastAppliedTime = &now
+	return nil
+}
+
+// emptyIfNil returns an empty list for nil, which clears the statements set
+// previously.
+func emptyIfNil(statements []string) []string {
+	if statements == nil {
+		return []string{}
+	}
+	return statements
+}
+
+// ttlOrZero returns "0" for an unset TTL, which resets it to the default of the
+// mount.
+func ttlOrZero(ttl string) string {
+	if ttl == "" {
+		return "0"
+	}
+	return ttl
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databasev1beta1.DatabaseRole{}).
+		Named("database-databaserole").
+		Complete(r)
+}
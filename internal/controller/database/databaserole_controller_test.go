@@ -0,0 +1,191 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	databasev1beta1 "hopopops/vault-operator/api/database/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("DatabaseRole Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-role"
+		const mount = "database"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *DatabaseRoleReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		readRole := func() map[string]interface{} {
+			s, err := vaultServer.Client().Read(ctx, mount+"/roles/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			return s.Data
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "database"})).To(Succeed())
+
+			controllerReconciler = &DatabaseRoleReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind DatabaseRole")
+			resource := &databasev1beta1.DatabaseRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &databasev1beta1.DatabaseRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: databasev1beta1.DatabaseRoleSpec{
+						Mount:                mount,
+						DBName:               "app",
+						CreationStatements:   []string{`CREATE ROLE "{{name}}" WITH LOGIN PASSWORD '{{password}}';`},
+						RevocationStatements: []string{`DROP ROLE "{{name}}";`},
+						DefaultTTL:           "1h",
+						MaxTTL:               "24h",
+						CredentialType:       "password",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &databasev1beta1.DatabaseRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance DatabaseRole")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should write the role to Vault", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			role := readRole()
+			Expect(role).To(HaveKeyWithValue("db_name", "app"))
+			Expect(role).To(HaveKeyWithValue("default_ttl", json.Number("3600")))
+			Expect(role).To(HaveKeyWithValue("max_ttl", json.Number("86400")))
+			Expect(role["creation_statements"]).To(ConsistOf(`CREATE ROLE "{{name}}" WITH LOGIN PASSWORD '{{password}}';`))
+
+			resource := &databasev1beta1.DatabaseRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(databaseRoleFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredDatabaseRole)).To(BeTrue())
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == mount+"/roles/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should update the role when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &databasev1beta1.DatabaseRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DefaultTTL = "30m"
+			resource.Spec.RevocationStatements = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			role := readRole()
+			Expect(role).To(HaveKeyWithValue("default_ttl", json.Number("1800")))
+			Expect(role["revocation_statements"]).To(BeEmpty())
+		})
+
+		It("should restore a role modified behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, err := vaultServer.Client().Write(ctx, mount+"/roles/"+resourceName, map[string]interface{}{"max_ttl": "1h"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(readRole()).To(HaveKeyWithValue("max_ttl", json.Number("86400")))
+		})
+
+		It("should report Vault errors in the status", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodGet, Path: mount + "/roles/" + resourceName})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &databasev1beta1.DatabaseRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredDatabaseRole)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the role from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &databasev1beta1.DatabaseRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, mount+"/roles/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeNil())
+		})
+	})
+})
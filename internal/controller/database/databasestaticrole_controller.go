@@ -0,0 +1,353 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	databasev1beta1 "hopopops/vault-operator/api/database/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	databaseStaticRoleFinalizer = "databasestaticrole.database.toolkit.vault.hopopops.com/finalizer"
+
+	// rotateAnnotation requests an immediate rotation of the password whenever
+	// its value changes, e.g. to the current date.
+	rotateAnnotation = "database.toolkit.vault.hopopops.com/rotate"
+
+	// staticCredentialsResyncPeriod is how often the credentials of roles
+	// rotated on a schedule are read again, as Vault does not report when the
+	// next rotation is due.
+	staticCredentialsResyncPeriod = 5 * time.Minute
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredDatabaseStaticRole = "Configured"
+)
+
+// staticCredentialsMetadata lists the fields of static-creds that describe the
+// rotation rather than the credentials.
+var staticCredentialsMetadata = []string{"ttl", "rotation_period", "rotation_schedule", "rotation_window", "last_vault_rotation"}
+
+// DatabaseStaticRoleReconciler reconciles a DatabaseStaticRole object
+type DatabaseStaticRoleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databasestaticroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databasestaticroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=database.toolkit.vault.hopopops.com,resources=databasestaticroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The current password is read back from static-creds once its ttl has
+// elapsed, so that the target Secret follows the rotations done by Vault.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *DatabaseStaticRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the DatabaseStaticRole instance
+	role := &databasev1beta1.DatabaseStaticRole{}
+	if err := r.Get(ctx, req.NamespacedName, role); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("DatabaseStaticRole resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get DatabaseStaticRole")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, role, &role.Status.Conditions, r.Mode, databaseStaticRoleFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag DatabaseStaticRole in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// DatabaseStaticRole Deletion
+	isRoleMarkedToBeDeleted := role.GetDeletionTimestamp() != nil
+	if isRoleMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(role, databaseStaticRoleFinalizer) {
+			if err := r.deleteVaultDatabaseStaticRole(ctx, role); err != nil {
+				log.Error(err, "Failed to delete DatabaseStaticRole")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, role, databaseStaticRolePath(role))
+
+			if role.Spec.Target.DeletionPolicy == "Delete" {
+				if err := deleteK8sSecret(ctx, r.Client, role, role.Spec.Target.Name); err != nil {
+					log.Error(err, "Failed to delete Secret")
+					return ctrl.Result{}, err
+				}
+			} else {
+				if err := releaseK8sSecret(ctx, r.Client, role, role.Spec.Target.Name); err != nil {
+					log.Error(err, "Failed to release Secret")
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(role, databaseStaticRoleFinalizer)
+			if err := r.Update(ctx, role); err != nil {
+				log.Error(err, "Failed to remove finalizer from DatabaseStaticRole")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// DatabaseStaticRole Initialization
+	if !controllerutil.ContainsFinalizer(role, databaseStaticRoleFinalizer) {
+		controllerutil.AddFinalizer(role, databaseStaticRoleFinalizer)
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseStaticRole, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, role); err != nil {
+			log.Error(err, "Failed to initialize DatabaseStaticRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultDatabaseStaticRole(ctx, role)
+	if err != nil {
+		log.Error(err, "Failed to fetch DatabaseStaticRole")
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseStaticRole, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch database static role from Vault"})
+		events.Failed(r.Recorder, role, "FailedToFetch", "Failed to fetch database static role from Vault", databaseStaticRolePath(role), err)
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update DatabaseStaticRole status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&role.Spec) {
+		if err := r.updateVaultDatabaseStaticRole(ctx, role); err != nil {
+			log.Error(err, "Failed to update DatabaseStaticRole")
+			vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseStaticRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push database static role to Vault"})
+			events.Failed(r.Recorder, role, "FailedToUpdate", "Failed to push database static role to Vault", databaseStaticRolePath(role), err)
+			if err := r.Status().Update(ctx, role); err != nil {
+				log.Error(err, "Failed to update DatabaseStaticRole status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, role, role.Status.Conditions, current != nil, "DatabaseStaticRole", databaseStaticRolePath(role))
+	}
+
+	// On-demand rotation
+	if request := role.Annotations[rotateAnnotation]; request != "" && request != role.Status.RotationRequest {
+		if err := r.rotateVaultDatabaseStaticRole(ctx, role); err != nil {
+			log.Error(err, "Failed to rotate DatabaseStaticRole")
+			vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseStaticRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to rotate the password of the database static role"})
+			events.Failed(r.Recorder, role, "FailedToUpdate", "Failed to rotate the password of the database static role", fmt.Sprintf("%s/rotate-role/%s", role.Spec.Mount, role.Name), err)
+			if err := r.Status().Update(ctx, role); err != nil {
+				log.Error(err, "Failed to update DatabaseStaticRole status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Rotated DatabaseStaticRole password", "request", request)
+		events.Rotated(r.Recorder, role, fmt.Sprintf("%s/rotate-role/%s", role.Spec.Mount, role.Name))
+		role.Status.RotationRequest = request
+	}
+
+	// Sync the current credentials
+	s, err := r.syncCredentials(ctx, role)
+	if err != nil {
+		log.Error(err, "Failed to sync database static credentials")
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseStaticRole, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to deliver database credentials to k8s secret %s", role.Spec.Target.Name)})
+		events.Failed(r.Recorder, role, "FailedToCreate", fmt.Sprintf("Failed to deliver database credentials to k8s secret %s", role.Spec.Target.Name), "", err)
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update DatabaseStaticRole status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	role.Status.ObservedGeneration = role.Generation
+	role.Status.VaultPath = databaseStaticRolePath(role)
+	vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredDatabaseStaticRole, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully delivered database static credentials"})
+	if err := r.Status().Update(ctx, role); err != nil {
+		log.Error(err, "Failed to update DatabaseStaticRole status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: nextRotation(s)}, nil
+}
+
+func databaseStaticRolePath(role *databasev1beta1.DatabaseStaticRole) string {
+	return fmt.Sprintf("%s/static-roles/%s", role.Spec.Mount, role.Name)
+}
+
+func (r *DatabaseStaticRoleReconciler) deleteVaultDatabaseStaticRole(ctx context.Context, role *databasev1beta1.DatabaseStaticRole) error {
+	_, err := r.Vault.Delete(ctx, databaseStaticRolePath(role))
+	return err
+}
+
+func (r *DatabaseStaticRoleReconciler) fetchVaultDatabaseStaticRole(ctx context.Context, role *databasev1beta1.DatabaseStaticRole) (*vault.DatabaseStaticRole, error) {
+	s, err := r.Vault.Read(ctx, databaseStaticRolePath(role))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var sr vault.DatabaseStaticRole
+	if err := json.Unmarshal(jsonBytes, &sr); err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+func (r *DatabaseStaticRoleReconciler) updateVaultDatabaseStaticRole(ctx context.Context, role *databasev1beta1.DatabaseStaticRole) error {
+	data := map[string]interface{}{
+		"db_name":             role.Spec.DBName,
+		"username":            role.Spec.Username,
+		"rotation_statements": emptyIfNil(role.Spec.RotationStatements),
+		"credential_type":     role.Spec.CredentialType,
+	}
+	// Vault rejects roles with both a rotation period and a schedule
+	if role.Spec.RotationSchedule != "" {
+		data["rotation_schedule"] = role.Spec.RotationSchedule
+		data["rotation_window"] = ttlOrZero(role.Spec.RotationWindow)
+	} else {
+		data["rotation_period"] = role.Spec.RotationPeriod
+	}
+
+	if _, err := r.Vault.Write(ctx, databaseStaticRolePath(role), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	role.Status.LastAppliedHash = vault.Checksum(data)
+	role.Status.LastAppliedTime = &now
+	return nil
+}
+
+func (r *DatabaseStaticRoleReconciler) rotateVaultDatabaseStaticRole(ctx context.Context, role *databasev1beta1.DatabaseStaticRole) error {
+	_, err := r.Vault.Write(ctx, fmt.Sprintf("%s/rotate-role/%s", role.Spec.Mount, role.Name), nil)
+	return err
+}
+
+// syncCredentials writes the current credentials of the role to the target
+// Secret and returns them.
+func (r *DatabaseStaticRoleReconciler) syncCredentials(ctx context.Context, role *databasev1beta1.DatabaseStaticRole) (*vaultapi.Secret, error) {
+	existing, err := fetchK8sSecret(ctx, r.Client, role.Namespace, role.Spec.Target.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && !metav1.IsControlledBy(existing, role) {
+		return nil, apierrors.NewAlreadyExists(corev1.Resource("secrets"), existing.Name)
+	}
+
+	path := fmt.Sprintf("%s/static-creds/%s", role.Spec.Mount, role.Name)
+	s, err := r.Vault.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("no credentials returned by %s", path)
+	}
+
+	credentials := map[string]interface{}{}
+	for k, v := range s.Data {
+		credentials[k] = v
+	}
+	for _, k := range staticCredentialsMetadata {
+		delete(credentials, k)
+	}
+
+	data, err := secretData(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyK8sSecret(ctx, r.Client, r.Scheme, role, role.Spec.Target.Name, existing, data); err != nil {
+		return nil, err
+	}
+
+	if rotation, ok := s.Data["last_vault_rotation"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, rotation); err == nil {
+			lastVaultRotation := metav1.NewTime(t)
+			role.Status.LastVaultRotation = &lastVaultRotation
+		}
+	}
+
+	return s, nil
+}
+
+// nextRotation returns how long until the credentials should be read again,
+// shortly after Vault rotated them.
+func nextRotation(s *vaultapi.Secret) time.Duration {
+	ttl, err := strconv.Atoi(fmt.Sprint(s.Data["ttl"]))
+	if err != nil || ttl <= 0 {
+		return staticCredentialsResyncPeriod
+	}
+	return time.Duration(ttl)*time.Second + 10*time.Second
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseStaticRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databasev1beta1.DatabaseStaticRole{}).
+		Owns(&corev1.Secret{}).
+		Named("database-databasestaticrole").
+		Complete(r)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// secretData converts the data of a Vault secret to the data of a Secret.
+func secretData(in map[string]interface{}) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for k, v := range in {
+		value, err := stringify(v)
+		if err != nil {
+			return nil, err
+		}
+		data[k] = []byte(value)
+	}
+	return data, nil
+}
+
+// stringify returns strings as is and the JSON encoding of other values.
+func stringify(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	default:
+		b, err := json.Marshal(value)
+		return string(b), err
+	}
+}
+
+func fetchK8sSecret(ctx context.Context, c client.Client, namespace, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{
+		Name:      name,
+		Namespace: namespace,
+	}, secret)
+
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	return secret, nil
+}
+
+// applyK8sSecret creates the target Secret or replaces its data. Secrets that
+// are not owned by the resource are left untouched.
+func applyK8sSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, name string, existing *corev1.Secret, data map[string][]byte) error {
+	log := logf.FromContext(ctx)
+
+	if existing == nil {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: owner.GetNamespace(),
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+
+		if err := controllerutil.SetControllerReference(owner, secret, scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+
+		if err := c.Create(ctx, secret); err != nil {
+			return err
+		}
+		log.Info("Created secret", "secret", secret.Name)
+		return nil
+	}
+
+	if !metav1.IsControlledBy(existing, owner) {
+		return apierrors.NewAlreadyExists(corev1.Resource("secrets"), existing.Name)
+	}
+
+	if maps.EqualFunc(existing.Data, data, slices.Equal) {
+		return nil
+	}
+
+	existing.Data = data
+	if err := c.Update(ctx, existing); err != nil {
+		return err
+	}
+	log.Info("Updated secret", "secret", existing.Name)
+	return nil
+}
+
+func deleteK8sSecret(ctx context.Context, c client.Client, owner client.Object, name string) error {
+	secret, err := fetchK8sSecret(ctx, c, owner.GetNamespace(), name)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(secret, owner) {
+		ctrl.Log.Info("Secret exists but CR is not owner, skipping deletion", "secret", name)
+		return nil
+	}
+
+	if err := c.Delete(ctx, secret); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
+
+// releaseK8sSecret removes the owner reference of the resource from the target
+// Secret, so that it is not garbage collected along with the resource.
+func releaseK8sSecret(ctx context.Context, c client.Client, owner client.Object, name string) error {
+	secret, err := fetchK8sSecret(ctx, c, owner.GetNamespace(), name)
+	if err != nil || secret == nil || !metav1.IsControlledBy(secret, owner) {
+		return err
+	}
+
+	secret.OwnerReferences = slices.DeleteFunc(secret.OwnerReferences, func(o metav1.OwnerReference) bool {
+		return o.UID == owner.GetUID()
+	})
+	return c.Update(ctx, secret)
+}
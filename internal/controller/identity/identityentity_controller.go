@@ -0,0 +1,254 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	identityEntityFinalizer = "identityentity.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredIdentityEntity = "Configured"
+)
+
+// IdentityEntityReconciler reconciles a IdentityEntity object
+type IdentityEntityReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentities,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentities/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentities/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The entity is named after the resource. Deleting it deletes its aliases in
+// Vault as well.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *IdentityEntityReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the IdentityEntity instance
+	entity := &identityv1beta1.IdentityEntity{}
+	if err := r.Get(ctx, req.NamespacedName, entity); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("IdentityEntity resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get IdentityEntity")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, entity, &entity.Status.Conditions, r.Mode, identityEntityFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag IdentityEntity in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// IdentityEntity Deletion
+	isEntityMarkedToBeDeleted := entity.GetDeletionTimestamp() != nil
+	if isEntityMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(entity, identityEntityFinalizer) {
+			if err := r.deleteVaultIdentityEntity(ctx, entity); err != nil {
+				log.Error(err, "Failed to delete IdentityEntity")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, entity, identityEntityPath(entity))
+
+			controllerutil.RemoveFinalizer(entity, identityEntityFinalizer)
+			if err := r.Update(ctx, entity); err != nil {
+				log.Error(err, "Failed to remove finalizer from IdentityEntity")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// IdentityEntity Initialization
+	if !controllerutil.ContainsFinalizer(entity, identityEntityFinalizer) {
+		controllerutil.AddFinalizer(entity, identityEntityFinalizer)
+		vault.SetCondition(&entity.Status.Conditions, entity.Generation, metav1.Condition{Type: typeConfiguredIdentityEntity, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, entity); err != nil {
+			log.Error(err, "Failed to initialize IdentityEntity status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultIdentityEntity(ctx, entity)
+	if err != nil {
+		log.Error(err, "Failed to fetch IdentityEntity")
+		vault.SetCondition(&entity.Status.Conditions, entity.Generation, metav1.Condition{Type: typeConfiguredIdentityEntity, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch entity from Vault"})
+		events.Failed(r.Recorder, entity, "FailedToFetch", "Failed to fetch entity from Vault", identityEntityPath(entity), err)
+		if err := r.Status().Update(ctx, entity); err != nil {
+			log.Error(err, "Failed to update IdentityEntity status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&entity.Spec) {
+		if err := r.updateVaultIdentityEntity(ctx, entity); err != nil {
+			log.Error(err, "Failed to update IdentityEntity")
+			vault.SetCondition(&entity.Status.Conditions, entity.Generation, metav1.Condition{Type: typeConfiguredIdentityEntity, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push entity to Vault"})
+			events.Failed(r.Recorder, entity, "FailedToUpdate", "Failed to push entity to Vault", identityEntityPath(entity), err)
+			if err := r.Status().Update(ctx, entity); err != nil {
+				log.Error(err, "Failed to update IdentityEntity status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, entity, entity.Status.Conditions, current != nil, "IdentityEntity", identityEntityPath(entity))
+
+		// Read back the ID of a new entity
+		if current, err = r.fetchVaultIdentityEntity(ctx, entity); err != nil {
+			log.Error(err, "Failed to fetch IdentityEntity")
+			return ctrl.Result{}, err
+		}
+		if current == nil {
+			return ctrl.Result{}, fmt.Errorf("entity %s not found after update", entity.Name)
+		}
+	}
+
+	entity.Status.EntityID = current.ID
+	entity.Status.AliasIDs = nil
+	for _, a := range current.Aliases {
+		entity.Status.AliasIDs = append(entity.Status.AliasIDs, a.ID)
+	}
+	entity.Status.ObservedGeneration = entity.Generation
+	entity.Status.VaultPath = identityEntityPath(entity)
+	vault.SetCondition(&entity.Status.Conditions, entity.Generation, metav1.Condition{Type: typeConfiguredIdentityEntity, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed entity to Vault"})
+	if err := r.Status().Update(ctx, entity); err != nil {
+		log.Error(err, "Failed to update IdentityEntity status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func identityEntityPath(entity *identityv1beta1.IdentityEntity) string {
+	return fmt.Sprintf("identity/entity/name/%s", entity.Name)
+}
+
+func (r *IdentityEntityReconciler) deleteVaultIdentityEntity(ctx context.Context, entity *identityv1beta1.IdentityEntity) error {
+	_, err := r.Vault.Delete(ctx, identityEntityPath(entity))
+	return err
+}
+
+func (r *IdentityEntityReconciler) fetchVaultIdentityEntity(ctx context.Context, entity *identityv1beta1.IdentityEntity) (*vault.IdentityEntity, error) {
+	s, err := r.Vault.Read(ctx, identityEntityPath(entity))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var e vault.IdentityEntity
+	if err := json.Unmarshal(jsonBytes, &e); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+func (r *IdentityEntityReconciler) updateVaultIdentityEntity(ctx context.Context, entity *identityv1beta1.IdentityEntity) error {
+	policies := entity.Spec.Policies
+	if policies == nil {
+		policies = []string{}
+	}
+	metadata := entity.Spec.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	data := map[string]interface{}{
+		"policies": policies,
+		"metadata": metadata,
+		"disabled": entity.Spec.Disabled,
+	}
+	if _, err := r.Vault.Write(ctx, identityEntityPath(entity), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	entity.Status.LastAppliedHash = vault.Checksum(data)
+	entity.Status.LastAppliedTime = &now
+	return nil
+}
+
+// findEntityForAlias refreshes the alias IDs of the entity an alias belongs to.
+func (r *IdentityEntityReconciler) findEntityForAlias(ctx context.Context, alias client.Object) []reconcile.Request {
+	a, ok := alias.(*identityv1beta1.IdentityEntityAlias)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: a.Spec.EntityRef.Name, Namespace: a.Namespace}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IdentityEntityReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.IdentityEntity{}).
+		Watches(&identityv1beta1.IdentityEntityAlias{}, handler.EnqueueRequestsFromMapFunc(r.findEntityForAlias)).
+		Named("identity-identityentity").
+		Complete(r)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("IdentityEntity Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-entity"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *IdentityEntityReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+
+			controllerReconciler = &IdentityEntityReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind IdentityEntity")
+			resource := &identityv1beta1.IdentityEntity{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.IdentityEntity{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.IdentityEntitySpec{
+						Policies: []string{"app"},
+						Metadata: map[string]string{"team": "payments"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.IdentityEntity{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance IdentityEntity")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should create the entity and record its ID", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			entity, ok := vaultServer.IdentityEntity(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(entity.Policies).To(Equal([]string{"app"}))
+			Expect(entity.Metadata).To(HaveKeyWithValue("team", "payments"))
+
+			resource := &identityv1beta1.IdentityEntity{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(identityEntityFinalizer))
+			Expect(resource.Status.EntityID).To(Equal(entity.ID))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredIdentityEntity)).To(BeTrue())
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == "identity/entity/name/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should update the entity and report its aliases", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityEntity{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Policies = nil
+			resource.Spec.Disabled = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			By("binding an alias behind the operator's back")
+			v := vaultServer.Client()
+			Expect(v.EnableAuth(ctx, "kubernetes", &vaultapi.EnableAuthOptions{Type: "kubernetes"})).To(Succeed())
+			mount, err := v.GetAuth(ctx, "kubernetes")
+			Expect(err).NotTo(HaveOccurred())
+			s, err := v.Write(ctx, "identity/entity-alias", map[string]interface{}{"name": "default/app", "canonical_id": resource.Status.EntityID, "mount_accessor": mount.Accessor})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			entity, ok := vaultServer.IdentityEntity(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(entity.Policies).To(BeEmpty())
+			Expect(entity.Disabled).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AliasIDs).To(Equal([]string{s.Data["id"].(string)}))
+		})
+
+		It("should report Vault errors in the status", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodGet, Path: "identity/entity/name/" + resourceName})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &identityv1beta1.IdentityEntity{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredIdentityEntity)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the entity from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityEntity{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.IdentityEntity(resourceName)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
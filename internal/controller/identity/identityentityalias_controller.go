@@ -0,0 +1,397 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	identityEntityAliasFinalizer = "identityentityalias.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredIdentityEntityAlias = "Configured"
+)
+
+// IdentityEntityAliasReconciler reconciles a IdentityEntityAlias object
+type IdentityEntityAliasReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentityaliases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentityaliases/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentityaliases/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentities,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auths,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The entity ID and the mount accessor are resolved from entityRef and
+// authRef. An alias created by Vault on login with the same name and accessor
+// is adopted and moved to the entity.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *IdentityEntityAliasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the IdentityEntityAlias instance
+	alias := &identityv1beta1.IdentityEntityAlias{}
+	if err := r.Get(ctx, req.NamespacedName, alias); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("IdentityEntityAlias resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get IdentityEntityAlias")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, alias, &alias.Status.Conditions, r.Mode, identityEntityAliasFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag IdentityEntityAlias in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// IdentityEntityAlias Deletion
+	isAliasMarkedToBeDeleted := alias.GetDeletionTimestamp() != nil
+	if isAliasMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(alias, identityEntityAliasFinalizer) {
+			if err := r.deleteVaultIdentityEntityAlias(ctx, alias); err != nil {
+				log.Error(err, "Failed to delete IdentityEntityAlias")
+				return ctrl.Result{}, err
+			}
+			if alias.Status.AliasID != "" {
+				events.Deleted(r.Recorder, alias, identityEntityAliasPath(alias.Status.AliasID))
+			}
+
+			controllerutil.RemoveFinalizer(alias, identityEntityAliasFinalizer)
+			if err := r.Update(ctx, alias); err != nil {
+				log.Error(err, "Failed to remove finalizer from IdentityEntityAlias")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// IdentityEntityAlias Initialization
+	if !controllerutil.ContainsFinalizer(alias, identityEntityAliasFinalizer) {
+		controllerutil.AddFinalizer(alias, identityEntityAliasFinalizer)
+		vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityEntityAlias, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, alias); err != nil {
+			log.Error(err, "Failed to initialize IdentityEntityAlias status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	canonicalID, mountAccessor, err := r.resolveReferences(ctx, alias)
+	if err != nil {
+		log.Error(err, "Failed to resolve IdentityEntityAlias references")
+		vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityEntityAlias, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to resolve references: %s", err)})
+		events.Failed(r.Recorder, alias, "FailedToFetch", "Failed to resolve references", "", err)
+		if err := r.Status().Update(ctx, alias); err != nil {
+			log.Error(err, "Failed to update IdentityEntityAlias status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	current, err := r.fetchVaultIdentityEntityAlias(ctx, alias, mountAccessor)
+	if err != nil {
+		log.Error(err, "Failed to fetch IdentityEntityAlias")
+		vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityEntityAlias, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch entity alias from Vault"})
+		events.Failed(r.Recorder, alias, "FailedToFetch", "Failed to fetch entity alias from Vault", "identity/entity-alias", err)
+		if err := r.Status().Update(ctx, alias); err != nil {
+			log.Error(err, "Failed to update IdentityEntityAlias status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create
+	if current == nil {
+		id, err := r.createVaultIdentityEntityAlias(ctx, alias, canonicalID, mountAccessor)
+		if err != nil {
+			log.Error(err, "Failed to create IdentityEntityAlias")
+			vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityEntityAlias, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to create entity alias: %s", err)})
+			events.Failed(r.Recorder, alias, "FailedToCreate", "Failed to create entity alias", "identity/entity-alias", err)
+			if err := r.Status().Update(ctx, alias); err != nil {
+				log.Error(err, "Failed to update IdentityEntityAlias status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Created IdentityEntityAlias", "alias", id)
+		events.Pushed(r.Recorder, alias, alias.Status.Conditions, false, "IdentityEntityAlias", identityEntityAliasPath(id))
+		alias.Status.AliasID = id
+	} else {
+		// Update
+		if current.IsDifferentFromSpec(&alias.Spec, canonicalID, mountAccessor) {
+			if err := r.updateVaultIdentityEntityAlias(ctx, alias, current.ID, canonicalID, mountAccessor); err != nil {
+				log.Error(err, "Failed to update IdentityEntityAlias")
+				vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityEntityAlias, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push entity alias to Vault: %s", err)})
+				events.Failed(r.Recorder, alias, "FailedToUpdate", "Failed to push entity alias to Vault", identityEntityAliasPath(current.ID), err)
+				if err := r.Status().Update(ctx, alias); err != nil {
+					log.Error(err, "Failed to update IdentityEntityAlias status")
+					return ctrl.Result{}, err
+				}
+
+				return ctrl.Result{}, err
+			}
+
+			events.Pushed(r.Recorder, alias, alias.Status.Conditions, true, "IdentityEntityAlias", identityEntityAliasPath(current.ID))
+		}
+		alias.Status.AliasID = current.ID
+	}
+
+	alias.Status.ObservedGeneration = alias.Generation
+	alias.Status.VaultPath = identityEntityAliasPath(alias.Status.AliasID)
+	vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityEntityAlias, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed entity alias to Vault"})
+	if err := r.Status().Update(ctx, alias); err != nil {
+		log.Error(err, "Failed to update IdentityEntityAlias status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveReferences returns the ID of the referenced entity and the accessor
+// of the referenced auth method.
+func (r *IdentityEntityAliasReconciler) resolveReferences(ctx context.Context, alias *identityv1beta1.IdentityEntityAlias) (string, string, error) {
+	entity := &identityv1beta1.IdentityEntity{}
+	if err := r.Get(ctx, types.NamespacedName{Name: alias.Spec.EntityRef.Name, Namespace: alias.Namespace}, entity); err != nil {
+		return "", "", fmt.Errorf("failed to get entity %s: %w", alias.Spec.EntityRef.Name, err)
+	}
+	if entity.Status.EntityID == "" {
+		return "", "", fmt.Errorf("entity %s is not ready", entity.Name)
+	}
+
+	accessor, err := fetchAuthAccessor(ctx, r.Client, alias.Namespace, alias.Spec.AuthRef)
+	if err != nil {
+		return "", "", err
+	}
+
+	return entity.Status.EntityID, accessor, nil
+}
+
+// fetchAuthAccessor returns the accessor of the auth method of a referenced
+// Auth, once it is enabled.
+func fetchAuthAccessor(ctx context.Context, c client.Client, namespace string, ref identityv1beta1.AuthReference) (string, error) {
+	auth := &sysv1beta1.Auth{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, auth); err != nil {
+		return "", fmt.Errorf("failed to get auth %s: %w", ref.Name, err)
+	}
+	if auth.Status.Accessor == "" {
+		return "", fmt.Errorf("auth %s is not ready", auth.Name)
+	}
+	return auth.Status.Accessor, nil
+}
+
+func identityEntityAliasPath(id string) string {
+	return fmt.Sprintf("identity/entity-alias/id/%s", id)
+}
+
+func (r *IdentityEntityAliasReconciler) deleteVaultIdentityEntityAlias(ctx context.Context, alias *identityv1beta1.IdentityEntityAlias) error {
+	if alias.Status.AliasID == "" {
+		return nil
+	}
+
+	_, err := r.Vault.Delete(ctx, identityEntityAliasPath(alias.Status.AliasID))
+	return err
+}
+
+// fetchVaultIdentityEntityAlias reads the alias by the ID recorded in status,
+// falling back to looking it up by name and accessor, which finds aliases
+// created on login.
+func (r *IdentityEntityAliasReconciler) fetchVaultIdentityEntityAlias(ctx context.Context, alias *identityv1beta1.IdentityEntityAlias, mountAccessor string) (*vault.IdentityEntityAlias, error) {
+	if alias.Status.AliasID != "" {
+		s, err := r.Vault.Read(ctx, identityEntityAliasPath(alias.Status.AliasID))
+		if err != nil {
+			return nil, err
+		}
+
+		if s != nil {
+			jsonBytes, err := json.Marshal(s.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			var a vault.IdentityEntityAlias
+			if err := json.Unmarshal(jsonBytes, &a); err != nil {
+				return nil, err
+			}
+
+			return &a, nil
+		}
+	}
+
+	s, err := r.Vault.Write(ctx, "identity/lookup/entity", map[string]interface{}{
+		"alias_name":           alias.Spec.Name,
+		"alias_mount_accessor": mountAccessor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var e vault.IdentityEntity
+	if err := json.Unmarshal(jsonBytes, &e); err != nil {
+		return nil, err
+	}
+
+	for _, a := range e.Aliases {
+		if a.Name == alias.Spec.Name && a.MountAccessor == mountAccessor {
+			return &a, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *IdentityEntityAliasReconciler) createVaultIdentityEntityAlias(ctx context.Context, alias *identityv1beta1.IdentityEntityAlias, canonicalID, mountAccessor string) (string, error) {
+	data := aliasData(alias, canonicalID, mountAccessor)
+	s, err := r.Vault.Write(ctx, "identity/entity-alias", data)
+	if err != nil {
+		return "", err
+	}
+
+	if s == nil {
+		return "", fmt.Errorf("no alias returned by Vault")
+	}
+
+	id, ok := s.Data["id"].(string)
+	if !ok || id == "" {
+		return "", fmt.Errorf("no alias ID returned by Vault")
+	}
+
+	now := metav1.Now()
+	alias.Status.LastAppliedHash = vault.Checksum(data)
+	alias.Status.LastAppliedTime = &now
+	return id, nil
+}
+
+func (r *IdentityEntityAliasReconciler) updateVaultIdentityEntityAlias(ctx context.Context, alias *identityv1beta1.IdentityEntityAlias, id, canonicalID, mountAccessor string) error {
+	data := aliasData(alias, canonicalID, mountAccessor)
+	if _, err := r.Vault.Write(ctx, identityEntityAliasPath(id), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	alias.Status.LastAppliedHash = vault.Checksum(data)
+	alias.Status.LastAppliedTime = &now
+	return nil
+}
+
+func aliasData(alias *identityv1beta1.IdentityEntityAlias, canonicalID, mountAccessor string) map[string]interface{} {
+	customMetadata := alias.Spec.CustomMetadata
+	if customMetadata == nil {
+		customMetadata = map[string]string{}
+	}
+
+	return map[string]interface{}{
+		"name":            alias.Spec.Name,
+		"canonical_id":    canonicalID,
+		"mount_accessor":  mountAccessor,
+		"custom_metadata": customMetadata,
+	}
+}
+
+// findAliasesForEntity requeues the aliases of an entity, which resolve its ID.
+func (r *IdentityEntityAliasReconciler) findAliasesForEntity(ctx context.Context, entity client.Object) []reconcile.Request {
+	aliases := &identityv1beta1.IdentityEntityAliasList{}
+	if err := r.List(ctx, aliases, client.InNamespace(entity.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list IdentityEntityAliases")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, a := range aliases.Items {
+		if a.Spec.EntityRef.Name == entity.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name, Namespace: a.Namespace}})
+		}
+	}
+	return requests
+}
+
+// findAliasesForAuth requeues the aliases bound to an auth method, which
+// resolve its accessor.
+func (r *IdentityEntityAliasReconciler) findAliasesForAuth(ctx context.Context, auth client.Object) []reconcile.Request {
+	aliases := &identityv1beta1.IdentityEntityAliasList{}
+	if err := r.List(ctx, aliases, client.InNamespace(auth.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list IdentityEntityAliases")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, a := range aliases.Items {
+		if a.Spec.AuthRef.Name == auth.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name, Namespace: a.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IdentityEntityAliasReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.IdentityEntityAlias{}).
+		Watches(&identityv1beta1.IdentityEntity{}, handler.EnqueueRequestsFromMapFunc(r.findAliasesForEntity)).
+		Watches(&sysv1beta1.Auth{}, handler.EnqueueRequestsFromMapFunc(r.findAliasesForAuth)).
+		Named("identity-identityentityalias").
+		Complete(r)
+}
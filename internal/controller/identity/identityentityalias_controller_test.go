@@ -0,0 +1,219 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+)
+
+var _ = Describe("IdentityEntityAlias Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-alias"
+		const entityName = "test-alias-entity"
+		const authName = "kubernetes"
+		const aliasName = "default/app"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var (
+			controllerReconciler *IdentityEntityAliasReconciler
+			entityID             string
+			accessor             string
+		)
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			v := vaultServer.Client()
+
+			controllerReconciler = &IdentityEntityAliasReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  v,
+			}
+
+			By("preparing the referenced entity and auth method")
+			s, err := v.Write(ctx, "identity/entity/name/"+entityName, map[string]interface{}{})
+			Expect(err).NotTo(HaveOccurred())
+			entityID = s.Data["id"].(string)
+			Expect(v.EnableAuth(ctx, authName, &vaultapi.EnableAuthOptions{Type: "kubernetes"})).To(Succeed())
+			mount, err := v.GetAuth(ctx, authName)
+			Expect(err).NotTo(HaveOccurred())
+			accessor = mount.Accessor
+
+			entity := &identityv1beta1.IdentityEntity{ObjectMeta: metav1.ObjectMeta{Name: entityName, Namespace: "default"}}
+			Expect(k8sClient.Create(ctx, entity)).To(Succeed())
+			entity.Status.EntityID = entityID
+			Expect(k8sClient.Status().Update(ctx, entity)).To(Succeed())
+
+			auth := &sysv1beta1.Auth{ObjectMeta: metav1.ObjectMeta{Name: authName, Namespace: "default"}}
+			Expect(k8sClient.Create(ctx, auth)).To(Succeed())
+			auth.Status.Accessor = accessor
+			Expect(k8sClient.Status().Update(ctx, auth)).To(Succeed())
+
+			By("creating the custom resource for the Kind IdentityEntityAlias")
+			resource := &identityv1beta1.IdentityEntityAlias{}
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.IdentityEntityAlias{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.IdentityEntityAliasSpec{
+						Name:           aliasName,
+						EntityRef:      identityv1beta1.IdentityEntityReference{Name: entityName},
+						AuthRef:        identityv1beta1.AuthReference{Name: authName},
+						CustomMetadata: map[string]string{"cluster": "main"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.IdentityEntityAlias{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance IdentityEntityAlias")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			Expect(k8sClient.Delete(ctx, &identityv1beta1.IdentityEntity{ObjectMeta: metav1.ObjectMeta{Name: entityName, Namespace: "default"}})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &sysv1beta1.Auth{ObjectMeta: metav1.ObjectMeta{Name: authName, Namespace: "default"}})).To(Succeed())
+		})
+
+		It("should bind the alias to the entity and the auth accessor", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityEntityAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(identityEntityAliasFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredIdentityEntityAlias)).To(BeTrue())
+
+			alias, ok := vaultServer.IdentityEntityAlias(resource.Status.AliasID)
+			Expect(ok).To(BeTrue())
+			Expect(alias.Name).To(Equal(aliasName))
+			Expect(alias.CanonicalID).To(Equal(entityID))
+			Expect(alias.MountAccessor).To(Equal(accessor))
+			Expect(alias.CustomMetadata).To(HaveKeyWithValue("cluster", "main"))
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && strings.HasPrefix(r.Path, "identity/entity-alias") {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should adopt an alias created on login", func() {
+			s, err := vaultServer.Client().Write(ctx, "identity/entity-alias", map[string]interface{}{"name": aliasName, "mount_accessor": accessor})
+			Expect(err).NotTo(HaveOccurred())
+			aliasID := s.Data["id"].(string)
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityEntityAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AliasID).To(Equal(aliasID))
+			alias, ok := vaultServer.IdentityEntityAlias(aliasID)
+			Expect(ok).To(BeTrue())
+			Expect(alias.CanonicalID).To(Equal(entityID))
+		})
+
+		It("should recreate an alias deleted behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityEntityAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			_, err := vaultServer.Client().Delete(ctx, "identity/entity-alias/id/"+resource.Status.AliasID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			previous := resource.Status.AliasID
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AliasID).NotTo(Equal(previous))
+			_, ok := vaultServer.IdentityEntityAlias(resource.Status.AliasID)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should wait for the auth method to be ready", func() {
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: authName, Namespace: "default"}, auth)).To(Succeed())
+			auth.Status.Accessor = ""
+			Expect(k8sClient.Status().Update(ctx, auth)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &identityv1beta1.IdentityEntityAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredIdentityEntityAlias)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+			Expect(condition.Message).To(ContainSubstring("not ready"))
+		})
+
+		It("should delete the alias from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityEntityAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.IdentityEntityAlias(resource.Status.AliasID)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,340 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	identityGroupFinalizer = "identitygroup.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredIdentityGroup = "Configured"
+)
+
+// IdentityGroupReconciler reconciles a IdentityGroup object
+type IdentityGroupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identitygroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identitygroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identitygroups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityentities,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The group is named after the resource. Members of internal groups are
+// resolved from the IDs reported by the referenced resources, so that they
+// follow entities and groups recreated in Vault.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *IdentityGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the IdentityGroup instance
+	group := &identityv1beta1.IdentityGroup{}
+	if err := r.Get(ctx, req.NamespacedName, group); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("IdentityGroup resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get IdentityGroup")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, group, &group.Status.Conditions, r.Mode, identityGroupFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag IdentityGroup in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// IdentityGroup Deletion
+	isGroupMarkedToBeDeleted := group.GetDeletionTimestamp() != nil
+	if isGroupMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(group, identityGroupFinalizer) {
+			if err := r.deleteVaultIdentityGroup(ctx, group); err != nil {
+				log.Error(err, "Failed to delete IdentityGroup")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, group, identityGroupPath(group))
+
+			controllerutil.RemoveFinalizer(group, identityGroupFinalizer)
+			if err := r.Update(ctx, group); err != nil {
+				log.Error(err, "Failed to remove finalizer from IdentityGroup")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// IdentityGroup Initialization
+	if !controllerutil.ContainsFinalizer(group, identityGroupFinalizer) {
+		controllerutil.AddFinalizer(group, identityGroupFinalizer)
+		vault.SetCondition(&group.Status.Conditions, group.Generation, metav1.Condition{Type: typeConfiguredIdentityGroup, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, group); err != nil {
+			log.Error(err, "Failed to initialize IdentityGroup status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	memberEntityIDs, memberGroupIDs, err := r.resolveMembers(ctx, group)
+	if err != nil {
+		log.Error(err, "Failed to resolve IdentityGroup members")
+		vault.SetCondition(&group.Status.Conditions, group.Generation, metav1.Condition{Type: typeConfiguredIdentityGroup, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to resolve members: %s", err)})
+		events.Failed(r.Recorder, group, "FailedToFetch", "Failed to resolve members", "", err)
+		if err := r.Status().Update(ctx, group); err != nil {
+			log.Error(err, "Failed to update IdentityGroup status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create or update
+	current, err := r.fetchVaultIdentityGroup(ctx, group)
+	if err != nil {
+		log.Error(err, "Failed to fetch IdentityGroup")
+		vault.SetCondition(&group.Status.Conditions, group.Generation, metav1.Condition{Type: typeConfiguredIdentityGroup, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch group from Vault"})
+		events.Failed(r.Recorder, group, "FailedToFetch", "Failed to fetch group from Vault", identityGroupPath(group), err)
+		if err := r.Status().Update(ctx, group); err != nil {
+			log.Error(err, "Failed to update IdentityGroup status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&group.Spec, memberEntityIDs, memberGroupIDs) {
+		if err := r.updateVaultIdentityGroup(ctx, group, memberEntityIDs, memberGroupIDs); err != nil {
+			log.Error(err, "Failed to update IdentityGroup")
+			vault.SetCondition(&group.Status.Conditions, group.Generation, metav1.Condition{Type: typeConfiguredIdentityGroup, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push group to Vault: %s", err)})
+			events.Failed(r.Recorder, group, "FailedToUpdate", "Failed to push group to Vault", identityGroupPath(group), err)
+			if err := r.Status().Update(ctx, group); err != nil {
+				log.Error(err, "Failed to update IdentityGroup status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, group, group.Status.Conditions, current != nil, "IdentityGroup", identityGroupPath(group))
+
+		// Read back the ID of a new group
+		if current, err = r.fetchVaultIdentityGroup(ctx, group); err != nil {
+			log.Error(err, "Failed to fetch IdentityGroup")
+			return ctrl.Result{}, err
+		}
+		if current == nil {
+			return ctrl.Result{}, fmt.Errorf("group %s not found after update", group.Name)
+		}
+	}
+
+	group.Status.GroupID = current.ID
+	group.Status.ObservedGeneration = group.Generation
+	group.Status.VaultPath = identityGroupPath(group)
+	vault.SetCondition(&group.Status.Conditions, group.Generation, metav1.Condition{Type: typeConfiguredIdentityGroup, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed group to Vault"})
+	if err := r.Status().Update(ctx, group); err != nil {
+		log.Error(err, "Failed to update IdentityGroup status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveMembers returns the sorted IDs of the referenced member entities and
+// groups.
+func (r *IdentityGroupReconciler) resolveMembers(ctx context.Context, group *identityv1beta1.IdentityGroup) ([]string, []string, error) {
+	entityIDs := []string{}
+	for _, ref := range group.Spec.MemberEntityRefs {
+		entity := &identityv1beta1.IdentityEntity{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: group.Namespace}, entity); err != nil {
+			return nil, nil, fmt.Errorf("failed to get entity %s: %w", ref.Name, err)
+		}
+		if entity.Status.EntityID == "" {
+			return nil, nil, fmt.Errorf("entity %s is not ready", entity.Name)
+		}
+		entityIDs = append(entityIDs, entity.Status.EntityID)
+	}
+
+	groupIDs := []string{}
+	for _, ref := range group.Spec.MemberGroupRefs {
+		id, err := fetchGroupID(ctx, r.Client, group.Namespace, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	slices.Sort(entityIDs)
+	slices.Sort(groupIDs)
+	return slices.Compact(entityIDs), slices.Compact(groupIDs), nil
+}
+
+// fetchGroupID returns the ID of a referenced IdentityGroup, once it is
+// created.
+func fetchGroupID(ctx context.Context, c client.Client, namespace string, ref identityv1beta1.IdentityGroupReference) (string, error) {
+	group := &identityv1beta1.IdentityGroup{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, group); err != nil {
+		return "", fmt.Errorf("failed to get group %s: %w", ref.Name, err)
+	}
+	if group.Status.GroupID == "" {
+		return "", fmt.Errorf("group %s is not ready", group.Name)
+	}
+	return group.Status.GroupID, nil
+}
+
+func identityGroupPath(group *identityv1beta1.IdentityGroup) string {
+	return fmt.Sprintf("identity/group/name/%s", group.Name)
+}
+
+func (r *IdentityGroupReconciler) deleteVaultIdentityGroup(ctx context.Context, group *identityv1beta1.IdentityGroup) error {
+	_, err := r.Vault.Delete(ctx, identityGroupPath(group))
+	return err
+}
+
+func (r *IdentityGroupReconciler) fetchVaultIdentityGroup(ctx context.Context, group *identityv1beta1.IdentityGroup) (*vault.IdentityGroup, error) {
+	s, err := r.Vault.Read(ctx, identityGroupPath(group))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var g vault.IdentityGroup
+	if err := json.Unmarshal(jsonBytes, &g); err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+func (r *IdentityGroupReconciler) updateVaultIdentityGroup(ctx context.Context, group *identityv1beta1.IdentityGroup, memberEntityIDs, memberGroupIDs []string) error {
+	policies := group.Spec.Policies
+	if policies == nil {
+		policies = []string{}
+	}
+	metadata := group.Spec.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	data := map[string]interface{}{
+		"type":     group.Spec.Type,
+		"policies": policies,
+		"metadata": metadata,
+	}
+	// Vault manages the members of external groups
+	if group.Spec.Type != "external" {
+		data["member_entity_ids"] = memberEntityIDs
+		data["member_group_ids"] = memberGroupIDs
+	}
+
+	if _, err := r.Vault.Write(ctx, identityGroupPath(group), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	group.Status.LastAppliedHash = vault.Checksum(data)
+	group.Status.LastAppliedTime = &now
+	return nil
+}
+
+// findGroupsForEntity requeues the groups an entity is a member of.
+func (r *IdentityGroupReconciler) findGroupsForEntity(ctx context.Context, entity client.Object) []reconcile.Request {
+	groups := &identityv1beta1.IdentityGroupList{}
+	if err := r.List(ctx, groups, client.InNamespace(entity.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list IdentityGroups")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, g := range groups.Items {
+		if slices.ContainsFunc(g.Spec.MemberEntityRefs, func(ref identityv1beta1.IdentityEntityReference) bool { return ref.Name == entity.GetName() }) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: g.Name, Namespace: g.Namespace}})
+		}
+	}
+	return requests
+}
+
+// findParentGroups requeues the groups a group is a member of.
+func (r *IdentityGroupReconciler) findParentGroups(ctx context.Context, member client.Object) []reconcile.Request {
+	groups := &identityv1beta1.IdentityGroupList{}
+	if err := r.List(ctx, groups, client.InNamespace(member.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list IdentityGroups")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, g := range groups.Items {
+		if slices.ContainsFunc(g.Spec.MemberGroupRefs, func(ref identityv1beta1.IdentityGroupReference) bool { return ref.Name == member.GetName() }) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: g.Name, Namespace: g.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IdentityGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.IdentityGroup{}).
+		Watches(&identityv1beta1.IdentityEntity{}, handler.EnqueueRequestsFromMapFunc(r.findGroupsForEntity)).
+		Watches(&identityv1beta1.IdentityGroup{}, handler.EnqueueRequestsFromMapFunc(r.findParentGroups)).
+		Named("identity-identitygroup").
+		Complete(r)
+}
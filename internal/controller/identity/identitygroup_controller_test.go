@@ -0,0 +1,240 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+)
+
+var _ = Describe("IdentityGroup Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-group"
+		const entityName = "test-group-member"
+		const childName = "test-group-child"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var (
+			controllerReconciler *IdentityGroupReconciler
+			recorder             *record.FakeRecorder
+			entityID             string
+			childID              string
+		)
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			v := vaultServer.Client()
+
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &IdentityGroupReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Vault:    v,
+				Recorder: recorder,
+			}
+
+			By("preparing the member entity and group")
+			s, err := v.Write(ctx, "identity/entity/name/"+entityName, map[string]interface{}{})
+			Expect(err).NotTo(HaveOccurred())
+			entityID = s.Data["id"].(string)
+			s, err = v.Write(ctx, "identity/group/name/"+childName, map[string]interface{}{})
+			Expect(err).NotTo(HaveOccurred())
+			childID = s.Data["id"].(string)
+
+			entity := &identityv1beta1.IdentityEntity{ObjectMeta: metav1.ObjectMeta{Name: entityName, Namespace: "default"}}
+			Expect(k8sClient.Create(ctx, entity)).To(Succeed())
+			entity.Status.EntityID = entityID
+			Expect(k8sClient.Status().Update(ctx, entity)).To(Succeed())
+
+			child := &identityv1beta1.IdentityGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: childName, Namespace: "default"},
+				Spec:       identityv1beta1.IdentityGroupSpec{Type: "internal"},
+			}
+			Expect(k8sClient.Create(ctx, child)).To(Succeed())
+			child.Status.GroupID = childID
+			Expect(k8sClient.Status().Update(ctx, child)).To(Succeed())
+
+			By("creating the custom resource for the Kind IdentityGroup")
+			resource := &identityv1beta1.IdentityGroup{}
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.IdentityGroup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.IdentityGroupSpec{
+						Type:             "internal",
+						Policies:         []string{"team"},
+						Metadata:         map[string]string{"team": "payments"},
+						MemberEntityRefs: []identityv1beta1.IdentityEntityReference{{Name: entityName}},
+						MemberGroupRefs:  []identityv1beta1.IdentityGroupReference{{Name: childName}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.IdentityGroup{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance IdentityGroup")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			Expect(k8sClient.Delete(ctx, &identityv1beta1.IdentityEntity{ObjectMeta: metav1.ObjectMeta{Name: entityName, Namespace: "default"}})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &identityv1beta1.IdentityGroup{ObjectMeta: metav1.ObjectMeta{Name: childName, Namespace: "default"}})).To(Succeed())
+		})
+
+		It("should create the group with its members resolved by name", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			group, ok := vaultServer.IdentityGroup(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(group.Type).To(Equal("internal"))
+			Expect(group.Policies).To(Equal([]string{"team"}))
+			Expect(group.MemberEntityIDs).To(Equal([]string{entityID}))
+			Expect(group.MemberGroupIDs).To(Equal([]string{childID}))
+
+			resource := &identityv1beta1.IdentityGroup{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(identityGroupFinalizer))
+			Expect(resource.Status.GroupID).To(Equal(group.ID))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredIdentityGroup)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created Created identity/group/name/" + resourceName + " in Vault")))
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == "identity/group/name/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should follow a member entity recreated in Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			v := vaultServer.Client()
+			_, err := v.Delete(ctx, "identity/entity/name/"+entityName)
+			Expect(err).NotTo(HaveOccurred())
+			s, err := v.Write(ctx, "identity/entity/name/"+entityName, map[string]interface{}{})
+			Expect(err).NotTo(HaveOccurred())
+			entity := &identityv1beta1.IdentityEntity{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: entityName, Namespace: "default"}, entity)).To(Succeed())
+			entity.Status.EntityID = s.Data["id"].(string)
+			Expect(k8sClient.Status().Update(ctx, entity)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			group, ok := vaultServer.IdentityGroup(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(group.MemberEntityIDs).To(Equal([]string{entity.Status.EntityID}))
+		})
+
+		It("should wait for members to be ready", func() {
+			entity := &identityv1beta1.IdentityEntity{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: entityName, Namespace: "default"}, entity)).To(Succeed())
+			entity.Status.EntityID = ""
+			Expect(k8sClient.Status().Update(ctx, entity)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &identityv1beta1.IdentityGroup{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredIdentityGroup)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+			Expect(condition.Message).To(ContainSubstring("not ready"))
+		})
+
+		It("should leave the members of external groups to Vault", func() {
+			external := &identityv1beta1.IdentityGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-group-external", Namespace: "default"},
+				Spec:       identityv1beta1.IdentityGroupSpec{Type: "external", Policies: []string{"team"}},
+			}
+			Expect(k8sClient.Create(ctx, external)).To(Succeed())
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: external.Name, Namespace: "default"}}
+
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			group, ok := vaultServer.IdentityGroup(external.Name)
+			Expect(ok).To(BeTrue())
+			Expect(group.Type).To(Equal("external"))
+			Expect(group.Policies).To(Equal([]string{"team"}))
+
+			Expect(k8sClient.Get(ctx, request.NamespacedName, external)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, external)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			_, ok = vaultServer.IdentityGroup(external.Name)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should delete the group from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityGroup{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.IdentityGroup(resourceName)
+			Expect(ok).To(BeFalse())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleted identity/group/name/" + resourceName + " from Vault")))
+		})
+	})
+})
@@ -0,0 +1,369 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	identityGroupAliasFinalizer = "identitygroupalias.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredIdentityGroupAlias = "Configured"
+)
+
+// IdentityGroupAliasReconciler reconciles a IdentityGroupAlias object
+type IdentityGroupAliasReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identitygroupaliases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identitygroupaliases/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identitygroupaliases/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identitygroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auths,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The group ID and the mount accessor are resolved from groupRef and authRef.
+// An external group has at most one alias, so an alias already set on the
+// group is taken over.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *IdentityGroupAliasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the IdentityGroupAlias instance
+	alias := &identityv1beta1.IdentityGroupAlias{}
+	if err := r.Get(ctx, req.NamespacedName, alias); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("IdentityGroupAlias resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get IdentityGroupAlias")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, alias, &alias.Status.Conditions, r.Mode, identityGroupAliasFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag IdentityGroupAlias in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// IdentityGroupAlias Deletion
+	isAliasMarkedToBeDeleted := alias.GetDeletionTimestamp() != nil
+	if isAliasMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(alias, identityGroupAliasFinalizer) {
+			if err := r.deleteVaultIdentityGroupAlias(ctx, alias); err != nil {
+				log.Error(err, "Failed to delete IdentityGroupAlias")
+				return ctrl.Result{}, err
+			}
+			if alias.Status.AliasID != "" {
+				events.Deleted(r.Recorder, alias, identityGroupAliasPath(alias.Status.AliasID))
+			}
+
+			controllerutil.RemoveFinalizer(alias, identityGroupAliasFinalizer)
+			if err := r.Update(ctx, alias); err != nil {
+				log.Error(err, "Failed to remove finalizer from IdentityGroupAlias")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// IdentityGroupAlias Initialization
+	if !controllerutil.ContainsFinalizer(alias, identityGroupAliasFinalizer) {
+		controllerutil.AddFinalizer(alias, identityGroupAliasFinalizer)
+		vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityGroupAlias, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, alias); err != nil {
+			log.Error(err, "Failed to initialize IdentityGroupAlias status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	canonicalID, mountAccessor, err := r.resolveReferences(ctx, alias)
+	if err != nil {
+		log.Error(err, "Failed to resolve IdentityGroupAlias references")
+		vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityGroupAlias, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to resolve references: %s", err)})
+		events.Failed(r.Recorder, alias, "FailedToFetch", "Failed to resolve references", "", err)
+		if err := r.Status().Update(ctx, alias); err != nil {
+			log.Error(err, "Failed to update IdentityGroupAlias status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	current, err := r.fetchVaultIdentityGroupAlias(ctx, alias, canonicalID)
+	if err != nil {
+		log.Error(err, "Failed to fetch IdentityGroupAlias")
+		vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityGroupAlias, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch group alias from Vault"})
+		events.Failed(r.Recorder, alias, "FailedToFetch", "Failed to fetch group alias from Vault", "identity/group-alias", err)
+		if err := r.Status().Update(ctx, alias); err != nil {
+			log.Error(err, "Failed to update IdentityGroupAlias status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create
+	if current == nil {
+		id, err := r.createVaultIdentityGroupAlias(ctx, alias, canonicalID, mountAccessor)
+		if err != nil {
+			log.Error(err, "Failed to create IdentityGroupAlias")
+			vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityGroupAlias, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to create group alias: %s", err)})
+			events.Failed(r.Recorder, alias, "FailedToCreate", "Failed to create group alias", "identity/group-alias", err)
+			if err := r.Status().Update(ctx, alias); err != nil {
+				log.Error(err, "Failed to update IdentityGroupAlias status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Created IdentityGroupAlias", "alias", id)
+		events.Pushed(r.Recorder, alias, alias.Status.Conditions, false, "IdentityGroupAlias", identityGroupAliasPath(id))
+		alias.Status.AliasID = id
+	} else {
+		// Update
+		if current.IsDifferentFromSpec(&alias.Spec, canonicalID, mountAccessor) {
+			if err := r.updateVaultIdentityGroupAlias(ctx, alias, current.ID, canonicalID, mountAccessor); err != nil {
+				log.Error(err, "Failed to update IdentityGroupAlias")
+				vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityGroupAlias, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push group alias to Vault: %s", err)})
+				events.Failed(r.Recorder, alias, "FailedToUpdate", "Failed to push group alias to Vault", identityGroupAliasPath(current.ID), err)
+				if err := r.Status().Update(ctx, alias); err != nil {
+					log.Error(err, "Failed to update IdentityGroupAlias status")
+					return ctrl.Result{}, err
+				}
+
+				return ctrl.Result{}, err
+			}
+
+			events.Pushed(r.Recorder, alias, alias.Status.Conditions, true, "IdentityGroupAlias", identityGroupAliasPath(current.ID))
+		}
+		alias.Status.AliasID = current.ID
+	}
+
+	alias.Status.ObservedGeneration = alias.Generation
+	alias.Status.VaultPath = identityGroupAliasPath(alias.Status.AliasID)
+	vault.SetCondition(&alias.Status.Conditions, alias.Generation, metav1.Condition{Type: typeConfiguredIdentityGroupAlias, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed group alias to Vault"})
+	if err := r.Status().Update(ctx, alias); err != nil {
+		log.Error(err, "Failed to update IdentityGroupAlias status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveReferences returns the ID of the referenced group and the accessor of
+// the referenced auth method.
+func (r *IdentityGroupAliasReconciler) resolveReferences(ctx context.Context, alias *identityv1beta1.IdentityGroupAlias) (string, string, error) {
+	groupID, err := fetchGroupID(ctx, r.Client, alias.Namespace, alias.Spec.GroupRef)
+	if err != nil {
+		return "", "", err
+	}
+
+	accessor, err := fetchAuthAccessor(ctx, r.Client, alias.Namespace, alias.Spec.AuthRef)
+	if err != nil {
+		return "", "", err
+	}
+
+	return groupID, accessor, nil
+}
+
+func identityGroupAliasPath(id string) string {
+	return fmt.Sprintf("identity/group-alias/id/%s", id)
+}
+
+func (r *IdentityGroupAliasReconciler) deleteVaultIdentityGroupAlias(ctx context.Context, alias *identityv1beta1.IdentityGroupAlias) error {
+	if alias.Status.AliasID == "" {
+		return nil
+	}
+
+	_, err := r.Vault.Delete(ctx, identityGroupAliasPath(alias.Status.AliasID))
+	return err
+}
+
+// fetchVaultIdentityGroupAlias reads the alias by the ID recorded in status,
+// falling back to the alias set on the group.
+func (r *IdentityGroupAliasReconciler) fetchVaultIdentityGroupAlias(ctx context.Context, alias *identityv1beta1.IdentityGroupAlias, canonicalID string) (*vault.IdentityGroupAlias, error) {
+	if alias.Status.AliasID != "" {
+		s, err := r.Vault.Read(ctx, identityGroupAliasPath(alias.Status.AliasID))
+		if err != nil {
+			return nil, err
+		}
+
+		if s != nil {
+			jsonBytes, err := json.Marshal(s.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			var a vault.IdentityGroupAlias
+			if err := json.Unmarshal(jsonBytes, &a); err != nil {
+				return nil, err
+			}
+
+			return &a, nil
+		}
+	}
+
+	s, err := r.Vault.Read(ctx, fmt.Sprintf("identity/group/id/%s", canonicalID))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var g vault.IdentityGroup
+	if err := json.Unmarshal(jsonBytes, &g); err != nil {
+		return nil, err
+	}
+
+	if g.Alias == nil || g.Alias.ID == "" {
+		return nil, nil
+	}
+	return g.Alias, nil
+}
+
+func (r *IdentityGroupAliasReconciler) createVaultIdentityGroupAlias(ctx context.Context, alias *identityv1beta1.IdentityGroupAlias, canonicalID, mountAccessor string) (string, error) {
+	data := map[string]interface{}{
+		"name":           alias.Spec.Name,
+		"canonical_id":   canonicalID,
+		"mount_accessor": mountAccessor,
+	}
+	s, err := r.Vault.Write(ctx, "identity/group-alias", data)
+	if err != nil {
+		return "", err
+	}
+
+	if s == nil {
+		return "", fmt.Errorf("no alias returned by Vault")
+	}
+
+	id, ok := s.Data["id"].(string)
+	if !ok || id == "" {
+		return "", fmt.Errorf("no alias ID returned by Vault")
+	}
+
+	now := metav1.Now()
+	alias.Status.LastAppliedHash = vault.Checksum(data)
+	alias.Status.LastAppliedTime = &now
+	return id, nil
+}
+
+func (r *IdentityGroupAliasReconciler) updateVaultIdentityGroupAlias(ctx context.Context, alias *identityv1beta1.IdentityGroupAlias, id, canonicalID, mountAccessor string) error {
+	data := map[string]interface{}{
+		"name":           alias.Spec.Name,
+		"canonical_id":   canonicalID,
+		"mount_accessor": mountAccessor,
+	}
+	if _, err := r.Vault.Write(ctx, identityGroupAliasPath(id), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	alias.Status.LastAppliedHash = vault.Checksum(data)
+	alias.Status.LastAppliedTime = &now
+	return nil
+}
+
+// findAliasesForGroup requeues the alias of a group, which resolves its ID.
+func (r *IdentityGroupAliasReconciler) findAliasesForGroup(ctx context.Context, group client.Object) []reconcile.Request {
+	aliases := &identityv1beta1.IdentityGroupAliasList{}
+	if err := r.List(ctx, aliases, client.InNamespace(group.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list IdentityGroupAliases")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, a := range aliases.Items {
+		if a.Spec.GroupRef.Name == group.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name, Namespace: a.Namespace}})
+		}
+	}
+	return requests
+}
+
+// findAliasesForAuth requeues the aliases bound to an auth method, which
+// resolve its accessor.
+func (r *IdentityGroupAliasReconciler) findAliasesForAuth(ctx context.Context, auth client.Object) []reconcile.Request {
+	aliases := &identityv1beta1.IdentityGroupAliasList{}
+	if err := r.List(ctx, aliases, client.InNamespace(auth.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list IdentityGroupAliases")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, a := range aliases.Items {
+		if a.Spec.AuthRef.Name == auth.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name, Namespace: a.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IdentityGroupAliasReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.IdentityGroupAlias{}).
+		Watches(&identityv1beta1.IdentityGroup{}, handler.EnqueueRequestsFromMapFunc(r.findAliasesForGroup)).
+		Watches(&sysv1beta1.Auth{}, handler.EnqueueRequestsFromMapFunc(r.findAliasesForAuth)).
+		Named("identity-identitygroupalias").
+		Complete(r)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+)
+
+var _ = Describe("IdentityGroupAlias Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-group-alias"
+		const groupName = "test-group-alias-group"
+		const authName = "ldap"
+		const aliasName = "cn=devs,ou=groups,dc=example,dc=com"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var (
+			controllerReconciler *IdentityGroupAliasReconciler
+			groupID              string
+			accessor             string
+		)
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			v := vaultServer.Client()
+
+			controllerReconciler = &IdentityGroupAliasReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  v,
+			}
+
+			By("preparing the referenced group and auth method")
+			s, err := v.Write(ctx, "identity/group/name/"+groupName, map[string]interface{}{"type": "external"})
+			Expect(err).NotTo(HaveOccurred())
+			groupID = s.Data["id"].(string)
+			Expect(v.EnableAuth(ctx, authName, &vaultapi.EnableAuthOptions{Type: "ldap"})).To(Succeed())
+			mount, err := v.GetAuth(ctx, authName)
+			Expect(err).NotTo(HaveOccurred())
+			accessor = mount.Accessor
+
+			group := &identityv1beta1.IdentityGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: groupName, Namespace: "default"},
+				Spec:       identityv1beta1.IdentityGroupSpec{Type: "external"},
+			}
+			Expect(k8sClient.Create(ctx, group)).To(Succeed())
+			group.Status.GroupID = groupID
+			Expect(k8sClient.Status().Update(ctx, group)).To(Succeed())
+
+			auth := &sysv1beta1.Auth{ObjectMeta: metav1.ObjectMeta{Name: authName, Namespace: "default"}}
+			Expect(k8sClient.Create(ctx, auth)).To(Succeed())
+			auth.Status.Accessor = accessor
+			Expect(k8sClient.Status().Update(ctx, auth)).To(Succeed())
+
+			By("creating the custom resource for the Kind IdentityGroupAlias")
+			resource := &identityv1beta1.IdentityGroupAlias{}
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.IdentityGroupAlias{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.IdentityGroupAliasSpec{
+						Name:     aliasName,
+						GroupRef: identityv1beta1.IdentityGroupReference{Name: groupName},
+						AuthRef:  identityv1beta1.AuthReference{Name: authName},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.IdentityGroupAlias{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance IdentityGroupAlias")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			Expect(k8sClient.Delete(ctx, &identityv1beta1.IdentityGroup{ObjectMeta: metav1.ObjectMeta{Name: groupName, Namespace: "default"}})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &sysv1beta1.Auth{ObjectMeta: metav1.ObjectMeta{Name: authName, Namespace: "default"}})).To(Succeed())
+		})
+
+		It("should bind the alias to the group and the auth accessor", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityGroupAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(identityGroupAliasFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredIdentityGroupAlias)).To(BeTrue())
+
+			alias, ok := vaultServer.IdentityGroupAlias(resource.Status.AliasID)
+			Expect(ok).To(BeTrue())
+			Expect(alias.Name).To(Equal(aliasName))
+			Expect(alias.CanonicalID).To(Equal(groupID))
+			Expect(alias.MountAccessor).To(Equal(accessor))
+		})
+
+		It("should take over the alias already set on the group", func() {
+			s, err := vaultServer.Client().Write(ctx, "identity/group-alias", map[string]interface{}{"name": "cn=old", "canonical_id": groupID, "mount_accessor": accessor})
+			Expect(err).NotTo(HaveOccurred())
+			aliasID := s.Data["id"].(string)
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityGroupAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AliasID).To(Equal(aliasID))
+			alias, ok := vaultServer.IdentityGroupAlias(aliasID)
+			Expect(ok).To(BeTrue())
+			Expect(alias.Name).To(Equal(aliasName))
+		})
+
+		It("should recreate an alias deleted behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityGroupAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			_, err := vaultServer.Client().Delete(ctx, "identity/group-alias/id/"+resource.Status.AliasID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			group, ok := vaultServer.IdentityGroup(groupName)
+			Expect(ok).To(BeTrue())
+			Expect(group.Alias.Name).To(Equal(aliasName))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AliasID).To(Equal(group.Alias.ID))
+		})
+
+		It("should wait for the group to be ready", func() {
+			group := &identityv1beta1.IdentityGroup{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: groupName, Namespace: "default"}, group)).To(Succeed())
+			group.Status.GroupID = ""
+			Expect(k8sClient.Status().Update(ctx, group)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &identityv1beta1.IdentityGroupAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredIdentityGroupAlias)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the alias from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityGroupAlias{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.IdentityGroupAlias(resource.Status.AliasID)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
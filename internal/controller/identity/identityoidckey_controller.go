@@ -0,0 +1,224 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	identityOIDCKeyFinalizer = "identityoidckey.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredIdentityOIDCKey = "Configured"
+)
+
+// IdentityOIDCKeyReconciler reconciles a IdentityOIDCKey object
+type IdentityOIDCKeyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityoidckeys,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityoidckeys/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityoidckeys/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The key is named after the resource. Vault refuses to delete a key still
+// used by roles or clients, in which case deletion is retried.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *IdentityOIDCKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the IdentityOIDCKey instance
+	key := &identityv1beta1.IdentityOIDCKey{}
+	if err := r.Get(ctx, req.NamespacedName, key); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("IdentityOIDCKey resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get IdentityOIDCKey")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, key, &key.Status.Conditions, r.Mode, identityOIDCKeyFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag IdentityOIDCKey in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// IdentityOIDCKey Deletion
+	isKeyMarkedToBeDeleted := key.GetDeletionTimestamp() != nil
+	if isKeyMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(key, identityOIDCKeyFinalizer) {
+			if err := r.deleteVaultIdentityOIDCKey(ctx, key); err != nil {
+				log.Error(err, "Failed to delete IdentityOIDCKey")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, key, identityOIDCKeyPath(key))
+
+			controllerutil.RemoveFinalizer(key, identityOIDCKeyFinalizer)
+			if err := r.Update(ctx, key); err != nil {
+				log.Error(err, "Failed to remove finalizer from IdentityOIDCKey")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// IdentityOIDCKey Initialization
+	if !controllerutil.ContainsFinalizer(key, identityOIDCKeyFinalizer) {
+		controllerutil.AddFinalizer(key, identityOIDCKeyFinalizer)
+		vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCKey, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, key); err != nil {
+			log.Error(err, "Failed to initialize IdentityOIDCKey status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultIdentityOIDCKey(ctx, key)
+	if err != nil {
+		log.Error(err, "Failed to fetch IdentityOIDCKey")
+		vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCKey, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch key from Vault"})
+		events.Failed(r.Recorder, key, "FailedToFetch", "Failed to fetch key from Vault", identityOIDCKeyPath(key), err)
+		if err := r.Status().Update(ctx, key); err != nil {
+			log.Error(err, "Failed to update IdentityOIDCKey status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&key.Spec) {
+		if err := r.updateVaultIdentityOIDCKey(ctx, key); err != nil {
+			log.Error(err, "Failed to update IdentityOIDCKey")
+			vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCKey, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push key to Vault: %s", err)})
+			events.Failed(r.Recorder, key, "FailedToUpdate", "Failed to push key to Vault", identityOIDCKeyPath(key), err)
+			if err := r.Status().Update(ctx, key); err != nil {
+				log.Error(err, "Failed to update IdentityOIDCKey status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, key, key.Status.Conditions, current != nil, "IdentityOIDCKey", identityOIDCKeyPath(key))
+	}
+
+	key.Status.ObservedGeneration = key.Generation
+	key.Status.VaultPath = identityOIDCKeyPath(key)
+	vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCKey, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed key to Vault"})
+	if err := r.Status().Update(ctx, key); err != nil {
+		log.Error(err, "Failed to update IdentityOIDCKey status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func identityOIDCKeyPath(key *identityv1beta1.IdentityOIDCKey) string {
+	return fmt.Sprintf("identity/oidc/key/%s", key.Name)
+}
+
+func (r *IdentityOIDCKeyReconciler) deleteVaultIdentityOIDCKey(ctx context.Context, key *identityv1beta1.IdentityOIDCKey) error {
+	_, err := r.Vault.Delete(ctx, identityOIDCKeyPath(key))
+	return err
+}
+
+func (r *IdentityOIDCKeyReconciler) fetchVaultIdentityOIDCKey(ctx context.Context, key *identityv1beta1.IdentityOIDCKey) (*vault.IdentityOIDCKey, error) {
+	s, err := r.Vault.Read(ctx, identityOIDCKeyPath(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var k vault.IdentityOIDCKey
+	if err := json.Unmarshal(jsonBytes, &k); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+func (r *IdentityOIDCKeyReconciler) updateVaultIdentityOIDCKey(ctx context.Context, key *identityv1beta1.IdentityOIDCKey) error {
+	allowedClientIDs := key.Spec.AllowedClientIDs
+	if allowedClientIDs == nil {
+		allowedClientIDs = []string{}
+	}
+
+	data := map[string]interface{}{
+		"rotation_period":    key.Spec.RotationPeriod,
+		"verification_ttl":   key.Spec.VerificationTTL,
+		"allowed_client_ids": allowedClientIDs,
+		"algorithm":          key.Spec.Algorithm,
+	}
+	if _, err := r.Vault.Write(ctx, identityOIDCKeyPath(key), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	key.Status.LastAppliedHash = vault.Checksum(data)
+	key.Status.LastAppliedTime = &now
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IdentityOIDCKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.IdentityOIDCKey{}).
+		Named("identity-identityoidckey").
+		Complete(r)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+)
+
+var _ = Describe("IdentityOIDCKey Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-oidc-key"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *IdentityOIDCKeyReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+
+			controllerReconciler = &IdentityOIDCKeyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind IdentityOIDCKey")
+			resource := &identityv1beta1.IdentityOIDCKey{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.IdentityOIDCKey{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.IdentityOIDCKeySpec{
+						RotationPeriod:   "1h",
+						VerificationTTL:  "2h",
+						AllowedClientIDs: []string{"*"},
+						Algorithm:        "ES256",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.IdentityOIDCKey{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance IdentityOIDCKey")
+				vaultServer.ClearFaults()
+				_, _ = vaultServer.Client().Delete(ctx, "identity/oidc/role/test-oidc-key-user")
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should create the key", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, "identity/oidc/key/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			Expect(s.Data["rotation_period"]).To(Equal(json.Number("3600")))
+			Expect(s.Data["verification_ttl"]).To(Equal(json.Number("7200")))
+			Expect(s.Data["algorithm"]).To(Equal("ES256"))
+
+			resource := &identityv1beta1.IdentityOIDCKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(identityOIDCKeyFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredIdentityOIDCKey)).To(BeTrue())
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == "identity/oidc/key/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should retry deletion while the key is in use", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			_, err := vaultServer.Client().Write(ctx, "identity/oidc/role/test-oidc-key-user", map[string]interface{}{"key": resourceName})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &identityv1beta1.IdentityOIDCKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			_, err = vaultServer.Client().Delete(ctx, "identity/oidc/role/test-oidc-key-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, "identity/oidc/key/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	identityOIDCRoleFinalizer = "identityoidcrole.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredIdentityOIDCRole = "Configured"
+)
+
+// IdentityOIDCRoleReconciler reconciles a IdentityOIDCRole object
+type IdentityOIDCRoleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityoidcroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityoidcroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=identityoidcroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The role is named after the resource. The client ID Vault generates for it
+// is the audience of its tokens and is reported in the status, so that it can
+// be allowed by the key.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *IdentityOIDCRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the IdentityOIDCRole instance
+	role := &identityv1beta1.IdentityOIDCRole{}
+	if err := r.Get(ctx, req.NamespacedName, role); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("IdentityOIDCRole resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get IdentityOIDCRole")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, role, &role.Status.Conditions, r.Mode, identityOIDCRoleFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag IdentityOIDCRole in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// IdentityOIDCRole Deletion
+	isRoleMarkedToBeDeleted := role.GetDeletionTimestamp() != nil
+	if isRoleMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(role, identityOIDCRoleFinalizer) {
+			if err := r.deleteVaultIdentityOIDCRole(ctx, role); err != nil {
+				log.Error(err, "Failed to delete IdentityOIDCRole")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, role, identityOIDCRolePath(role))
+
+			controllerutil.RemoveFinalizer(role, identityOIDCRoleFinalizer)
+			if err := r.Update(ctx, role); err != nil {
+				log.Error(err, "Failed to remove finalizer from IdentityOIDCRole")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// IdentityOIDCRole Initialization
+	if !controllerutil.ContainsFinalizer(role, identityOIDCRoleFinalizer) {
+		controllerutil.AddFinalizer(role, identityOIDCRoleFinalizer)
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCRole, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, role); err != nil {
+			log.Error(err, "Failed to initialize IdentityOIDCRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultIdentityOIDCRole(ctx, role)
+	if err != nil {
+		log.Error(err, "Failed to fetch IdentityOIDCRole")
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCRole, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch role from Vault"})
+		events.Failed(r.Recorder, role, "FailedToFetch", "Failed to fetch role from Vault", identityOIDCRolePath(role), err)
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update IdentityOIDCRole status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&role.Spec) {
+		if err := r.updateVaultIdentityOIDCRole(ctx, role); err != nil {
+			log.Error(err, "Failed to update IdentityOIDCRole")
+			vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push role to Vault: %s", err)})
+			events.Failed(r.Recorder, role, "FailedToUpdate", "Failed to push role to Vault", identityOIDCRolePath(role), err)
+			if err := r.Status().Update(ctx, role); err != nil {
+				log.Error(err, "Failed to update IdentityOIDCRole status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, role, role.Status.Conditions, current != nil, "IdentityOIDCRole", identityOIDCRolePath(role))
+
+		// Read back the client ID of a new role
+		if current, err = r.fetchVaultIdentityOIDCRole(ctx, role); err != nil {
+			log.Error(err, "Failed to fetch IdentityOIDCRole")
+			return ctrl.Result{}, err
+		}
+		if current == nil {
+			return ctrl.Result{}, fmt.Errorf("role %s not found after update", role.Name)
+		}
+	}
+
+	role.Status.ClientID = current.ClientID
+	role.Status.ObservedGeneration = role.Generation
+	role.Status.VaultPath = identityOIDCRolePath(role)
+	vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredIdentityOIDCRole, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed role to Vault"})
+	if err := r.Status().Update(ctx, role); err != nil {
+		log.Error(err, "Failed to update IdentityOIDCRole status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func identityOIDCRolePath(role *identityv1beta1.IdentityOIDCRole) string {
+	return fmt.Sprintf("identity/oidc/role/%s", role.Name)
+}
+
+func (r *IdentityOIDCRoleReconciler) deleteVaultIdentityOIDCRole(ctx context.Context, role *identityv1beta1.IdentityOIDCRole) error {
+	_, err := r.Vault.Delete(ctx, identityOIDCRolePath(role))
+	return err
+}
+
+func (r *IdentityOIDCRoleReconciler) fetchVaultIdentityOIDCRole(ctx context.Context, role *identityv1beta1.IdentityOIDCRole) (*vault.IdentityOIDCRole, error) {
+	s, err := r.Vault.Read(ctx, identityOIDCRolePath(role))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var ro vault.IdentityOIDCRole
+	if err := json.Unmarshal(jsonBytes, &ro); err != nil {
+		return nil, err
+	}
+
+	return &ro, nil
+}
+
+func (r *IdentityOIDCRoleReconciler) updateVaultIdentityOIDCRole(ctx context.Context, role *identityv1beta1.IdentityOIDCRole) error {
+	data := map[string]interface{}{
+		"key":      role.Spec.Key,
+		"template": role.Spec.Template,
+		"ttl":      role.Spec.TTL,
+	}
+	if _, err := r.Vault.Write(ctx, identityOIDCRolePath(role), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	role.Status.LastAppliedHash = vault.Checksum(data)
+	role.Status.LastAppliedTime = &now
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IdentityOIDCRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.IdentityOIDCRole{}).
+		Named("identity-identityoidcrole").
+		Complete(r)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+)
+
+var _ = Describe("IdentityOIDCRole Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-oidc-role"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *IdentityOIDCRoleReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+
+			controllerReconciler = &IdentityOIDCRoleReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind IdentityOIDCRole")
+			resource := &identityv1beta1.IdentityOIDCRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.IdentityOIDCRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.IdentityOIDCRoleSpec{
+						Key:      "default",
+						Template: `{"team": {{identity.entity.metadata.team}}}`,
+						TTL:      "1h",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.IdentityOIDCRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance IdentityOIDCRole")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should create the role and report its client ID", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, "identity/oidc/role/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			Expect(s.Data["key"]).To(Equal("default"))
+			Expect(s.Data["ttl"]).To(Equal(json.Number("3600")))
+
+			resource := &identityv1beta1.IdentityOIDCRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(identityOIDCRoleFinalizer))
+			Expect(resource.Status.ClientID).To(Equal(s.Data["client_id"]))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredIdentityOIDCRole)).To(BeTrue())
+		})
+
+		It("should report a missing key", func() {
+			resource := &identityv1beta1.IdentityOIDCRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Key = "missing"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredIdentityOIDCRole)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+
+		It("should delete the role from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.IdentityOIDCRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, "identity/oidc/role/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeNil())
+		})
+	})
+})
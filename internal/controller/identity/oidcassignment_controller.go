@@ -0,0 +1,301 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	oidcAssignmentFinalizer = "oidcassignment.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredOIDCAssignment = "Configured"
+)
+
+// OIDCAssignmentReconciler reconciles a OIDCAssignment object
+type OIDCAssignmentReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcassignments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcassignments/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcassignments/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The assignment is named after the resource. Its entities and groups are
+// resolved from the IDs reported by the referenced resources. Vault refuses to
+// delete an assignment still used by clients, in which case deletion is
+// retried.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *OIDCAssignmentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the OIDCAssignment instance
+	assignment := &identityv1beta1.OIDCAssignment{}
+	if err := r.Get(ctx, req.NamespacedName, assignment); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("OIDCAssignment resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get OIDCAssignment")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, assignment, &assignment.Status.Conditions, r.Mode, oidcAssignmentFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag OIDCAssignment in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// OIDCAssignment Deletion
+	isAssignmentMarkedToBeDeleted := assignment.GetDeletionTimestamp() != nil
+	if isAssignmentMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(assignment, oidcAssignmentFinalizer) {
+			if err := r.deleteVaultOIDCAssignment(ctx, assignment); err != nil {
+				log.Error(err, "Failed to delete OIDCAssignment")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, assignment, oidcAssignmentPath(assignment))
+
+			controllerutil.RemoveFinalizer(assignment, oidcAssignmentFinalizer)
+			if err := r.Update(ctx, assignment); err != nil {
+				log.Error(err, "Failed to remove finalizer from OIDCAssignment")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// OIDCAssignment Initialization
+	if !controllerutil.ContainsFinalizer(assignment, oidcAssignmentFinalizer) {
+		controllerutil.AddFinalizer(assignment, oidcAssignmentFinalizer)
+		vault.SetCondition(&assignment.Status.Conditions, assignment.Generation, metav1.Condition{Type: typeConfiguredOIDCAssignment, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, assignment); err != nil {
+			log.Error(err, "Failed to initialize OIDCAssignment status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	entityIDs, groupIDs, err := r.resolveReferences(ctx, assignment)
+	if err != nil {
+		log.Error(err, "Failed to resolve OIDCAssignment references")
+		vault.SetCondition(&assignment.Status.Conditions, assignment.Generation, metav1.Condition{Type: typeConfiguredOIDCAssignment, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to resolve references: %s", err)})
+		events.Failed(r.Recorder, assignment, "FailedToFetch", "Failed to resolve references", "", err)
+		if err := r.Status().Update(ctx, assignment); err != nil {
+			log.Error(err, "Failed to update OIDCAssignment status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create or update
+	current, err := r.fetchVaultOIDCAssignment(ctx, assignment)
+	if err != nil {
+		log.Error(err, "Failed to fetch OIDCAssignment")
+		vault.SetCondition(&assignment.Status.Conditions, assignment.Generation, metav1.Condition{Type: typeConfiguredOIDCAssignment, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch assignment from Vault"})
+		events.Failed(r.Recorder, assignment, "FailedToFetch", "Failed to fetch assignment from Vault", oidcAssignmentPath(assignment), err)
+		if err := r.Status().Update(ctx, assignment); err != nil {
+			log.Error(err, "Failed to update OIDCAssignment status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(entityIDs, groupIDs) {
+		if err := r.updateVaultOIDCAssignment(ctx, assignment, entityIDs, groupIDs); err != nil {
+			log.Error(err, "Failed to update OIDCAssignment")
+			vault.SetCondition(&assignment.Status.Conditions, assignment.Generation, metav1.Condition{Type: typeConfiguredOIDCAssignment, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push assignment to Vault: %s", err)})
+			events.Failed(r.Recorder, assignment, "FailedToUpdate", "Failed to push assignment to Vault", oidcAssignmentPath(assignment), err)
+			if err := r.Status().Update(ctx, assignment); err != nil {
+				log.Error(err, "Failed to update OIDCAssignment status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, assignment, assignment.Status.Conditions, current != nil, "OIDCAssignment", oidcAssignmentPath(assignment))
+	}
+
+	assignment.Status.ObservedGeneration = assignment.Generation
+	assignment.Status.VaultPath = oidcAssignmentPath(assignment)
+	vault.SetCondition(&assignment.Status.Conditions, assignment.Generation, metav1.Condition{Type: typeConfiguredOIDCAssignment, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed assignment to Vault"})
+	if err := r.Status().Update(ctx, assignment); err != nil {
+		log.Error(err, "Failed to update OIDCAssignment status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveReferences returns the sorted IDs of the referenced entities and
+// groups.
+func (r *OIDCAssignmentReconciler) resolveReferences(ctx context.Context, assignment *identityv1beta1.OIDCAssignment) ([]string, []string, error) {
+	entityIDs := []string{}
+	for _, ref := range assignment.Spec.EntityRefs {
+		entity := &identityv1beta1.IdentityEntity{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: assignment.Namespace}, entity); err != nil {
+			return nil, nil, fmt.Errorf("failed to get entity %s: %w", ref.Name, err)
+		}
+		if entity.Status.EntityID == "" {
+			return nil, nil, fmt.Errorf("entity %s is not ready", entity.Name)
+		}
+		entityIDs = append(entityIDs, entity.Status.EntityID)
+	}
+
+	groupIDs := []string{}
+	for _, ref := range assignment.Spec.GroupRefs {
+		id, err := fetchGroupID(ctx, r.Client, assignment.Namespace, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	slices.Sort(entityIDs)
+	slices.Sort(groupIDs)
+	return slices.Compact(entityIDs), slices.Compact(groupIDs), nil
+}
+
+func oidcAssignmentPath(assignment *identityv1beta1.OIDCAssignment) string {
+	return fmt.Sprintf("identity/oidc/assignment/%s", assignment.Name)
+}
+
+func (r *OIDCAssignmentReconciler) deleteVaultOIDCAssignment(ctx context.Context, assignment *identityv1beta1.OIDCAssignment) error {
+	_, err := r.Vault.Delete(ctx, oidcAssignmentPath(assignment))
+	return err
+}
+
+func (r *OIDCAssignmentReconciler) fetchVaultOIDCAssignment(ctx context.Context, assignment *identityv1beta1.OIDCAssignment) (*vault.OIDCAssignment, error) {
+	s, err := r.Vault.Read(ctx, oidcAssignmentPath(assignment))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var a vault.OIDCAssignment
+	if err := json.Unmarshal(jsonBytes, &a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (r *OIDCAssignmentReconciler) updateVaultOIDCAssignment(ctx context.Context, assignment *identityv1beta1.OIDCAssignment, entityIDs, groupIDs []string) error {
+	data := map[string]interface{}{
+		"entity_ids": entityIDs,
+		"group_ids":  groupIDs,
+	}
+	if _, err := r.Vault.Write(ctx, oidcAssignmentPath(assignment), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	assignment.Status.LastAppliedHash = vault.Checksum(data)
+	assignment.Status.LastAppliedTime = &now
+	return nil
+}
+
+// findAssignmentsForEntity requeues the assignments referencing an entity.
+func (r *OIDCAssignmentReconciler) findAssignmentsForEntity(ctx context.Context, entity client.Object) []reconcile.Request {
+	assignments := &identityv1beta1.OIDCAssignmentList{}
+	if err := r.List(ctx, assignments, client.InNamespace(entity.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list OIDCAssignments")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, a := range assignments.Items {
+		if slices.ContainsFunc(a.Spec.EntityRefs, func(ref identityv1beta1.IdentityEntityReference) bool { return ref.Name == entity.GetName() }) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name, Namespace: a.Namespace}})
+		}
+	}
+	return requests
+}
+
+// findAssignmentsForGroup requeues the assignments referencing a group.
+func (r *OIDCAssignmentReconciler) findAssignmentsForGroup(ctx context.Context, group client.Object) []reconcile.Request {
+	assignments := &identityv1beta1.OIDCAssignmentList{}
+	if err := r.List(ctx, assignments, client.InNamespace(group.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list OIDCAssignments")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, a := range assignments.Items {
+		if slices.ContainsFunc(a.Spec.GroupRefs, func(ref identityv1beta1.IdentityGroupReference) bool { return ref.Name == group.GetName() }) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name, Namespace: a.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OIDCAssignmentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.OIDCAssignment{}).
+		Watches(&identityv1beta1.IdentityEntity{}, handler.EnqueueRequestsFromMapFunc(r.findAssignmentsForEntity)).
+		Watches(&identityv1beta1.IdentityGroup{}, handler.EnqueueRequestsFromMapFunc(r.findAssignmentsForGroup)).
+		Named("identity-oidcassignment").
+		Complete(r)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+)
+
+var _ = Describe("OIDCAssignment Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-oidc-assignment"
+		const entityName = "test-oidc-assignment-entity"
+		const groupName = "test-oidc-assignment-group"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *OIDCAssignmentReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+
+			controllerReconciler = &OIDCAssignmentReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("preparing the referenced entity and group")
+			entity := &identityv1beta1.IdentityEntity{ObjectMeta: metav1.ObjectMeta{Name: entityName, Namespace: "default"}}
+			Expect(k8sClient.Create(ctx, entity)).To(Succeed())
+			entity.Status.EntityID = "entity-id"
+			Expect(k8sClient.Status().Update(ctx, entity)).To(Succeed())
+
+			group := &identityv1beta1.IdentityGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: groupName, Namespace: "default"},
+				Spec:       identityv1beta1.IdentityGroupSpec{Type: "internal"},
+			}
+			Expect(k8sClient.Create(ctx, group)).To(Succeed())
+			group.Status.GroupID = "group-id"
+			Expect(k8sClient.Status().Update(ctx, group)).To(Succeed())
+
+			By("creating the custom resource for the Kind OIDCAssignment")
+			resource := &identityv1beta1.OIDCAssignment{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.OIDCAssignment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.OIDCAssignmentSpec{
+						EntityRefs: []identityv1beta1.IdentityEntityReference{{Name: entityName}},
+						GroupRefs:  []identityv1beta1.IdentityGroupReference{{Name: groupName}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.OIDCAssignment{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance OIDCAssignment")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			Expect(k8sClient.Delete(ctx, &identityv1beta1.IdentityEntity{ObjectMeta: metav1.ObjectMeta{Name: entityName, Namespace: "default"}})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &identityv1beta1.IdentityGroup{ObjectMeta: metav1.ObjectMeta{Name: groupName, Namespace: "default"}})).To(Succeed())
+		})
+
+		It("should create the assignment with its references resolved by name", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, "identity/oidc/assignment/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			Expect(s.Data["entity_ids"]).To(Equal([]interface{}{"entity-id"}))
+			Expect(s.Data["group_ids"]).To(Equal([]interface{}{"group-id"}))
+
+			resource := &identityv1beta1.OIDCAssignment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(oidcAssignmentFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredOIDCAssignment)).To(BeTrue())
+		})
+
+		It("should follow a group recreated in Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			group := &identityv1beta1.IdentityGroup{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: groupName, Namespace: "default"}, group)).To(Succeed())
+			group.Status.GroupID = "new-group-id"
+			Expect(k8sClient.Status().Update(ctx, group)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, "identity/oidc/assignment/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Data["group_ids"]).To(Equal([]interface{}{"new-group-id"}))
+		})
+
+		It("should wait for references to be ready", func() {
+			entity := &identityv1beta1.IdentityEntity{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: entityName, Namespace: "default"}, entity)).To(Succeed())
+			entity.Status.EntityID = ""
+			Expect(k8sClient.Status().Update(ctx, entity)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &identityv1beta1.OIDCAssignment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredOIDCAssignment)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+			Expect(condition.Message).To(ContainSubstring("not ready"))
+		})
+	})
+})
@@ -0,0 +1,285 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	oidcClientFinalizer = "oidcclient.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredOIDCClient = "Configured"
+)
+
+// OIDCClientReconciler reconciles a OIDCClient object
+type OIDCClientReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcclients,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcclients/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcclients/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The client is named after the resource. The client ID and, for confidential
+// clients, the client secret generated by Vault are written to the target
+// Secret, which is owned by the resource and deleted or released along with it
+// depending on the deletion policy.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *OIDCClientReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the OIDCClient instance
+	oidcClient := &identityv1beta1.OIDCClient{}
+	if err := r.Get(ctx, req.NamespacedName, oidcClient); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("OIDCClient resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get OIDCClient")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, oidcClient, &oidcClient.Status.Conditions, r.Mode, oidcClientFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag OIDCClient in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// OIDCClient Deletion
+	isClientMarkedToBeDeleted := oidcClient.GetDeletionTimestamp() != nil
+	if isClientMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(oidcClient, oidcClientFinalizer) {
+			if err := r.deleteVaultOIDCClient(ctx, oidcClient); err != nil {
+				log.Error(err, "Failed to delete OIDCClient")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, oidcClient, oidcClientPath(oidcClient))
+
+			if oidcClient.Spec.Target.DeletionPolicy == "Delete" {
+				if err := deleteK8sSecret(ctx, r.Client, oidcClient, oidcClient.Spec.Target.Name); err != nil {
+					log.Error(err, "Failed to delete Secret")
+					return ctrl.Result{}, err
+				}
+			} else {
+				if err := releaseK8sSecret(ctx, r.Client, oidcClient, oidcClient.Spec.Target.Name); err != nil {
+					log.Error(err, "Failed to release Secret")
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(oidcClient, oidcClientFinalizer)
+			if err := r.Update(ctx, oidcClient); err != nil {
+				log.Error(err, "Failed to remove finalizer from OIDCClient")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// OIDCClient Initialization
+	if !controllerutil.ContainsFinalizer(oidcClient, oidcClientFinalizer) {
+		controllerutil.AddFinalizer(oidcClient, oidcClientFinalizer)
+		vault.SetCondition(&oidcClient.Status.Conditions, oidcClient.Generation, metav1.Condition{Type: typeConfiguredOIDCClient, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, oidcClient); err != nil {
+			log.Error(err, "Failed to initialize OIDCClient status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultOIDCClient(ctx, oidcClient)
+	if err != nil {
+		log.Error(err, "Failed to fetch OIDCClient")
+		vault.SetCondition(&oidcClient.Status.Conditions, oidcClient.Generation, metav1.Condition{Type: typeConfiguredOIDCClient, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch client from Vault"})
+		events.Failed(r.Recorder, oidcClient, "FailedToFetch", "Failed to fetch client from Vault", oidcClientPath(oidcClient), err)
+		if err := r.Status().Update(ctx, oidcClient); err != nil {
+			log.Error(err, "Failed to update OIDCClient status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&oidcClient.Spec) {
+		if err := r.updateVaultOIDCClient(ctx, oidcClient); err != nil {
+			log.Error(err, "Failed to update OIDCClient")
+			vault.SetCondition(&oidcClient.Status.Conditions, oidcClient.Generation, metav1.Condition{Type: typeConfiguredOIDCClient, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push client to Vault: %s", err)})
+			events.Failed(r.Recorder, oidcClient, "FailedToUpdate", "Failed to push client to Vault", oidcClientPath(oidcClient), err)
+			if err := r.Status().Update(ctx, oidcClient); err != nil {
+				log.Error(err, "Failed to update OIDCClient status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, oidcClient, oidcClient.Status.Conditions, current != nil, "OIDCClient", oidcClientPath(oidcClient))
+
+		// Read back the credentials of a new client
+		if current, err = r.fetchVaultOIDCClient(ctx, oidcClient); err != nil {
+			log.Error(err, "Failed to fetch OIDCClient")
+			return ctrl.Result{}, err
+		}
+		if current == nil {
+			return ctrl.Result{}, fmt.Errorf("client %s not found after update", oidcClient.Name)
+		}
+	}
+
+	if err := r.applyK8sSecret(ctx, oidcClient, current); err != nil {
+		log.Error(err, "Failed to apply k8s secret")
+		vault.SetCondition(&oidcClient.Status.Conditions, oidcClient.Generation, metav1.Condition{Type: typeConfiguredOIDCClient, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to create k8s secret %s", oidcClient.Spec.Target.Name)})
+		events.Failed(r.Recorder, oidcClient, "FailedToCreate", fmt.Sprintf("Failed to create k8s secret %s", oidcClient.Spec.Target.Name), "", err)
+		if err := r.Status().Update(ctx, oidcClient); err != nil {
+			log.Error(err, "Failed to update OIDCClient status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	oidcClient.Status.ClientID = current.ClientID
+	oidcClient.Status.ObservedGeneration = oidcClient.Generation
+	oidcClient.Status.VaultPath = oidcClientPath(oidcClient)
+	vault.SetCondition(&oidcClient.Status.Conditions, oidcClient.Generation, metav1.Condition{Type: typeConfiguredOIDCClient, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed client to Vault"})
+	if err := r.Status().Update(ctx, oidcClient); err != nil {
+		log.Error(err, "Failed to update OIDCClient status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func oidcClientPath(oidcClient *identityv1beta1.OIDCClient) string {
+	return fmt.Sprintf("identity/oidc/client/%s", oidcClient.Name)
+}
+
+func (r *OIDCClientReconciler) deleteVaultOIDCClient(ctx context.Context, oidcClient *identityv1beta1.OIDCClient) error {
+	_, err := r.Vault.Delete(ctx, oidcClientPath(oidcClient))
+	return err
+}
+
+func (r *OIDCClientReconciler) fetchVaultOIDCClient(ctx context.Context, oidcClient *identityv1beta1.OIDCClient) (*vault.OIDCClient, error) {
+	s, err := r.Vault.Read(ctx, oidcClientPath(oidcClient))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var c vault.OIDCClient
+	if err := json.Unmarshal(jsonBytes, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (r *OIDCClientReconciler) updateVaultOIDCClient(ctx context.Context, oidcClient *identityv1beta1.OIDCClient) error {
+	redirectURIs := oidcClient.Spec.RedirectURIs
+	if redirectURIs == nil {
+		redirectURIs = []string{}
+	}
+	assignments := oidcClient.Spec.Assignments
+	if assignments == nil {
+		assignments = []string{}
+	}
+
+	data := map[string]interface{}{
+		"key":              oidcClient.Spec.Key,
+		"redirect_uris":    redirectURIs,
+		"assignments":      assignments,
+		"client_type":      oidcClient.Spec.ClientType,
+		"id_token_ttl":     oidcClient.Spec.IDTokenTTL,
+		"access_token_ttl": oidcClient.Spec.AccessTokenTTL,
+	}
+	if _, err := r.Vault.Write(ctx, oidcClientPath(oidcClient), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	oidcClient.Status.LastAppliedHash = vault.Checksum(data)
+	oidcClient.Status.LastAppliedTime = &now
+	return nil
+}
+
+// applyK8sSecret writes the credentials of the client to the target Secret.
+// Public clients have no secret.
+func (r *OIDCClientReconciler) applyK8sSecret(ctx context.Context, oidcClient *identityv1beta1.OIDCClient, current *vault.OIDCClient) error {
+	existing, err := fetchK8sSecret(ctx, r.Client, oidcClient.Namespace, oidcClient.Spec.Target.Name)
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{"client_id": []byte(current.ClientID)}
+	if current.ClientSecret != "" {
+		data["client_secret"] = []byte(current.ClientSecret)
+	}
+
+	return applyK8sSecret(ctx, r.Client, r.Scheme, oidcClient, oidcClient.Spec.Target.Name, existing, data)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OIDCClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.OIDCClient{}).
+		Owns(&corev1.Secret{}).
+		Named("identity-oidcclient").
+		Complete(r)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+)
+
+var _ = Describe("OIDCClient Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-oidc-client"
+		const secretName = "test-oidc-client-credentials"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		secretNamespacedName := types.NamespacedName{
+			Name:      secretName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *OIDCClientReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+
+			controllerReconciler = &OIDCClientReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind OIDCClient")
+			resource := &identityv1beta1.OIDCClient{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.OIDCClient{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.OIDCClientSpec{
+						Key:            "default",
+						RedirectURIs:   []string{"https://app.example.com/callback"},
+						Assignments:    []string{"allow_all"},
+						ClientType:     "confidential",
+						IDTokenTTL:     "1h",
+						AccessTokenTTL: "24h",
+						Target: identityv1beta1.OIDCClientTarget{
+							Name:           secretName,
+							DeletionPolicy: "Delete",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.OIDCClient{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance OIDCClient")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			// envtest does not garbage collect owned Secrets
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, secretNamespacedName, secret); err == nil {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			}
+		})
+
+		It("should create the client and write its credentials to the Secret", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			c, ok := vaultServer.OIDCClient(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(c.RedirectURIs).To(Equal([]string{"https://app.example.com/callback"}))
+			Expect(c.IDTokenTTL).To(Equal(3600))
+
+			resource := &identityv1beta1.OIDCClient{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(oidcClientFinalizer))
+			Expect(resource.Status.ClientID).To(Equal(c.ClientID))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredOIDCClient)).To(BeTrue())
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			Expect(string(secret.Data["client_id"])).To(Equal(c.ClientID))
+			Expect(string(secret.Data["client_secret"])).To(Equal(c.ClientSecret))
+			Expect(metav1.IsControlledBy(secret, resource)).To(BeTrue())
+		})
+
+		It("should write no client secret for public clients", func() {
+			public := &identityv1beta1.OIDCClient{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-oidc-client-public", Namespace: "default"},
+				Spec: identityv1beta1.OIDCClientSpec{
+					Key:            "default",
+					ClientType:     "public",
+					IDTokenTTL:     "24h",
+					AccessTokenTTL: "24h",
+					Target:         identityv1beta1.OIDCClientTarget{Name: "test-oidc-client-public", DeletionPolicy: "Delete"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, public)).To(Succeed())
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: public.Name, Namespace: "default"}}
+
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, request.NamespacedName, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKey("client_id"))
+			Expect(secret.Data).NotTo(HaveKey("client_secret"))
+
+			Expect(k8sClient.Get(ctx, request.NamespacedName, public)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, public)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, request.NamespacedName, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should refuse to overwrite a Secret it does not own", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "default"},
+				Data:       map[string][]byte{"client_id": []byte("foreign")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &identityv1beta1.OIDCClient{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredOIDCClient)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			Expect(string(secret.Data["client_id"])).To(Equal("foreign"))
+		})
+
+		It("should rewrite the Secret when the client is recreated in Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			previous, _ := vaultServer.OIDCClient(resourceName)
+
+			_, err := vaultServer.Client().Delete(ctx, "identity/oidc/client/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconcileOnce()).To(Succeed())
+
+			c, ok := vaultServer.OIDCClient(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(c.ClientID).NotTo(Equal(previous.ClientID))
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			Expect(string(secret.Data["client_id"])).To(Equal(c.ClientID))
+		})
+
+		It("should keep the Secret with the Retain deletion policy", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &identityv1beta1.OIDCClient{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Target.DeletionPolicy = "Retain"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.OIDCClient(resourceName)
+			Expect(ok).To(BeFalse())
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			Expect(secret.OwnerReferences).To(BeEmpty())
+		})
+	})
+})
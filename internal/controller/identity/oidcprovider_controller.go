@@ -0,0 +1,291 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	oidcProviderFinalizer = "oidcprovider.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredOIDCProvider = "Configured"
+)
+
+// OIDCProviderReconciler reconciles a OIDCProvider object
+type OIDCProviderReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcproviders,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcproviders/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcproviders/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The provider is named after the resource. Allowed clients are resolved from
+// the client IDs reported by the referenced OIDCClients, and the issuer URL
+// Vault serves the provider under is reported in the status.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *OIDCProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the OIDCProvider instance
+	provider := &identityv1beta1.OIDCProvider{}
+	if err := r.Get(ctx, req.NamespacedName, provider); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("OIDCProvider resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get OIDCProvider")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, provider, &provider.Status.Conditions, r.Mode, oidcProviderFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag OIDCProvider in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// OIDCProvider Deletion
+	isProviderMarkedToBeDeleted := provider.GetDeletionTimestamp() != nil
+	if isProviderMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(provider, oidcProviderFinalizer) {
+			if err := r.deleteVaultOIDCProvider(ctx, provider); err != nil {
+				log.Error(err, "Failed to delete OIDCProvider")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, provider, oidcProviderPath(provider))
+
+			controllerutil.RemoveFinalizer(provider, oidcProviderFinalizer)
+			if err := r.Update(ctx, provider); err != nil {
+				log.Error(err, "Failed to remove finalizer from OIDCProvider")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// OIDCProvider Initialization
+	if !controllerutil.ContainsFinalizer(provider, oidcProviderFinalizer) {
+		controllerutil.AddFinalizer(provider, oidcProviderFinalizer)
+		vault.SetCondition(&provider.Status.Conditions, provider.Generation, metav1.Condition{Type: typeConfiguredOIDCProvider, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, provider); err != nil {
+			log.Error(err, "Failed to initialize OIDCProvider status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	allowedClientIDs, err := r.resolveReferences(ctx, provider)
+	if err != nil {
+		log.Error(err, "Failed to resolve OIDCProvider references")
+		vault.SetCondition(&provider.Status.Conditions, provider.Generation, metav1.Condition{Type: typeConfiguredOIDCProvider, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to resolve references: %s", err)})
+		events.Failed(r.Recorder, provider, "FailedToFetch", "Failed to resolve references", "", err)
+		if err := r.Status().Update(ctx, provider); err != nil {
+			log.Error(err, "Failed to update OIDCProvider status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create or update
+	current, err := r.fetchVaultOIDCProvider(ctx, provider)
+	if err != nil {
+		log.Error(err, "Failed to fetch OIDCProvider")
+		vault.SetCondition(&provider.Status.Conditions, provider.Generation, metav1.Condition{Type: typeConfiguredOIDCProvider, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch provider from Vault"})
+		events.Failed(r.Recorder, provider, "FailedToFetch", "Failed to fetch provider from Vault", oidcProviderPath(provider), err)
+		if err := r.Status().Update(ctx, provider); err != nil {
+			log.Error(err, "Failed to update OIDCProvider status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&provider.Spec, allowedClientIDs) {
+		if err := r.updateVaultOIDCProvider(ctx, provider, allowedClientIDs); err != nil {
+			log.Error(err, "Failed to update OIDCProvider")
+			vault.SetCondition(&provider.Status.Conditions, provider.Generation, metav1.Condition{Type: typeConfiguredOIDCProvider, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push provider to Vault: %s", err)})
+			events.Failed(r.Recorder, provider, "FailedToUpdate", "Failed to push provider to Vault", oidcProviderPath(provider), err)
+			if err := r.Status().Update(ctx, provider); err != nil {
+				log.Error(err, "Failed to update OIDCProvider status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, provider, provider.Status.Conditions, current != nil, "OIDCProvider", oidcProviderPath(provider))
+
+		// Read back the issuer of a new provider
+		if current, err = r.fetchVaultOIDCProvider(ctx, provider); err != nil {
+			log.Error(err, "Failed to fetch OIDCProvider")
+			return ctrl.Result{}, err
+		}
+		if current == nil {
+			return ctrl.Result{}, fmt.Errorf("provider %s not found after update", provider.Name)
+		}
+	}
+
+	provider.Status.Issuer = current.Issuer
+	provider.Status.ObservedGeneration = provider.Generation
+	provider.Status.VaultPath = oidcProviderPath(provider)
+	vault.SetCondition(&provider.Status.Conditions, provider.Generation, metav1.Condition{Type: typeConfiguredOIDCProvider, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed provider to Vault"})
+	if err := r.Status().Update(ctx, provider); err != nil {
+		log.Error(err, "Failed to update OIDCProvider status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveReferences returns the sorted client IDs of the referenced clients.
+func (r *OIDCProviderReconciler) resolveReferences(ctx context.Context, provider *identityv1beta1.OIDCProvider) ([]string, error) {
+	clientIDs := []string{}
+	for _, ref := range provider.Spec.AllowedClientRefs {
+		c := &identityv1beta1.OIDCClient{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: provider.Namespace}, c); err != nil {
+			return nil, fmt.Errorf("failed to get client %s: %w", ref.Name, err)
+		}
+		if c.Status.ClientID == "" {
+			return nil, fmt.Errorf("client %s is not ready", c.Name)
+		}
+		clientIDs = append(clientIDs, c.Status.ClientID)
+	}
+
+	slices.Sort(clientIDs)
+	return slices.Compact(clientIDs), nil
+}
+
+func oidcProviderPath(provider *identityv1beta1.OIDCProvider) string {
+	return fmt.Sprintf("identity/oidc/provider/%s", provider.Name)
+}
+
+func (r *OIDCProviderReconciler) deleteVaultOIDCProvider(ctx context.Context, provider *identityv1beta1.OIDCProvider) error {
+	_, err := r.Vault.Delete(ctx, oidcProviderPath(provider))
+	return err
+}
+
+func (r *OIDCProviderReconciler) fetchVaultOIDCProvider(ctx context.Context, provider *identityv1beta1.OIDCProvider) (*vault.OIDCProvider, error) {
+	s, err := r.Vault.Read(ctx, oidcProviderPath(provider))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var p vault.OIDCProvider
+	if err := json.Unmarshal(jsonBytes, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (r *OIDCProviderReconciler) updateVaultOIDCProvider(ctx context.Context, provider *identityv1beta1.OIDCProvider, allowedClientIDs []string) error {
+	scopesSupported := provider.Spec.ScopesSupported
+	if scopesSupported == nil {
+		scopesSupported = []string{}
+	}
+
+	data := map[string]interface{}{
+		"allowed_client_ids": allowedClientIDs,
+		"scopes_supported":   scopesSupported,
+	}
+	// Vault defaults to its api_addr
+	if provider.Spec.Issuer != "" {
+		data["issuer"] = provider.Spec.Issuer
+	}
+
+	if _, err := r.Vault.Write(ctx, oidcProviderPath(provider), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	provider.Status.LastAppliedHash = vault.Checksum(data)
+	provider.Status.LastAppliedTime = &now
+	return nil
+}
+
+// findProvidersForClient requeues the providers allowing a client.
+func (r *OIDCProviderReconciler) findProvidersForClient(ctx context.Context, c client.Object) []reconcile.Request {
+	providers := &identityv1beta1.OIDCProviderList{}
+	if err := r.List(ctx, providers, client.InNamespace(c.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list OIDCProviders")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, p := range providers.Items {
+		if slices.ContainsFunc(p.Spec.AllowedClientRefs, func(ref identityv1beta1.OIDCClientReference) bool { return ref.Name == c.GetName() }) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.Name, Namespace: p.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OIDCProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.OIDCProvider{}).
+		Watches(&identityv1beta1.OIDCClient{}, handler.EnqueueRequestsFromMapFunc(r.findProvidersForClient)).
+		Named("identity-oidcprovider").
+		Complete(r)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+)
+
+var _ = Describe("OIDCProvider Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-oidc-provider"
+		const clientName = "test-oidc-provider-client"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *OIDCProviderReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			v := vaultServer.Client()
+
+			controllerReconciler = &OIDCProviderReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  v,
+			}
+
+			By("preparing the scope and the allowed client")
+			_, err := v.Write(ctx, "identity/oidc/scope/groups", map[string]interface{}{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c := &identityv1beta1.OIDCClient{
+				ObjectMeta: metav1.ObjectMeta{Name: clientName, Namespace: "default"},
+				Spec: identityv1beta1.OIDCClientSpec{
+					Key:    "default",
+					Target: identityv1beta1.OIDCClientTarget{Name: clientName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, c)).To(Succeed())
+			c.Status.ClientID = "client-id"
+			Expect(k8sClient.Status().Update(ctx, c)).To(Succeed())
+
+			By("creating the custom resource for the Kind OIDCProvider")
+			resource := &identityv1beta1.OIDCProvider{}
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &identityv1beta1.OIDCProvider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: identityv1beta1.OIDCProviderSpec{
+						Issuer:            "https://vault.example.com:8200",
+						AllowedClientRefs: []identityv1beta1.OIDCClientReference{{Name: clientName}},
+						ScopesSupported:   []string{"groups"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &identityv1beta1.OIDCProvider{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance OIDCProvider")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			Expect(k8sClient.Delete(ctx, &identityv1beta1.OIDCClient{ObjectMeta: metav1.ObjectMeta{Name: clientName, Namespace: "default"}})).To(Succeed())
+		})
+
+		It("should create the provider and report its issuer", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, "identity/oidc/provider/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			Expect(s.Data["allowed_client_ids"]).To(Equal([]interface{}{"client-id"}))
+			Expect(s.Data["scopes_supported"]).To(Equal([]interface{}{"groups"}))
+
+			resource := &identityv1beta1.OIDCProvider{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(oidcProviderFinalizer))
+			Expect(resource.Status.Issuer).To(Equal("https://vault.example.com:8200/v1/identity/oidc/provider/" + resourceName))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredOIDCProvider)).To(BeTrue())
+		})
+
+		It("should wait for clients to be ready", func() {
+			c := &identityv1beta1.OIDCClient{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: clientName, Namespace: "default"}, c)).To(Succeed())
+			c.Status.ClientID = ""
+			Expect(k8sClient.Status().Update(ctx, c)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &identityv1beta1.OIDCProvider{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredOIDCProvider)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+			Expect(condition.Message).To(ContainSubstring("not ready"))
+		})
+
+		It("should report unsupported scopes", func() {
+			resource := &identityv1beta1.OIDCProvider{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.ScopesSupported = []string{"missing"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredOIDCProvider)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+	})
+})
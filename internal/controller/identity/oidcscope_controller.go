@@ -0,0 +1,217 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	identityv1beta1 "hopopops/vault-operator/api/identity/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	oidcScopeFinalizer = "oidcscope.identity.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredOIDCScope = "Configured"
+)
+
+// OIDCScopeReconciler reconciles a OIDCScope object
+type OIDCScopeReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcscopes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcscopes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=identity.toolkit.vault.hopopops.com,resources=oidcscopes/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The scope is named after the resource. Vault refuses to delete a scope still
+// supported by providers, in which case deletion is retried.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *OIDCScopeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the OIDCScope instance
+	scope := &identityv1beta1.OIDCScope{}
+	if err := r.Get(ctx, req.NamespacedName, scope); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("OIDCScope resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get OIDCScope")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, scope, &scope.Status.Conditions, r.Mode, oidcScopeFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag OIDCScope in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// OIDCScope Deletion
+	isScopeMarkedToBeDeleted := scope.GetDeletionTimestamp() != nil
+	if isScopeMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(scope, oidcScopeFinalizer) {
+			if err := r.deleteVaultOIDCScope(ctx, scope); err != nil {
+				log.Error(err, "Failed to delete OIDCScope")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, scope, oidcScopePath(scope))
+
+			controllerutil.RemoveFinalizer(scope, oidcScopeFinalizer)
+			if err := r.Update(ctx, scope); err != nil {
+				log.Error(err, "Failed to remove finalizer from OIDCScope")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// OIDCScope Initialization
+	if !controllerutil.ContainsFinalizer(scope, oidcScopeFinalizer) {
+		controllerutil.AddFinalizer(scope, oidcScopeFinalizer)
+		vault.SetCondition(&scope.Status.Conditions, scope.Generation, metav1.Condition{Type: typeConfiguredOIDCScope, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, scope); err != nil {
+			log.Error(err, "Failed to initialize OIDCScope status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultOIDCScope(ctx, scope)
+	if err != nil {
+		log.Error(err, "Failed to fetch OIDCScope")
+		vault.SetCondition(&scope.Status.Conditions, scope.Generation, metav1.Condition{Type: typeConfiguredOIDCScope, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch scope from Vault"})
+		events.Failed(r.Recorder, scope, "FailedToFetch", "Failed to fetch scope from Vault", oidcScopePath(scope), err)
+		if err := r.Status().Update(ctx, scope); err != nil {
+			log.Error(err, "Failed to update OIDCScope status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&scope.Spec) {
+		if err := r.updateVaultOIDCScope(ctx, scope); err != nil {
+			log.Error(err, "Failed to update OIDCScope")
+			vault.SetCondition(&scope.Status.Conditions, scope.Generation, metav1.Condition{Type: typeConfiguredOIDCScope, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push scope to Vault: %s", err)})
+			events.Failed(r.Recorder, scope, "FailedToUpdate", "Failed to push scope to Vault", oidcScopePath(scope), err)
+			if err := r.Status().Update(ctx, scope); err != nil {
+				log.Error(err, "Failed to update OIDCScope status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, scope, scope.Status.Conditions, current != nil, "OIDCScope", oidcScopePath(scope))
+	}
+
+	scope.Status.ObservedGeneration = scope.Generation
+	scope.Status.VaultPath = oidcScopePath(scope)
+	vault.SetCondition(&scope.Status.Conditions, scope.Generation, metav1.Condition{Type: typeConfiguredOIDCScope, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed scope to Vault"})
+	if err := r.Status().Update(ctx, scope); err != nil {
+		log.Error(err, "Failed to update OIDCScope status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func oidcScopePath(scope *identityv1beta1.OIDCScope) string {
+	return fmt.Sprintf("identity/oidc/scope/%s", scope.Name)
+}
+
+func (r *OIDCScopeReconciler) deleteVaultOIDCScope(ctx context.Context, scope *identityv1beta1.OIDCScope) error {
+	_, err := r.Vault.Delete(ctx, oidcScopePath(scope))
+	return err
+}
+
+func (r *OIDCScopeReconciler) fetchVaultOIDCScope(ctx context.Context, scope *identityv1beta1.OIDCScope) (*vault.OIDCScope, error) {
+	s, err := r.Vault.Read(ctx, oidcScopePath(scope))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var o vault.OIDCScope
+	if err := json.Unmarshal(jsonBytes, &o); err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+func (r *OIDCScopeReconciler) updateVaultOIDCScope(ctx context.Context, scope *identityv1beta1.OIDCScope) error {
+	data := map[string]interface{}{
+		"template":    scope.Spec.Template,
+		"description": scope.Spec.Description,
+	}
+	if _, err := r.Vault.Write(ctx, oidcScopePath(scope), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	scope.Status.LastAppliedHash = vault.Checksum(data)
+	scope.Status.LastAppliedTime = &now
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OIDCScopeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&identityv1beta1.OIDCScope{}).
+		Named("identity-oidcscope").
+		Complete(r)
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kvv1beta1 "hopopops/vault-operator/api/kv/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	kvSecretFinalizer = "kvsecret.kv.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredKVSecret = "Configured"
+)
+
+// KVSecretReconciler reconciles a KVSecret object
+type KVSecretReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=kv.toolkit.vault.hopopops.com,resources=kvsecrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kv.toolkit.vault.hopopops.com,resources=kvsecrets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kv.toolkit.vault.hopopops.com,resources=kvsecrets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *KVSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the KVSecret instance
+	kvSecret := &kvv1beta1.KVSecret{}
+	if err := r.Get(ctx, req.NamespacedName, kvSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("KVSecret resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get KVSecret")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, kvSecret, &kvSecret.Status.Conditions, r.Mode, kvSecretFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag KVSecret in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// KVSecret Deletion
+	isKVSecretMarkedToBeDeleted := kvSecret.GetDeletionTimestamp() != nil
+	if isKVSecretMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(kvSecret, kvSecretFinalizer) {
+			if err := r.deleteVaultKVSecret(ctx, kvSecret); err != nil {
+				log.Error(err, "Failed to delete KVSecret")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, kvSecret, kvSecretPath(kvSecret, "metadata"))
+
+			controllerutil.RemoveFinalizer(kvSecret, kvSecretFinalizer)
+			if err := r.Update(ctx, kvSecret); err != nil {
+				log.Error(err, "Failed to remove finalizer from KVSecret")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// KVSecret Initialization
+	if !controllerutil.ContainsFinalizer(kvSecret, kvSecretFinalizer) {
+		controllerutil.AddFinalizer(kvSecret, kvSecretFinalizer)
+		vault.SetCondition(&kvSecret.Status.Conditions, kvSecret.Generation, metav1.Condition{Type: typeConfiguredKVSecret, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, kvSecret); err != nil {
+			log.Error(err, "Failed to initialize KVSecret status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	data, sourceVersion, err := r.desiredData(ctx, kvSecret)
+	if err != nil {
+		log.Error(err, "Failed to build KVSecret data")
+		vault.SetCondition(&kvSecret.Status.Conditions, kvSecret.Generation, metav1.Condition{Type: typeConfiguredKVSecret, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to build secret data: %s", err)})
+		events.Failed(r.Recorder, kvSecret, "FailedToFetch", "Failed to build secret data", "", err)
+		if err := r.Status().Update(ctx, kvSecret); err != nil {
+			log.Error(err, "Failed to update KVSecret status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Metadata, only supported by KV v2
+	version := 0
+	if !isKVv1(kvSecret) {
+		metadata, err := r.fetchVaultKVMetadata(ctx, kvSecret)
+		if err != nil {
+			log.Error(err, "Failed to fetch KVSecret metadata")
+			vault.SetCondition(&kvSecret.Status.Conditions, kvSecret.Generation, metav1.Condition{Type: typeConfiguredKVSecret, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch secret metadata from Vault"})
+			events.Failed(r.Recorder, kvSecret, "FailedToFetch", "Failed to fetch secret metadata from Vault", kvSecretPath(kvSecret, "metadata"), err)
+			if err := r.Status().Update(ctx, kvSecret); err != nil {
+				log.Error(err, "Failed to update KVSecret status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		if metadata == nil || metadata.IsDifferentFromSpec(&kvSecret.Spec) {
+			if err := r.updateVaultKVMetadata(ctx, kvSecret); err != nil {
+				log.Error(err, "Failed to update KVSecret metadata")
+				vault.SetCondition(&kvSecret.Status.Conditions, kvSecret.Generation, metav1.Condition{Type: typeConfiguredKVSecret, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push secret metadata to Vault"})
+				events.Failed(r.Recorder, kvSecret, "FailedToUpdate", "Failed to push secret metadata to Vault", kvSecretPath(kvSecret, "metadata"), err)
+				if err := r.Status().Update(ctx, kvSecret); err != nil {
+					log.Error(err, "Failed to update KVSecret status")
+					return ctrl.Result{}, err
+				}
+
+				return ctrl.Result{}, err
+			}
+
+			// New metadata is reported along with the data
+			if metadata != nil {
+				events.Pushed(r.Recorder, kvSecret, kvSecret.Status.Conditions, true, "KVSecret", kvSecretPath(kvSecret, "metadata"))
+			}
+		}
+
+		if metadata != nil {
+			version = metadata.CurrentVersion
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultKVData(ctx, kvSecret)
+	if err != nil {
+		log.Error(err, "Failed to fetch KVSecret")
+		vault.SetCondition(&kvSecret.Status.Conditions, kvSecret.Generation, metav1.Condition{Type: typeConfiguredKVSecret, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch secret from Vault"})
+		events.Failed(r.Recorder, kvSecret, "FailedToFetch", "Failed to fetch secret from Vault", kvSecretPath(kvSecret, "data"), err)
+		if err := r.Status().Update(ctx, kvSecret); err != nil {
+			log.Error(err, "Failed to update KVSecret status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFrom(data) {
+		if version, err = r.updateVaultKVData(ctx, kvSecret, data, version); err != nil {
+			log.Error(err, "Failed to update KVSecret")
+			vault.SetCondition(&kvSecret.Status.Conditions, kvSecret.Generation, metav1.Condition{Type: typeConfiguredKVSecret, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push secret to Vault"})
+			events.Failed(r.Recorder, kvSecret, "FailedToUpdate", "Failed to push secret to Vault", kvSecretPath(kvSecret, "data"), err)
+			if err := r.Status().Update(ctx, kvSecret); err != nil {
+				log.Error(err, "Failed to update KVSecret status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		now := metav1.Now()
+		kvSecret.Status.LastAppliedHash = appliedChecksum(kvSecret, data, sourceVersion)
+		kvSecret.Status.LastAppliedTime = &now
+		events.Pushed(r.Recorder, kvSecret, kvSecret.Status.Conditions, current != nil, "KVSecret", kvSecretPath(kvSecret, "data"))
+	} else if meta.IsStatusConditionTrue(kvSecret.Status.Conditions, typeConfiguredKVSecret) &&
+		kvSecret.Status.ObservedGeneration == kvSecret.Generation &&
+		kvSecret.Status.Version == version && maps.Equal(kvSecret.Status.CustomMetadata, kvSecret.Spec.CustomMetadata) {
+		return ctrl.Result{}, nil
+	}
+
+	kvSecret.Status.Version = version
+	kvSecret.Status.CustomMetadata = kvSecret.Spec.CustomMetadata
+	kvSecret.Status.ObservedGeneration = kvSecret.Generation
+	kvSecret.Status.VaultPath = kvSecretPath(kvSecret, "data")
+	vault.SetCondition(&kvSecret.Status.Conditions, kvSecret.Generation, metav1.Condition{Type: typeConfiguredKVSecret, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed secret to Vault"})
+	if err := r.Status().Update(ctx, kvSecret); err != nil {
+		log.Error(err, "Failed to update KVSecret status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// desiredData returns the data of the referenced Secret, overlaid with the
+// rendered templates of spec.data, along with the resource version of the
+// Secret.
+func (r *KVSecretReconciler) desiredData(ctx context.Context, kvSecret *kvv1beta1.KVSecret) (map[string]string, string, error) {
+	source := map[string]string{}
+	sourceVersion := ""
+	if kvSecret.Spec.SecretRef != nil {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: kvSecret.Spec.SecretRef.Name, Namespace: kvSecret.Namespace}, secret); err != nil {
+			return nil, "", fmt.Errorf("failed to get secret %s: %w", kvSecret.Spec.SecretRef.Name, err)
+		}
+		for k, v := range secret.Data {
+			source[k] = string(v)
+		}
+		sourceVersion = secret.ResourceVersion
+	}
+
+	data := maps.Clone(source)
+	for k, v := range kvSecret.Spec.Data {
+		t, err := template.New(k).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse template of key %s: %w", k, err)
+		}
+
+		var sb strings.Builder
+		if err := t.Execute(&sb, map[string]interface{}{"Secret": source}); err != nil {
+			return nil, "", fmt.Errorf("failed to render template of key %s: %w", k, err)
+		}
+		data[k] = sb.String()
+	}
+
+	return data, sourceVersion, nil
+}
+
+// appliedChecksum returns the checksum of what the data written to Vault is
+// made of rather than of its values, which are all secrets: its keys, the
+// templates of spec.data and the resource version of the referenced Secret.
+func appliedChecksum(kvSecret *kvv1beta1.KVSecret, data map[string]string, sourceVersion string) string {
+	return vault.Checksum(map[string]interface{}{
+		"keys":          slices.Sorted(maps.Keys(data)),
+		"templates":     kvSecret.Spec.Data,
+		"sourceVersion": sourceVersion,
+	})
+}
+
+func isKVv1(kvSecret *kvv1beta1.KVSecret) bool {
+	return kvSecret.Spec.KVVersion == 1
+}
+
+func kvSecretPath(kvSecret *kvv1beta1.KVSecret, operation string) string {
+	return kvPath(kvSecret.Spec.Mount, kvSecret.Spec.Path, kvSecret.Spec.KVVersion, operation)
+}
+
+// kvPath returns the path of a secret for the given KV v2 operation, such as
+// data or metadata. KV v1 has a single path per secret.
+func kvPath(mount, secretPath string, kvVersion int, operation string) string {
+	if kvVersion == 1 {
+		return fmt.Sprintf("%s/%s", mount, secretPath)
+	}
+	return fmt.Sprintf("%s/%s/%s", mount, operation, secretPath)
+}
+
+func (r *KVSecretReconciler) deleteVaultKVSecret(ctx context.Context, kvSecret *kvv1beta1.KVSecret) error {
+	var err error
+	switch kvSecret.Spec.DeletionPolicy {
+	case "SoftDelete":
+		_, err = r.Vault.Delete(ctx, kvSecretPath(kvSecret, "data"))
+	case "Destroy":
+		_, err = r.Vault.Delete(ctx, kvSecretPath(kvSecret, "metadata"))
+	}
+	return err
+}
+
+func (r *KVSecretReconciler) fetchVaultKVMetadata(ctx context.Context, kvSecret *kvv1beta1.KVSecret) (*vault.KVMetadata, error) {
+	return fetchVaultKVMetadata(ctx, r.Vault, kvSecretPath(kvSecret, "metadata"))
+}
+
+// fetchVaultKVMetadata reads the metadata of a KV v2 secret, nil if the secret
+// does not exist.
+func fetchVaultKVMetadata(ctx context.Context, v vault.Interface, path string) (*vault.KVMetadata, error) {
+	s, err := v.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var m vault.KVMetadata
+	if err := json.Unmarshal(jsonBytes, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (r *KVSecretReconciler) updateVaultKVMetadata(ctx context.Context, kvSecret *kvv1beta1.KVSecret) error {
+	customMetadata := kvSecret.Spec.CustomMetadata
+	if customMetadata == nil {
+		// An empty map clears the custom metadata set previously
+		customMetadata = map[string]string{}
+	}
+
+	_, err := r.Vault.Write(ctx, kvSecretPath(kvSecret, "metadata"), map[string]interface{}{
+		"max_versions":    kvSecret.Spec.MaxVersions,
+		"cas_required":    kvSecret.Spec.CASRequired,
+		"custom_metadata": customMetadata,
+	})
+	return err
+}
+
+func (r *KVSecretReconciler) fetchVaultKVData(ctx context.Context, kvSecret *kvv1beta1.KVSecret) (vault.KVData, error) {
+	s, err := r.Vault.Read(ctx, kvSecretPath(kvSecret, "data"))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	if isKVv1(kvSecret) {
+		return s.Data, nil
+	}
+
+	// Deleted versions are returned without data
+	data, _ := s.Data["data"].(map[string]interface{})
+	return data, nil
+}
+
+// updateVaultKVData writes the data to Vault and returns the version created.
+// KV v2 writes use check-and-set against version, so that concurrent writes
+// made outside the operator are not overwritten blindly.
+func (r *KVSecretReconciler) updateVaultKVData(ctx context.Context, kvSecret *kvv1beta1.KVSecret, data map[string]string, version int) (int, error) {
+	m := map[string]interface{}{}
+	for k, v := range data {
+		m[k] = v
+	}
+
+	if isKVv1(kvSecret) {
+		_, err := r.Vault.Write(ctx, kvSecretPath(kvSecret, "data"), m)
+		return 0, err
+	}
+
+	s, err := r.Vault.Write(ctx, kvSecretPath(kvSecret, "data"), map[string]interface{}{
+		"data":    m,
+		"options": map[string]interface{}{"cas": version},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if s == nil {
+		return version + 1, nil
+	}
+	return strconv.Atoi(fmt.Sprint(s.Data["version"]))
+}
+
+// findKVSecretsForSecret returns the KVSecrets that use the Secret as source.
+func (r *KVSecretReconciler) findKVSecretsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	kvSecrets := &kvv1beta1.KVSecretList{}
+	if err := r.List(ctx, kvSecrets, client.InNamespace(secret.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list KVSecrets")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, s := range kvSecrets.Items {
+		if s.Spec.SecretRef != nil && s.Spec.SecretRef.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: s.Name, Namespace: s.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KVSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kvv1beta1.KVSecret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.findKVSecretsForSecret)).
+		Named("kv-kvsecret").
+		Complete(r)
+}
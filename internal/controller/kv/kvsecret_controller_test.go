@@ -0,0 +1,312 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kv
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kvv1beta1 "hopopops/vault-operator/api/kv/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("KVSecret Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-kvsecret"
+		const sourceName = "test-source"
+		const mount = "secret"
+		const secretPath = "apps/test"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		sourceNamespacedName := types.NamespacedName{
+			Name:      sourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *KVSecretReconciler
+		var recorder *record.FakeRecorder
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		updateSpec := func(mutate func(spec *kvv1beta1.KVSecretSpec)) {
+			kvSecret := &kvv1beta1.KVSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+			mutate(&kvSecret.Spec)
+			Expect(k8sClient.Update(ctx, kvSecret)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "kv", Options: map[string]string{"version": "2"}})).To(Succeed())
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &KVSecretReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Vault:    vaultServer.Client(),
+				Recorder: recorder,
+			}
+
+			By("creating the source Secret")
+			source := &corev1.Secret{}
+			err := k8sClient.Get(ctx, sourceNamespacedName, source)
+			if err != nil && errors.IsNotFound(err) {
+				source = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      sourceName,
+						Namespace: "default",
+					},
+					Data: map[string][]byte{
+						"username": []byte("app"),
+						"password": []byte("s3cr3t"),
+					},
+				}
+				Expect(k8sClient.Create(ctx, source)).To(Succeed())
+			}
+
+			By("creating the custom resource for the Kind KVSecret")
+			kvSecret := &kvv1beta1.KVSecret{}
+			err = k8sClient.Get(ctx, typeNamespacedName, kvSecret)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &kvv1beta1.KVSecret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: kvv1beta1.KVSecretSpec{
+						Mount:          mount,
+						Path:           secretPath,
+						KVVersion:      2,
+						SecretRef:      &corev1.LocalObjectReference{Name: sourceName},
+						Data:           map[string]string{"dsn": "postgres://{{ .Secret.username }}:{{ .Secret.password }}@db:5432"},
+						CustomMetadata: map[string]string{"owner": "team-a"},
+						MaxVersions:    5,
+						DeletionPolicy: "Retain",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			source := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, sourceNamespacedName, source); err == nil {
+				Expect(k8sClient.Delete(ctx, source)).To(Succeed())
+			}
+
+			resource := &kvv1beta1.KVSecret{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance KVSecret")
+			vaultServer.ClearFaults()
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+		})
+
+		It("should write the source and rendered data to Vault", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			data, version, ok := vaultServer.KVData(mount, secretPath)
+			Expect(ok).To(BeTrue())
+			Expect(version).To(Equal(1))
+			Expect(data).To(Equal(map[string]interface{}{
+				"username": "app",
+				"password": "s3cr3t",
+				"dsn":      "postgres://app:s3cr3t@db:5432",
+			}))
+
+			metadata, _ := vaultServer.KVMetadata(mount, secretPath)
+			Expect(metadata.MaxVersions).To(Equal(5))
+			Expect(metadata.CustomMetadata).To(HaveKeyWithValue("owner", "team-a"))
+
+			kvSecret := &kvv1beta1.KVSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+			Expect(kvSecret.Finalizers).To(ContainElement(kvSecretFinalizer))
+			Expect(kvSecret.Status.Version).To(Equal(1))
+			Expect(kvSecret.Status.CustomMetadata).To(HaveKeyWithValue("owner", "team-a"))
+			Expect(meta.IsStatusConditionTrue(kvSecret.Status.Conditions, typeConfiguredKVSecret)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created Created " + mount + "/data/" + secretPath + " in Vault")))
+		})
+
+		It("should not write a new version when the secret is in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, version, _ := vaultServer.KVData(mount, secretPath)
+			Expect(version).To(Equal(1))
+		})
+
+		It("should write a new version when the source Secret changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			kvSecret := &kvv1beta1.KVSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+			applied := kvSecret.Status.LastAppliedHash
+
+			By("Rotating the password in the source Secret")
+			source := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, sourceNamespacedName, source)).To(Succeed())
+			source.Data["password"] = []byte("r0tat3d")
+			Expect(k8sClient.Update(ctx, source)).To(Succeed())
+
+			Expect(controllerReconciler.findKVSecretsForSecret(ctx, source)).To(ConsistOf(reconcile.Request{NamespacedName: typeNamespacedName}))
+			Expect(reconcileOnce()).To(Succeed())
+
+			data, version, _ := vaultServer.KVData(mount, secretPath)
+			Expect(version).To(Equal(2))
+			Expect(data).To(HaveKeyWithValue("dsn", "postgres://app:r0tat3d@db:5432"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+			Expect(kvSecret.Status.Version).To(Equal(2))
+
+			By("Recording the checksum of the source Secret version rather than of the values")
+			Expect(k8sClient.Get(ctx, sourceNamespacedName, source)).To(Succeed())
+			Expect(kvSecret.Status.LastAppliedHash).NotTo(Equal(applied))
+			Expect(kvSecret.Status.LastAppliedHash).To(Equal(appliedChecksum(kvSecret, map[string]string{
+				"username": "",
+				"password": "",
+				"dsn":      "",
+			}, source.ResourceVersion)))
+		})
+
+		It("should honor cas_required", func() {
+			updateSpec(func(spec *kvv1beta1.KVSecretSpec) { spec.CASRequired = true })
+			Expect(reconcileOnce()).To(Succeed())
+
+			metadata, _ := vaultServer.KVMetadata(mount, secretPath)
+			Expect(metadata.CASRequired).To(BeTrue())
+
+			By("Changing the secret behind the operator's back")
+			_, err := vaultServer.Client().Write(ctx, mount+"/data/"+secretPath, map[string]interface{}{
+				"data":    map[string]interface{}{"username": "intruder"},
+				"options": map[string]interface{}{"cas": 1},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+			data, version, _ := vaultServer.KVData(mount, secretPath)
+			Expect(version).To(Equal(3))
+			Expect(data).To(HaveKeyWithValue("username", "app"))
+		})
+
+		It("should report a missing source Secret", func() {
+			source := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, sourceNamespacedName, source)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, source)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			kvSecret := &kvv1beta1.KVSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+			condition := meta.FindStatusCondition(kvSecret.Status.Conditions, typeConfiguredKVSecret)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should report a failure to write the secret", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPut, Path: mount + "/data/" + secretPath, Status: http.StatusForbidden})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			kvSecret := &kvv1beta1.KVSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+			condition := meta.FindStatusCondition(kvSecret.Status.Conditions, typeConfiguredKVSecret)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+			Expect(recorder.Events).To(Receive(Equal("Warning FailedToUpdate Failed to push secret to Vault at " + mount + "/data/" + secretPath + ": permission denied (HTTP 403)")))
+		})
+
+		It("should write KV v1 secrets", func() {
+			Expect(vaultServer.Client().Mount(ctx, "kv1", &vaultapi.MountInput{Type: "kv", Options: map[string]string{"version": "1"}})).To(Succeed())
+
+			kvSecret := &kvv1beta1.KVSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, kvSecret)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			kvSecret = &kvv1beta1.KVSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: kvv1beta1.KVSecretSpec{
+					Mount:          "kv1",
+					Path:           secretPath,
+					KVVersion:      1,
+					Data:           map[string]string{"static": "value"},
+					DeletionPolicy: "Destroy",
+				},
+			}
+			Expect(k8sClient.Create(ctx, kvSecret)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			data, ok := vaultServer.Data("kv1/" + secretPath)
+			Expect(ok).To(BeTrue())
+			Expect(data).To(Equal(map[string]interface{}{"static": "value"}))
+
+			Expect(k8sClient.Delete(ctx, kvSecret)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+			_, ok = vaultServer.Data("kv1/" + secretPath)
+			Expect(ok).To(BeFalse())
+		})
+
+		DescribeTable("should apply the deletion policy",
+			func(policy string, live, exists bool) {
+				updateSpec(func(spec *kvv1beta1.KVSecretSpec) { spec.DeletionPolicy = policy })
+				Expect(reconcileOnce()).To(Succeed())
+
+				kvSecret := &kvv1beta1.KVSecret{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, kvSecret)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, kvSecret)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+
+				_, _, ok := vaultServer.KVData(mount, secretPath)
+				Expect(ok).To(Equal(live))
+				_, ok = vaultServer.KVMetadata(mount, secretPath)
+				Expect(ok).To(Equal(exists))
+				err := k8sClient.Get(ctx, typeNamespacedName, kvSecret)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			},
+			Entry("Retain keeps the secret", "Retain", true, true),
+			Entry("SoftDelete deletes the latest version", "SoftDelete", false, true),
+			Entry("Destroy removes every version", "Destroy", false, false),
+		)
+	})
+})
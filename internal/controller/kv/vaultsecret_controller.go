@@ -0,0 +1,514 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kv
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	kvv1beta1 "hopopops/vault-operator/api/kv/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	vaultSecretFinalizer = "vaultsecret.kv.toolkit.vault.hopopops.com/finalizer"
+
+	// rolloutAnnotationPrefix prefixes the name of the target Secret in the pod
+	// template annotation holding the keyed checksum of its data.
+	rolloutAnnotationPrefix = "checksum.vault.hopopops.com/"
+
+	defaultRefreshInterval = 5 * time.Minute
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredVaultSecret = "Configured"
+)
+
+// VaultSecretReconciler reconciles a VaultSecret object
+type VaultSecretReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=kv.toolkit.vault.hopopops.com,resources=vaultsecrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kv.toolkit.vault.hopopops.com,resources=vaultsecrets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kv.toolkit.vault.hopopops.com,resources=vaultsecrets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *VaultSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the VaultSecret instance
+	vaultSecret := &kvv1beta1.VaultSecret{}
+	if err := r.Get(ctx, req.NamespacedName, vaultSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("VaultSecret resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get VaultSecret")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, vaultSecret, &vaultSecret.Status.Conditions, r.Mode, vaultSecretFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag VaultSecret in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// VaultSecret Deletion
+	isVaultSecretMarkedToBeDeleted := vaultSecret.GetDeletionTimestamp() != nil
+	if isVaultSecretMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(vaultSecret, vaultSecretFinalizer) {
+			if vaultSecret.Spec.Target.DeletionPolicy == "Delete" {
+				if err := r.deleteK8sSecret(ctx, vaultSecret); err != nil {
+					log.Error(err, "Failed to delete Secret")
+					return ctrl.Result{}, err
+				}
+			} else {
+				if err := r.releaseK8sSecret(ctx, vaultSecret); err != nil {
+					log.Error(err, "Failed to release Secret")
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(vaultSecret, vaultSecretFinalizer)
+			if err := r.Update(ctx, vaultSecret); err != nil {
+				log.Error(err, "Failed to remove finalizer from VaultSecret")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// VaultSecret Initialization
+	if !controllerutil.ContainsFinalizer(vaultSecret, vaultSecretFinalizer) {
+		controllerutil.AddFinalizer(vaultSecret, vaultSecretFinalizer)
+		vault.SetCondition(&vaultSecret.Status.Conditions, vaultSecret.Generation, metav1.Condition{Type: typeConfiguredVaultSecret, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, vaultSecret); err != nil {
+			log.Error(err, "Failed to initialize VaultSecret status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	result := ctrl.Result{RequeueAfter: defaultRefreshInterval}
+	if vaultSecret.Spec.RefreshInterval != nil {
+		result.RequeueAfter = vaultSecret.Spec.RefreshInterval.Duration
+	}
+
+	// The latest version is known from the metadata, which is cheaper to read
+	version := vaultSecret.Spec.Version
+	if vaultSecret.Spec.KVVersion != 1 && version == 0 {
+		metadata, err := fetchVaultKVMetadata(ctx, r.Vault, vaultSecretPath(vaultSecret, "metadata"))
+		if err == nil && metadata == nil {
+			err = fmt.Errorf("secret %s not found", vaultSecretPath(vaultSecret, "data"))
+		}
+		if err != nil {
+			log.Error(err, "Failed to fetch VaultSecret metadata")
+			vault.SetCondition(&vaultSecret.Status.Conditions, vaultSecret.Generation, metav1.Condition{Type: typeConfiguredVaultSecret, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch secret metadata from Vault"})
+			events.Failed(r.Recorder, vaultSecret, "FailedToFetch", "Failed to fetch secret metadata from Vault", vaultSecretPath(vaultSecret, "metadata"), err)
+			if err := r.Status().Update(ctx, vaultSecret); err != nil {
+				log.Error(err, "Failed to update VaultSecret status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		version = metadata.CurrentVersion
+	}
+
+	existing, err := r.fetchK8sSecret(ctx, vaultSecret)
+	if err != nil {
+		log.Error(err, "Failed to get Secret")
+		return ctrl.Result{}, err
+	}
+
+	// Nothing changed since the last refresh. KV v1 has no version to compare to.
+	if vaultSecret.Spec.KVVersion != 1 &&
+		vaultSecret.Status.ObservedGeneration == vaultSecret.Generation &&
+		vaultSecret.Status.Version == version &&
+		existing != nil && metav1.IsControlledBy(existing, vaultSecret) &&
+		vaultSecret.Status.DataHash != "" && hashData(existing.UID, existing.Data) == vaultSecret.Status.DataHash {
+		return result, nil
+	}
+
+	s, err := r.fetchVaultSecret(ctx, vaultSecret, version)
+	if err != nil {
+		log.Error(err, "Failed to fetch VaultSecret")
+		vault.SetCondition(&vaultSecret.Status.Conditions, vaultSecret.Generation, metav1.Condition{Type: typeConfiguredVaultSecret, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch secret from Vault"})
+		events.Failed(r.Recorder, vaultSecret, "FailedToFetch", "Failed to fetch secret from Vault", vaultSecretPath(vaultSecret, "data"), err)
+		if err := r.Status().Update(ctx, vaultSecret); err != nil {
+			log.Error(err, "Failed to update VaultSecret status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	data, err := renderData(vaultSecret, s)
+	if err != nil {
+		log.Error(err, "Failed to render VaultSecret data")
+		vault.SetCondition(&vaultSecret.Status.Conditions, vaultSecret.Generation, metav1.Condition{Type: typeConfiguredVaultSecret, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to render secret data: %s", err)})
+		events.Failed(r.Recorder, vaultSecret, "FailedToUpdate", "Failed to render secret data", "", err)
+		if err := r.Status().Update(ctx, vaultSecret); err != nil {
+			log.Error(err, "Failed to update VaultSecret status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	secret, err := r.applyK8sSecret(ctx, vaultSecret, existing, data)
+	if err != nil {
+		log.Error(err, "Failed to write k8s secret")
+		vault.SetCondition(&vaultSecret.Status.Conditions, vaultSecret.Generation, metav1.Condition{Type: typeConfiguredVaultSecret, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to write k8s secret %s", vaultSecret.Spec.Target.Name)})
+		events.Failed(r.Recorder, vaultSecret, "FailedToCreate", fmt.Sprintf("Failed to write k8s secret %s", vaultSecret.Spec.Target.Name), "", err)
+		if err := r.Status().Update(ctx, vaultSecret); err != nil {
+			log.Error(err, "Failed to update VaultSecret status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	hash := hashData(secret.UID, data)
+	if err := r.rolloutWorkloads(ctx, vaultSecret, hash); err != nil {
+		log.Error(err, "Failed to roll out workloads")
+		vault.SetCondition(&vaultSecret.Status.Conditions, vaultSecret.Generation, metav1.Condition{Type: typeConfiguredVaultSecret, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to roll out workloads using the secret"})
+		events.Failed(r.Recorder, vaultSecret, "FailedToUpdate", "Failed to roll out workloads using the secret", "", err)
+		if err := r.Status().Update(ctx, vaultSecret); err != nil {
+			log.Error(err, "Failed to update VaultSecret status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	vaultSecret.Status.Version = version
+	vaultSecret.Status.DataHash = hash
+	vaultSecret.Status.LastRefreshTime = &now
+	vaultSecret.Status.ObservedGeneration = vaultSecret.Generation
+	vaultSecret.Status.VaultPath = vaultSecretPath(vaultSecret, "data")
+	vault.SetCondition(&vaultSecret.Status.Conditions, vaultSecret.Generation, metav1.Condition{Type: typeConfiguredVaultSecret, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully synced secret from Vault"})
+	if err := r.Status().Update(ctx, vaultSecret); err != nil {
+		log.Error(err, "Failed to update VaultSecret status")
+		return ctrl.Result{}, err
+	}
+
+	return result, nil
+}
+
+func vaultSecretPath(vaultSecret *kvv1beta1.VaultSecret, operation string) string {
+	return kvPath(vaultSecret.Spec.Mount, vaultSecret.Spec.Path, vaultSecret.Spec.KVVersion, operation)
+}
+
+// fetchVaultSecret reads the given version of the secret, with its metadata
+// for KV v2.
+func (r *VaultSecretReconciler) fetchVaultSecret(ctx context.Context, vaultSecret *kvv1beta1.VaultSecret, version int) (*vaultapi.Secret, error) {
+	p := vaultSecretPath(vaultSecret, "data")
+
+	var s *vaultapi.Secret
+	var err error
+	if vaultSecret.Spec.KVVersion == 1 {
+		s, err = r.Vault.Read(ctx, p)
+	} else {
+		s, err = r.Vault.ReadWithData(ctx, p, map[string][]string{"version": {strconv.Itoa(version)}})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, fmt.Errorf("secret %s not found", p)
+	}
+
+	if vaultSecret.Spec.KVVersion != 1 && s.Data["data"] == nil {
+		return nil, fmt.Errorf("version %d of secret %s is deleted or destroyed", version, p)
+	}
+
+	return s, nil
+}
+
+// renderData builds the data of the target Secret from the Vault secret.
+func renderData(vaultSecret *kvv1beta1.VaultSecret, s *vaultapi.Secret) (map[string][]byte, error) {
+	source := s.Data
+	values := map[string]interface{}{}
+	if vaultSecret.Spec.KVVersion != 1 {
+		source, _ = s.Data["data"].(map[string]interface{})
+		if metadata, ok := s.Data["metadata"].(map[string]interface{}); ok {
+			values["Version"] = metadata["version"]
+			values["CustomMetadata"] = metadata["custom_metadata"]
+		}
+	}
+	values["Secret"] = source
+
+	data := map[string][]byte{}
+	if len(vaultSecret.Spec.Keys) == 0 && len(vaultSecret.Spec.Templates) == 0 {
+		for k, v := range source {
+			value, err := stringify(v)
+			if err != nil {
+				return nil, err
+			}
+			data[k] = []byte(value)
+		}
+		return data, nil
+	}
+
+	for k, from := range vaultSecret.Spec.Keys {
+		v, ok := source[from]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in secret", from)
+		}
+		value, err := stringify(v)
+		if err != nil {
+			return nil, err
+		}
+		data[k] = []byte(value)
+	}
+
+	for k, v := range vaultSecret.Spec.Templates {
+		t, err := template.New(k).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template of key %s: %w", k, err)
+		}
+
+		var sb strings.Builder
+		if err := t.Execute(&sb, values); err != nil {
+			return nil, fmt.Errorf("failed to render template of key %s: %w", k, err)
+		}
+		data[k] = []byte(sb.String())
+	}
+
+	return data, nil
+}
+
+// stringify returns strings as is and the JSON encoding of other values.
+func stringify(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	default:
+		b, err := json.Marshal(value)
+		return string(b), err
+	}
+}
+
+// hashData returns a checksum of the data, independent of the order of keys.
+// It is copied to workloads that may be read by more than the Secret, so it is
+// keyed with the UID of the Secret, which they do not reveal, to prevent
+// guesses of the data from being checked against it.
+func hashData(key types.UID, data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	h := hmac.New(sha256.New, []byte(key))
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(data[k])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (r *VaultSecretReconciler) fetchK8sSecret(ctx context.Context, vaultSecret *kvv1beta1.VaultSecret) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      vaultSecret.Spec.Target.Name,
+		Namespace: vaultSecret.Namespace,
+	}, secret)
+
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	return secret, nil
+}
+
+// applyK8sSecret creates the target Secret or updates its data, and returns it.
+// Secrets that are not owned by the resource are left untouched.
+func (r *VaultSecretReconciler) applyK8sSecret(ctx context.Context, vaultSecret *kvv1beta1.VaultSecret, existing *corev1.Secret, data map[string][]byte) (*corev1.Secret, error) {
+	log := logf.FromContext(ctx)
+
+	if existing == nil {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      vaultSecret.Spec.Target.Name,
+				Namespace: vaultSecret.Namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+
+		if err := controllerutil.SetControllerReference(vaultSecret, secret, r.Scheme); err != nil {
+			return nil, fmt.Errorf("failed to set controller reference: %w", err)
+		}
+
+		if err := r.Create(ctx, secret); err != nil {
+			return nil, err
+		}
+		log.Info("Created secret", "secret", secret.Name)
+		return secret, nil
+	}
+
+	if !metav1.IsControlledBy(existing, vaultSecret) {
+		return nil, apierrors.NewAlreadyExists(corev1.Resource("secrets"), existing.Name)
+	}
+
+	if maps.EqualFunc(existing.Data, data, bytes.Equal) {
+		return existing, nil
+	}
+
+	existing.Data = data
+	if err := r.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	log.Info("Updated secret", "secret", existing.Name)
+	return existing, nil
+}
+
+func (r *VaultSecretReconciler) deleteK8sSecret(ctx context.Context, vaultSecret *kvv1beta1.VaultSecret) error {
+	secret, err := r.fetchK8sSecret(ctx, vaultSecret)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(secret, vaultSecret) {
+		ctrl.Log.Info("Secret exists but CR is not owner, skipping deletion", "secret", vaultSecret.Spec.Target.Name)
+		return nil
+	}
+
+	if err := r.Delete(ctx, secret); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
+
+// releaseK8sSecret removes the owner reference of the resource from the target
+// Secret, so that it is not garbage collected along with the resource.
+func (r *VaultSecretReconciler) releaseK8sSecret(ctx context.Context, vaultSecret *kvv1beta1.VaultSecret) error {
+	secret, err := r.fetchK8sSecret(ctx, vaultSecret)
+	if err != nil || secret == nil || !metav1.IsControlledBy(secret, vaultSecret) {
+		return err
+	}
+
+	secret.OwnerReferences = slices.DeleteFunc(secret.OwnerReferences, func(o metav1.OwnerReference) bool {
+		return o.UID == vaultSecret.UID
+	})
+	return r.Update(ctx, secret)
+}
+
+// rolloutWorkloads sets the checksum of the data on the pod template of the
+// rollout targets, which restarts their pods when it changes.
+func (r *VaultSecretReconciler) rolloutWorkloads(ctx context.Context, vaultSecret *kvv1beta1.VaultSecret, hash string) error {
+	key := rolloutAnnotationPrefix + vaultSecret.Spec.Target.Name
+
+	for _, target := range vaultSecret.Spec.RolloutTargets {
+		var obj client.Object
+		var podTemplate *corev1.PodTemplateSpec
+		switch target.Kind {
+		case "Deployment":
+			d := &appsv1.Deployment{}
+			obj, podTemplate = d, &d.Spec.Template
+		case "StatefulSet":
+			s := &appsv1.StatefulSet{}
+			obj, podTemplate = s, &s.Spec.Template
+		case "DaemonSet":
+			d := &appsv1.DaemonSet{}
+			obj, podTemplate = d, &d.Spec.Template
+		default:
+			return fmt.Errorf("unsupported rollout target kind %s", target.Kind)
+		}
+
+		if err := r.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: vaultSecret.Namespace}, obj); err != nil {
+			return fmt.Errorf("failed to get %s %s: %w", target.Kind, target.Name, err)
+		}
+
+		if podTemplate.Annotations[key] == hash {
+			continue
+		}
+
+		patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+		if podTemplate.Annotations == nil {
+			podTemplate.Annotations = map[string]string{}
+		}
+		podTemplate.Annotations[key] = hash
+		if err := r.Patch(ctx, obj, patch); err != nil {
+			return fmt.Errorf("failed to patch %s %s: %w", target.Kind, target.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VaultSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kvv1beta1.VaultSecret{}).
+		Owns(&corev1.Secret{}).
+		Named("kv-vaultsecret").
+		Complete(r)
+}
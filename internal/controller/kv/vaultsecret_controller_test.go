@@ -0,0 +1,319 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kv
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kvv1beta1 "hopopops/vault-operator/api/kv/v1beta1"
+)
+
+var _ = Describe("VaultSecret Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-vaultsecret"
+		const targetName = "test-target"
+		const deploymentName = "test-app"
+		const mount = "secret"
+		const secretPath = "apps/test"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		targetNamespacedName := types.NamespacedName{
+			Name:      targetName,
+			Namespace: "default",
+		}
+		deploymentNamespacedName := types.NamespacedName{
+			Name:      deploymentName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *VaultSecretReconciler
+
+		reconcileWithResult := func() (reconcile.Result, error) {
+			return controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+		}
+		reconcileOnce := func() error {
+			_, err := reconcileWithResult()
+			return err
+		}
+
+		writeVault := func(data map[string]interface{}) {
+			_, err := vaultServer.Client().Write(ctx, mount+"/data/"+secretPath, map[string]interface{}{"data": data})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		target := func() *corev1.Secret {
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, targetNamespacedName, secret)).To(Succeed())
+			return secret
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "kv", Options: map[string]string{"version": "2"}})).To(Succeed())
+			writeVault(map[string]interface{}{"username": "app", "password": "v1"})
+
+			controllerReconciler = &VaultSecretReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the consumer Deployment")
+			deployment := &appsv1.Deployment{}
+			err := k8sClient.Get(ctx, deploymentNamespacedName, deployment)
+			if err != nil && errors.IsNotFound(err) {
+				labels := map[string]string{"app": deploymentName}
+				deployment = &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: deploymentName, Namespace: "default"},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: labels},
+						Template: corev1.PodTemplateSpec{
+							ObjectMeta: metav1.ObjectMeta{Labels: labels},
+							Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+			}
+
+			By("creating the custom resource for the Kind VaultSecret")
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			err = k8sClient.Get(ctx, typeNamespacedName, vaultSecret)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &kvv1beta1.VaultSecret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: kvv1beta1.VaultSecretSpec{
+						Mount:     mount,
+						Path:      secretPath,
+						KVVersion: 2,
+						Target: kvv1beta1.VaultSecretTarget{
+							Name:           targetName,
+							DeletionPolicy: "Delete",
+						},
+						Keys:            map[string]string{"DB_PASSWORD": "password"},
+						Templates:       map[string]string{"DSN": "{{ .Secret.username }}:{{ .Secret.password }}@v{{ .Version }}"},
+						RefreshInterval: &metav1.Duration{Duration: time.Minute},
+						RolloutTargets:  []kvv1beta1.VaultSecretRolloutTarget{{Kind: "Deployment", Name: deploymentName}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			deployment := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, deploymentNamespacedName, deployment); err == nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			}
+
+			resource := &kvv1beta1.VaultSecret{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance VaultSecret")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, targetNamespacedName, secret); err == nil {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			}
+		})
+
+		It("should write the mapped and templated keys to the target Secret", func() {
+			By("Reconciling the created resource")
+			result, err := reconcileWithResult()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			secret := target()
+			Expect(secret.Data).To(Equal(map[string][]byte{
+				"DB_PASSWORD": []byte("v1"),
+				"DSN":         []byte("app:v1@v1"),
+			}))
+
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			Expect(metav1.IsControlledBy(secret, vaultSecret)).To(BeTrue())
+			Expect(vaultSecret.Finalizers).To(ContainElement(vaultSecretFinalizer))
+			Expect(vaultSecret.Status.Version).To(Equal(1))
+			Expect(vaultSecret.Status.DataHash).To(Equal(hashData(secret.UID, secret.Data)))
+			Expect(vaultSecret.Status.LastRefreshTime).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(vaultSecret.Status.Conditions, typeConfiguredVaultSecret)).To(BeTrue())
+		})
+
+		It("should copy every key when no mapping is set", func() {
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			vaultSecret.Spec.Keys = nil
+			vaultSecret.Spec.Templates = nil
+			Expect(k8sClient.Update(ctx, vaultSecret)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(target().Data).To(Equal(map[string][]byte{
+				"username": []byte("app"),
+				"password": []byte("v1"),
+			}))
+		})
+
+		It("should only read the metadata while the version is unchanged", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			reads := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodGet && r.Path == mount+"/data/"+secretPath {
+					reads++
+				}
+			}
+			Expect(reads).To(Equal(1))
+		})
+
+		It("should refresh the Secret and roll out the Deployment on a new version", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, deploymentNamespacedName, deployment)).To(Succeed())
+			firstHash := deployment.Spec.Template.Annotations[rolloutAnnotationPrefix+targetName]
+			Expect(firstHash).NotTo(BeEmpty())
+
+			By("Rotating the password in Vault")
+			writeVault(map[string]interface{}{"username": "app", "password": "v2"})
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(target().Data).To(HaveKeyWithValue("DSN", []byte("app:v2@v2")))
+			Expect(k8sClient.Get(ctx, deploymentNamespacedName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations[rolloutAnnotationPrefix+targetName]).NotTo(Equal(firstHash))
+
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			Expect(vaultSecret.Status.Version).To(Equal(2))
+			Expect(deployment.Spec.Template.Annotations[rolloutAnnotationPrefix+targetName]).To(Equal(vaultSecret.Status.DataHash))
+		})
+
+		It("should read the pinned version", func() {
+			writeVault(map[string]interface{}{"username": "app", "password": "v2"})
+
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			vaultSecret.Spec.Version = 1
+			Expect(k8sClient.Update(ctx, vaultSecret)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(target().Data).To(HaveKeyWithValue("DB_PASSWORD", []byte("v1")))
+		})
+
+		It("should restore a Secret modified behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			secret := target()
+			secret.Data["DB_PASSWORD"] = []byte("tampered")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(target().Data).To(HaveKeyWithValue("DB_PASSWORD", []byte("v1")))
+		})
+
+		It("should not take over a Secret it does not own", func() {
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: targetName, Namespace: "default"},
+				Data:       map[string][]byte{"foreign": []byte("data")},
+			})).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(target().Data).To(HaveKey("foreign"))
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			condition := meta.FindStatusCondition(vaultSecret.Status.Conditions, typeConfiguredVaultSecret)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should report a missing Vault secret", func() {
+			_, err := vaultServer.Client().Delete(ctx, mount+"/metadata/"+secretPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			condition := meta.FindStatusCondition(vaultSecret.Status.Conditions, typeConfiguredVaultSecret)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the owned Secret with the Delete policy", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, vaultSecret)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			err := k8sClient.Get(ctx, targetNamespacedName, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should release the Secret with the Retain policy", func() {
+			vaultSecret := &kvv1beta1.VaultSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			vaultSecret.Spec.Target.DeletionPolicy = "Retain"
+			Expect(k8sClient.Update(ctx, vaultSecret)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, vaultSecret)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, vaultSecret)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			secret := target()
+			Expect(secret.OwnerReferences).To(BeEmpty())
+			Expect(secret.Data).To(HaveKeyWithValue("DB_PASSWORD", []byte("v1")))
+		})
+	})
+
+	It("should key the checksum of the data with the UID of the Secret", func() {
+		data := map[string][]byte{"password": []byte("v1")}
+		Expect(hashData("a", data)).To(Equal(hashData("a", data)))
+		Expect(hashData("a", data)).NotTo(Equal(hashData("b", data)))
+	})
+})
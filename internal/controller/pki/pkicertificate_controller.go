@@ -0,0 +1,425 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	pkiCertificateFinalizer = "pkicertificate.pki.toolkit.vault.hopopops.com/finalizer"
+
+	defaultRenewalPercentage = 67
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredPKICertificate = "Configured"
+)
+
+// PKICertificateReconciler reconciles a PKICertificate object
+type PKICertificateReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkicertificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkicertificates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkicertificates/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// A certificate is issued when none was yet, when the issue request changes,
+// when the target Secret disappears or does not hold it and once
+// renewalPercentage of its lifetime has elapsed. Certificates are only revoked
+// on deletion, as consumers may still be using the previous one after a
+// renewal, unless they were never written to the Secret.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *PKICertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the PKICertificate instance
+	certificate := &pkiv1beta1.PKICertificate{}
+	if err := r.Get(ctx, req.NamespacedName, certificate); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("PKICertificate resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get PKICertificate")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, certificate, &certificate.Status.Conditions, r.Mode, pkiCertificateFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag PKICertificate in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// PKICertificate Deletion
+	isCertificateMarkedToBeDeleted := certificate.GetDeletionTimestamp() != nil
+	if isCertificateMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(certificate, pkiCertificateFinalizer) {
+			if certificate.Status.SerialNumber != "" {
+				if err := r.revokeVaultCertificate(ctx, certificate, certificate.Status.SerialNumber); err != nil {
+					log.Error(err, "Failed to revoke certificate", "serial", certificate.Status.SerialNumber)
+					return ctrl.Result{}, err
+				}
+				events.Revoked(r.Recorder, certificate, "certificate", pkiCertPath(certificate))
+			}
+
+			if certificate.Spec.Target.DeletionPolicy == "Delete" {
+				if err := r.deleteK8sSecret(ctx, certificate); err != nil {
+					log.Error(err, "Failed to delete Secret")
+					return ctrl.Result{}, err
+				}
+			} else {
+				if err := r.releaseK8sSecret(ctx, certificate); err != nil {
+					log.Error(err, "Failed to release Secret")
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(certificate, pkiCertificateFinalizer)
+			if err := r.Update(ctx, certificate); err != nil {
+				log.Error(err, "Failed to remove finalizer from PKICertificate")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// PKICertificate Initialization
+	if !controllerutil.ContainsFinalizer(certificate, pkiCertificateFinalizer) {
+		controllerutil.AddFinalizer(certificate, pkiCertificateFinalizer)
+		vault.SetCondition(&certificate.Status.Conditions, certificate.Generation, metav1.Condition{Type: typeConfiguredPKICertificate, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, certificate); err != nil {
+			log.Error(err, "Failed to initialize PKICertificate status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	existing, err := r.fetchK8sSecret(ctx, certificate)
+	if err != nil {
+		log.Error(err, "Failed to get Secret")
+		return ctrl.Result{}, err
+	}
+
+	request := issueRequest(certificate)
+	issue := certificate.Status.SerialNumber == "" || existing == nil || !holdsCertificate(existing, certificate) || certificate.Status.LastAppliedHash != issueChecksum(certificate, request)
+	if !issue {
+		// Spec changes leaving the request as is, such as of the renewal, are
+		// applied without issuing a new certificate
+		if certificate.Status.ObservedGeneration != certificate.Generation {
+			certificate.Status.ObservedGeneration = certificate.Generation
+			vault.SetCondition(&certificate.Status.Conditions, certificate.Generation, metav1.Condition{Type: typeConfiguredPKICertificate, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully delivered certificate"})
+			if err := r.Status().Update(ctx, certificate); err != nil {
+				log.Error(err, "Failed to update PKICertificate status")
+				return ctrl.Result{}, err
+			}
+		}
+		if now := time.Now(); certificate.Status.RenewalTime != nil && now.Before(certificate.Status.RenewalTime.Time) {
+			return ctrl.Result{RequeueAfter: certificate.Status.RenewalTime.Sub(now)}, nil
+		}
+		log.Info("Certificate is due for renewal", "serial", certificate.Status.SerialNumber)
+	}
+
+	if err := r.issueCertificate(ctx, certificate, existing, request); err != nil {
+		log.Error(err, "Failed to issue certificate")
+		vault.SetCondition(&certificate.Status.Conditions, certificate.Generation, metav1.Condition{Type: typeConfiguredPKICertificate, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to deliver certificate to k8s secret %s", certificate.Spec.Target.Name)})
+		events.Failed(r.Recorder, certificate, "FailedToCreate", fmt.Sprintf("Failed to deliver certificate to k8s secret %s", certificate.Spec.Target.Name), pkiIssuePath(certificate), err)
+		if err := r.Status().Update(ctx, certificate); err != nil {
+			log.Error(err, "Failed to update PKICertificate status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	events.Pushed(r.Recorder, certificate, certificate.Status.Conditions, false, "PKICertificate", pkiCertPath(certificate))
+	certificate.Status.ObservedGeneration = certificate.Generation
+	certificate.Status.VaultPath = pkiCertPath(certificate)
+	vault.SetCondition(&certificate.Status.Conditions, certificate.Generation, metav1.Condition{Type: typeConfiguredPKICertificate, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully delivered certificate"})
+	if err := r.Status().Update(ctx, certificate); err != nil {
+		log.Error(err, "Failed to update PKICertificate status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: time.Until(certificate.Status.RenewalTime.Time)}, nil
+}
+
+func pkiIssuePath(certificate *pkiv1beta1.PKICertificate) string {
+	return fmt.Sprintf("%s/issue/%s", certificate.Spec.Mount, certificate.Spec.Role)
+}
+
+// issueRequest returns the parameters of pki/issue for the spec.
+func issueRequest(certificate *pkiv1beta1.PKICertificate) map[string]interface{} {
+	request := map[string]interface{}{
+		"common_name": certificate.Spec.CommonName,
+		"alt_names":   strings.Join(certificate.Spec.AltNames, ","),
+		"ip_sans":     strings.Join(certificate.Spec.IPSANs, ","),
+		"uri_sans":    strings.Join(certificate.Spec.URISANs, ","),
+		"format":      "pem",
+	}
+	if certificate.Spec.TTL != nil {
+		request["ttl"] = strconv.Itoa(int(certificate.Spec.TTL.Seconds()))
+	}
+	return request
+}
+
+// issueChecksum returns the checksum of the issue request along with the role
+// it is sent to, recorded as the last applied hash so that a change of either
+// issues a new certificate.
+func issueChecksum(certificate *pkiv1beta1.PKICertificate, request map[string]interface{}) string {
+	return vault.Checksum(map[string]interface{}{
+		"path":    pkiIssuePath(certificate),
+		"request": request,
+	})
+}
+
+// holdsCertificate reports whether the Secret holds the last certificate
+// issued for the resource.
+func holdsCertificate(secret *corev1.Secret, certificate *pkiv1beta1.PKICertificate) bool {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	return formatSerial(cert.SerialNumber.Bytes()) == certificate.Status.SerialNumber
+}
+
+// issueCertificate issues a new certificate and writes it to the target
+// Secret along with its private key and the issuing CA. The certificate is
+// saved in the status before the Secret is written, so that it is never lost
+// track of. A certificate the Secret does not hold was never delivered, and is
+// revoked as it is replaced.
+func (r *PKICertificateReconciler) issueCertificate(ctx context.Context, certificate *pkiv1beta1.PKICertificate, existing *corev1.Secret, request map[string]interface{}) error {
+	log := logf.FromContext(ctx)
+
+	if existing != nil && !metav1.IsControlledBy(existing, certificate) {
+		return apierrors.NewAlreadyExists(corev1.Resource("secrets"), existing.Name)
+	}
+
+	if serial := certificate.Status.SerialNumber; serial != "" && existing != nil && !holdsCertificate(existing, certificate) {
+		if err := r.revokeVaultCertificate(ctx, certificate, serial); err != nil {
+			return err
+		}
+		events.Revoked(r.Recorder, certificate, "certificate", pkiCertPath(certificate))
+	}
+
+	s, err := r.Vault.Write(ctx, pkiIssuePath(certificate), request)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("no certificate returned by %s", pkiIssuePath(certificate))
+	}
+
+	certPEM, _ := s.Data["certificate"].(string)
+	keyPEM, _ := s.Data["private_key"].(string)
+	caPEM, _ := s.Data["issuing_ca"].(string)
+
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return fmt.Errorf("no certificate found in the response of %s", pkiIssuePath(certificate))
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	percentage := certificate.Spec.RenewalPercentage
+	if percentage == 0 {
+		percentage = defaultRenewalPercentage
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	notBefore := metav1.NewTime(cert.NotBefore)
+	notAfter := metav1.NewTime(cert.NotAfter)
+	renewalTime := metav1.NewTime(cert.NotBefore.Add(lifetime * time.Duration(percentage) / 100))
+
+	previous := *certificate.Status.DeepCopy()
+	now := metav1.Now()
+	certificate.Status.LastAppliedHash = issueChecksum(certificate, request)
+	certificate.Status.LastAppliedTime = &now
+	certificate.Status.SerialNumber = fmt.Sprint(s.Data["serial_number"])
+	certificate.Status.NotBefore = &notBefore
+	certificate.Status.NotAfter = &notAfter
+	certificate.Status.RenewalTime = &renewalTime
+	if err := r.Status().Update(ctx, certificate); err != nil {
+		// Do not leave behind a certificate the resource lost track of
+		if err := r.revokeVaultCertificate(ctx, certificate, certificate.Status.SerialNumber); err != nil {
+			log.Error(err, "Failed to revoke unused certificate", "serial", certificate.Status.SerialNumber)
+		}
+		certificate.Status = previous
+		return err
+	}
+
+	return r.applyK8sSecret(ctx, certificate, existing, map[string][]byte{
+		corev1.TLSCertKey:       []byte(certPEM + "\n"),
+		corev1.TLSPrivateKeyKey: []byte(keyPEM + "\n"),
+		"ca.crt":                []byte(caPEM + "\n"),
+	})
+}
+
+// pkiCertPath returns the path of the last certificate issued for the
+// resource in Vault.
+func pkiCertPath(certificate *pkiv1beta1.PKICertificate) string {
+	return fmt.Sprintf("%s/cert/%s", certificate.Spec.Mount, certificate.Status.SerialNumber)
+}
+
+func (r *PKICertificateReconciler) revokeVaultCertificate(ctx context.Context, certificate *pkiv1beta1.PKICertificate, serial string) error {
+	_, err := r.Vault.Write(ctx, fmt.Sprintf("%s/revoke", certificate.Spec.Mount), map[string]interface{}{
+		"serial_number": serial,
+	})
+	return err
+}
+
+func (r *PKICertificateReconciler) fetchK8sSecret(ctx context.Context, certificate *pkiv1beta1.PKICertificate) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      certificate.Spec.Target.Name,
+		Namespace: certificate.Namespace,
+	}, secret)
+
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	return secret, nil
+}
+
+// applyK8sSecret creates the kubernetes.io/tls target Secret or replaces its
+// data. Secrets that are not owned by the resource are left untouched.
+func (r *PKICertificateReconciler) applyK8sSecret(ctx context.Context, certificate *pkiv1beta1.PKICertificate, existing *corev1.Secret, data map[string][]byte) error {
+	log := logf.FromContext(ctx)
+
+	if existing == nil {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      certificate.Spec.Target.Name,
+				Namespace: certificate.Namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}
+
+		if err := controllerutil.SetControllerReference(certificate, secret, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+
+		if err := r.Create(ctx, secret); err != nil {
+			return err
+		}
+		log.Info("Created secret", "secret", secret.Name)
+		return nil
+	}
+
+	if !metav1.IsControlledBy(existing, certificate) {
+		return apierrors.NewAlreadyExists(corev1.Resource("secrets"), existing.Name)
+	}
+
+	if maps.EqualFunc(existing.Data, data, slices.Equal) {
+		return nil
+	}
+
+	existing.Data = data
+	if err := r.Update(ctx, existing); err != nil {
+		return err
+	}
+	log.Info("Updated secret", "secret", existing.Name)
+	return nil
+}
+
+func (r *PKICertificateReconciler) deleteK8sSecret(ctx context.Context, certificate *pkiv1beta1.PKICertificate) error {
+	secret, err := r.fetchK8sSecret(ctx, certificate)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(secret, certificate) {
+		ctrl.Log.Info("Secret exists but CR is not owner, skipping deletion", "secret", certificate.Spec.Target.Name)
+		return nil
+	}
+
+	if err := r.Delete(ctx, secret); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
+
+// releaseK8sSecret removes the owner reference of the resource from the target
+// Secret, so that it is not garbage collected along with the resource.
+func (r *PKICertificateReconciler) releaseK8sSecret(ctx context.Context, certificate *pkiv1beta1.PKICertificate) error {
+	secret, err := r.fetchK8sSecret(ctx, certificate)
+	if err != nil || secret == nil || !metav1.IsControlledBy(secret, certificate) {
+		return err
+	}
+
+	secret.OwnerReferences = slices.DeleteFunc(secret.OwnerReferences, func(o metav1.OwnerReference) bool {
+		return o.UID == certificate.UID
+	})
+	return r.Update(ctx, secret)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PKICertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&pkiv1beta1.PKICertificate{}).
+		Owns(&corev1.Secret{}).
+		Named("pki-pkicertificate").
+		Complete(r)
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+)
+
+var _ = Describe("PKICertificate Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-certificate"
+		const targetName = "test-tls"
+		const mount = "pki"
+		const role = "svc"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		targetNamespacedName := types.NamespacedName{
+			Name:      targetName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *PKICertificateReconciler
+
+		reconcileWithResult := func() (reconcile.Result, error) {
+			return controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+		}
+		reconcileOnce := func() error {
+			_, err := reconcileWithResult()
+			return err
+		}
+
+		get := func() *pkiv1beta1.PKICertificate {
+			certificate := &pkiv1beta1.PKICertificate{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, certificate)).To(Succeed())
+			return certificate
+		}
+
+		target := func() *corev1.Secret {
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, targetNamespacedName, secret)).To(Succeed())
+			return secret
+		}
+
+		leaf := func() *x509.Certificate {
+			block, _ := pem.Decode(target().Data[corev1.TLSCertKey])
+			Expect(block).NotTo(BeNil())
+			cert, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+			return cert
+		}
+
+		// ageCertificate makes the certificate look due for renewal.
+		ageCertificate := func() {
+			certificate := get()
+			due := metav1.NewTime(time.Now().Add(-time.Minute))
+			certificate.Status.RenewalTime = &due
+			Expect(k8sClient.Status().Update(ctx, certificate)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+			_, err := vaultServer.Client().Write(ctx, mount+"/root/generate/internal", map[string]interface{}{"common_name": "Test Root CA"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = vaultServer.Client().Write(ctx, mount+"/roles/"+role, map[string]interface{}{
+				"allowed_domains":  []string{"svc.cluster.local"},
+				"allow_subdomains": true,
+				"key_type":         "ec",
+				"ttl":              "30h",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			controllerReconciler = &PKICertificateReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind PKICertificate")
+			resource := &pkiv1beta1.PKICertificate{}
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &pkiv1beta1.PKICertificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: pkiv1beta1.PKICertificateSpec{
+						Mount:             mount,
+						Role:              role,
+						CommonName:        "app.default.svc.cluster.local",
+						AltNames:          []string{"app.svc.cluster.local"},
+						RenewalPercentage: 50,
+						Target: pkiv1beta1.PKICertificateTarget{
+							Name:           targetName,
+							DeletionPolicy: "Delete",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &pkiv1beta1.PKICertificate{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance PKICertificate")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, targetNamespacedName, secret); err == nil {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			}
+		})
+
+		It("should write the certificate to a kubernetes.io/tls Secret", func() {
+			By("Reconciling the created resource")
+			result, err := reconcileWithResult()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 15*time.Hour, time.Minute))
+
+			secret := target()
+			Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+			Expect(secret.Data).To(HaveKey("ca.crt"))
+			_, err = tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+			Expect(err).NotTo(HaveOccurred())
+
+			cert := leaf()
+			Expect(cert.Subject.CommonName).To(Equal("app.default.svc.cluster.local"))
+			Expect(cert.DNSNames).To(ContainElement("app.svc.cluster.local"))
+
+			certificate := get()
+			Expect(metav1.IsControlledBy(secret, certificate)).To(BeTrue())
+			Expect(certificate.Finalizers).To(ContainElement(pkiCertificateFinalizer))
+			Expect(certificate.Status.SerialNumber).NotTo(BeEmpty())
+			Expect(certificate.Status.NotAfter.Time).To(BeTemporally("~", cert.NotAfter, time.Second))
+			Expect(certificate.Status.RenewalTime.Time).To(BeTemporally("~", cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore)/2), time.Second))
+			Expect(meta.IsStatusConditionTrue(certificate.Status.Conditions, typeConfiguredPKICertificate)).To(BeTrue())
+		})
+
+		It("should not issue a new certificate before the renewal time", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			serial := get().Status.SerialNumber
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(get().Status.SerialNumber).To(Equal(serial))
+		})
+
+		It("should renew the certificate once due", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			serial := get().Status.SerialNumber
+
+			ageCertificate()
+			Expect(reconcileOnce()).To(Succeed())
+
+			certificate := get()
+			Expect(certificate.Status.SerialNumber).NotTo(Equal(serial))
+			Expect(certificate.Status.RenewalTime.Time).To(BeTemporally(">", time.Now()))
+
+			By("Leaving the previous certificate valid")
+			_, revoked, ok := vaultServer.PKICertificate(mount, serial)
+			Expect(ok).To(BeTrue())
+			Expect(revoked).To(BeFalse())
+		})
+
+		It("should issue a new certificate when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			certificate := get()
+			certificate.Spec.AltNames = append(certificate.Spec.AltNames, "app.cluster.svc.cluster.local")
+			certificate.Spec.TTL = &metav1.Duration{Duration: 2 * time.Hour}
+			Expect(k8sClient.Update(ctx, certificate)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			cert := leaf()
+			Expect(cert.DNSNames).To(ContainElement("app.cluster.svc.cluster.local"))
+			Expect(cert.NotAfter.Sub(cert.NotBefore)).To(BeNumerically("~", 2*time.Hour, time.Minute))
+		})
+
+		It("should issue a new certificate when the Secret is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			serial := get().Status.SerialNumber
+			Expect(k8sClient.Delete(ctx, target())).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(target().Data).To(HaveKey(corev1.TLSCertKey))
+			Expect(get().Status.SerialNumber).NotTo(Equal(serial))
+		})
+
+		It("should revoke and issue again a certificate that never reached the Secret", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			delivered := get().Status.SerialNumber
+
+			By("Saving a certificate that never reached the Secret")
+			s, err := vaultServer.Client().Write(ctx, mount+"/issue/"+role, map[string]interface{}{"common_name": "app.default.svc.cluster.local"})
+			Expect(err).NotTo(HaveOccurred())
+			undelivered := s.Data["serial_number"].(string)
+			certificate := get()
+			certificate.Status.SerialNumber = undelivered
+			Expect(k8sClient.Status().Update(ctx, certificate)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			certificate = get()
+			Expect(certificate.Status.SerialNumber).NotTo(BeElementOf(delivered, undelivered))
+			Expect(formatSerial(leaf().SerialNumber.Bytes())).To(Equal(certificate.Status.SerialNumber))
+			_, revoked, _ := vaultServer.PKICertificate(mount, undelivered)
+			Expect(revoked).To(BeTrue())
+			_, revoked, _ = vaultServer.PKICertificate(mount, delivered)
+			Expect(revoked).To(BeFalse())
+		})
+
+		It("should not take over a Secret it does not own", func() {
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: targetName, Namespace: "default"},
+				Data:       map[string][]byte{"foreign": []byte("data")},
+			})).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(target().Data).To(HaveKey("foreign"))
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredPKICertificate)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should report a name the role does not allow", func() {
+			certificate := get()
+			certificate.Spec.CommonName = "app.example.com"
+			Expect(k8sClient.Update(ctx, certificate)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredPKICertificate)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should revoke the certificate and delete the Secret on deletion", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			serial := get().Status.SerialNumber
+
+			Expect(k8sClient.Delete(ctx, get())).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, revoked, _ := vaultServer.PKICertificate(mount, serial)
+			Expect(revoked).To(BeTrue())
+			err := k8sClient.Get(ctx, targetNamespacedName, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
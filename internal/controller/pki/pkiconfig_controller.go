@@ -0,0 +1,306 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredPKIConfig = "Configured"
+)
+
+// PKIConfigReconciler reconciles a PKIConfig object
+type PKIConfigReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiconfigs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Each section of the spec maps to a configuration endpoint of the mount and
+// sections left unset are not managed. The configuration of a mount cannot be
+// deleted, so it is left as is when the resource is deleted.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *PKIConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the PKIConfig instance
+	config := &pkiv1beta1.PKIConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("PKIConfig resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get PKIConfig")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, config, &config.Status.Conditions, r.Mode, ""); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag PKIConfig in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if config.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// PKIConfig Initialization
+	if meta.FindStatusCondition(config.Status.Conditions, typeConfiguredPKIConfig) == nil {
+		vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredPKIConfig, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Status().Update(ctx, config); err != nil {
+			log.Error(err, "Failed to initialize PKIConfig status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := config.Status.DeepCopy()
+	sections := []struct {
+		name      string
+		path      string
+		reconcile func(context.Context, *pkiv1beta1.PKIConfig) error
+	}{
+		{"default issuer", "config/issuers", r.reconcileDefaultIssuer},
+		{"URLs", "config/urls", r.reconcileURLs},
+		{"CRL", "config/crl", r.reconcileCRL},
+		{"auto-tidy", "config/auto-tidy", r.reconcileAutoTidy},
+	}
+	for _, section := range sections {
+		if err := section.reconcile(ctx, config); err != nil {
+			log.Error(err, "Failed to configure PKI mount", "section", section.name)
+			vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredPKIConfig, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to configure %s: %s", section.name, err)})
+			events.Failed(r.Recorder, config, "FailedToUpdate", fmt.Sprintf("Failed to configure %s", section.name), fmt.Sprintf("%s/%s", config.Spec.Mount, section.path), err)
+			if err := r.Status().Update(ctx, config); err != nil {
+				log.Error(err, "Failed to update PKIConfig status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The status is left untouched while the mount stays configured
+	config.Status.ObservedGeneration = config.Generation
+	config.Status.VaultPath = config.Spec.Mount + "/config"
+	vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredPKIConfig, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully configured PKI mount in Vault"})
+	if !equality.Semantic.DeepEqual(status, &config.Status) {
+		if err := r.Status().Update(ctx, config); err != nil {
+			log.Error(err, "Failed to update PKIConfig status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileDefaultIssuer resolves the default issuer, given by name or ID, and
+// selects it when another one is the default.
+func (r *PKIConfigReconciler) reconcileDefaultIssuer(ctx context.Context, config *pkiv1beta1.PKIConfig) error {
+	if config.Spec.DefaultIssuer == "" {
+		return nil
+	}
+
+	issuer, err := fetchVaultIssuer(ctx, r.Vault, config.Spec.Mount, config.Spec.DefaultIssuer)
+	if err != nil {
+		return err
+	}
+	if issuer == nil {
+		return fmt.Errorf("issuer %s not found", config.Spec.DefaultIssuer)
+	}
+
+	path := fmt.Sprintf("%s/config/issuers", config.Spec.Mount)
+	s, err := r.Vault.Read(ctx, path)
+	if err != nil {
+		return err
+	}
+	if s != nil && s.Data["default"] == issuer.IssuerID {
+		return nil
+	}
+
+	if err := r.writeVaultConfig(ctx, config, path, map[string]interface{}{
+		"default": issuer.IssuerID,
+	}); err != nil {
+		return err
+	}
+
+	events.Pushed(r.Recorder, config, config.Status.Conditions, true, "PKIConfig", path)
+	return nil
+}
+
+func (r *PKIConfigReconciler) reconcileURLs(ctx context.Context, config *pkiv1beta1.PKIConfig) error {
+	if config.Spec.URLs == nil {
+		return nil
+	}
+
+	path := fmt.Sprintf("%s/config/urls", config.Spec.Mount)
+	current := &vault.PKIURLs{}
+	found, err := r.fetchVaultConfig(ctx, path, current)
+	if err != nil {
+		return err
+	} else if found && !current.IsDifferentFromSpec(config.Spec.URLs) {
+		return nil
+	}
+
+	err = r.writeVaultConfig(ctx, config, path, map[string]interface{}{
+		"issuing_certificates":    emptyIfNil(config.Spec.URLs.IssuingCertificates),
+		"crl_distribution_points": emptyIfNil(config.Spec.URLs.CRLDistributionPoints),
+		"ocsp_servers":            emptyIfNil(config.Spec.URLs.OCSPServers),
+		"enable_templating":       config.Spec.URLs.EnableTemplating,
+	})
+	if err != nil {
+		return err
+	}
+
+	events.Pushed(r.Recorder, config, config.Status.Conditions, found, "PKIConfig", path)
+	return nil
+}
+
+func (r *PKIConfigReconciler) reconcileCRL(ctx context.Context, config *pkiv1beta1.PKIConfig) error {
+	if config.Spec.CRL == nil {
+		return nil
+	}
+
+	path := fmt.Sprintf("%s/config/crl", config.Spec.Mount)
+	current := &vault.PKICRL{}
+	found, err := r.fetchVaultConfig(ctx, path, current)
+	if err != nil {
+		return err
+	} else if found && !current.IsDifferentFromSpec(config.Spec.CRL) {
+		return nil
+	}
+
+	err = r.writeVaultConfig(ctx, config, path, map[string]interface{}{
+		"expiry":                    config.Spec.CRL.Expiry,
+		"disable":                   config.Spec.CRL.Disable,
+		"ocsp_disable":              config.Spec.CRL.OCSPDisable,
+		"auto_rebuild":              config.Spec.CRL.AutoRebuild,
+		"auto_rebuild_grace_period": config.Spec.CRL.AutoRebuildGracePeriod,
+		"enable_delta":              config.Spec.CRL.EnableDelta,
+		"delta_rebuild_interval":    config.Spec.CRL.DeltaRebuildInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	events.Pushed(r.Recorder, config, config.Status.Conditions, found, "PKIConfig", path)
+	return nil
+}
+
+func (r *PKIConfigReconciler) reconcileAutoTidy(ctx context.Context, config *pkiv1beta1.PKIConfig) error {
+	if config.Spec.AutoTidy == nil {
+		return nil
+	}
+
+	path := fmt.Sprintf("%s/config/auto-tidy", config.Spec.Mount)
+	current := &vault.PKIAutoTidy{}
+	found, err := r.fetchVaultConfig(ctx, path, current)
+	if err != nil {
+		return err
+	} else if found && !current.IsDifferentFromSpec(config.Spec.AutoTidy) {
+		return nil
+	}
+
+	err = r.writeVaultConfig(ctx, config, path, map[string]interface{}{
+		"enabled":              config.Spec.AutoTidy.Enabled,
+		"interval_duration":    config.Spec.AutoTidy.Interval,
+		"tidy_cert_store":      config.Spec.AutoTidy.TidyCertStore,
+		"tidy_revoked_certs":   config.Spec.AutoTidy.TidyRevokedCerts,
+		"tidy_expired_issuers": config.Spec.AutoTidy.TidyExpiredIssuers,
+		"safety_buffer":        config.Spec.AutoTidy.SafetyBuffer,
+	})
+	if err != nil {
+		return err
+	}
+
+	events.Pushed(r.Recorder, config, config.Status.Conditions, found, "PKIConfig", path)
+	return nil
+}
+
+// writeVaultConfig writes a configuration endpoint of the mount and records
+// the payload as the last one applied.
+func (r *PKIConfigReconciler) writeVaultConfig(ctx context.Context, config *pkiv1beta1.PKIConfig, path string, data map[string]interface{}) error {
+	if _, err := r.Vault.Write(ctx, path, data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	config.Status.LastAppliedHash = vault.Checksum(data)
+	config.Status.LastAppliedTime = &now
+	return nil
+}
+
+// fetchVaultConfig reads a configuration endpoint of the mount into out and
+// reports whether it was found.
+func (r *PKIConfigReconciler) fetchVaultConfig(ctx context.Context, path string, out interface{}) (bool, error) {
+	s, err := r.Vault.Read(ctx, path)
+	if err != nil {
+		return false, err
+	}
+
+	if s == nil {
+		return false, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return false, err
+	}
+
+	return true, json.Unmarshal(jsonBytes, out)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PKIConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&pkiv1beta1.PKIConfig{}).
+		Named("pki-pkiconfig").
+		Complete(r)
+}
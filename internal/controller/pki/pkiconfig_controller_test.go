@@ -0,0 +1,229 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+)
+
+var _ = Describe("PKIConfig Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-config"
+		const mount = "pki"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *PKIConfigReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		get := func() *pkiv1beta1.PKIConfig {
+			config := &pkiv1beta1.PKIConfig{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, config)).To(Succeed())
+			return config
+		}
+
+		read := func(path string) map[string]interface{} {
+			s, err := vaultServer.Client().Read(ctx, mount+"/"+path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			return s.Data
+		}
+
+		generateRoot := func(name string) string {
+			s, err := vaultServer.Client().Write(ctx, mount+"/root/generate/internal", map[string]interface{}{
+				"common_name": name,
+				"issuer_name": name,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return s.Data["issuer_id"].(string)
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+
+			controllerReconciler = &PKIConfigReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind PKIConfig")
+			resource := &pkiv1beta1.PKIConfig{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &pkiv1beta1.PKIConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: pkiv1beta1.PKIConfigSpec{
+						Mount: mount,
+						URLs: &pkiv1beta1.PKIURLs{
+							IssuingCertificates:   []string{"https://vault.example.com/v1/pki/ca"},
+							CRLDistributionPoints: []string{"https://vault.example.com/v1/pki/crl"},
+						},
+						CRL: &pkiv1beta1.PKICRL{
+							Expiry:                 "48h",
+							AutoRebuild:            true,
+							AutoRebuildGracePeriod: "12h",
+							DeltaRebuildInterval:   "15m",
+						},
+						AutoTidy: &pkiv1beta1.PKIAutoTidy{
+							Enabled:          true,
+							Interval:         "24h",
+							TidyCertStore:    true,
+							TidyRevokedCerts: true,
+							SafetyBuffer:     "72h",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &pkiv1beta1.PKIConfig{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance PKIConfig")
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should configure the mount", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			urls := read("config/urls")
+			Expect(urls["issuing_certificates"]).To(ConsistOf("https://vault.example.com/v1/pki/ca"))
+			Expect(urls["crl_distribution_points"]).To(ConsistOf("https://vault.example.com/v1/pki/crl"))
+			Expect(urls["ocsp_servers"]).To(BeEmpty())
+
+			crl := read("config/crl")
+			Expect(crl).To(HaveKeyWithValue("expiry", "48h"))
+			Expect(crl).To(HaveKeyWithValue("auto_rebuild", true))
+
+			autoTidy := read("config/auto-tidy")
+			Expect(autoTidy).To(HaveKeyWithValue("enabled", true))
+			Expect(autoTidy).To(HaveKeyWithValue("interval_duration", json.Number("86400")))
+			Expect(autoTidy).To(HaveKeyWithValue("safety_buffer", json.Number("259200")))
+
+			Expect(meta.IsStatusConditionTrue(get().Status.Conditions, typeConfiguredPKIConfig)).To(BeTrue())
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(3))
+		})
+
+		It("should select the default issuer by name", func() {
+			generateRoot("first")
+			second := generateRoot("second")
+			Expect(read("config/issuers")).NotTo(HaveKeyWithValue("default", second))
+
+			config := get()
+			config.Spec.DefaultIssuer = "second"
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(read("config/issuers")).To(HaveKeyWithValue("default", second))
+		})
+
+		It("should restore settings modified behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, err := vaultServer.Client().Write(ctx, mount+"/config/crl", map[string]interface{}{"expiry": "1h"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(read("config/crl")).To(HaveKeyWithValue("expiry", "48h"))
+		})
+
+		It("should leave unmanaged sections alone", func() {
+			config := get()
+			config.Spec.URLs = nil
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			for _, r := range vaultServer.Requests() {
+				Expect(r.Path).NotTo(Equal(mount + "/config/urls"))
+			}
+		})
+
+		It("should report an unknown default issuer", func() {
+			config := get()
+			config.Spec.DefaultIssuer = "missing"
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredPKIConfig)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+
+		It("should report settings rejected by Vault", func() {
+			config := get()
+			config.Spec.CRL.AutoRebuild = false
+			config.Spec.CRL.EnableDelta = true
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredPKIConfig)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+	})
+})
@@ -0,0 +1,491 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"maps"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	pkiIssuerFinalizer = "pkiissuer.pki.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredPKIIssuer = "Configured"
+)
+
+// PKIIssuerReconciler reconciles a PKIIssuer object
+type PKIIssuerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiissuers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiissuers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiissuers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The issuer is named after the resource in Vault, which is how it is found
+// again on later reconciliations. Roots are generated in one step.
+// Intermediates take three: a CSR is generated on the mount, signed by the
+// issuer of signerRef, and the certificate and its chain are imported back.
+// The key and the imported issuer are saved in the status as soon as they
+// exist, so that a failed attempt resumes with them rather than leaving
+// duplicates behind.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *PKIIssuerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the PKIIssuer instance
+	issuer := &pkiv1beta1.PKIIssuer{}
+	if err := r.Get(ctx, req.NamespacedName, issuer); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("PKIIssuer resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get PKIIssuer")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, issuer, &issuer.Status.Conditions, r.Mode, pkiIssuerFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag PKIIssuer in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// PKIIssuer Deletion
+	isIssuerMarkedToBeDeleted := issuer.GetDeletionTimestamp() != nil
+	if isIssuerMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(issuer, pkiIssuerFinalizer) {
+			if issuer.Spec.DeletionPolicy == "Delete" && (issuer.Status.IssuerID != "" || issuer.Status.KeyID != "") {
+				if err := r.deleteVaultPKIIssuer(ctx, issuer); err != nil {
+					log.Error(err, "Failed to delete PKIIssuer")
+					return ctrl.Result{}, err
+				}
+				if issuer.Status.IssuerID != "" {
+					events.Deleted(r.Recorder, issuer, pkiIssuerPath(issuer.Spec.Mount, issuer.Status.IssuerID))
+				} else {
+					events.Deleted(r.Recorder, issuer, fmt.Sprintf("%s/key/%s", issuer.Spec.Mount, issuer.Status.KeyID))
+				}
+			}
+
+			controllerutil.RemoveFinalizer(issuer, pkiIssuerFinalizer)
+			if err := r.Update(ctx, issuer); err != nil {
+				log.Error(err, "Failed to remove finalizer from PKIIssuer")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// PKIIssuer Initialization
+	if !controllerutil.ContainsFinalizer(issuer, pkiIssuerFinalizer) {
+		controllerutil.AddFinalizer(issuer, pkiIssuerFinalizer)
+		vault.SetCondition(&issuer.Status.Conditions, issuer.Generation, metav1.Condition{Type: typeConfiguredPKIIssuer, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, issuer); err != nil {
+			log.Error(err, "Failed to initialize PKIIssuer status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	current, err := r.fetchVaultPKIIssuer(ctx, issuer)
+	if err != nil {
+		log.Error(err, "Failed to fetch PKIIssuer")
+		vault.SetCondition(&issuer.Status.Conditions, issuer.Generation, metav1.Condition{Type: typeConfiguredPKIIssuer, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch issuer from Vault"})
+		events.Failed(r.Recorder, issuer, "FailedToFetch", "Failed to fetch issuer from Vault", pkiIssuerPath(issuer.Spec.Mount, issuer.Name), err)
+		if err := r.Status().Update(ctx, issuer); err != nil {
+			log.Error(err, "Failed to update PKIIssuer status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create
+	if current == nil {
+		if current, err = r.createVaultPKIIssuer(ctx, issuer); err != nil {
+			log.Error(err, "Failed to create PKIIssuer")
+			vault.SetCondition(&issuer.Status.Conditions, issuer.Generation, metav1.Condition{Type: typeConfiguredPKIIssuer, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to create %s issuer: %s", issuer.Spec.Type, err)})
+			events.Failed(r.Recorder, issuer, "FailedToCreate", fmt.Sprintf("Failed to create %s issuer", issuer.Spec.Type), pkiIssuerPath(issuer.Spec.Mount, issuer.Name), err)
+			if err := r.Status().Update(ctx, issuer); err != nil {
+				log.Error(err, "Failed to update PKIIssuer status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Created PKIIssuer", "issuer", current.IssuerID)
+		events.Pushed(r.Recorder, issuer, issuer.Status.Conditions, false, "PKIIssuer", pkiIssuerPath(issuer.Spec.Mount, current.IssuerID))
+	}
+
+	// Update, naming the issuer as well when its creation stopped short of it
+	if current.IsDifferentFromSpec(&issuer.Spec) || current.IssuerName != issuer.Name {
+		if err := r.updateVaultPKIIssuer(ctx, issuer, current.IssuerID); err != nil {
+			log.Error(err, "Failed to update PKIIssuer")
+			vault.SetCondition(&issuer.Status.Conditions, issuer.Generation, metav1.Condition{Type: typeConfiguredPKIIssuer, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push issuer settings to Vault"})
+			events.Failed(r.Recorder, issuer, "FailedToUpdate", "Failed to push issuer settings to Vault", pkiIssuerPath(issuer.Spec.Mount, current.IssuerID), err)
+			if err := r.Status().Update(ctx, issuer); err != nil {
+				log.Error(err, "Failed to update PKIIssuer status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, issuer, issuer.Status.Conditions, true, "PKIIssuer", pkiIssuerPath(issuer.Spec.Mount, current.IssuerID))
+	}
+
+	if err := setIssuerStatus(issuer, current); err != nil {
+		log.Error(err, "Failed to parse issuer certificate")
+		return ctrl.Result{}, err
+	}
+	issuer.Status.ObservedGeneration = issuer.Generation
+	issuer.Status.VaultPath = pkiIssuerPath(issuer.Spec.Mount, issuer.Status.IssuerID)
+	vault.SetCondition(&issuer.Status.Conditions, issuer.Generation, metav1.Condition{Type: typeConfiguredPKIIssuer, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully configured issuer in Vault"})
+	if err := r.Status().Update(ctx, issuer); err != nil {
+		log.Error(err, "Failed to update PKIIssuer status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func pkiIssuerPath(mount, ref string) string {
+	return fmt.Sprintf("%s/issuer/%s", mount, ref)
+}
+
+// fetchVaultPKIIssuer returns the issuer of the resource, looked up by name
+// unless its ID is known already. Several issuers of that name are an error,
+// as there is no telling which one the resource owns.
+func (r *PKIIssuerReconciler) fetchVaultPKIIssuer(ctx context.Context, issuer *pkiv1beta1.PKIIssuer) (*vault.PKIIssuer, error) {
+	ref := issuer.Status.IssuerID
+	if ref == "" {
+		s, err := r.Vault.List(ctx, fmt.Sprintf("%s/issuers", issuer.Spec.Mount))
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, nil
+		}
+
+		keyInfo, _ := s.Data["key_info"].(map[string]interface{})
+		for id, info := range keyInfo {
+			if m, ok := info.(map[string]interface{}); ok && m["issuer_name"] == issuer.Name {
+				if ref != "" {
+					return nil, fmt.Errorf("several issuers named %s: %s and %s", issuer.Name, ref, id)
+				}
+				ref = id
+			}
+		}
+		if ref == "" {
+			return nil, nil
+		}
+	}
+
+	return fetchVaultIssuer(ctx, r.Vault, issuer.Spec.Mount, ref)
+}
+
+func fetchVaultIssuer(ctx context.Context, v vault.Interface, mount, ref string) (*vault.PKIIssuer, error) {
+	s, err := v.Read(ctx, pkiIssuerPath(mount, ref))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var i vault.PKIIssuer
+	if err := json.Unmarshal(jsonBytes, &i); err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// createVaultPKIIssuer generates the issuer and names it after the resource.
+// The key of an intermediate is saved in the status once its CSR is
+// generated, and the issuer once imported, so that a retry generates the CSR
+// again for the same key and an imported issuer is only left to be named.
+func (r *PKIIssuerReconciler) createVaultPKIIssuer(ctx context.Context, issuer *pkiv1beta1.PKIIssuer) (*vault.PKIIssuer, error) {
+	// An issuer deleted behind the operator's back is generated again from
+	// scratch
+	if issuer.Status.IssuerID != "" {
+		issuer.Status.IssuerID = ""
+		issuer.Status.KeyID = ""
+	}
+
+	data := map[string]interface{}{
+		"common_name": issuer.Spec.CommonName,
+		"key_type":    issuer.Spec.KeyType,
+		"key_bits":    issuer.Spec.KeyBits,
+	}
+	if issuer.Spec.Organization != nil {
+		data["organization"] = strings.Join(issuer.Spec.Organization, ",")
+	}
+
+	var issuerID string
+	if issuer.Spec.Type == "root" {
+		data["issuer_name"] = issuer.Name
+		if issuer.Spec.TTL != "" {
+			data["ttl"] = issuer.Spec.TTL
+		}
+
+		s, err := r.Vault.Write(ctx, fmt.Sprintf("%s/root/generate/internal", issuer.Spec.Mount), data)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, fmt.Errorf("no issuer returned by the generation of the root")
+		}
+		issuerID = fmt.Sprint(s.Data["issuer_id"])
+	} else {
+		signer, err := r.fetchSigner(ctx, issuer)
+		if err != nil {
+			return nil, err
+		}
+
+		// Generate a CSR, for the key of an earlier attempt if any
+		generate := maps.Clone(data)
+		path := fmt.Sprintf("%s/intermediate/generate/internal", issuer.Spec.Mount)
+		if issuer.Status.KeyID != "" {
+			generate["key_ref"] = issuer.Status.KeyID
+			path = fmt.Sprintf("%s/intermediate/generate/existing", issuer.Spec.Mount)
+		}
+		s, err := r.Vault.Write(ctx, path, generate)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, fmt.Errorf("no CSR returned by the generation of the intermediate")
+		}
+		csr := fmt.Sprint(s.Data["csr"])
+		if issuer.Status.KeyID == "" {
+			issuer.Status.KeyID = fmt.Sprint(s.Data["key_id"])
+			if err := r.Status().Update(ctx, issuer); err != nil {
+				return nil, err
+			}
+		}
+
+		// Have the signer sign it
+		sign := map[string]interface{}{
+			"csr":         csr,
+			"common_name": issuer.Spec.CommonName,
+		}
+		if issuer.Spec.TTL != "" {
+			sign["ttl"] = issuer.Spec.TTL
+		}
+		s, err = r.Vault.Write(ctx, pkiIssuerPath(signer.Spec.Mount, signer.Status.IssuerID)+"/sign-intermediate", sign)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign intermediate with %s: %w", signer.Name, err)
+		}
+		if s == nil {
+			return nil, fmt.Errorf("no certificate returned by %s", signer.Name)
+		}
+		bundle := []string{fmt.Sprint(s.Data["certificate"])}
+		if chain, ok := s.Data["ca_chain"].([]interface{}); ok {
+			for _, c := range chain {
+				bundle = append(bundle, fmt.Sprint(c))
+			}
+		}
+
+		// Import the certificate along with its chain
+		s, err = r.Vault.Write(ctx, fmt.Sprintf("%s/intermediate/set-signed", issuer.Spec.Mount), map[string]interface{}{
+			"certificate": strings.Join(bundle, "\n"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, fmt.Errorf("no issuer imported from the signed intermediate")
+		}
+		// Parents in the chain are imported as well, without a key
+		mapping, _ := s.Data["mapping"].(map[string]interface{})
+		for id, keyID := range mapping {
+			if keyID != nil && keyID != "" {
+				issuerID = id
+			}
+		}
+		if issuerID == "" {
+			return nil, fmt.Errorf("no issuer imported from the signed intermediate")
+		}
+		issuer.Status.IssuerID = issuerID
+		if err := r.Status().Update(ctx, issuer); err != nil {
+			return nil, err
+		}
+
+		if _, err := r.Vault.Write(ctx, pkiIssuerPath(issuer.Spec.Mount, issuerID), map[string]interface{}{
+			"issuer_name": issuer.Name,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	now := metav1.Now()
+	issuer.Status.LastAppliedHash = vault.Checksum(data)
+	issuer.Status.LastAppliedTime = &now
+
+	current, err := fetchVaultIssuer(ctx, r.Vault, issuer.Spec.Mount, issuerID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("issuer %s not found after its creation", issuerID)
+	}
+	return current, nil
+}
+
+// fetchSigner returns the PKIIssuer signing an intermediate, provided it was
+// created in Vault already.
+func (r *PKIIssuerReconciler) fetchSigner(ctx context.Context, issuer *pkiv1beta1.PKIIssuer) (*pkiv1beta1.PKIIssuer, error) {
+	signer := &pkiv1beta1.PKIIssuer{}
+	if err := r.Get(ctx, types.NamespacedName{Name: issuer.Spec.SignerRef.Name, Namespace: issuer.Namespace}, signer); err != nil {
+		return nil, fmt.Errorf("failed to get signer %s: %w", issuer.Spec.SignerRef.Name, err)
+	}
+	if signer.Status.IssuerID == "" {
+		return nil, fmt.Errorf("signer %s is not ready", signer.Name)
+	}
+	return signer, nil
+}
+
+func (r *PKIIssuerReconciler) updateVaultPKIIssuer(ctx context.Context, issuer *pkiv1beta1.PKIIssuer, issuerID string) error {
+	data := map[string]interface{}{
+		"issuer_name":             issuer.Name,
+		"leaf_not_after_behavior": issuer.Spec.LeafNotAfterBehavior,
+	}
+	if _, err := r.Vault.Write(ctx, pkiIssuerPath(issuer.Spec.Mount, issuerID), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	issuer.Status.LastAppliedHash = vault.Checksum(data)
+	issuer.Status.LastAppliedTime = &now
+	return nil
+}
+
+// deleteVaultPKIIssuer deletes the issuer and then its key, which Vault keeps
+// otherwise. An intermediate whose creation stopped short of importing its
+// issuer only has a key.
+func (r *PKIIssuerReconciler) deleteVaultPKIIssuer(ctx context.Context, issuer *pkiv1beta1.PKIIssuer) error {
+	if issuer.Status.IssuerID != "" {
+		if _, err := r.Vault.Delete(ctx, pkiIssuerPath(issuer.Spec.Mount, issuer.Status.IssuerID)); err != nil {
+			return err
+		}
+	}
+	if issuer.Status.KeyID == "" {
+		return nil
+	}
+	_, err := r.Vault.Delete(ctx, fmt.Sprintf("%s/key/%s", issuer.Spec.Mount, issuer.Status.KeyID))
+	return err
+}
+
+func setIssuerStatus(issuer *pkiv1beta1.PKIIssuer, current *vault.PKIIssuer) error {
+	block, _ := pem.Decode([]byte(current.Certificate))
+	if block == nil {
+		return fmt.Errorf("no certificate found for issuer %s", current.IssuerID)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+
+	notAfter := metav1.NewTime(cert.NotAfter)
+	issuer.Status.IssuerID = current.IssuerID
+	issuer.Status.KeyID = current.KeyID
+	issuer.Status.SerialNumber = formatSerial(cert.SerialNumber.Bytes())
+	issuer.Status.NotAfter = &notAfter
+	issuer.Status.Certificate = current.Certificate
+	return nil
+}
+
+// formatSerial formats a serial number the way Vault does, as colon separated
+// hex bytes.
+func formatSerial(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02x", v)
+	}
+	return strings.Join(parts, ":")
+}
+
+// findIntermediatesForSigner returns the intermediates signed by a PKIIssuer,
+// so that they are created once it is.
+func (r *PKIIssuerReconciler) findIntermediatesForSigner(ctx context.Context, signer client.Object) []reconcile.Request {
+	issuers := &pkiv1beta1.PKIIssuerList{}
+	if err := r.List(ctx, issuers, client.InNamespace(signer.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list PKIIssuers")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, i := range issuers.Items {
+		if i.Spec.SignerRef != nil && i.Spec.SignerRef.Name == signer.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: i.Name, Namespace: i.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PKIIssuerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&pkiv1beta1.PKIIssuer{}).
+		Watches(&pkiv1beta1.PKIIssuer{}, handler.EnqueueRequestsFromMapFunc(r.findIntermediatesForSigner)).
+		Named("pki-pkiissuer").
+		Complete(r)
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("PKIIssuer Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-root"
+		const intermediateName = "test-intermediate"
+		const mount = "pki"
+		const intermediateMount = "pki-int"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		intermediateNamespacedName := types.NamespacedName{
+			Name:      intermediateName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *PKIIssuerReconciler
+
+		reconcileName := func(name types.NamespacedName) error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: name,
+			})
+			return err
+		}
+		reconcileOnce := func() error {
+			return reconcileName(typeNamespacedName)
+		}
+
+		get := func(name types.NamespacedName) *pkiv1beta1.PKIIssuer {
+			issuer := &pkiv1beta1.PKIIssuer{}
+			Expect(k8sClient.Get(ctx, name, issuer)).To(Succeed())
+			return issuer
+		}
+
+		readIssuer := func(mount, ref string) map[string]interface{} {
+			s, err := vaultServer.Client().Read(ctx, mount+"/issuer/"+ref)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			return s.Data
+		}
+
+		parse := func(certificate string) *x509.Certificate {
+			block, _ := pem.Decode([]byte(certificate))
+			Expect(block).NotTo(BeNil())
+			cert, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+			return cert
+		}
+
+		createIntermediate := func() {
+			Expect(k8sClient.Create(ctx, &pkiv1beta1.PKIIssuer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      intermediateName,
+					Namespace: "default",
+				},
+				Spec: pkiv1beta1.PKIIssuerSpec{
+					Mount:                intermediateMount,
+					Type:                 "intermediate",
+					SignerRef:            &pkiv1beta1.PKIIssuerReference{Name: resourceName},
+					CommonName:           "Example Intermediate CA",
+					KeyType:              "ec",
+					TTL:                  "8760h",
+					LeafNotAfterBehavior: "err",
+					DeletionPolicy:       "Delete",
+				},
+			})).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+			Expect(vaultServer.Client().Mount(ctx, intermediateMount, &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+
+			controllerReconciler = &PKIIssuerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind PKIIssuer")
+			resource := &pkiv1beta1.PKIIssuer{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &pkiv1beta1.PKIIssuer{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: pkiv1beta1.PKIIssuerSpec{
+						Mount:                mount,
+						Type:                 "root",
+						CommonName:           "Example Root CA",
+						Organization:         []string{"Example"},
+						KeyType:              "ec",
+						TTL:                  "87600h",
+						LeafNotAfterBehavior: "err",
+						DeletionPolicy:       "Delete",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			vaultServer.ClearFaults()
+			for _, name := range []types.NamespacedName{intermediateNamespacedName, typeNamespacedName} {
+				resource := &pkiv1beta1.PKIIssuer{}
+				if err := k8sClient.Get(ctx, name, resource); err == nil {
+					By("Cleanup the specific resource instance PKIIssuer")
+					Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+					Expect(reconcileName(name)).To(Succeed())
+				}
+			}
+		})
+
+		It("should generate a root issuer named after the resource", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			issuer := get(typeNamespacedName)
+			Expect(issuer.Finalizers).To(ContainElement(pkiIssuerFinalizer))
+			Expect(issuer.Status.IssuerID).NotTo(BeEmpty())
+			Expect(issuer.Status.KeyID).NotTo(BeEmpty())
+			Expect(issuer.Status.NotAfter).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(issuer.Status.Conditions, typeConfiguredPKIIssuer)).To(BeTrue())
+
+			Expect(readIssuer(mount, resourceName)).To(HaveKeyWithValue("issuer_id", issuer.Status.IssuerID))
+			cert := parse(issuer.Status.Certificate)
+			Expect(cert.IsCA).To(BeTrue())
+			Expect(cert.Subject.CommonName).To(Equal("Example Root CA"))
+			Expect(cert.Subject.Organization).To(ConsistOf("Example"))
+			Expect(issuer.Status.SerialNumber).To(Equal(formatSerial(cert.SerialNumber.Bytes())))
+		})
+
+		It("should not generate another issuer when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			issuerID := get(typeNamespacedName).Status.IssuerID
+
+			By("Finding the issuer by name when the status was lost")
+			issuer := get(typeNamespacedName)
+			issuer.Status.IssuerID = ""
+			Expect(k8sClient.Status().Update(ctx, issuer)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(get(typeNamespacedName).Status.IssuerID).To(Equal(issuerID))
+			generated := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Path == mount+"/root/generate/internal" {
+					generated++
+				}
+			}
+			Expect(generated).To(Equal(1))
+		})
+
+		It("should update the leaf not after behavior", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			issuer := get(typeNamespacedName)
+			issuer.Spec.LeafNotAfterBehavior = "truncate"
+			Expect(k8sClient.Update(ctx, issuer)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(readIssuer(mount, resourceName)).To(HaveKeyWithValue("leaf_not_after_behavior", "truncate"))
+		})
+
+		It("should sign and import an intermediate", func() {
+			createIntermediate()
+
+			By("Waiting for the signer to be created")
+			Expect(reconcileName(intermediateNamespacedName)).NotTo(Succeed())
+			condition := meta.FindStatusCondition(get(intermediateNamespacedName).Status.Conditions, typeConfiguredPKIIssuer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileName(intermediateNamespacedName)).To(Succeed())
+
+			root := get(typeNamespacedName)
+			intermediate := get(intermediateNamespacedName)
+			Expect(meta.IsStatusConditionTrue(intermediate.Status.Conditions, typeConfiguredPKIIssuer)).To(BeTrue())
+			Expect(readIssuer(intermediateMount, intermediateName)).To(HaveKeyWithValue("issuer_id", intermediate.Status.IssuerID))
+
+			cert := parse(intermediate.Status.Certificate)
+			Expect(cert.IsCA).To(BeTrue())
+			Expect(cert.Subject.CommonName).To(Equal("Example Intermediate CA"))
+			Expect(cert.CheckSignatureFrom(parse(root.Status.Certificate))).To(Succeed())
+
+			By("Finding the signed intermediate from the signer")
+			requests := controllerReconciler.findIntermediatesForSigner(ctx, root)
+			Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: intermediateNamespacedName}))
+		})
+
+		It("should sign the intermediate again with the key generated before the signer failed", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			createIntermediate()
+
+			root := get(typeNamespacedName)
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPut, Path: mount + "/issuer/" + root.Status.IssuerID + "/sign-intermediate", Times: 1})
+			Expect(reconcileName(intermediateNamespacedName)).NotTo(Succeed())
+			keyID := get(intermediateNamespacedName).Status.KeyID
+			Expect(keyID).NotTo(BeEmpty())
+			Expect(get(intermediateNamespacedName).Status.IssuerID).To(BeEmpty())
+
+			Expect(reconcileName(intermediateNamespacedName)).To(Succeed())
+
+			intermediate := get(intermediateNamespacedName)
+			Expect(intermediate.Status.KeyID).To(Equal(keyID))
+			Expect(readIssuer(intermediateMount, intermediateName)).To(HaveKeyWithValue("key_id", keyID))
+			generated := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Path == intermediateMount+"/intermediate/generate/internal" {
+					generated++
+				}
+			}
+			Expect(generated).To(Equal(1))
+		})
+
+		It("should name the imported intermediate once it failed to", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			createIntermediate()
+
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPut, Path: intermediateMount + "/issuer", Times: 1})
+			Expect(reconcileName(intermediateNamespacedName)).NotTo(Succeed())
+			issuerID := get(intermediateNamespacedName).Status.IssuerID
+			Expect(issuerID).NotTo(BeEmpty())
+
+			Expect(reconcileName(intermediateNamespacedName)).To(Succeed())
+
+			intermediate := get(intermediateNamespacedName)
+			Expect(intermediate.Status.IssuerID).To(Equal(issuerID))
+			Expect(meta.IsStatusConditionTrue(intermediate.Status.Conditions, typeConfiguredPKIIssuer)).To(BeTrue())
+			Expect(readIssuer(intermediateMount, intermediateName)).To(HaveKeyWithValue("issuer_id", issuerID))
+			s, err := vaultServer.Client().List(ctx, intermediateMount+"/issuers")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Data["keys"]).To(HaveLen(1))
+		})
+
+		It("should report Vault errors in the status", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPut, Path: mount + "/root/generate/internal"})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get(typeNamespacedName).Status.Conditions, typeConfiguredPKIIssuer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should not pick one of several issuers named after the resource", func() {
+			for range 2 {
+				_, err := vaultServer.Client().Write(ctx, mount+"/root/generate/internal", map[string]interface{}{"common_name": "Example Root CA", "issuer_name": resourceName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			issuer := get(typeNamespacedName)
+			Expect(issuer.Status.IssuerID).To(BeEmpty())
+			condition := meta.FindStatusCondition(issuer.Status.Conditions, typeConfiguredPKIIssuer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should fail instead of panicking when Vault returns no data", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPut, Path: mount + "/root/generate/internal", Status: http.StatusNoContent})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get(typeNamespacedName).Status.Conditions, typeConfiguredPKIIssuer)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should delete the issuer and its key from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			keyID := get(typeNamespacedName).Status.KeyID
+
+			Expect(k8sClient.Delete(ctx, get(typeNamespacedName))).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, mount+"/issuer/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeNil())
+			s, err = vaultServer.Client().Read(ctx, mount+"/key/"+keyID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeNil())
+		})
+
+		It("should keep the issuer in Vault with the Retain policy", func() {
+			issuer := get(typeNamespacedName)
+			issuer.Spec.DeletionPolicy = "Retain"
+			Expect(k8sClient.Update(ctx, issuer)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, get(typeNamespacedName))).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(readIssuer(mount, resourceName)).To(HaveKey("certificate"))
+		})
+	})
+})
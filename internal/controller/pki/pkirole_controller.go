@@ -0,0 +1,266 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	pkiRoleFinalizer = "pkirole.pki.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredPKIRole = "Configured"
+)
+
+// PKIRoleReconciler reconciles a PKIRole object
+type PKIRoleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=pki.toolkit.vault.hopopops.com,resources=pkiroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *PKIRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the PKIRole instance
+	role := &pkiv1beta1.PKIRole{}
+	if err := r.Get(ctx, req.NamespacedName, role); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("PKIRole resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get PKIRole")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, role, &role.Status.Conditions, r.Mode, pkiRoleFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag PKIRole in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// PKIRole Deletion
+	isRoleMarkedToBeDeleted := role.GetDeletionTimestamp() != nil
+	if isRoleMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(role, pkiRoleFinalizer) {
+			if err := r.deleteVaultPKIRole(ctx, role); err != nil {
+				log.Error(err, "Failed to delete PKIRole")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, role, pkiRolePath(role))
+
+			controllerutil.RemoveFinalizer(role, pkiRoleFinalizer)
+			if err := r.Update(ctx, role); err != nil {
+				log.Error(err, "Failed to remove finalizer from PKIRole")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// PKIRole Initialization
+	if !controllerutil.ContainsFinalizer(role, pkiRoleFinalizer) {
+		controllerutil.AddFinalizer(role, pkiRoleFinalizer)
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredPKIRole, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, role); err != nil {
+			log.Error(err, "Failed to initialize PKIRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultPKIRole(ctx, role)
+	if err != nil {
+		log.Error(err, "Failed to fetch PKIRole")
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredPKIRole, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch PKI role from Vault"})
+		events.Failed(r.Recorder, role, "FailedToFetch", "Failed to fetch PKI role from Vault", pkiRolePath(role), err)
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update PKIRole status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	status := role.Status.DeepCopy()
+	message := "PKI role in Vault matches the spec"
+	if current == nil || current.IsDifferentFromSpec(&role.Spec) {
+		if err := r.updateVaultPKIRole(ctx, role); err != nil {
+			log.Error(err, "Failed to update PKIRole")
+			vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredPKIRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push PKI role to Vault"})
+			events.Failed(r.Recorder, role, "FailedToUpdate", "Failed to push PKI role to Vault", pkiRolePath(role), err)
+			if err := r.Status().Update(ctx, role); err != nil {
+				log.Error(err, "Failed to update PKIRole status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, role, role.Status.Conditions, current != nil, "PKIRole", pkiRolePath(role))
+		message = "Successfully pushed PKI role to Vault"
+	}
+
+	role.Status.ObservedGeneration = role.Generation
+	role.Status.VaultPath = pkiRolePath(role)
+	vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredPKIRole, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &role.Status) {
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update PKIRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func pkiRolePath(role *pkiv1beta1.PKIRole) string {
+	return fmt.Sprintf("%s/roles/%s", role.Spec.Mount, role.Name)
+}
+
+func (r *PKIRoleReconciler) deleteVaultPKIRole(ctx context.Context, role *pkiv1beta1.PKIRole) error {
+	_, err := r.Vault.Delete(ctx, pkiRolePath(role))
+	return err
+}
+
+func (r *PKIRoleReconciler) fetchVaultPKIRole(ctx context.Context, role *pkiv1beta1.PKIRole) (*vault.PKIRole, error) {
+	s, err := r.Vault.Read(ctx, pkiRolePath(role))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr vault.PKIRole
+	if err := json.Unmarshal(jsonBytes, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (r *PKIRoleReconciler) updateVaultPKIRole(ctx context.Context, role *pkiv1beta1.PKIRole) error {
+	data := map[string]interface{}{
+		"issuer_ref":         role.Spec.IssuerRef,
+		"allowed_domains":    emptyIfNil(role.Spec.AllowedDomains),
+		"allow_bare_domains": role.Spec.AllowBareDomains,
+		"allow_subdomains":   role.Spec.AllowSubdomains,
+		"allow_glob_domains": role.Spec.AllowGlobDomains,
+		"allow_any_name":     role.Spec.AllowAnyName,
+		"allow_localhost":    boolOrDefault(role.Spec.AllowLocalhost, true),
+		"allow_ip_sans":      boolOrDefault(role.Spec.AllowIPSANs, true),
+		"server_flag":        boolOrDefault(role.Spec.ServerFlag, true),
+		"client_flag":        boolOrDefault(role.Spec.ClientFlag, true),
+		"key_type":           role.Spec.KeyType,
+		"key_bits":           role.Spec.KeyBits,
+		"ttl":                ttlOrZero(role.Spec.TTL),
+		"max_ttl":            ttlOrZero(role.Spec.MaxTTL),
+		"ext_key_usage":      emptyIfNil(role.Spec.ExtKeyUsage),
+	}
+	if role.Spec.IssuerRef == "" {
+		data["issuer_ref"] = "default"
+	}
+	// Vault applies its default key usage when none is sent
+	if role.Spec.KeyUsage != nil {
+		data["key_usage"] = role.Spec.KeyUsage
+	}
+
+	if _, err := r.Vault.Write(ctx, pkiRolePath(role), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	role.Status.LastAppliedHash = vault.Checksum(data)
+	role.Status.LastAppliedTime = &now
+	return nil
+}
+
+// emptyIfNil returns an empty list for nil, which clears the values set
+// previously.
+func emptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// ttlOrZero returns "0" for an unset TTL, which resets it to the default of the
+// mount.
+func ttlOrZero(ttl string) string {
+	if ttl == "" {
+		return "0"
+	}
+	return ttl
+}
+
+func boolOrDefault(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PKIRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&pkiv1beta1.PKIRole{}).
+		Named("pki-pkirole").
+		Complete(r)
+}
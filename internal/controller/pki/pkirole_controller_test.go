@@ -0,0 +1,205 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pkiv1beta1 "hopopops/vault-operator/api/pki/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("PKIRole Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-role"
+		const mount = "pki"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *PKIRoleReconciler
+		var recorder *record.FakeRecorder
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		readRole := func() map[string]interface{} {
+			s, err := vaultServer.Client().Read(ctx, mount+"/roles/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			return s.Data
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "pki"})).To(Succeed())
+
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &PKIRoleReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Vault:    vaultServer.Client(),
+				Recorder: recorder,
+			}
+
+			By("creating the custom resource for the Kind PKIRole")
+			resource := &pkiv1beta1.PKIRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &pkiv1beta1.PKIRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: pkiv1beta1.PKIRoleSpec{
+						Mount:           mount,
+						AllowedDomains:  []string{"svc.cluster.local"},
+						AllowSubdomains: true,
+						KeyType:         "ec",
+						TTL:             "72h",
+						MaxTTL:          "720h",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &pkiv1beta1.PKIRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance PKIRole")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should write the role to Vault", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			role := readRole()
+			Expect(role["allowed_domains"]).To(ConsistOf("svc.cluster.local"))
+			Expect(role).To(HaveKeyWithValue("allow_subdomains", true))
+			Expect(role).To(HaveKeyWithValue("allow_localhost", true))
+			Expect(role).To(HaveKeyWithValue("key_type", "ec"))
+			Expect(role).To(HaveKeyWithValue("key_bits", json.Number("256")))
+			Expect(role).To(HaveKeyWithValue("ttl", json.Number("259200")))
+			Expect(role["key_usage"]).To(ConsistOf("DigitalSignature", "KeyAgreement", "KeyEncipherment"))
+
+			resource := &pkiv1beta1.PKIRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(pkiRoleFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredPKIRole)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created Created " + mount + "/roles/" + resourceName + " in Vault")))
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == mount+"/roles/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should update the role when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &pkiv1beta1.PKIRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			clientFlag := false
+			resource.Spec.ClientFlag = &clientFlag
+			resource.Spec.KeyUsage = []string{"DigitalSignature"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			role := readRole()
+			Expect(role).To(HaveKeyWithValue("client_flag", false))
+			Expect(role["key_usage"]).To(ConsistOf("DigitalSignature"))
+		})
+
+		It("should restore a role modified behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, err := vaultServer.Client().Write(ctx, mount+"/roles/"+resourceName, map[string]interface{}{"allow_any_name": true})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(Equal("Normal DriftCorrected Overwrote " + mount + "/roles/" + resourceName + ", changed in Vault outside of the resource")))
+			role := readRole()
+			Expect(role).To(HaveKeyWithValue("allow_any_name", false))
+			Expect(role["allowed_domains"]).To(ConsistOf("svc.cluster.local"))
+		})
+
+		It("should report Vault errors in the status", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodGet, Path: mount + "/roles/" + resourceName})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &pkiv1beta1.PKIRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredPKIRole)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the role from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &pkiv1beta1.PKIRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, mount+"/roles/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeNil())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleted " + mount + "/roles/" + resourceName + " from Vault")))
+		})
+	})
+})
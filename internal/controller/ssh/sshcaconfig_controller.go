@@ -0,0 +1,428 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sshv1beta1 "hopopops/vault-operator/api/ssh/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	sshCAConfigFinalizer = "sshcaconfig.ssh.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredSSHCAConfig = "Configured"
+)
+
+// SSHCAConfigReconciler reconciles a SSHCAConfig object
+type SSHCAConfigReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=ssh.toolkit.vault.hopopops.com,resources=sshcaconfigs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ssh.toolkit.vault.hopopops.com,resources=sshcaconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ssh.toolkit.vault.hopopops.com,resources=sshcaconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Vault generates the CA unless keySecretRef references a key pair to import.
+// Imported CAs are replaced whenever the public key in Vault differs from the
+// one of the Secret, while a CA generated by Vault is kept as long as it
+// exists. The public key is published to the target ConfigMap for hosts to
+// trust it.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *SSHCAConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the SSHCAConfig instance
+	config := &sshv1beta1.SSHCAConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("SSHCAConfig resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get SSHCAConfig")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, config, &config.Status.Conditions, r.Mode, sshCAConfigFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag SSHCAConfig in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// SSHCAConfig Deletion
+	isConfigMarkedToBeDeleted := config.GetDeletionTimestamp() != nil
+	if isConfigMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(config, sshCAConfigFinalizer) {
+			if config.Spec.DeletionPolicy == "Delete" {
+				if _, err := r.Vault.Delete(ctx, sshCAPath(config)); err != nil {
+					log.Error(err, "Failed to delete SSHCAConfig")
+					return ctrl.Result{}, err
+				}
+				events.Deleted(r.Recorder, config, sshCAPath(config))
+			}
+
+			if config.Spec.Target.DeletionPolicy == "Delete" {
+				if err := r.deleteK8sConfigMap(ctx, config); err != nil {
+					log.Error(err, "Failed to delete ConfigMap")
+					return ctrl.Result{}, err
+				}
+			} else {
+				if err := r.releaseK8sConfigMap(ctx, config); err != nil {
+					log.Error(err, "Failed to release ConfigMap")
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(config, sshCAConfigFinalizer)
+			if err := r.Update(ctx, config); err != nil {
+				log.Error(err, "Failed to remove finalizer from SSHCAConfig")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// SSHCAConfig Initialization
+	if !controllerutil.ContainsFinalizer(config, sshCAConfigFinalizer) {
+		controllerutil.AddFinalizer(config, sshCAConfigFinalizer)
+		vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredSSHCAConfig, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, config); err != nil {
+			log.Error(err, "Failed to initialize SSHCAConfig status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Fetch the key pair to import and the current CA
+	var privateKey, publicKey string
+	current, err := r.fetchVaultSSHCA(ctx, config)
+	if err == nil && config.Spec.KeySecretRef != nil {
+		privateKey, publicKey, err = r.fetchKeyPair(ctx, config)
+	}
+	if err != nil {
+		log.Error(err, "Failed to fetch SSHCAConfig")
+		vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredSSHCAConfig, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to fetch SSH CA: %s", err)})
+		events.Failed(r.Recorder, config, "FailedToFetch", "Failed to fetch SSH CA", sshCAPath(config), err)
+		if err := r.Status().Update(ctx, config); err != nil {
+			log.Error(err, "Failed to update SSHCAConfig status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create or replace
+	if current == nil || (publicKey != "" && !samePublicKey(current, publicKey)) {
+		existed := current != nil
+		if current, err = r.updateVaultSSHCA(ctx, config, existed, privateKey, publicKey); err != nil {
+			log.Error(err, "Failed to configure SSHCAConfig")
+			vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredSSHCAConfig, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to configure SSH CA in Vault"})
+			events.Failed(r.Recorder, config, "FailedToUpdate", "Failed to configure SSH CA in Vault", sshCAPath(config), err)
+			if err := r.Status().Update(ctx, config); err != nil {
+				log.Error(err, "Failed to update SSHCAConfig status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Configured SSH CA", "fingerprint", ssh.FingerprintSHA256(current))
+		events.Pushed(r.Recorder, config, config.Status.Conditions, existed, "SSHCAConfig", sshCAPath(config))
+	}
+
+	// Publish the public key
+	if err := r.applyK8sConfigMap(ctx, config, string(ssh.MarshalAuthorizedKey(current))); err != nil {
+		log.Error(err, "Failed to publish SSH CA public key")
+		vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredSSHCAConfig, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: fmt.Sprintf("Failed to publish SSH CA public key to k8s config map %s", config.Spec.Target.Name)})
+		events.Failed(r.Recorder, config, "FailedToCreate", fmt.Sprintf("Failed to publish SSH CA public key to k8s config map %s", config.Spec.Target.Name), "", err)
+		if err := r.Status().Update(ctx, config); err != nil {
+			log.Error(err, "Failed to update SSHCAConfig status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	config.Status.Fingerprint = ssh.FingerprintSHA256(current)
+	config.Status.ObservedGeneration = config.Generation
+	config.Status.VaultPath = sshCAPath(config)
+	vault.SetCondition(&config.Status.Conditions, config.Generation, metav1.Condition{Type: typeConfiguredSSHCAConfig, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully configured SSH CA"})
+	if err := r.Status().Update(ctx, config); err != nil {
+		log.Error(err, "Failed to update SSHCAConfig status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func sshCAPath(config *sshv1beta1.SSHCAConfig) string {
+	return fmt.Sprintf("%s/config/ca", config.Spec.Mount)
+}
+
+// fetchVaultSSHCA returns the public key of the CA, or nil when none is
+// configured, which Vault reports as a bad request.
+func (r *SSHCAConfigReconciler) fetchVaultSSHCA(ctx context.Context, config *sshv1beta1.SSHCAConfig) (ssh.PublicKey, error) {
+	s, err := r.Vault.Read(ctx, sshCAPath(config))
+	var responseError *vaultapi.ResponseError
+	if errors.As(err, &responseError) && responseError.StatusCode == http.StatusBadRequest {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	return parsePublicKey(fmt.Sprint(s.Data["public_key"]))
+}
+
+// fetchKeyPair returns the key pair referenced by keySecretRef.
+func (r *SSHCAConfigReconciler) fetchKeyPair(ctx context.Context, config *sshv1beta1.SSHCAConfig) (string, string, error) {
+	ref := config.Spec.KeySecretRef
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: config.Namespace}, secret); err != nil {
+		return "", "", fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+	}
+
+	privateKey, ok := secret.Data[ref.PrivateKeyKey]
+	if !ok {
+		return "", "", fmt.Errorf("key %s not found in secret %s", ref.PrivateKeyKey, ref.Name)
+	}
+	publicKey, ok := secret.Data[ref.PublicKeyKey]
+	if !ok {
+		return "", "", fmt.Errorf("key %s not found in secret %s", ref.PublicKeyKey, ref.Name)
+	}
+	return string(privateKey), string(publicKey), nil
+}
+
+// updateVaultSSHCA generates or imports the CA, deleting the existing one
+// first since Vault refuses to overwrite it.
+func (r *SSHCAConfigReconciler) updateVaultSSHCA(ctx context.Context, config *sshv1beta1.SSHCAConfig, exists bool, privateKey, publicKey string) (ssh.PublicKey, error) {
+	if exists {
+		if _, err := r.Vault.Delete(ctx, sshCAPath(config)); err != nil {
+			return nil, err
+		}
+	}
+
+	data := map[string]interface{}{
+		"generate_signing_key": true,
+		"key_type":             config.Spec.KeyType,
+	}
+	if config.Spec.KeyBits != 0 {
+		data["key_bits"] = config.Spec.KeyBits
+	}
+	if config.Spec.KeySecretRef != nil {
+		data = map[string]interface{}{
+			"generate_signing_key": false,
+			"private_key":          privateKey,
+			"public_key":           publicKey,
+		}
+	}
+
+	s, err := r.Vault.Write(ctx, sshCAPath(config), data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := metav1.Now()
+	config.Status.LastAppliedHash = vault.Checksum(vault.Redacted(data, "private_key"))
+	config.Status.LastAppliedTime = &now
+	if s == nil {
+		return r.fetchVaultSSHCA(ctx, config)
+	}
+	return parsePublicKey(fmt.Sprint(s.Data["public_key"]))
+}
+
+func parsePublicKey(publicKey string) (ssh.PublicKey, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return key, nil
+}
+
+// samePublicKey reports whether the key in authorized_keys format is key,
+// ignoring its comment.
+func samePublicKey(key ssh.PublicKey, publicKey string) bool {
+	other, err := parsePublicKey(publicKey)
+	// Let Vault report malformed keys
+	return err == nil && slices.Equal(key.Marshal(), other.Marshal())
+}
+
+func (r *SSHCAConfigReconciler) fetchK8sConfigMap(ctx context.Context, config *sshv1beta1.SSHCAConfig) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      config.Spec.Target.Name,
+		Namespace: config.Namespace,
+	}, configMap)
+
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config map: %w", err)
+	}
+	return configMap, nil
+}
+
+// applyK8sConfigMap creates the target ConfigMap or replaces its data.
+// ConfigMaps that are not owned by the resource are left untouched.
+func (r *SSHCAConfigReconciler) applyK8sConfigMap(ctx context.Context, config *sshv1beta1.SSHCAConfig, publicKey string) error {
+	log := logf.FromContext(ctx)
+
+	existing, err := r.fetchK8sConfigMap(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{config.Spec.Target.Key: publicKey}
+	if existing == nil {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.Spec.Target.Name,
+				Namespace: config.Namespace,
+			},
+			Data: data,
+		}
+
+		if err := controllerutil.SetControllerReference(config, configMap, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+
+		if err := r.Create(ctx, configMap); err != nil {
+			return err
+		}
+		log.Info("Created config map", "configMap", configMap.Name)
+		return nil
+	}
+
+	if !metav1.IsControlledBy(existing, config) {
+		return apierrors.NewAlreadyExists(corev1.Resource("configmaps"), existing.Name)
+	}
+
+	if len(existing.Data) == 1 && existing.Data[config.Spec.Target.Key] == publicKey {
+		return nil
+	}
+
+	existing.Data = data
+	if err := r.Update(ctx, existing); err != nil {
+		return err
+	}
+	log.Info("Updated config map", "configMap", existing.Name)
+	return nil
+}
+
+func (r *SSHCAConfigReconciler) deleteK8sConfigMap(ctx context.Context, config *sshv1beta1.SSHCAConfig) error {
+	configMap, err := r.fetchK8sConfigMap(ctx, config)
+	if err != nil || configMap == nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(configMap, config) {
+		ctrl.Log.Info("ConfigMap exists but CR is not owner, skipping deletion", "configMap", config.Spec.Target.Name)
+		return nil
+	}
+
+	if err := r.Delete(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to delete config map: %w", err)
+	}
+	return nil
+}
+
+// releaseK8sConfigMap removes the owner reference of the resource from the
+// target ConfigMap, so that it is not garbage collected along with the
+// resource.
+func (r *SSHCAConfigReconciler) releaseK8sConfigMap(ctx context.Context, config *sshv1beta1.SSHCAConfig) error {
+	configMap, err := r.fetchK8sConfigMap(ctx, config)
+	if err != nil || configMap == nil || !metav1.IsControlledBy(configMap, config) {
+		return err
+	}
+
+	configMap.OwnerReferences = slices.DeleteFunc(configMap.OwnerReferences, func(o metav1.OwnerReference) bool {
+		return o.UID == config.UID
+	})
+	return r.Update(ctx, configMap)
+}
+
+// findSSHCAConfigsForSecret returns the SSHCAConfigs importing the key pair of
+// a Secret.
+func (r *SSHCAConfigReconciler) findSSHCAConfigsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	configs := &sshv1beta1.SSHCAConfigList{}
+	if err := r.List(ctx, configs, client.InNamespace(secret.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list SSHCAConfigs")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, c := range configs.Items {
+		if c.Spec.KeySecretRef != nil && c.Spec.KeySecretRef.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: c.Name, Namespace: c.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SSHCAConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sshv1beta1.SSHCAConfig{}).
+		Owns(&corev1.ConfigMap{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.findSSHCAConfigsForSecret)).
+		Named("ssh-sshcaconfig").
+		Complete(r)
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sshv1beta1 "hopopops/vault-operator/api/ssh/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+var _ = Describe("SSHCAConfig Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-ca"
+		const targetName = "test-trusted-ca"
+		const keySecretName = "test-ca-key"
+		const mount = "ssh"
+		const key = "trusted-user-ca-keys.pem"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		targetNamespacedName := types.NamespacedName{
+			Name:      targetName,
+			Namespace: "default",
+		}
+		keySecretNamespacedName := types.NamespacedName{
+			Name:      keySecretName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *SSHCAConfigReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		get := func() *sshv1beta1.SSHCAConfig {
+			config := &sshv1beta1.SSHCAConfig{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, config)).To(Succeed())
+			return config
+		}
+
+		target := func() *corev1.ConfigMap {
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, targetNamespacedName, configMap)).To(Succeed())
+			return configMap
+		}
+
+		caPublicKey := func() string {
+			publicKey, ok := vaultServer.SSHCAPublicKey(mount)
+			Expect(ok).To(BeTrue())
+			return publicKey
+		}
+
+		// keyPair returns a new key pair in the format of kubernetes.io/ssh-auth
+		// Secrets.
+		keyPair := func() map[string][]byte {
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			block, err := ssh.MarshalPrivateKey(private, "")
+			Expect(err).NotTo(HaveOccurred())
+			publicKey, err := ssh.NewPublicKey(public)
+			Expect(err).NotTo(HaveOccurred())
+			return map[string][]byte{
+				"ssh-privatekey": pem.EncodeToMemory(block),
+				"ssh-publickey":  ssh.MarshalAuthorizedKey(publicKey),
+			}
+		}
+
+		importKeyPair := func(data map[string][]byte) {
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, keySecretNamespacedName, secret); err == nil {
+				secret.Data = data
+				Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			} else {
+				Expect(k8sClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: keySecretName, Namespace: "default"},
+					Data:       data,
+				})).To(Succeed())
+			}
+
+			config := get()
+			config.Spec.KeySecretRef = &sshv1beta1.SSHCAKeySecretRef{
+				Name:          keySecretName,
+				PrivateKeyKey: "ssh-privatekey",
+				PublicKeyKey:  "ssh-publickey",
+			}
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "ssh"})).To(Succeed())
+
+			controllerReconciler = &SSHCAConfigReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind SSHCAConfig")
+			resource := &sshv1beta1.SSHCAConfig{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &sshv1beta1.SSHCAConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sshv1beta1.SSHCAConfigSpec{
+						Mount:   mount,
+						KeyType: "ssh-ed25519",
+						Target: sshv1beta1.SSHCAConfigTarget{
+							Name:           targetName,
+							Key:            key,
+							DeletionPolicy: "Delete",
+						},
+						DeletionPolicy: "Delete",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sshv1beta1.SSHCAConfig{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance SSHCAConfig")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			for _, obj := range []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}} {
+				name := targetNamespacedName
+				if _, ok := obj.(*corev1.Secret); ok {
+					name = keySecretNamespacedName
+				}
+				if err := k8sClient.Get(ctx, name, obj); err == nil {
+					Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+				}
+			}
+		})
+
+		It("should generate the CA and publish its public key", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			publicKey := caPublicKey()
+			Expect(publicKey).To(HavePrefix("ssh-ed25519 "))
+
+			configMap := target()
+			Expect(strings.TrimSpace(configMap.Data[key])).To(Equal(publicKey))
+
+			config := get()
+			Expect(metav1.IsControlledBy(configMap, config)).To(BeTrue())
+			Expect(config.Finalizers).To(ContainElement(sshCAConfigFinalizer))
+			Expect(config.Status.Fingerprint).To(HavePrefix("SHA256:"))
+			Expect(meta.IsStatusConditionTrue(config.Status.Conditions, typeConfiguredSSHCAConfig)).To(BeTrue())
+		})
+
+		It("should keep the generated CA", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			publicKey := caPublicKey()
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(caPublicKey()).To(Equal(publicKey))
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == mount+"/config/ca" {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should import the CA and replace it when the Secret changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			first := keyPair()
+			importKeyPair(first)
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(caPublicKey()).To(Equal(strings.TrimSpace(string(first["ssh-publickey"]))))
+			Expect(target().Data[key]).To(Equal(string(first["ssh-publickey"])))
+			Expect(get().Status.LastAppliedHash).To(Equal(vault.Checksum(map[string]interface{}{
+				"generate_signing_key": false,
+				"public_key":           string(first["ssh-publickey"]),
+			})))
+
+			second := keyPair()
+			importKeyPair(second)
+			Expect(controllerReconciler.findSSHCAConfigsForSecret(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: keySecretName, Namespace: "default"}})).
+				To(ConsistOf(reconcile.Request{NamespacedName: typeNamespacedName}))
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(caPublicKey()).To(Equal(strings.TrimSpace(string(second["ssh-publickey"]))))
+			Expect(target().Data[key]).To(Equal(string(second["ssh-publickey"])))
+		})
+
+		It("should report a missing key in the Secret", func() {
+			data := keyPair()
+			delete(data, "ssh-privatekey")
+			importKeyPair(data)
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredSSHCAConfig)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should not take over a ConfigMap it does not own", func() {
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: targetName, Namespace: "default"},
+				Data:       map[string]string{"foreign": "data"},
+			})).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(target().Data).To(HaveKey("foreign"))
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredSSHCAConfig)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should delete the CA and the ConfigMap on deletion", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, get())).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.SSHCAPublicKey(mount)
+			Expect(ok).To(BeFalse())
+			err := k8sClient.Get(ctx, targetNamespacedName, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should keep the CA and release the ConfigMap with the Retain policies", func() {
+			config := get()
+			config.Spec.DeletionPolicy = "Retain"
+			config.Spec.Target.DeletionPolicy = "Retain"
+			Expect(k8sClient.Update(ctx, config)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, get())).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			caPublicKey()
+			Expect(target().OwnerReferences).To(BeEmpty())
+		})
+	})
+})
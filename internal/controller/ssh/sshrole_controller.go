@@ -0,0 +1,253 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sshv1beta1 "hopopops/vault-operator/api/ssh/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	sshRoleFinalizer = "sshrole.ssh.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredSSHRole = "Configured"
+)
+
+// SSHRoleReconciler reconciles a SSHRole object
+type SSHRoleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=ssh.toolkit.vault.hopopops.com,resources=sshroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ssh.toolkit.vault.hopopops.com,resources=sshroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ssh.toolkit.vault.hopopops.com,resources=sshroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *SSHRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the SSHRole instance
+	role := &sshv1beta1.SSHRole{}
+	if err := r.Get(ctx, req.NamespacedName, role); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("SSHRole resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get SSHRole")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, role, &role.Status.Conditions, r.Mode, sshRoleFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag SSHRole in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// SSHRole Deletion
+	isRoleMarkedToBeDeleted := role.GetDeletionTimestamp() != nil
+	if isRoleMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(role, sshRoleFinalizer) {
+			if err := r.deleteVaultSSHRole(ctx, role); err != nil {
+				log.Error(err, "Failed to delete SSHRole")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, role, sshRolePath(role))
+
+			controllerutil.RemoveFinalizer(role, sshRoleFinalizer)
+			if err := r.Update(ctx, role); err != nil {
+				log.Error(err, "Failed to remove finalizer from SSHRole")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// SSHRole Initialization
+	if !controllerutil.ContainsFinalizer(role, sshRoleFinalizer) {
+		controllerutil.AddFinalizer(role, sshRoleFinalizer)
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredSSHRole, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, role); err != nil {
+			log.Error(err, "Failed to initialize SSHRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultSSHRole(ctx, role)
+	if err != nil {
+		log.Error(err, "Failed to fetch SSHRole")
+		vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredSSHRole, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch SSH role from Vault"})
+		events.Failed(r.Recorder, role, "FailedToFetch", "Failed to fetch SSH role from Vault", sshRolePath(role), err)
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update SSHRole status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	status := role.Status.DeepCopy()
+	message := "SSH role in Vault matches the spec"
+	if current == nil || current.IsDifferentFromSpec(&role.Spec) {
+		if err := r.updateVaultSSHRole(ctx, role); err != nil {
+			log.Error(err, "Failed to update SSHRole")
+			vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredSSHRole, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push SSH role to Vault"})
+			events.Failed(r.Recorder, role, "FailedToUpdate", "Failed to push SSH role to Vault", sshRolePath(role), err)
+			if err := r.Status().Update(ctx, role); err != nil {
+				log.Error(err, "Failed to update SSHRole status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, role, role.Status.Conditions, current != nil, "SSHRole", sshRolePath(role))
+		message = "Successfully pushed SSH role to Vault"
+	}
+
+	role.Status.ObservedGeneration = role.Generation
+	role.Status.VaultPath = sshRolePath(role)
+	vault.SetCondition(&role.Status.Conditions, role.Generation, metav1.Condition{Type: typeConfiguredSSHRole, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &role.Status) {
+		if err := r.Status().Update(ctx, role); err != nil {
+			log.Error(err, "Failed to update SSHRole status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func sshRolePath(role *sshv1beta1.SSHRole) string {
+	return fmt.Sprintf("%s/roles/%s", role.Spec.Mount, role.Name)
+}
+
+func (r *SSHRoleReconciler) deleteVaultSSHRole(ctx context.Context, role *sshv1beta1.SSHRole) error {
+	_, err := r.Vault.Delete(ctx, sshRolePath(role))
+	return err
+}
+
+func (r *SSHRoleReconciler) fetchVaultSSHRole(ctx context.Context, role *sshv1beta1.SSHRole) (*vault.SSHRole, error) {
+	s, err := r.Vault.Read(ctx, sshRolePath(role))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var sr vault.SSHRole
+	if err := json.Unmarshal(jsonBytes, &sr); err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+func (r *SSHRoleReconciler) updateVaultSSHRole(ctx context.Context, role *sshv1beta1.SSHRole) error {
+	data := map[string]interface{}{
+		"key_type":                 "ca",
+		"allow_user_certificates":  role.Spec.AllowUserCertificates,
+		"allow_host_certificates":  role.Spec.AllowHostCertificates,
+		"allowed_users":            strings.Join(role.Spec.AllowedUsers, ","),
+		"allowed_users_template":   role.Spec.AllowedUsersTemplate,
+		"default_user":             role.Spec.DefaultUser,
+		"allowed_domains":          strings.Join(role.Spec.AllowedDomains, ","),
+		"allow_subdomains":         role.Spec.AllowSubdomains,
+		"allow_bare_domains":       role.Spec.AllowBareDomains,
+		"allowed_extensions":       strings.Join(role.Spec.AllowedExtensions, ","),
+		"default_extensions":       emptyIfNil(role.Spec.DefaultExtensions),
+		"allowed_critical_options": strings.Join(role.Spec.AllowedCriticalOptions, ","),
+		"default_critical_options": emptyIfNil(role.Spec.DefaultCriticalOptions),
+		"ttl":                      ttlOrZero(role.Spec.TTL),
+		"max_ttl":                  ttlOrZero(role.Spec.MaxTTL),
+		"algorithm_signer":         role.Spec.AlgorithmSigner,
+	}
+	if _, err := r.Vault.Write(ctx, sshRolePath(role), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	role.Status.LastAppliedHash = vault.Checksum(data)
+	role.Status.LastAppliedTime = &now
+	return nil
+}
+
+// emptyIfNil returns an empty map for nil, which clears the values set
+// previously.
+func emptyIfNil(values map[string]string) map[string]string {
+	if values == nil {
+		return map[string]string{}
+	}
+	return values
+}
+
+// ttlOrZero returns "0" for an unset TTL, which resets it to the default of the
+// mount.
+func ttlOrZero(ttl string) string {
+	if ttl == "" {
+		return "0"
+	}
+	return ttl
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SSHRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sshv1beta1.SSHRole{}).
+		Named("ssh-sshrole").
+		Complete(r)
+}
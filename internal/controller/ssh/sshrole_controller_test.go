@@ -0,0 +1,209 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sshv1beta1 "hopopops/vault-operator/api/ssh/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("SSHRole Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-role"
+		const mount = "ssh"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *SSHRoleReconciler
+		var recorder *record.FakeRecorder
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		readRole := func() map[string]interface{} {
+			s, err := vaultServer.Client().Read(ctx, mount+"/roles/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).NotTo(BeNil())
+			return s.Data
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "ssh"})).To(Succeed())
+
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &SSHRoleReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Vault:    vaultServer.Client(),
+				Recorder: recorder,
+			}
+
+			By("creating the custom resource for the Kind SSHRole")
+			resource := &sshv1beta1.SSHRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &sshv1beta1.SSHRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sshv1beta1.SSHRoleSpec{
+						Mount:                 mount,
+						AllowUserCertificates: true,
+						AllowedUsers:          []string{"ubuntu", "ops"},
+						DefaultUser:           "ubuntu",
+						AllowedExtensions:     []string{"permit-pty", "permit-port-forwarding"},
+						DefaultExtensions:     map[string]string{"permit-pty": ""},
+						TTL:                   "30m",
+						MaxTTL:                "8h",
+						AlgorithmSigner:       "rsa-sha2-256",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sshv1beta1.SSHRole{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance SSHRole")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should write the role to Vault", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			role := readRole()
+			Expect(role).To(HaveKeyWithValue("key_type", "ca"))
+			Expect(role).To(HaveKeyWithValue("allowed_users", "ubuntu,ops"))
+			Expect(role).To(HaveKeyWithValue("default_user", "ubuntu"))
+			Expect(role).To(HaveKeyWithValue("allowed_extensions", "permit-pty,permit-port-forwarding"))
+			Expect(role["default_extensions"]).To(HaveKeyWithValue("permit-pty", ""))
+			Expect(role).To(HaveKeyWithValue("ttl", json.Number("1800")))
+			Expect(role).To(HaveKeyWithValue("max_ttl", json.Number("28800")))
+
+			resource := &sshv1beta1.SSHRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(sshRoleFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredSSHRole)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created Created " + mount + "/roles/" + resourceName + " in Vault")))
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == mount+"/roles/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should update the role when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &sshv1beta1.SSHRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.AllowedCriticalOptions = []string{"force-command"}
+			resource.Spec.DefaultCriticalOptions = map[string]string{"force-command": "/usr/bin/true"}
+			resource.Spec.DefaultExtensions = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			role := readRole()
+			Expect(role).To(HaveKeyWithValue("allowed_critical_options", "force-command"))
+			Expect(role["default_critical_options"]).To(HaveKeyWithValue("force-command", "/usr/bin/true"))
+			Expect(role["default_extensions"]).To(BeEmpty())
+		})
+
+		It("should restore a role modified behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, err := vaultServer.Client().Write(ctx, mount+"/roles/"+resourceName, map[string]interface{}{
+				"key_type":                "ca",
+				"allow_user_certificates": true,
+				"allowed_users":           "*",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(readRole()).To(HaveKeyWithValue("allowed_users", "ubuntu,ops"))
+		})
+
+		It("should report Vault errors in the status", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodGet, Path: mount + "/roles/" + resourceName})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &sshv1beta1.SSHRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredSSHRole)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the role from Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &sshv1beta1.SSHRole{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			s, err := vaultServer.Client().Read(ctx, mount+"/roles/"+resourceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(BeNil())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleted " + mount + "/roles/" + resourceName + " from Vault")))
+		})
+	})
+})
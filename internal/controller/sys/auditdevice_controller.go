@@ -0,0 +1,336 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	auditDeviceFinalizer = "auditdevice.sys.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredAuditDevice = "Configured"
+)
+
+// AuditDeviceReconciler reconciles a AuditDevice object
+type AuditDeviceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auditdevices,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auditdevices/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auditdevices/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *AuditDeviceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the AuditDevice instance
+	device := &sysv1beta1.AuditDevice{}
+	if err := r.Get(ctx, req.NamespacedName, device); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("AuditDevice resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get AuditDevice")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, device, &device.Status.Conditions, r.Mode, auditDeviceFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag AuditDevice in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// AuditDevice Deletion
+	isDeviceMarkedToBeDeleted := device.GetDeletionTimestamp() != nil
+	if isDeviceMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(device, auditDeviceFinalizer) {
+			for _, p := range []string{device.Status.ReplacedPath, device.Status.Path} {
+				if p == "" {
+					continue
+				}
+				if err := r.Vault.DisableAudit(ctx, p); err != nil {
+					log.Error(err, "Failed to delete AuditDevice", "path", p)
+					return ctrl.Result{}, err
+				}
+				events.Deleted(r.Recorder, device, "sys/audit/"+p)
+			}
+
+			controllerutil.RemoveFinalizer(device, auditDeviceFinalizer)
+			if err := r.Update(ctx, device); err != nil {
+				log.Error(err, "Failed to remove finalizer from AuditDevice")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// AuditDevice Initialization
+	if !controllerutil.ContainsFinalizer(device, auditDeviceFinalizer) {
+		controllerutil.AddFinalizer(device, auditDeviceFinalizer)
+		vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, device); err != nil {
+			log.Error(err, "Failed to initialize AuditDevice status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	devices, err := r.Vault.ListAudit(ctx)
+	if err != nil {
+		log.Error(err, "Failed to fetch AuditDevice")
+		vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch audit devices from Vault"})
+		events.Failed(r.Recorder, device, "FailedToFetch", "Failed to fetch audit devices from Vault", "sys/audit", err)
+		if err := r.Status().Update(ctx, device); err != nil {
+			log.Error(err, "Failed to update AuditDevice status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create, or re-enable a device disabled behind the operator's back
+	current, ok := devices[device.Status.Path+"/"]
+	if device.Status.Path == "" || !ok {
+		p := device.Status.Path
+		if p == "" {
+			p = auditDevicePath(device)
+		}
+
+		// Adopt the audit device of the same type already enabled, replaced
+		// on the next reconciliation if it differs from the spec
+		existing, ok := devices[p+"/"]
+		if ok && (!vault.Adopts(device.Annotations) || existing.Type != device.Spec.Type) {
+			err := fmt.Errorf("%s audit device already enabled at %s", existing.Type, p)
+			log.Error(err, "Failed to create AuditDevice")
+			message := fmt.Sprintf("A %s audit device is already enabled, adopted only when of the same type and annotated with %s", existing.Type, vault.AdoptAnnotation)
+			vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionFalse, Reason: "AlreadyExists", Message: message})
+			events.Conflicted(r.Recorder, device, "AlreadyExists", message, "sys/audit/"+p)
+			if err := r.Status().Update(ctx, device); err != nil {
+				log.Error(err, "Failed to update AuditDevice status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		if !ok {
+			if err := r.enableVaultAuditDevice(ctx, device, p); err != nil {
+				log.Error(err, "Failed to create AuditDevice")
+				vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: "Failed to enable audit device in Vault"})
+				events.Failed(r.Recorder, device, "FailedToCreate", "Failed to enable audit device in Vault", "sys/audit/"+p, err)
+				if err := r.Status().Update(ctx, device); err != nil {
+					log.Error(err, "Failed to update AuditDevice status")
+					return ctrl.Result{}, err
+				}
+
+				return ctrl.Result{}, err
+			}
+
+			// A device disabled behind the operator's back keeps its path
+			events.Pushed(r.Recorder, device, device.Status.Conditions, device.Status.Path != "", "AuditDevice", "sys/audit/"+p)
+		}
+
+		device.Status.Path = p
+		device.Status.ObservedGeneration = device.Generation
+		device.Status.VaultPath = "sys/audit/" + p
+		vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully enabled audit device in Vault"})
+		if err := r.Status().Update(ctx, device); err != nil {
+			log.Error(err, "Failed to update AuditDevice status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	status := device.Status.DeepCopy()
+	message := "Audit device in Vault matches the spec"
+
+	// Disable the device replaced once its replacement is enabled
+	if device.Status.ReplacedPath != "" {
+		if err := r.disableReplacedAuditDevice(ctx, device, devices); err != nil {
+			log.Error(err, "Failed to disable replaced AuditDevice", "path", device.Status.ReplacedPath)
+			vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to disable replaced audit device in Vault"})
+			events.Failed(r.Recorder, device, "FailedToUpdate", "Failed to disable replaced audit device in Vault", "sys/audit/"+device.Status.ReplacedPath, err)
+			if err := r.Status().Update(ctx, device); err != nil {
+				log.Error(err, "Failed to update AuditDevice status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		message = "Successfully replaced audit device in Vault"
+	}
+
+	// Replace, enabling the new device before disabling the old one. The path
+	// of the new device is saved first, so that a failure to save the status
+	// never leaves a device enabled that the resource lost track of. A device
+	// already enabled at that path is therefore never the resource's own.
+	if (*vault.AuditDevice)(current).IsDifferentFromSpec(&device.Spec) {
+		next := replacementAuditDevicePath(device)
+		if existing, ok := devices[next+"/"]; ok {
+			err := fmt.Errorf("%s audit device already enabled at %s", existing.Type, next)
+			log.Error(err, "Failed to enable replacement AuditDevice", "path", next)
+			message := fmt.Sprintf("A %s audit device the resource does not own is already enabled at the path of the replacement", existing.Type)
+			vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionFalse, Reason: "AlreadyExists", Message: message})
+			events.Conflicted(r.Recorder, device, "AlreadyExists", message, "sys/audit/"+next)
+			if err := r.Status().Update(ctx, device); err != nil {
+				log.Error(err, "Failed to update AuditDevice status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		device.Status.ReplacedPath = device.Status.Path
+		device.Status.Path = next
+		device.Status.VaultPath = "sys/audit/" + next
+		if err := r.Status().Update(ctx, device); err != nil {
+			log.Error(err, "Failed to update AuditDevice status")
+			return ctrl.Result{}, err
+		}
+
+		if err := r.enableVaultAuditDevice(ctx, device, next); err != nil {
+			log.Error(err, "Failed to enable replacement AuditDevice", "path", next)
+			vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to enable replacement audit device in Vault"})
+			events.Failed(r.Recorder, device, "FailedToUpdate", "Failed to enable replacement audit device in Vault", "sys/audit/"+next, err)
+			if err := r.Status().Update(ctx, device); err != nil {
+				log.Error(err, "Failed to update AuditDevice status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		events.Pushed(r.Recorder, device, device.Status.Conditions, true, "AuditDevice", "sys/audit/"+next)
+
+		if err := r.disableReplacedAuditDevice(ctx, device, devices); err != nil {
+			log.Error(err, "Failed to disable replaced AuditDevice", "path", device.Status.ReplacedPath)
+			vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to disable replaced audit device in Vault"})
+			events.Failed(r.Recorder, device, "FailedToUpdate", "Failed to disable replaced audit device in Vault", "sys/audit/"+device.Status.ReplacedPath, err)
+			if err := r.Status().Update(ctx, device); err != nil {
+				log.Error(err, "Failed to update AuditDevice status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		message = "Successfully replaced audit device in Vault"
+	}
+
+	device.Status.ObservedGeneration = device.Generation
+	device.Status.VaultPath = "sys/audit/" + device.Status.Path
+	vault.SetCondition(&device.Status.Conditions, device.Generation, metav1.Condition{Type: typeConfiguredAuditDevice, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &device.Status) {
+		if err := r.Status().Update(ctx, device); err != nil {
+			log.Error(err, "Failed to update AuditDevice status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// auditDevicePath returns the path the audit device is first enabled at.
+func auditDevicePath(device *sysv1beta1.AuditDevice) string {
+	if device.Spec.Path != "" {
+		return device.Spec.Path
+	}
+	return device.Name
+}
+
+// replacementAuditDevicePath returns the path a replacement for the current
+// audit device is enabled at, which must differ from the current one since
+// both are enabled at the same time.
+func replacementAuditDevicePath(device *sysv1beta1.AuditDevice) string {
+	p := fmt.Sprintf("%s-%d", auditDevicePath(device), device.Generation)
+	if p == device.Status.Path {
+		return auditDevicePath(device)
+	}
+	return p
+}
+
+// disableReplacedAuditDevice disables the device replaced, unless it was
+// disabled already, and forgets it.
+func (r *AuditDeviceReconciler) disableReplacedAuditDevice(ctx context.Context, device *sysv1beta1.AuditDevice, devices map[string]*vaultapi.Audit) error {
+	if _, ok := devices[device.Status.ReplacedPath+"/"]; ok {
+		if err := r.Vault.DisableAudit(ctx, device.Status.ReplacedPath); err != nil {
+			return err
+		}
+	}
+
+	device.Status.ReplacedPath = ""
+	return nil
+}
+
+func (r *AuditDeviceReconciler) enableVaultAuditDevice(ctx context.Context, device *sysv1beta1.AuditDevice, path string) error {
+	options := &vaultapi.EnableAuditOptions{
+		Type:        device.Spec.Type,
+		Description: device.Spec.Description,
+		Options:     device.Spec.Options,
+		Local:       device.Spec.Local,
+	}
+	if err := r.Vault.EnableAudit(ctx, path, options); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	device.Status.LastAppliedHash = vault.Checksum(options)
+	device.Status.LastAppliedTime = &now
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AuditDeviceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sysv1beta1.AuditDevice{}).
+		Named("sys-auditdevice").
+		Complete(r)
+}
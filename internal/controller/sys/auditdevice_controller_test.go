@@ -0,0 +1,329 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("AuditDevice Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-audit"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *AuditDeviceReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			controllerReconciler = &AuditDeviceReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind AuditDevice")
+			device := &sysv1beta1.AuditDevice{}
+			err := k8sClient.Get(ctx, typeNamespacedName, device)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &sysv1beta1.AuditDevice{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sysv1beta1.AuditDeviceSpec{
+						Type:    "file",
+						Options: map[string]string{"file_path": "stdout"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sysv1beta1.AuditDevice{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance AuditDevice")
+			vaultServer.ClearFaults()
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+		})
+
+		It("should enable the audit device in Vault", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			audit, ok := vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(audit.Type).To(Equal("file"))
+			Expect(audit.Options).To(HaveKeyWithValue("file_path", "stdout"))
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Finalizers).To(ContainElement(auditDeviceFinalizer))
+			Expect(device.Status.Path).To(Equal(resourceName))
+			Expect(meta.IsStatusConditionTrue(device.Status.Conditions, typeConfiguredAuditDevice)).To(BeTrue())
+		})
+
+		It("should not enable the audit device twice", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			enabled := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == "sys/audit/"+resourceName {
+					enabled++
+				}
+			}
+			Expect(enabled).To(Equal(1))
+		})
+
+		It("should enable the replacement before disabling the old device when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Changing the options of the audit device")
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			device.Spec.Options["file_path"] = "/vault/audit/audit.log"
+			Expect(k8sClient.Update(ctx, device)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Status.Path).NotTo(Equal(resourceName))
+			audit, ok := vaultServer.AuditDevice(device.Status.Path)
+			Expect(ok).To(BeTrue())
+			Expect(audit.Options).To(HaveKeyWithValue("file_path", "/vault/audit/audit.log"))
+			_, ok = vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeFalse())
+
+			By("Checking that audit logging never stopped")
+			var calls []fake.Request
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut || r.Method == http.MethodDelete {
+					calls = append(calls, r)
+				}
+			}
+			Expect(calls).To(Equal([]fake.Request{
+				{Method: http.MethodPut, Path: "sys/audit/" + resourceName},
+				{Method: http.MethodPut, Path: "sys/audit/" + device.Status.Path},
+				{Method: http.MethodDelete, Path: "sys/audit/" + resourceName},
+			}))
+		})
+
+		It("should keep the old device when the replacement cannot be enabled", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			device.Spec.Description = "changed"
+			Expect(k8sClient.Update(ctx, device)).To(Succeed())
+
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPut, Path: "sys/audit", Status: http.StatusBadRequest})
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			_, ok := vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Status.ReplacedPath).To(Equal(resourceName))
+			condition := meta.FindStatusCondition(device.Status.Conditions, typeConfiguredAuditDevice)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+
+		It("should keep the old device when a device it does not own is enabled at the path of the replacement", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			device.Spec.Description = "changed"
+			Expect(k8sClient.Update(ctx, device)).To(Succeed())
+
+			next := fmt.Sprintf("%s-%d", resourceName, device.Generation)
+			Expect(vaultServer.Client().EnableAudit(ctx, next, &vaultapi.EnableAuditOptions{Type: "file", Description: "foreign", Options: map[string]string{"file_path": "stdout"}})).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			_, ok := vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeTrue())
+			audit, ok := vaultServer.AuditDevice(next)
+			Expect(ok).To(BeTrue())
+			Expect(audit.Description).To(Equal("foreign"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Status.Path).To(Equal(resourceName))
+			Expect(device.Status.ReplacedPath).To(BeEmpty())
+			condition := meta.FindStatusCondition(device.Status.Conditions, typeConfiguredAuditDevice)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("AlreadyExists"))
+		})
+
+		It("should leave alone the audit device already enabled when not annotated", func() {
+			Expect(vaultServer.Client().EnableAudit(ctx, resourceName, &vaultapi.EnableAuditOptions{Type: "file", Description: "foreign", Options: map[string]string{"file_path": "stdout"}})).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Status.Path).To(BeEmpty())
+			condition := meta.FindStatusCondition(device.Status.Conditions, typeConfiguredAuditDevice)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("AlreadyExists"))
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, device)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+			audit, ok := vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(audit.Description).To(Equal("foreign"))
+		})
+
+		It("should disable the replaced device once it failed to", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			device.Spec.Description = "changed"
+			Expect(k8sClient.Update(ctx, device)).To(Succeed())
+
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodDelete, Path: "sys/audit/" + resourceName, Status: http.StatusInternalServerError})
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Status.ReplacedPath).To(Equal(resourceName))
+			_, ok := vaultServer.AuditDevice(device.Status.Path)
+			Expect(ok).To(BeTrue())
+
+			vaultServer.ClearFaults()
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Status.ReplacedPath).To(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(device.Status.Conditions, typeConfiguredAuditDevice)).To(BeTrue())
+			_, ok = vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeFalse())
+			_, ok = vaultServer.AuditDevice(device.Status.Path)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should disable the replaced device along with the current one when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			device.Spec.Description = "changed"
+			Expect(k8sClient.Update(ctx, device)).To(Succeed())
+
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodDelete, Path: "sys/audit/" + resourceName, Status: http.StatusInternalServerError, Times: 1})
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			next := device.Status.Path
+			Expect(k8sClient.Delete(ctx, device)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeFalse())
+			_, ok = vaultServer.AuditDevice(next)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should re-enable an audit device disabled behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(vaultServer.Client().DisableAudit(ctx, resourceName)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			_, ok := vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should disable the audit device when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, device)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.AuditDevice(resourceName)
+			Expect(ok).To(BeFalse())
+			err := k8sClient.Get(ctx, typeNamespacedName, device)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should adopt the audit device already enabled when annotated", func() {
+			Expect(vaultServer.Client().EnableAudit(ctx, resourceName, &vaultapi.EnableAuditOptions{Type: "file", Options: map[string]string{"file_path": "stdout"}})).To(Succeed())
+
+			resource := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{vault.AdoptAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			enabled := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == "sys/audit/"+resourceName {
+					enabled++
+				}
+			}
+			Expect(enabled).To(Equal(1))
+
+			device := &sysv1beta1.AuditDevice{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, device)).To(Succeed())
+			Expect(device.Status.Path).To(Equal(resourceName))
+			Expect(meta.IsStatusConditionTrue(device.Status.Conditions, typeConfiguredAuditDevice)).To(BeTrue())
+		})
+	})
+})
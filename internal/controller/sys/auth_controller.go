@@ -18,12 +18,15 @@ package sys
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -32,6 +35,9 @@ import (
 	vaultapi "github.com/hashicorp/vault/api"
 
 	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+	"hopopops/vault-operator/internal/plan"
 )
 
 const (
@@ -41,18 +47,22 @@ const (
 // Definitions to manage status conditions
 const (
 	typeConfiguredAuth = "Configured"
+	typeInSyncAuth     = "InSync"
 )
 
 // AuthReconciler reconciles a Auth object
 type AuthReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	Vault  *vaultapi.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
 }
 
 // +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auths,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auths/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auths/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -61,6 +71,13 @@ type AuthReconciler struct {
 // perform operations to make the cluster state reflect the state specified by
 // the user.
 //
+// In observe mode, the auth method is only compared with the spec and the
+// finalizer leaves Vault untouched.
+//
+// A paused resource is not reconciled, and its finalizer leaves Vault
+// untouched. A new reconcile-at annotation enables the auth method again when
+// it has been disabled in Vault.
+//
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
 func (r *AuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -77,13 +94,26 @@ func (r *AuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, err
 	}
 
+	observe := vault.ResourceMode(r.Mode, auth.Annotations) == vault.ModeObserve
+	paused := vault.Paused(auth.Annotations)
+
+	if vault.SetPausedCondition(&auth.Status.Conditions, auth.Generation, paused) {
+		if err := r.Status().Update(ctx, auth); err != nil {
+			log.Error(err, "Failed to update Auth status")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Auth Deletion
 	isAuthMarkedToBeDeleted := auth.GetDeletionTimestamp() != nil
 	if isAuthMarkedToBeDeleted {
 		if controllerutil.ContainsFinalizer(auth, authFinalizer) {
-			if err := r.deleteVaultAuth(ctx, auth); err != nil {
-				log.Error(err, "Failed to delete Auth")
-				return ctrl.Result{}, err
+			if !observe && !paused {
+				if err := r.deleteVaultAuth(ctx, auth); err != nil {
+					log.Error(err, "Failed to delete Auth")
+					return ctrl.Result{}, err
+				}
+				events.Deleted(r.Recorder, auth, authPath(auth))
 			}
 
 			controllerutil.RemoveFinalizer(auth, authFinalizer)
@@ -95,30 +125,69 @@ func (r *AuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, nil
 	}
 
+	if paused {
+		log.Info("Auth reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
 	// Auth Initialization
-	if !controllerutil.ContainsFinalizer(auth, authFinalizer) {
+	if !controllerutil.ContainsFinalizer(auth, authFinalizer) && !observe {
 		controllerutil.AddFinalizer(auth, authFinalizer)
-		meta.SetStatusCondition(&auth.Status.Conditions, metav1.Condition{Type: typeConfiguredAuth, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		vault.SetCondition(&auth.Status.Conditions, auth.Generation, metav1.Condition{Type: typeConfiguredAuth, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
 		if err := r.Update(ctx, auth); err != nil {
 			log.Error(err, "Failed to initialize Auth status")
 			return ctrl.Result{}, err
 		}
 	}
 
-	// Create, do not allow update
+	if observe {
+		return r.observeVaultAuth(ctx, auth)
+	}
+
+	status := auth.Status.DeepCopy()
+
+	// A reconcile request checks the auth method is still enabled in Vault
+	requestedAt, forced := vault.ReconcileRequested(auth.Annotations, auth.Status.ReconcileRequest)
+	if forced && auth.Status.Accessor != "" {
+		m, err := r.fetchVaultAuth(ctx, auth)
+		if err != nil {
+			log.Error(err, "Failed to fetch Auth")
+			return ctrl.Result{}, err
+		}
+
+		if m == nil {
+			log.Info("Auth is not enabled in Vault anymore, enabling it again")
+			auth.Status.Accessor = ""
+		} else {
+			auth.Status.Accessor = m.Accessor
+		}
+	}
+
+	// Create, or adopt the auth method already enabled, do not allow update
+	message := "Auth engine is enabled in Vault"
 	if auth.Status.Accessor == "" {
-		if err := r.createVaultAuth(ctx, auth); err != nil {
-			log.Error(err, "Failed to create Auth")
-			meta.SetStatusCondition(&auth.Status.Conditions, metav1.Condition{Type: typeConfiguredAuth, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: "Failed to create auth engine in Vault"})
-			if err := r.Status().Update(ctx, auth); err != nil {
-				log.Error(err, "Failed to update Auth status")
+		adopted, err := r.adoptVaultAuth(ctx, auth)
+		if err != nil {
+			log.Error(err, "Failed to fetch Auth")
+			return ctrl.Result{}, err
+		}
+
+		if !adopted {
+			if err := r.createVaultAuth(ctx, auth); err != nil {
+				log.Error(err, "Failed to create Auth")
+				vault.SetCondition(&auth.Status.Conditions, auth.Generation, metav1.Condition{Type: typeConfiguredAuth, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: "Failed to create auth engine in Vault"})
+				events.Failed(r.Recorder, auth, "FailedToCreate", "Failed to create auth engine in Vault", authPath(auth), err)
+				if err := r.Status().Update(ctx, auth); err != nil {
+					log.Error(err, "Failed to update Auth status")
+					return ctrl.Result{}, err
+				}
+
 				return ctrl.Result{}, err
 			}
-
-			return ctrl.Result{}, err
+			events.Pushed(r.Recorder, auth, auth.Status.Conditions, false, "Auth", authPath(auth))
 		}
 
-		ae, err := r.Vault.Sys().GetAuthWithContext(ctx, auth.Name)
+		ae, err := r.Vault.GetAuth(ctx, auth.Name)
 		if err != nil {
 			log.Error(err, "Failed to get auth engine from Vault")
 			return ctrl.Result{}, err
@@ -126,7 +195,16 @@ func (r *AuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 		// Set accessor for reference
 		auth.Status.Accessor = ae.Accessor
-		meta.SetStatusCondition(&auth.Status.Conditions, metav1.Condition{Type: typeConfiguredAuth, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully created auth engine in Vault"})
+		message = "Successfully created auth engine in Vault"
+	}
+
+	// The generation is applied once the auth method is enabled, as it is never
+	// updated
+	auth.Status.ObservedGeneration = auth.Generation
+	auth.Status.VaultPath = authPath(auth)
+	auth.Status.ReconcileRequest = requestedAt
+	vault.SetCondition(&auth.Status.Conditions, auth.Generation, metav1.Condition{Type: typeConfiguredAuth, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &auth.Status) {
 		if err := r.Status().Update(ctx, auth); err != nil {
 			log.Error(err, "Failed to update Auth status")
 			return ctrl.Result{}, err
@@ -136,15 +214,135 @@ func (r *AuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	return ctrl.Result{}, nil
 }
 
+// observeVaultAuth reports whether the auth method enabled in Vault matches
+// the spec.
+func (r *AuthReconciler) observeVaultAuth(ctx context.Context, auth *sysv1beta1.Auth) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	m, err := r.fetchVaultAuth(ctx, auth)
+	if err != nil {
+		log.Error(err, "Failed to fetch Auth")
+		vault.SetCondition(&auth.Status.Conditions, auth.Generation, metav1.Condition{Type: typeInSyncAuth, Status: metav1.ConditionUnknown, Reason: "FailedToFetch", Message: "Failed to fetch auth engines from Vault"})
+		events.Failed(r.Recorder, auth, "FailedToFetch", "Failed to fetch auth engines from Vault", "sys/auth", err)
+		if err := r.Status().Update(ctx, auth); err != nil {
+			log.Error(err, "Failed to update Auth status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	condition := metav1.Condition{Type: typeInSyncAuth, Status: metav1.ConditionTrue, Reason: "InSync", Message: "Auth engine in Vault matches the spec"}
+	if m == nil {
+		condition = metav1.Condition{Type: typeInSyncAuth, Status: metav1.ConditionFalse, Reason: "OutOfSync", Message: fmt.Sprintf("Auth engine %s/ is not enabled in Vault", auth.Name)}
+	} else if diff := vault.Diff(authFields(m.Type, m.Description), desiredAuthFields(auth)); diff != "" {
+		condition = metav1.Condition{Type: typeInSyncAuth, Status: metav1.ConditionFalse, Reason: "OutOfSync", Message: "Auth engine in Vault differs from the spec:\n" + diff}
+	}
+
+	vault.SetCondition(&auth.Status.Conditions, auth.Generation, condition)
+	auth.Status.ObservedGeneration = auth.Generation
+	auth.Status.VaultPath = authPath(auth)
+	auth.Status.ReconcileRequest = auth.Annotations[vault.ReconcileAtAnnotation]
+	if err := r.Status().Update(ctx, auth); err != nil {
+		log.Error(err, "Failed to update Auth status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Plan returns the change applying the auth method, or deleting it, makes in
+// Vault. Auth methods are only enabled, never updated.
+func (r *AuthReconciler) Plan(ctx context.Context, obj client.Object, deleted bool) (*plan.Change, error) {
+	auth, ok := obj.(*sysv1beta1.Auth)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+
+	m, err := r.fetchVaultAuth(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &plan.Change{Path: authPath(auth), Action: plan.ActionNone}
+	switch {
+	case deleted:
+		if m != nil {
+			change.Action = plan.ActionDelete
+		}
+	case m == nil:
+		change.Action, change.Diff = plan.ActionCreate, vault.Diff(authFields("", ""), desiredAuthFields(auth))
+	}
+	return change, nil
+}
+
+// fetchVaultAuth returns the auth method enabled at the path of the resource,
+// nil when there is none.
+func (r *AuthReconciler) fetchVaultAuth(ctx context.Context, auth *sysv1beta1.Auth) (*vaultapi.MountOutput, error) {
+	s, err := r.Vault.Read(ctx, "sys/auth")
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := map[string]*vaultapi.MountOutput{}
+	if s != nil {
+		jsonBytes, err := json.Marshal(s.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(jsonBytes, &mounts); err != nil {
+			return nil, err
+		}
+	}
+
+	return mounts[auth.Name+"/"], nil
+}
+
+// authFields are the fields of an auth method the resource sets.
+func authFields(typ, description string) map[string]string {
+	return map[string]string{"type": typ, "description": description}
+}
+
+func desiredAuthFields(auth *sysv1beta1.Auth) map[string]string {
+	return authFields(ptr.Deref(auth.Spec.Type, ""), ptr.Deref(auth.Spec.Description, ""))
+}
+
+// adoptVaultAuth reports whether the resource adopts an auth method of its
+// type already enabled at its path.
+func (r *AuthReconciler) adoptVaultAuth(ctx context.Context, auth *sysv1beta1.Auth) (bool, error) {
+	if !vault.Adopts(auth.Annotations) {
+		return false, nil
+	}
+
+	m, err := r.fetchVaultAuth(ctx, auth)
+	if err != nil {
+		return false, err
+	}
+	return m != nil && m.Type == ptr.Deref(auth.Spec.Type, ""), nil
+}
+
+// authPath returns the path the auth method is enabled at in Vault.
+func authPath(auth *sysv1beta1.Auth) string {
+	return fmt.Sprintf("sys/auth/%s", auth.Name)
+}
+
 func (r *AuthReconciler) deleteVaultAuth(ctx context.Context, auth *sysv1beta1.Auth) error {
-	return r.Vault.Sys().DisableAuthWithContext(ctx, fmt.Sprintf("%s/", auth.Name))
+	return r.Vault.DisableAuth(ctx, fmt.Sprintf("%s/", auth.Name))
 }
 
 func (r *AuthReconciler) createVaultAuth(ctx context.Context, auth *sysv1beta1.Auth) error {
-	return r.Vault.Sys().EnableAuthWithOptionsWithContext(ctx, fmt.Sprintf("%s/", auth.Name), &vaultapi.EnableAuthOptions{
+	options := &vaultapi.EnableAuthOptions{
 		Type:        *auth.Spec.Type,
 		Description: *auth.Spec.Description,
-	})
+	}
+	if err := r.Vault.EnableAuth(ctx, fmt.Sprintf("%s/", auth.Name), options); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	auth.Status.LastAppliedHash = vault.Checksum(options)
+	auth.Status.LastAppliedTime = &now
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
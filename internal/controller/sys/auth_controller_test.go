@@ -18,32 +18,55 @@ package sys
 
 import (
 	"context"
+	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	vaultapi "github.com/hashicorp/vault/api"
+
 	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
 )
 
 var _ = Describe("Auth Controller", func() {
 	Context("When reconciling a resource", func() {
-		const resourceName = "test-resource"
+		const resourceName = "test-auth"
 
 		ctx := context.Background()
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
+		}
+
+		var controllerReconciler *AuthReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
 		}
-		auth := &sysv1beta1.Auth{}
 
 		BeforeEach(func() {
+			vaultServer.Reset()
+			controllerReconciler = &AuthReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
 			By("creating the custom resource for the Kind Auth")
+			auth := &sysv1beta1.Auth{}
 			err := k8sClient.Get(ctx, typeNamespacedName, auth)
 			if err != nil && errors.IsNotFound(err) {
 				resource := &sysv1beta1.Auth{
@@ -51,34 +74,163 @@ var _ = Describe("Auth Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: sysv1beta1.AuthSpec{
+						Type:        ptr.To("kubernetes"),
+						Description: ptr.To("test cluster"),
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &sysv1beta1.Auth{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance Auth")
+			vaultServer.ClearFaults()
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
 		})
-		It("should successfully reconcile the resource", func() {
+
+		It("should enable the auth method in Vault", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &AuthReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+			Expect(reconcileOnce()).To(Succeed())
+
+			mount, ok := vaultServer.AuthMount(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(mount.Type).To(Equal("kubernetes"))
+			Expect(mount.Description).To(Equal("test cluster"))
+
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			Expect(auth.Status.Accessor).To(Equal(mount.Accessor))
+			Expect(meta.IsStatusConditionTrue(auth.Status.Conditions, typeConfiguredAuth)).To(BeTrue())
+		})
+
+		It("should not enable the auth method twice", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			enabled := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPost && r.Path == "sys/auth/"+resourceName {
+					enabled++
+				}
 			}
+			Expect(enabled).To(Equal(1))
+		})
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: typeNamespacedName,
-			})
-			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+		It("should report a failure to enable the auth method", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPost, Path: "sys/auth", Status: http.StatusForbidden})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			Expect(auth.Status.Accessor).To(BeEmpty())
+			condition := meta.FindStatusCondition(auth.Status.Conditions, typeConfiguredAuth)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should disable the auth method when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, auth)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.AuthMount(resourceName)
+			Expect(ok).To(BeFalse())
+			err := k8sClient.Get(ctx, typeNamespacedName, auth)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should only report drift in observe mode", func() {
+			controllerReconciler.Mode = vault.ModeObserve
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.AuthMount(resourceName)
+			Expect(ok).To(BeFalse())
+			for _, r := range vaultServer.Requests() {
+				Expect(r.Method).To(Equal(http.MethodGet))
+			}
+
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			Expect(auth.Finalizers).NotTo(ContainElement(authFinalizer))
+			condition := meta.FindStatusCondition(auth.Status.Conditions, typeInSyncAuth)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("OutOfSync"))
+
+			By("Reporting the fields that differ once the auth method is enabled")
+			Expect(vaultServer.Client().EnableAuth(ctx, resourceName+"/", &vaultapi.EnableAuthOptions{Type: "kubernetes", Description: "other cluster"})).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			condition = meta.FindStatusCondition(auth.Status.Conditions, typeInSyncAuth)
+			Expect(condition.Reason).To(Equal("OutOfSync"))
+			Expect(condition.Message).To(HaveSuffix(`description: "other cluster" -> "test cluster"`))
+		})
+
+		It("should adopt the auth method already enabled when annotated", func() {
+			Expect(vaultServer.Client().EnableAuth(ctx, resourceName+"/", &vaultapi.EnableAuthOptions{Type: "kubernetes"})).To(Succeed())
+
+			resource := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{vault.AdoptAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			mount, _ := vaultServer.AuthMount(resourceName)
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			Expect(auth.Status.Accessor).To(Equal(mount.Accessor))
+			Expect(meta.IsStatusConditionTrue(auth.Status.Conditions, typeConfiguredAuth)).To(BeTrue())
+		})
+
+		It("should not enable the auth method while paused", func() {
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			auth.Annotations = map[string]string{vault.PausedAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, auth)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.AuthMount(resourceName)
+			Expect(ok).To(BeFalse())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(auth.Status.Conditions, vault.PausedCondition)).To(BeTrue())
+		})
+
+		It("should enable the auth method disabled in Vault on a reconcile request", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Disabling the auth method behind the operator's back")
+			Expect(vaultServer.Client().DisableAuth(ctx, resourceName+"/")).To(Succeed())
+
+			auth := &sysv1beta1.Auth{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			auth.Annotations = map[string]string{vault.ReconcileAtAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, auth)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			mount, ok := vaultServer.AuthMount(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, auth)).To(Succeed())
+			Expect(auth.Status.Accessor).To(Equal(mount.Accessor))
+			Expect(auth.Status.ReconcileRequest).To(Equal("1"))
 		})
 	})
 })
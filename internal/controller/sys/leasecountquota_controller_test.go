@@ -0,0 +1,138 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+)
+
+var _ = Describe("LeaseCountQuota Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-lease-count-quota"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *LeaseCountQuotaReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			v := vaultServer.Client()
+			controllerReconciler = &LeaseCountQuotaReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  v,
+			}
+
+			Expect(v.Mount(ctx, "database", &vaultapi.MountInput{Type: "database"})).To(Succeed())
+
+			By("creating the custom resource for the Kind LeaseCountQuota")
+			quota := &sysv1beta1.LeaseCountQuota{}
+			err := k8sClient.Get(ctx, typeNamespacedName, quota)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &sysv1beta1.LeaseCountQuota{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sysv1beta1.LeaseCountQuotaSpec{
+						QuotaScope: sysv1beta1.QuotaScope{Path: "database"},
+						MaxLeases:  500,
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sysv1beta1.LeaseCountQuota{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance LeaseCountQuota")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should create the quota on the mount", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			q, ok := vaultServer.Quota("lease-count", resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(q.Path).To(Equal("database/"))
+			Expect(q.MaxLeases).To(Equal(500))
+
+			resource := &sysv1beta1.LeaseCountQuota{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(leaseCountQuotaFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredLeaseCountQuota)).To(BeTrue())
+		})
+
+		It("should report a path Vault rejects", func() {
+			resource := &sysv1beta1.LeaseCountQuota{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Path = "missing"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredLeaseCountQuota)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+
+		It("should delete the quota from Vault when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &sysv1beta1.LeaseCountQuota{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Quota("lease-count", resourceName)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
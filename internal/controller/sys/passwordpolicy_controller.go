@@ -0,0 +1,244 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	passwordPolicyFinalizer = "passwordpolicy.sys.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredPasswordPolicy = "Configured"
+)
+
+// PasswordPolicyReconciler reconciles a PasswordPolicy object
+type PasswordPolicyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=passwordpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=passwordpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=passwordpolicies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The policy is named after the resource. Once written, a password is
+// generated from it, so that a policy Vault accepts but cannot satisfy is
+// reported rather than failing the engines that reference it.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *PasswordPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the PasswordPolicy instance
+	policy := &sysv1beta1.PasswordPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("PasswordPolicy resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get PasswordPolicy")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, policy, &policy.Status.Conditions, r.Mode, passwordPolicyFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag PasswordPolicy in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// PasswordPolicy Deletion
+	isPolicyMarkedToBeDeleted := policy.GetDeletionTimestamp() != nil
+	if isPolicyMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(policy, passwordPolicyFinalizer) {
+			if err := r.deleteVaultPasswordPolicy(ctx, policy); err != nil {
+				log.Error(err, "Failed to delete PasswordPolicy")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, policy, passwordPolicyPath(policy))
+
+			controllerutil.RemoveFinalizer(policy, passwordPolicyFinalizer)
+			if err := r.Update(ctx, policy); err != nil {
+				log.Error(err, "Failed to remove finalizer from PasswordPolicy")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// PasswordPolicy Initialization
+	if !controllerutil.ContainsFinalizer(policy, passwordPolicyFinalizer) {
+		controllerutil.AddFinalizer(policy, passwordPolicyFinalizer)
+		vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPasswordPolicy, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, policy); err != nil {
+			log.Error(err, "Failed to initialize PasswordPolicy status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create or update
+	current, err := r.fetchVaultPasswordPolicy(ctx, policy)
+	if err != nil {
+		log.Error(err, "Failed to fetch PasswordPolicy")
+		vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPasswordPolicy, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch password policy from Vault"})
+		events.Failed(r.Recorder, policy, "FailedToFetch", "Failed to fetch password policy from Vault", passwordPolicyPath(policy), err)
+		if err := r.Status().Update(ctx, policy); err != nil {
+			log.Error(err, "Failed to update PasswordPolicy status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&policy.Spec) {
+		if err := r.updateVaultPasswordPolicy(ctx, policy); err != nil {
+			log.Error(err, "Failed to update PasswordPolicy")
+			vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPasswordPolicy, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push password policy to Vault: %s", err)})
+			events.Failed(r.Recorder, policy, "FailedToUpdate", "Failed to push password policy to Vault", passwordPolicyPath(policy), err)
+			if err := r.Status().Update(ctx, policy); err != nil {
+				log.Error(err, "Failed to update PasswordPolicy status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, policy, policy.Status.Conditions, current != nil, "PasswordPolicy", passwordPolicyPath(policy))
+	}
+
+	if err := r.generateVaultPassword(ctx, policy); err != nil {
+		log.Error(err, "Failed to generate a password from PasswordPolicy")
+		vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPasswordPolicy, Status: metav1.ConditionFalse, Reason: "FailedToValidate", Message: fmt.Sprintf("Failed to generate a password from the policy: %s", err)})
+		events.Failed(r.Recorder, policy, "FailedToValidate", "Failed to generate a password from the policy", passwordPolicyPath(policy), err)
+		if err := r.Status().Update(ctx, policy); err != nil {
+			log.Error(err, "Failed to update PasswordPolicy status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	policy.Status.ObservedGeneration = policy.Generation
+	policy.Status.VaultPath = passwordPolicyPath(policy)
+	vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPasswordPolicy, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed password policy to Vault"})
+	if err := r.Status().Update(ctx, policy); err != nil {
+		log.Error(err, "Failed to update PasswordPolicy status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func passwordPolicyPath(policy *sysv1beta1.PasswordPolicy) string {
+	return fmt.Sprintf("sys/policies/password/%s", policy.Name)
+}
+
+func (r *PasswordPolicyReconciler) deleteVaultPasswordPolicy(ctx context.Context, policy *sysv1beta1.PasswordPolicy) error {
+	_, err := r.Vault.Delete(ctx, passwordPolicyPath(policy))
+	return err
+}
+
+func (r *PasswordPolicyReconciler) fetchVaultPasswordPolicy(ctx context.Context, policy *sysv1beta1.PasswordPolicy) (*vault.PasswordPolicy, error) {
+	s, err := r.Vault.Read(ctx, passwordPolicyPath(policy))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var p vault.PasswordPolicy
+	if err := json.Unmarshal(jsonBytes, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (r *PasswordPolicyReconciler) updateVaultPasswordPolicy(ctx context.Context, policy *sysv1beta1.PasswordPolicy) error {
+	data := map[string]interface{}{
+		"policy": vault.PasswordPolicyDocument(&policy.Spec),
+	}
+	if _, err := r.Vault.Write(ctx, passwordPolicyPath(policy), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	policy.Status.LastAppliedHash = vault.Checksum(data)
+	policy.Status.LastAppliedTime = &now
+	return nil
+}
+
+// generateVaultPassword generates a password from the policy and discards it.
+func (r *PasswordPolicyReconciler) generateVaultPassword(ctx context.Context, policy *sysv1beta1.PasswordPolicy) error {
+	s, err := r.Vault.Read(ctx, passwordPolicyPath(policy)+"/generate")
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("no password generated")
+	}
+	if password, _ := s.Data["password"].(string); password == "" {
+		return fmt.Errorf("no password generated")
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PasswordPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sysv1beta1.PasswordPolicy{}).
+		Named("sys-passwordpolicy").
+		Complete(r)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("PasswordPolicy Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-password-policy"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *PasswordPolicyReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			controllerReconciler = &PasswordPolicyReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind PasswordPolicy")
+			policy := &sysv1beta1.PasswordPolicy{}
+			err := k8sClient.Get(ctx, typeNamespacedName, policy)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &sysv1beta1.PasswordPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sysv1beta1.PasswordPolicySpec{
+						Length: 20,
+						Rules: []sysv1beta1.PasswordPolicyRule{
+							{Charset: "abcdefghijklmnopqrstuvwxyz", MinChars: 1},
+							{Charset: "0123456789", MinChars: 2},
+							{Charset: `!@#$%^&*"\`},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sysv1beta1.PasswordPolicy{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance PasswordPolicy")
+			vaultServer.ClearFaults()
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+		})
+
+		It("should render the policy and validate it", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			policy, ok := vaultServer.PasswordPolicy(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(policy).To(Equal(`length = 20
+
+rule "charset" {
+  charset = "abcdefghijklmnopqrstuvwxyz"
+  min-chars = 1
+}
+
+rule "charset" {
+  charset = "0123456789"
+  min-chars = 2
+}
+
+rule "charset" {
+  charset = "!@#$%^&*\"\\"
+}
+`))
+
+			resource := &sysv1beta1.PasswordPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(passwordPolicyFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredPasswordPolicy)).To(BeTrue())
+
+			generated := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodGet && r.Path == "sys/policies/password/"+resourceName+"/generate" {
+					generated++
+				}
+			}
+			Expect(generated).To(Equal(1))
+		})
+
+		It("should push raw HCL as is", func() {
+			document := "length = 8\nrule \"charset\" {\n  charset = \"ab\"\n}\n"
+			resource := &sysv1beta1.PasswordPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec = sysv1beta1.PasswordPolicySpec{Policy: document}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			policy, _ := vaultServer.PasswordPolicy(resourceName)
+			Expect(policy).To(Equal(document))
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == "sys/policies/password/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should report a policy Vault rejects", func() {
+			resource := &sysv1beta1.PasswordPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Rules[1].MinChars = 30
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredPasswordPolicy)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+
+		It("should report a failure to generate a password", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodGet, Path: "sys/policies/password/" + resourceName + "/generate", Status: http.StatusInternalServerError})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &sysv1beta1.PasswordPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredPasswordPolicy)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToValidate"))
+		})
+
+		It("should only flag a resource to observe and leave Vault untouched", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+
+			resource := &sysv1beta1.PasswordPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{vault.ModeAnnotation: string(vault.ModeObserve)}
+			resource.Spec.Length = 30
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			requests := len(vaultServer.Requests())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(vaultServer.Requests()).To(HaveLen(requests))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredPasswordPolicy)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(vault.UnsupportedModeReason))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning UnsupportedMode")))
+
+			By("Releasing the resource on deletion")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(vaultServer.Requests()).To(HaveLen(requests))
+			_, ok := vaultServer.PasswordPolicy(resourceName)
+			Expect(ok).To(BeTrue())
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should delete the policy from Vault when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &sysv1beta1.PasswordPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.PasswordPolicy(resourceName)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,409 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	pluginFinalizer = "plugin.sys.toolkit.vault.hopopops.com/finalizer"
+
+	// reloadAnnotation triggers a reload of the mounts running the plugin
+	// whenever its value changes.
+	reloadAnnotation = "sys.toolkit.vault.hopopops.com/reload"
+
+	// pluginMountsInterval is how often mounts are checked while they still
+	// run a replaced version of the plugin.
+	pluginMountsInterval = 5 * time.Minute
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredPlugin = "Configured"
+)
+
+// PluginReconciler reconciles a Plugin object
+type PluginReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=plugins,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=plugins/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=plugins/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The plugin is registered in the catalog under the name of the resource.
+// Changing the version registers the new one next to the previous ones, which
+// are deregistered once no mount is configured with or runs them anymore, so
+// that mounts can be moved over with a tune and a reload. Every version is
+// deregistered with the resource, which waits for the mounts to be disabled.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *PluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the Plugin instance
+	plugin := &sysv1beta1.Plugin{}
+	if err := r.Get(ctx, req.NamespacedName, plugin); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("Plugin resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get Plugin")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, plugin, &plugin.Status.Conditions, r.Mode, pluginFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag Plugin in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Plugin Deletion
+	isPluginMarkedToBeDeleted := plugin.GetDeletionTimestamp() != nil
+	if isPluginMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(plugin, pluginFinalizer) {
+			if err := r.deleteVaultPlugin(ctx, plugin); err != nil {
+				log.Error(err, "Failed to delete Plugin")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, plugin, pluginPath(plugin))
+
+			controllerutil.RemoveFinalizer(plugin, pluginFinalizer)
+			if err := r.Update(ctx, plugin); err != nil {
+				log.Error(err, "Failed to remove finalizer from Plugin")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Plugin Initialization
+	if !controllerutil.ContainsFinalizer(plugin, pluginFinalizer) {
+		controllerutil.AddFinalizer(plugin, pluginFinalizer)
+		vault.SetCondition(&plugin.Status.Conditions, plugin.Generation, metav1.Condition{Type: typeConfiguredPlugin, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, plugin); err != nil {
+			log.Error(err, "Failed to initialize Plugin status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Register, the environment is not returned by Vault so it is pushed
+	// whenever the spec changes
+	current, err := r.fetchVaultPlugin(ctx, plugin, plugin.Spec.Version)
+	if err != nil {
+		log.Error(err, "Failed to fetch Plugin")
+		vault.SetCondition(&plugin.Status.Conditions, plugin.Generation, metav1.Condition{Type: typeConfiguredPlugin, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch plugin from Vault"})
+		events.Failed(r.Recorder, plugin, "FailedToFetch", "Failed to fetch plugin from Vault", pluginPath(plugin), err)
+		if err := r.Status().Update(ctx, plugin); err != nil {
+			log.Error(err, "Failed to update Plugin status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&plugin.Spec) || plugin.Status.ObservedGeneration != plugin.Generation {
+		if err := r.updateVaultPlugin(ctx, plugin); err != nil {
+			log.Error(err, "Failed to update Plugin")
+			vault.SetCondition(&plugin.Status.Conditions, plugin.Generation, metav1.Condition{Type: typeConfiguredPlugin, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push plugin to Vault: %s", err)})
+			events.Failed(r.Recorder, plugin, "FailedToUpdate", "Failed to push plugin to Vault", pluginPath(plugin), err)
+			if err := r.Status().Update(ctx, plugin); err != nil {
+				log.Error(err, "Failed to update Plugin status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, plugin, plugin.Status.Conditions, current != nil, "Plugin", pluginPath(plugin))
+		plugin.Status.ObservedGeneration = plugin.Generation
+	}
+
+	// On-demand reload, once the version the mounts are tuned to is registered
+	if request := plugin.Annotations[reloadAnnotation]; request != "" && request != plugin.Status.ReloadRequest {
+		if err := r.reloadVaultPlugin(ctx, plugin); err != nil {
+			log.Error(err, "Failed to reload Plugin")
+			vault.SetCondition(&plugin.Status.Conditions, plugin.Generation, metav1.Condition{Type: typeConfiguredPlugin, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to reload plugin: %s", err)})
+			events.Failed(r.Recorder, plugin, "FailedToUpdate", "Failed to reload plugin", pluginPath(plugin), err)
+			if err := r.Status().Update(ctx, plugin); err != nil {
+				log.Error(err, "Failed to update Plugin status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Reloaded Plugin", "request", request)
+		plugin.Status.ReloadRequest = request
+	}
+
+	// Registered versions, replaced ones being dropped once unused
+	versions, err := r.syncVaultPluginVersions(ctx, plugin)
+	if err != nil {
+		log.Error(err, "Failed to sync Plugin versions")
+		vault.SetCondition(&plugin.Status.Conditions, plugin.Generation, metav1.Condition{Type: typeConfiguredPlugin, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to sync registered plugin versions: %s", err)})
+		events.Failed(r.Recorder, plugin, "FailedToFetch", "Failed to sync registered plugin versions", pluginPath(plugin), err)
+		if err := r.Status().Update(ctx, plugin); err != nil {
+			log.Error(err, "Failed to update Plugin status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	plugin.Status.Versions = versions
+	plugin.Status.VaultPath = pluginPath(plugin)
+	vault.SetCondition(&plugin.Status.Conditions, plugin.Generation, metav1.Condition{Type: typeConfiguredPlugin, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully registered plugin in Vault"})
+	if err := r.Status().Update(ctx, plugin); err != nil {
+		log.Error(err, "Failed to update Plugin status")
+		return ctrl.Result{}, err
+	}
+
+	// Check back for the mounts still running a replaced version
+	if plugin.Spec.Type != "database" {
+		for _, v := range versions {
+			if v.Version != plugin.Spec.Version {
+				return ctrl.Result{RequeueAfter: pluginMountsInterval}, nil
+			}
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func pluginPath(plugin *sysv1beta1.Plugin) string {
+	return fmt.Sprintf("sys/plugins/catalog/%s/%s", plugin.Spec.Type, plugin.Name)
+}
+
+// pluginVersionData selects a version of the plugin, an empty version being
+// the unversioned plugin.
+func pluginVersionData(version string) map[string][]string {
+	return url.Values{"version": []string{version}}
+}
+
+func (r *PluginReconciler) deleteVaultPlugin(ctx context.Context, plugin *sysv1beta1.Plugin) error {
+	versions, err := r.fetchVaultPluginVersions(ctx, plugin)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if _, err := r.Vault.DeleteWithData(ctx, pluginPath(plugin), pluginVersionData(version)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PluginReconciler) fetchVaultPlugin(ctx context.Context, plugin *sysv1beta1.Plugin, version string) (*vault.Plugin, error) {
+	s, err := r.Vault.ReadWithData(ctx, pluginPath(plugin), pluginVersionData(version))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var p vault.Plugin
+	if err := json.Unmarshal(jsonBytes, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (r *PluginReconciler) updateVaultPlugin(ctx context.Context, plugin *sysv1beta1.Plugin) error {
+	data := map[string]interface{}{
+		"command": plugin.Spec.Command,
+		"sha256":  plugin.Spec.SHA256,
+		"version": plugin.Spec.Version,
+		"args":    plugin.Spec.Args,
+		"env":     plugin.Spec.Env,
+	}
+	if _, err := r.Vault.Write(ctx, pluginPath(plugin), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	plugin.Status.LastAppliedHash = vault.Checksum(data)
+	plugin.Status.LastAppliedTime = &now
+	return nil
+}
+
+func (r *PluginReconciler) reloadVaultPlugin(ctx context.Context, plugin *sysv1beta1.Plugin) error {
+	_, err := r.Vault.Write(ctx, "sys/plugins/reload/backend", map[string]interface{}{
+		"plugin": plugin.Name,
+	})
+	return err
+}
+
+// fetchVaultPluginVersions returns the versions of the plugin registered in
+// the catalog, builtin ones aside.
+func (r *PluginReconciler) fetchVaultPluginVersions(ctx context.Context, plugin *sysv1beta1.Plugin) ([]string, error) {
+	s, err := r.Vault.Read(ctx, "sys/plugins/catalog")
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog vault.PluginCatalog
+	if err := json.Unmarshal(jsonBytes, &catalog); err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, p := range catalog.Detailed {
+		if p.Type == plugin.Spec.Type && p.Name == plugin.Name && !p.Builtin {
+			versions = append(versions, p.Version)
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// fetchVaultPluginMounts returns the auth methods or secrets engines backed by
+// the plugin, by path. Database plugins run within connections instead.
+func (r *PluginReconciler) fetchVaultPluginMounts(ctx context.Context, plugin *sysv1beta1.Plugin) (map[string]vaultapi.MountOutput, error) {
+	var p, prefix string
+	switch plugin.Spec.Type {
+	case "auth":
+		p, prefix = "sys/auth", "auth/"
+	case "secret":
+		p = "sys/mounts"
+	default:
+		return nil, nil
+	}
+
+	s, err := r.Vault.Read(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]vaultapi.MountOutput
+	if err := json.Unmarshal(jsonBytes, &all); err != nil {
+		return nil, err
+	}
+
+	mounts := map[string]vaultapi.MountOutput{}
+	for path, m := range all {
+		if m.Type == plugin.Name {
+			mounts[prefix+path] = m
+		}
+	}
+	return mounts, nil
+}
+
+// syncVaultPluginVersions deregisters the replaced versions no mount is
+// configured with or runs anymore, and reports the remaining ones.
+func (r *PluginReconciler) syncVaultPluginVersions(ctx context.Context, plugin *sysv1beta1.Plugin) ([]sysv1beta1.PluginVersionStatus, error) {
+	versions, err := r.fetchVaultPluginVersions(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts, err := r.fetchVaultPluginMounts(ctx, plugin)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []sysv1beta1.PluginVersionStatus
+	for _, version := range versions {
+		status := sysv1beta1.PluginVersionStatus{Version: version}
+		configured := false
+		for path, m := range mounts {
+			if m.RunningVersion == version {
+				status.Mounts = append(status.Mounts, path)
+			}
+			configured = configured || m.PluginVersion == version
+		}
+		sort.Strings(status.Mounts)
+		status.Running = len(status.Mounts) > 0
+
+		if version != plugin.Spec.Version && plugin.Spec.Type != "database" && !configured && !status.Running {
+			if _, err := r.Vault.DeleteWithData(ctx, pluginPath(plugin), pluginVersionData(version)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PluginReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sysv1beta1.Plugin{}).
+		Named("sys-plugin").
+		Complete(r)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+)
+
+var _ = Describe("Plugin Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-plugin"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		sha256v1 := strings.Repeat("a1", 32)
+		sha256v2 := strings.Repeat("b2", 32)
+
+		var controllerReconciler *PluginReconciler
+
+		reconcileOnce := func() (reconcile.Result, error) {
+			return controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+		}
+
+		getPlugin := func() *sysv1beta1.Plugin {
+			resource := &sysv1beta1.Plugin{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		upgrade := func() {
+			resource := getPlugin()
+			resource.Spec.Version = "v1.1.0"
+			resource.Spec.SHA256 = sha256v2
+			resource.Spec.Command = "test-plugin-v1.1.0"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			controllerReconciler = &PluginReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind Plugin")
+			plugin := &sysv1beta1.Plugin{}
+			err := k8sClient.Get(ctx, typeNamespacedName, plugin)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &sysv1beta1.Plugin{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sysv1beta1.PluginSpec{
+						Type:    "secret",
+						Command: "test-plugin-v1.0.0",
+						SHA256:  sha256v1,
+						Version: "v1.0.0",
+						Args:    []string{"-tls-skip-verify"},
+						Env:     []string{"LOG_LEVEL=debug"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sysv1beta1.Plugin{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance Plugin")
+				vaultServer.ClearFaults()
+				_ = vaultServer.Client().Unmount(ctx, "custom")
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				_, err := reconcileOnce()
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should register the plugin in the catalog", func() {
+			By("Reconciling the created resource")
+			_, err := reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+
+			p, ok := vaultServer.Plugin("secret", resourceName, "v1.0.0")
+			Expect(ok).To(BeTrue())
+			Expect(p.Command).To(Equal("test-plugin-v1.0.0"))
+			Expect(p.SHA256).To(Equal(sha256v1))
+			Expect(p.Args).To(Equal([]string{"-tls-skip-verify"}))
+			Expect(p.Env).To(Equal([]string{"LOG_LEVEL=debug"}))
+
+			resource := getPlugin()
+			Expect(resource.Finalizers).To(ContainElement(pluginFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredPlugin)).To(BeTrue())
+			Expect(resource.Status.Versions).To(Equal([]sysv1beta1.PluginVersionStatus{{Version: "v1.0.0"}}))
+		})
+
+		It("should push the environment when the spec changes", func() {
+			_, err := reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := getPlugin()
+			resource.Spec.Env = []string{"LOG_LEVEL=info"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			_, err = reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+
+			p, _ := vaultServer.Plugin("secret", resourceName, "v1.0.0")
+			Expect(p.Env).To(Equal([]string{"LOG_LEVEL=info"}))
+		})
+
+		It("should report the mounts running each version", func() {
+			_, err := reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+
+			v := vaultServer.Client()
+			Expect(v.Mount(ctx, "custom", &vaultapi.MountInput{Type: resourceName, Config: vaultapi.MountConfigInput{PluginVersion: "v1.0.0"}})).To(Succeed())
+
+			_, err = reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getPlugin().Status.Versions).To(Equal([]sysv1beta1.PluginVersionStatus{
+				{Version: "v1.0.0", Running: true, Mounts: []string{"custom/"}},
+			}))
+		})
+
+		It("should keep replaced versions until no mount runs them", func() {
+			_, err := reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+			v := vaultServer.Client()
+			Expect(v.Mount(ctx, "custom", &vaultapi.MountInput{Type: resourceName, Config: vaultapi.MountConfigInput{PluginVersion: "v1.0.0"}})).To(Succeed())
+
+			By("registering the new version next to the running one")
+			upgrade()
+			result, err := reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(pluginMountsInterval))
+			Expect(getPlugin().Status.Versions).To(Equal([]sysv1beta1.PluginVersionStatus{
+				{Version: "v1.0.0", Running: true, Mounts: []string{"custom/"}},
+				{Version: "v1.1.0"},
+			}))
+
+			By("reloading the mount tuned to the new version")
+			Expect(v.TuneMount(ctx, "custom", vaultapi.MountConfigInput{PluginVersion: "v1.1.0"})).To(Succeed())
+			resource := getPlugin()
+			resource.Annotations = map[string]string{reloadAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			result, err = reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			resource = getPlugin()
+			Expect(resource.Status.ReloadRequest).To(Equal("1"))
+			Expect(resource.Status.Versions).To(Equal([]sysv1beta1.PluginVersionStatus{
+				{Version: "v1.1.0", Running: true, Mounts: []string{"custom/"}},
+			}))
+			_, ok := vaultServer.Plugin("secret", resourceName, "v1.0.0")
+			Expect(ok).To(BeFalse())
+			m, _ := vaultServer.Mount("custom")
+			Expect(m.RunningSha256).To(Equal(sha256v2))
+		})
+
+		It("should deregister every version when the resource is deleted", func() {
+			_, err := reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+			v := vaultServer.Client()
+			Expect(v.Mount(ctx, "custom", &vaultapi.MountInput{Type: resourceName, Config: vaultapi.MountConfigInput{PluginVersion: "v1.0.0"}})).To(Succeed())
+			upgrade()
+			_, err = reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Delete(ctx, getPlugin())).To(Succeed())
+
+			By("waiting for the mount to be disabled")
+			_, err = reconcileOnce()
+			Expect(err).To(HaveOccurred())
+			Expect(getPlugin().Finalizers).To(ContainElement(pluginFinalizer))
+
+			Expect(v.Unmount(ctx, "custom")).To(Succeed())
+			_, err = reconcileOnce()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, ok := vaultServer.Plugin("secret", resourceName, "v1.0.0")
+			Expect(ok).To(BeFalse())
+			_, ok = vaultServer.Plugin("secret", resourceName, "v1.1.0")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
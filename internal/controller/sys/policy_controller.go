@@ -18,20 +18,23 @@ package sys
 
 import (
 	"context"
+	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	vaultapi "github.com/hashicorp/vault/api"
-
 	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
 	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+	"hopopops/vault-operator/internal/plan"
 )
 
 const (
@@ -41,22 +44,33 @@ const (
 // Definitions to manage status conditions
 const (
 	typeConfiguredPolicy = "Configured"
+	typeInSyncPolicy     = "InSync"
 )
 
 // PolicyReconciler reconciles a Policy object
 type PolicyReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	Vault  *vaultapi.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
 }
 
 // +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=policies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=policies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=policies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
+// In observe mode, the policy is only compared with the spec and the finalizer
+// leaves Vault untouched.
+//
+// A paused resource is not reconciled, and its finalizer leaves Vault
+// untouched. A new reconcile-at annotation pushes the policy even when Vault
+// already matches it.
+//
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
 func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -73,8 +87,22 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	observe := vault.ResourceMode(r.Mode, policy.Annotations) == vault.ModeObserve
+	paused := vault.Paused(policy.Annotations)
+
+	if vault.SetPausedCondition(&policy.Status.Conditions, policy.Generation, paused) {
+		if err := r.Status().Update(ctx, policy); err != nil {
+			log.Error(err, "Failed to update Policy status")
+			return ctrl.Result{}, err
+		}
+	}
+	if paused && policy.ObjectMeta.DeletionTimestamp.IsZero() {
+		log.Info("Policy reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
 	if len(policy.Status.Conditions) == 0 {
-		meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
 		if err := r.Status().Update(ctx, policy); err != nil {
 			log.Error(err, "Failed to update Policy status")
 			return ctrl.Result{}, err
@@ -87,7 +115,7 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	if policy.ObjectMeta.DeletionTimestamp.IsZero() {
-		if !controllerutil.ContainsFinalizer(policy, policyFinalizer) {
+		if !controllerutil.ContainsFinalizer(policy, policyFinalizer) && !observe {
 			// Initialize finalizer
 			controllerutil.AddFinalizer(policy, policyFinalizer)
 			if err := r.Update(ctx, policy); err != nil {
@@ -103,9 +131,12 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	} else {
 		if controllerutil.ContainsFinalizer(policy, policyFinalizer) {
 			// Delete managed resources for this Policy
-			if err := r.deleteVaultPolicy(ctx, policy); err != nil {
-				log.Error(err, "Failed to delete Policy")
-				return ctrl.Result{}, err
+			if !observe && !paused {
+				if err := r.deleteVaultPolicy(ctx, policy); err != nil {
+					log.Error(err, "Failed to delete Policy")
+					return ctrl.Result{}, err
+				}
+				events.Deleted(r.Recorder, policy, policyPath(policy))
 			}
 
 			controllerutil.RemoveFinalizer(policy, policyFinalizer)
@@ -121,18 +152,25 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	// Create or update
 	if p, err := r.fetchVaultPolicy(ctx, policy); err != nil {
 		log.Error(err, "Failed to fetch Policy")
-		meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch policy from Vault"})
+		vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch policy from Vault"})
+		events.Failed(r.Recorder, policy, "FailedToFetch", "Failed to fetch policy from Vault", policyPath(policy), err)
 		if err := r.Status().Update(ctx, policy); err != nil {
 			log.Error(err, "Failed to update Policy status")
 			return ctrl.Result{}, err
 		}
 
 		return ctrl.Result{}, err
+	} else if observe {
+		return r.observeVaultPolicy(ctx, policy, p)
 	} else {
-		if p == nil || p.Name != policy.Name || p.Policy != *policy.Spec.Policy {
+		status := policy.Status.DeepCopy()
+		message := "Policy in Vault matches the spec"
+		requestedAt, forced := vault.ReconcileRequested(policy.Annotations, policy.Status.ReconcileRequest)
+		if forced || p == nil || p.Name != policy.Name || p.Policy != *policy.Spec.Policy {
 			if err := r.updateVaultPolicy(ctx, policy); err != nil {
 				log.Error(err, "Failed to update Policy")
-				meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push policy to Vault"})
+				vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to push policy to Vault"})
+				events.Failed(r.Recorder, policy, "FailedToUpdate", "Failed to push policy to Vault", policyPath(policy), err)
 				if err := r.Status().Update(ctx, policy); err != nil {
 					log.Error(err, "Failed to update Policy status")
 					return ctrl.Result{}, err
@@ -141,7 +179,21 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 				return ctrl.Result{}, err
 			}
 
-			meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed policy to Vault"})
+			if p.Name == policy.Name && p.Policy == *policy.Spec.Policy {
+				events.Reapplied(r.Recorder, policy, policyPath(policy))
+			} else {
+				events.Pushed(r.Recorder, policy, policy.Status.Conditions, p.Name != "", "Policy", policyPath(policy))
+			}
+
+			policy.Status.ReconcileRequest = requestedAt
+			message = "Successfully pushed policy to Vault"
+		}
+
+		// The generation is applied even when Vault already matched it
+		policy.Status.ObservedGeneration = policy.Generation
+		policy.Status.VaultPath = policyPath(policy)
+		vault.SetCondition(&policy.Status.Conditions, policy.Generation, metav1.Condition{Type: typeConfiguredPolicy, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+		if !equality.Semantic.DeepEqual(status, &policy.Status) {
 			if err := r.Status().Update(ctx, policy); err != nil {
 				log.Error(err, "Failed to update Policy status")
 				return ctrl.Result{}, err
@@ -152,12 +204,65 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, nil
 }
 
+// observeVaultPolicy reports whether the policy in Vault matches the spec.
+func (r *PolicyReconciler) observeVaultPolicy(ctx context.Context, policy *sysv1beta1.Policy, p *vault.Policy) (ctrl.Result, error) {
+	condition := metav1.Condition{Type: typeInSyncPolicy, Status: metav1.ConditionTrue, Reason: "InSync", Message: "Policy in Vault matches the spec"}
+	if p.Name == "" {
+		condition = metav1.Condition{Type: typeInSyncPolicy, Status: metav1.ConditionFalse, Reason: "OutOfSync", Message: fmt.Sprintf("Policy %s does not exist in Vault", policy.Name)}
+	} else if p.Policy != *policy.Spec.Policy {
+		condition = metav1.Condition{Type: typeInSyncPolicy, Status: metav1.ConditionFalse, Reason: "OutOfSync", Message: "Policy in Vault differs from the spec:\n" + vault.Diff(p, &vault.Policy{Name: policy.Name, Policy: *policy.Spec.Policy})}
+	}
+
+	vault.SetCondition(&policy.Status.Conditions, policy.Generation, condition)
+	policy.Status.ObservedGeneration = policy.Generation
+	policy.Status.VaultPath = policyPath(policy)
+	policy.Status.ReconcileRequest = policy.Annotations[vault.ReconcileAtAnnotation]
+	if err := r.Status().Update(ctx, policy); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update Policy status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Plan returns the change applying the policy, or deleting it, makes in Vault.
+func (r *PolicyReconciler) Plan(ctx context.Context, obj client.Object, deleted bool) (*plan.Change, error) {
+	policy, ok := obj.(*sysv1beta1.Policy)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object %T", obj)
+	}
+
+	p, err := r.fetchVaultPolicy(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := &vault.Policy{Name: policy.Name, Policy: ptr.Deref(policy.Spec.Policy, "")}
+	change := &plan.Change{Path: policyPath(policy), Action: plan.ActionNone}
+	switch {
+	case deleted:
+		if p.Name != "" {
+			change.Action = plan.ActionDelete
+		}
+	case p.Name == "":
+		change.Action, change.Diff = plan.ActionCreate, vault.Diff(&vault.Policy{Name: policy.Name}, desired)
+	case p.Policy != desired.Policy:
+		change.Action, change.Diff = plan.ActionUpdate, vault.Diff(p, desired)
+	}
+	return change, nil
+}
+
+// policyPath returns the path of the policy in Vault.
+func policyPath(policy *sysv1beta1.Policy) string {
+	return fmt.Sprintf("sys/policies/acl/%s", policy.Name)
+}
+
 func (r *PolicyReconciler) deleteVaultPolicy(ctx context.Context, policy *sysv1beta1.Policy) error {
-	return r.Vault.Sys().DeletePolicyWithContext(ctx, policy.Name)
+	return r.Vault.DeletePolicy(ctx, policy.Name)
 }
 
 func (r *PolicyReconciler) fetchVaultPolicy(ctx context.Context, policy *sysv1beta1.Policy) (*vault.Policy, error) {
-	content, err := r.Vault.Sys().GetPolicyWithContext(ctx, policy.Name)
+	content, err := r.Vault.GetPolicy(ctx, policy.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +276,14 @@ func (r *PolicyReconciler) fetchVaultPolicy(ctx context.Context, policy *sysv1be
 }
 
 func (r *PolicyReconciler) updateVaultPolicy(ctx context.Context, policy *sysv1beta1.Policy) error {
-	return r.Vault.Sys().PutPolicyWithContext(ctx, policy.Name, *policy.Spec.Policy)
+	if err := r.Vault.PutPolicy(ctx, policy.Name, *policy.Spec.Policy); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	policy.Status.LastAppliedHash = vault.Checksum(*policy.Spec.Policy)
+	policy.Status.LastAppliedTime = &now
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
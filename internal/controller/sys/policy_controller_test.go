@@ -18,32 +18,60 @@ package sys
 
 import (
 	"context"
+	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+	"hopopops/vault-operator/internal/metrics"
 )
 
 var _ = Describe("Policy Controller", func() {
 	Context("When reconciling a resource", func() {
-		const resourceName = "test-resource"
+		const resourceName = "test-policy"
+		const document = `path "secret/data/foo" { capabilities = ["read"] }`
 
 		ctx := context.Background()
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
+		}
+
+		var controllerReconciler *PolicyReconciler
+		var recorder *record.FakeRecorder
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
 		}
-		policy := &sysv1beta1.Policy{}
 
 		BeforeEach(func() {
+			vaultServer.Reset()
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &PolicyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Vault:    vaultServer.Client(),
+				Recorder: recorder,
+			}
+
 			By("creating the custom resource for the Kind Policy")
+			policy := &sysv1beta1.Policy{}
 			err := k8sClient.Get(ctx, typeNamespacedName, policy)
 			if err != nil && errors.IsNotFound(err) {
 				resource := &sysv1beta1.Policy{
@@ -51,34 +79,222 @@ var _ = Describe("Policy Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: sysv1beta1.PolicySpec{
+						Policy: ptr.To(document),
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &sysv1beta1.Policy{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance Policy")
+			vaultServer.ClearFaults()
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
 		})
-		It("should successfully reconcile the resource", func() {
+
+		It("should push the policy to Vault", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &PolicyReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+			Expect(reconcileOnce()).To(Succeed())
+
+			rules, ok := vaultServer.Policy(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(rules).To(Equal(document))
+
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(policy.Finalizers).To(ContainElement(policyFinalizer))
+			Expect(meta.IsStatusConditionTrue(policy.Status.Conditions, typeConfiguredPolicy)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(policy.Status.Conditions, vault.ReadyCondition)).To(BeTrue())
+			Expect(meta.FindStatusCondition(policy.Status.Conditions, vault.ReadyCondition).ObservedGeneration).To(Equal(policy.Generation))
+			Expect(policy.Status.ObservedGeneration).To(Equal(policy.Generation))
+			Expect(policy.Status.VaultPath).To(Equal("sys/policies/acl/" + resourceName))
+			Expect(policy.Status.LastAppliedHash).To(Equal(vault.Checksum(document)))
+			Expect(policy.Status.LastAppliedTime).NotTo(BeNil())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created Created sys/policies/acl/" + resourceName + " in Vault")))
+		})
+
+		It("should update the policy when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Changing the policy document")
+			updated := `path "secret/data/bar" { capabilities = ["list"] }`
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			policy.Spec.Policy = ptr.To(updated)
+			Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			rules, _ := vaultServer.Policy(resourceName)
+			Expect(rules).To(Equal(updated))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(policy.Status.ObservedGeneration).To(Equal(policy.Generation))
+			Expect(policy.Status.LastAppliedHash).To(Equal(vault.Checksum(updated)))
+		})
+
+		It("should correct drift made directly in Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Modifying the policy behind the operator's back")
+			vaultServer.SetPolicy(resourceName, `path "*" { capabilities = ["sudo"] }`)
+			corrections := testutil.ToFloat64(metrics.DriftCorrections.WithLabelValues("Policy"))
+
+			Expect(reconcileOnce()).To(Succeed())
+			rules, _ := vaultServer.Policy(resourceName)
+			Expect(rules).To(Equal(document))
+			Expect(testutil.ToFloat64(metrics.DriftCorrections.WithLabelValues("Policy"))).To(Equal(corrections + 1))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal DriftCorrected")))
+		})
+
+		It("should report a failure to push the policy", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPut, Path: "sys/policies/acl", Status: http.StatusForbidden})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			condition := meta.FindStatusCondition(policy.Status.Conditions, typeConfiguredPolicy)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+			Expect(recorder.Events).To(Receive(Equal("Warning FailedToUpdate Failed to push policy to Vault at sys/policies/acl/" + resourceName + ": permission denied (HTTP 403)")))
+		})
+
+		It("should delete the policy from Vault when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Policy(resourceName)
+			Expect(ok).To(BeFalse())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleted sys/policies/acl/" + resourceName + " from Vault")))
+			err := k8sClient.Get(ctx, typeNamespacedName, policy)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should only report drift in observe mode", func() {
+			controllerReconciler.Mode = vault.ModeObserve
+			vaultServer.SetPolicy(resourceName, `path "*" { capabilities = ["sudo"] }`)
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			rules, _ := vaultServer.Policy(resourceName)
+			Expect(rules).To(Equal(`path "*" { capabilities = ["sudo"] }`))
+			for _, r := range vaultServer.Requests() {
+				Expect(r.Method).To(Equal(http.MethodGet))
 			}
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: typeNamespacedName,
-			})
-			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(policy.Finalizers).NotTo(ContainElement(policyFinalizer))
+			condition := meta.FindStatusCondition(policy.Status.Conditions, typeInSyncPolicy)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("OutOfSync"))
+			Expect(condition.Message).To(ContainSubstring(`policy: "path \"*\" { capabilities = [\"sudo\"] }" -> "path \"secret/data/foo\" { capabilities = [\"read\"] }"`))
+
+			By("Reporting the policy in sync once Vault matches the spec")
+			vaultServer.SetPolicy(resourceName, document)
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(policy.Status.Conditions, typeInSyncPolicy)).To(BeTrue())
+		})
+
+		It("should leave the policy in Vault when deleted with the observe annotation", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			policy.Annotations = map[string]string{vault.ModeAnnotation: string(vault.ModeObserve)}
+			Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			rules, ok := vaultServer.Policy(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(rules).To(Equal(document))
+			err := k8sClient.Get(ctx, typeNamespacedName, policy)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should neither push nor delete the policy while paused", func() {
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			policy.Annotations = map[string]string{vault.PausedAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Policy(resourceName)
+			Expect(ok).To(BeFalse())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(policy.Status.Conditions, vault.PausedCondition)).To(BeTrue())
+
+			By("Resuming the reconciliation")
+			policy.Annotations = nil
+			Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok = vaultServer.Policy(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(meta.FindStatusCondition(policy.Status.Conditions, vault.PausedCondition)).To(BeNil())
+
+			By("Deleting the resource while paused")
+			policy.Annotations = map[string]string{vault.PausedAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok = vaultServer.Policy(resourceName)
+			Expect(ok).To(BeTrue())
+			err := k8sClient.Get(ctx, typeNamespacedName, policy)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should push the policy again once per reconcile request", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			pushes := func() int {
+				n := 0
+				for _, r := range vaultServer.Requests() {
+					if r.Method == http.MethodPut && r.Path == "sys/policies/acl/"+resourceName {
+						n++
+					}
+				}
+				return n
+			}
+			Expect(pushes()).To(Equal(1))
+
+			policy := &sysv1beta1.Policy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			policy.Annotations = map[string]string{vault.ReconcileAtAnnotation: "2025-01-01T00:00:00Z"}
+			Expect(k8sClient.Update(ctx, policy)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(pushes()).To(Equal(2))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, policy)).To(Succeed())
+			Expect(policy.Status.ReconcileRequest).To(Equal("2025-01-01T00:00:00Z"))
 		})
 	})
 })
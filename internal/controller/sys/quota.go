@@ -0,0 +1,56 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+)
+
+// resolveQuotaScope returns the path and role a quota applies to, resolving
+// referenced mounts and roles once they are configured.
+func resolveQuotaScope(ctx context.Context, c client.Client, namespace string, scope sysv1beta1.QuotaScope) (string, string, error) {
+	switch {
+	case scope.AuthRef != nil:
+		auth := &sysv1beta1.Auth{}
+		if err := c.Get(ctx, types.NamespacedName{Name: scope.AuthRef.Name, Namespace: namespace}, auth); err != nil {
+			return "", "", fmt.Errorf("failed to get auth %s: %w", scope.AuthRef.Name, err)
+		}
+		if auth.Status.Accessor == "" {
+			return "", "", fmt.Errorf("auth %s is not ready", auth.Name)
+		}
+		return fmt.Sprintf("auth/%s/", auth.Name), "", nil
+	case scope.KubernetesRoleRef != nil:
+		role := &authv1beta1.KubernetesRole{}
+		if err := c.Get(ctx, types.NamespacedName{Name: scope.KubernetesRoleRef.Name, Namespace: namespace}, role); err != nil {
+			return "", "", fmt.Errorf("failed to get kubernetes role %s: %w", scope.KubernetesRoleRef.Name, err)
+		}
+		if !meta.IsStatusConditionTrue(role.Status.Conditions, "Configured") {
+			return "", "", fmt.Errorf("kubernetes role %s is not ready", role.Name)
+		}
+		return fmt.Sprintf("auth/%s/", role.Spec.AuthPath), role.Name, nil
+	default:
+		return scope.Path, "", nil
+	}
+}
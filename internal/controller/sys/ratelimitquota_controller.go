@@ -0,0 +1,280 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	rateLimitQuotaFinalizer = "ratelimitquota.sys.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredRateLimitQuota = "Configured"
+)
+
+// RateLimitQuotaReconciler reconciles a RateLimitQuota object
+type RateLimitQuotaReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=ratelimitquotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=ratelimitquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=ratelimitquotas/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=auths,verbs=get;list;watch
+// +kubebuilder:rbac:groups=auth.toolkit.vault.hopopops.com,resources=kubernetesroles,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The quota is named after the resource. Its path and role are resolved from
+// the referenced Auth or KubernetesRole, so that it follows them.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *RateLimitQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the RateLimitQuota instance
+	quota := &sysv1beta1.RateLimitQuota{}
+	if err := r.Get(ctx, req.NamespacedName, quota); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("RateLimitQuota resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get RateLimitQuota")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, quota, &quota.Status.Conditions, r.Mode, rateLimitQuotaFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag RateLimitQuota in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// RateLimitQuota Deletion
+	isQuotaMarkedToBeDeleted := quota.GetDeletionTimestamp() != nil
+	if isQuotaMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(quota, rateLimitQuotaFinalizer) {
+			if err := r.deleteVaultRateLimitQuota(ctx, quota); err != nil {
+				log.Error(err, "Failed to delete RateLimitQuota")
+				return ctrl.Result{}, err
+			}
+			events.Deleted(r.Recorder, quota, rateLimitQuotaPath(quota))
+
+			controllerutil.RemoveFinalizer(quota, rateLimitQuotaFinalizer)
+			if err := r.Update(ctx, quota); err != nil {
+				log.Error(err, "Failed to remove finalizer from RateLimitQuota")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// RateLimitQuota Initialization
+	if !controllerutil.ContainsFinalizer(quota, rateLimitQuotaFinalizer) {
+		controllerutil.AddFinalizer(quota, rateLimitQuotaFinalizer)
+		vault.SetCondition(&quota.Status.Conditions, quota.Generation, metav1.Condition{Type: typeConfiguredRateLimitQuota, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, quota); err != nil {
+			log.Error(err, "Failed to initialize RateLimitQuota status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	path, role, err := resolveQuotaScope(ctx, r.Client, quota.Namespace, quota.Spec.QuotaScope)
+	if err != nil {
+		log.Error(err, "Failed to resolve RateLimitQuota references")
+		vault.SetCondition(&quota.Status.Conditions, quota.Generation, metav1.Condition{Type: typeConfiguredRateLimitQuota, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to resolve references: %s", err)})
+		events.Failed(r.Recorder, quota, "FailedToFetch", "Failed to resolve references", "", err)
+		if err := r.Status().Update(ctx, quota); err != nil {
+			log.Error(err, "Failed to update RateLimitQuota status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create or update
+	current, err := r.fetchVaultRateLimitQuota(ctx, quota)
+	if err != nil {
+		log.Error(err, "Failed to fetch RateLimitQuota")
+		vault.SetCondition(&quota.Status.Conditions, quota.Generation, metav1.Condition{Type: typeConfiguredRateLimitQuota, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch quota from Vault"})
+		events.Failed(r.Recorder, quota, "FailedToFetch", "Failed to fetch quota from Vault", rateLimitQuotaPath(quota), err)
+		if err := r.Status().Update(ctx, quota); err != nil {
+			log.Error(err, "Failed to update RateLimitQuota status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&quota.Spec, path, role) {
+		if err := r.updateVaultRateLimitQuota(ctx, quota, path, role); err != nil {
+			log.Error(err, "Failed to update RateLimitQuota")
+			vault.SetCondition(&quota.Status.Conditions, quota.Generation, metav1.Condition{Type: typeConfiguredRateLimitQuota, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push quota to Vault: %s", err)})
+			events.Failed(r.Recorder, quota, "FailedToUpdate", "Failed to push quota to Vault", rateLimitQuotaPath(quota), err)
+			if err := r.Status().Update(ctx, quota); err != nil {
+				log.Error(err, "Failed to update RateLimitQuota status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, quota, quota.Status.Conditions, current != nil, "RateLimitQuota", rateLimitQuotaPath(quota))
+	}
+
+	quota.Status.ObservedGeneration = quota.Generation
+	quota.Status.VaultPath = rateLimitQuotaPath(quota)
+	vault.SetCondition(&quota.Status.Conditions, quota.Generation, metav1.Condition{Type: typeConfiguredRateLimitQuota, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed quota to Vault"})
+	if err := r.Status().Update(ctx, quota); err != nil {
+		log.Error(err, "Failed to update RateLimitQuota status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func rateLimitQuotaPath(quota *sysv1beta1.RateLimitQuota) string {
+	return fmt.Sprintf("sys/quotas/rate-limit/%s", quota.Name)
+}
+
+func (r *RateLimitQuotaReconciler) deleteVaultRateLimitQuota(ctx context.Context, quota *sysv1beta1.RateLimitQuota) error {
+	_, err := r.Vault.Delete(ctx, rateLimitQuotaPath(quota))
+	return err
+}
+
+func (r *RateLimitQuotaReconciler) fetchVaultRateLimitQuota(ctx context.Context, quota *sysv1beta1.RateLimitQuota) (*vault.RateLimitQuota, error) {
+	s, err := r.Vault.Read(ctx, rateLimitQuotaPath(quota))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var q vault.RateLimitQuota
+	if err := json.Unmarshal(jsonBytes, &q); err != nil {
+		return nil, err
+	}
+
+	return &q, nil
+}
+
+func (r *RateLimitQuotaReconciler) updateVaultRateLimitQuota(ctx context.Context, quota *sysv1beta1.RateLimitQuota, path, role string) error {
+	blockInterval := quota.Spec.BlockInterval
+	if blockInterval == "" {
+		blockInterval = "0"
+	}
+
+	data := map[string]interface{}{
+		"path":           path,
+		"role":           role,
+		"rate":           quota.Spec.Rate,
+		"interval":       quota.Spec.Interval,
+		"block_interval": blockInterval,
+	}
+	if _, err := r.Vault.Write(ctx, rateLimitQuotaPath(quota), data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	quota.Status.LastAppliedHash = vault.Checksum(data)
+	quota.Status.LastAppliedTime = &now
+	return nil
+}
+
+// findQuotasForAuth requeues the quotas applying to the mount of an Auth.
+func (r *RateLimitQuotaReconciler) findQuotasForAuth(ctx context.Context, auth client.Object) []reconcile.Request {
+	quotas := &sysv1beta1.RateLimitQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(auth.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list RateLimitQuotas")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, q := range quotas.Items {
+		if q.Spec.AuthRef != nil && q.Spec.AuthRef.Name == auth.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: q.Name, Namespace: q.Namespace}})
+		}
+	}
+	return requests
+}
+
+// findQuotasForKubernetesRole requeues the quotas applying to a role.
+func (r *RateLimitQuotaReconciler) findQuotasForKubernetesRole(ctx context.Context, role client.Object) []reconcile.Request {
+	quotas := &sysv1beta1.RateLimitQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(role.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list RateLimitQuotas")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, q := range quotas.Items {
+		if q.Spec.KubernetesRoleRef != nil && q.Spec.KubernetesRoleRef.Name == role.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: q.Name, Namespace: q.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RateLimitQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sysv1beta1.RateLimitQuota{}).
+		Watches(&sysv1beta1.Auth{}, handler.EnqueueRequestsFromMapFunc(r.findQuotasForAuth)).
+		Watches(&authv1beta1.KubernetesRole{}, handler.EnqueueRequestsFromMapFunc(r.findQuotasForKubernetesRole)).
+		Named("sys-ratelimitquota").
+		Complete(r)
+}
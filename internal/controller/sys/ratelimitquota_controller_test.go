@@ -0,0 +1,224 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"net/http"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+)
+
+var _ = Describe("RateLimitQuota Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-rate-limit-quota"
+		const authName = "test-quota-kubernetes"
+		const roleName = "test-quota-role"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *RateLimitQuotaReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		updateSpec := func(update func(*sysv1beta1.RateLimitQuotaSpec)) {
+			resource := &sysv1beta1.RateLimitQuota{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			update(&resource.Spec)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			v := vaultServer.Client()
+			controllerReconciler = &RateLimitQuotaReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  v,
+			}
+
+			By("preparing the referenced auth method and role")
+			Expect(v.EnableAuth(ctx, authName+"/", &vaultapi.EnableAuthOptions{Type: "kubernetes"})).To(Succeed())
+			mount, err := v.GetAuth(ctx, authName)
+			Expect(err).NotTo(HaveOccurred())
+
+			auth := &sysv1beta1.Auth{ObjectMeta: metav1.ObjectMeta{Name: authName, Namespace: "default"}}
+			Expect(k8sClient.Create(ctx, auth)).To(Succeed())
+			auth.Status.Accessor = mount.Accessor
+			Expect(k8sClient.Status().Update(ctx, auth)).To(Succeed())
+
+			role := &authv1beta1.KubernetesRole{
+				ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: "default"},
+				Spec: authv1beta1.KubernetesRoleSpec{
+					BoundServiceAccountNames: []string{"default"},
+					AuthPath:                 authName,
+				},
+			}
+			Expect(k8sClient.Create(ctx, role)).To(Succeed())
+			meta.SetStatusCondition(&role.Status.Conditions, metav1.Condition{Type: "Configured", Status: metav1.ConditionTrue, Reason: "Configured"})
+			Expect(k8sClient.Status().Update(ctx, role)).To(Succeed())
+
+			By("creating the custom resource for the Kind RateLimitQuota")
+			quota := &sysv1beta1.RateLimitQuota{}
+			err = k8sClient.Get(ctx, typeNamespacedName, quota)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &sysv1beta1.RateLimitQuota{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sysv1beta1.RateLimitQuotaSpec{
+						Rate:          100,
+						Interval:      "1s",
+						BlockInterval: "1m",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sysv1beta1.RateLimitQuota{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance RateLimitQuota")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+
+			Expect(k8sClient.Delete(ctx, &sysv1beta1.Auth{ObjectMeta: metav1.ObjectMeta{Name: authName, Namespace: "default"}})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &authv1beta1.KubernetesRole{ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: "default"}})).To(Succeed())
+		})
+
+		It("should create a global quota", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			q, ok := vaultServer.Quota("rate-limit", resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(q.Path).To(BeEmpty())
+			Expect(q.Rate).To(Equal(100.0))
+			Expect(q.Interval).To(Equal(1))
+			Expect(q.BlockInterval).To(Equal(60))
+
+			resource := &sysv1beta1.RateLimitQuota{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(rateLimitQuotaFinalizer))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredRateLimitQuota)).To(BeTrue())
+		})
+
+		It("should scope the quota to a referenced auth method", func() {
+			updateSpec(func(s *sysv1beta1.RateLimitQuotaSpec) {
+				s.AuthRef = &sysv1beta1.AuthReference{Name: authName}
+			})
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			q, ok := vaultServer.Quota("rate-limit", resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(q.Path).To(Equal("auth/" + authName + "/"))
+			Expect(q.Role).To(BeEmpty())
+		})
+
+		It("should scope the quota to a referenced role", func() {
+			updateSpec(func(s *sysv1beta1.RateLimitQuotaSpec) {
+				s.KubernetesRoleRef = &sysv1beta1.KubernetesRoleReference{Name: roleName}
+			})
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			q, ok := vaultServer.Quota("rate-limit", resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(q.Path).To(Equal("auth/" + authName + "/"))
+			Expect(q.Role).To(Equal(roleName))
+		})
+
+		It("should wait for references to be ready", func() {
+			role := &authv1beta1.KubernetesRole{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: roleName, Namespace: "default"}, role)).To(Succeed())
+			role.Status.Conditions = nil
+			Expect(k8sClient.Status().Update(ctx, role)).To(Succeed())
+			updateSpec(func(s *sysv1beta1.RateLimitQuotaSpec) {
+				s.KubernetesRoleRef = &sysv1beta1.KubernetesRoleReference{Name: roleName}
+			})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			resource := &sysv1beta1.RateLimitQuota{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredRateLimitQuota)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+			Expect(condition.Message).To(ContainSubstring("not ready"))
+		})
+
+		It("should correct drift made directly in Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, err := vaultServer.Client().Write(ctx, "sys/quotas/rate-limit/"+resourceName, map[string]interface{}{"rate": 1000})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+			q, _ := vaultServer.Quota("rate-limit", resourceName)
+			Expect(q.Rate).To(Equal(100.0))
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == "sys/quotas/rate-limit/"+resourceName {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(3))
+		})
+
+		It("should delete the quota from Vault when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			resource := &sysv1beta1.RateLimitQuota{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Quota("rate-limit", resourceName)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
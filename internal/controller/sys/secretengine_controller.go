@@ -0,0 +1,332 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	secretEngineFinalizer = "secretengine.sys.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredSecretEngine = "Configured"
+)
+
+// SecretEngineReconciler reconciles a SecretEngine object
+type SecretEngineReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=secretengines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=secretengines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=secretengines/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *SecretEngineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the SecretEngine instance
+	engine := &sysv1beta1.SecretEngine{}
+	if err := r.Get(ctx, req.NamespacedName, engine); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("SecretEngine resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get SecretEngine")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, engine, &engine.Status.Conditions, r.Mode, secretEngineFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag SecretEngine in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// SecretEngine Deletion
+	isEngineMarkedToBeDeleted := engine.GetDeletionTimestamp() != nil
+	if isEngineMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(engine, secretEngineFinalizer) {
+			// Only the secrets engine created or adopted, as told by its
+			// accessor, is disabled
+			if engine.Status.Accessor != "" {
+				if err := r.deleteVaultSecretEngine(ctx, engine); err != nil {
+					log.Error(err, "Failed to delete SecretEngine")
+					return ctrl.Result{}, err
+				}
+				events.Deleted(r.Recorder, engine, "sys/mounts/"+mountPath(engine))
+			}
+
+			controllerutil.RemoveFinalizer(engine, secretEngineFinalizer)
+			if err := r.Update(ctx, engine); err != nil {
+				log.Error(err, "Failed to remove finalizer from SecretEngine")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// SecretEngine Initialization
+	if !controllerutil.ContainsFinalizer(engine, secretEngineFinalizer) {
+		controllerutil.AddFinalizer(engine, secretEngineFinalizer)
+		vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, engine); err != nil {
+			log.Error(err, "Failed to initialize SecretEngine status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Create, or adopt the secrets engine of the type already enabled at the
+	// path when annotated to
+	if engine.Status.Accessor == "" {
+		existing, err := r.fetchVaultSecretEngine(ctx, engine)
+		if err != nil {
+			log.Error(err, "Failed to fetch SecretEngine")
+			vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch secrets engine from Vault"})
+			events.Failed(r.Recorder, engine, "FailedToFetch", "Failed to fetch secrets engine from Vault", "sys/mounts/"+mountPath(engine), err)
+			if err := r.Status().Update(ctx, engine); err != nil {
+				log.Error(err, "Failed to update SecretEngine status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		if existing != nil && (!vault.Adopts(engine.Annotations) || existing.Type != engine.Spec.Type) {
+			err := fmt.Errorf("%s secrets engine already enabled at %s", existing.Type, mountPath(engine))
+			log.Error(err, "Failed to create SecretEngine")
+			message := fmt.Sprintf("A %s secrets engine is already enabled, adopted only when of the same type and annotated with %s", existing.Type, vault.AdoptAnnotation)
+			vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionFalse, Reason: "AlreadyExists", Message: message})
+			events.Conflicted(r.Recorder, engine, "AlreadyExists", message, "sys/mounts/"+mountPath(engine))
+			if err := r.Status().Update(ctx, engine); err != nil {
+				log.Error(err, "Failed to update SecretEngine status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		if existing == nil {
+			if err := r.createVaultSecretEngine(ctx, engine); err != nil {
+				log.Error(err, "Failed to create SecretEngine")
+				vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: "Failed to enable secrets engine in Vault"})
+				events.Failed(r.Recorder, engine, "FailedToCreate", "Failed to enable secrets engine in Vault", "sys/mounts/"+mountPath(engine), err)
+				if err := r.Status().Update(ctx, engine); err != nil {
+					log.Error(err, "Failed to update SecretEngine status")
+					return ctrl.Result{}, err
+				}
+
+				return ctrl.Result{}, err
+			}
+			events.Pushed(r.Recorder, engine, engine.Status.Conditions, false, "SecretEngine", "sys/mounts/"+mountPath(engine))
+		}
+
+		m, err := r.Vault.GetMount(ctx, mountPath(engine))
+		if err != nil {
+			log.Error(err, "Failed to get secrets engine from Vault")
+			return ctrl.Result{}, err
+		}
+
+		// Set accessor for reference
+		engine.Status.Accessor = m.Accessor
+		engine.Status.ObservedGeneration = engine.Generation
+		engine.Status.VaultPath = "sys/mounts/" + mountPath(engine)
+		vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully enabled secrets engine in Vault"})
+		if err := r.Status().Update(ctx, engine); err != nil {
+			log.Error(err, "Failed to update SecretEngine status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// Tune in place
+	status := engine.Status.DeepCopy()
+	message := "Secrets engine in Vault matches the spec"
+	if m, err := r.Vault.GetMount(ctx, mountPath(engine)); err != nil {
+		log.Error(err, "Failed to fetch SecretEngine")
+		vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch secrets engine from Vault"})
+		events.Failed(r.Recorder, engine, "FailedToFetch", "Failed to fetch secrets engine from Vault", "sys/mounts/"+mountPath(engine), err)
+		if err := r.Status().Update(ctx, engine); err != nil {
+			log.Error(err, "Failed to update SecretEngine status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	} else if (*vault.SecretEngine)(m).IsDifferentFromSpec(&engine.Spec) {
+		if err := r.tuneVaultSecretEngine(ctx, engine); err != nil {
+			log.Error(err, "Failed to tune SecretEngine")
+			vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to tune secrets engine in Vault"})
+			events.Failed(r.Recorder, engine, "FailedToUpdate", "Failed to tune secrets engine in Vault", "sys/mounts/"+mountPath(engine), err)
+			if err := r.Status().Update(ctx, engine); err != nil {
+				log.Error(err, "Failed to update SecretEngine status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, engine, engine.Status.Conditions, true, "SecretEngine", "sys/mounts/"+mountPath(engine))
+		message = "Successfully tuned secrets engine in Vault"
+	}
+
+	engine.Status.ObservedGeneration = engine.Generation
+	engine.Status.VaultPath = "sys/mounts/" + mountPath(engine)
+	vault.SetCondition(&engine.Status.Conditions, engine.Generation, metav1.Condition{Type: typeConfiguredSecretEngine, Status: metav1.ConditionTrue, Reason: "Configured", Message: message})
+	if !equality.Semantic.DeepEqual(status, &engine.Status) {
+		if err := r.Status().Update(ctx, engine); err != nil {
+			log.Error(err, "Failed to update SecretEngine status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// mountPath returns the path the secrets engine is mounted at.
+func mountPath(engine *sysv1beta1.SecretEngine) string {
+	if engine.Spec.Path != "" {
+		return engine.Spec.Path
+	}
+	return engine.Name
+}
+
+// fetchVaultSecretEngine returns the secrets engine enabled at the path of the
+// resource, or nil. The mounts are listed, a missing mount being an error for
+// Vault when fetched on its own.
+func (r *SecretEngineReconciler) fetchVaultSecretEngine(ctx context.Context, engine *sysv1beta1.SecretEngine) (*vaultapi.MountOutput, error) {
+	s, err := r.Vault.Read(ctx, "sys/mounts")
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := map[string]*vaultapi.MountOutput{}
+	if s != nil {
+		jsonBytes, err := json.Marshal(s.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(jsonBytes, &mounts); err != nil {
+			return nil, err
+		}
+	}
+
+	return mounts[mountPath(engine)+"/"], nil
+}
+
+func (r *SecretEngineReconciler) deleteVaultSecretEngine(ctx context.Context, engine *sysv1beta1.SecretEngine) error {
+	return r.Vault.Unmount(ctx, mountPath(engine))
+}
+
+func (r *SecretEngineReconciler) createVaultSecretEngine(ctx context.Context, engine *sysv1beta1.SecretEngine) error {
+	input := &vaultapi.MountInput{
+		Type:                  engine.Spec.Type,
+		Description:           engine.Spec.Description,
+		Config:                mountConfigInput(engine),
+		Local:                 engine.Spec.Local,
+		SealWrap:              engine.Spec.SealWrap,
+		ExternalEntropyAccess: engine.Spec.ExternalEntropyAccess,
+		Options:               engine.Spec.Options,
+	}
+	if err := r.Vault.Mount(ctx, mountPath(engine), input); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	engine.Status.LastAppliedHash = vault.Checksum(input)
+	engine.Status.LastAppliedTime = &now
+	return nil
+}
+
+func (r *SecretEngineReconciler) tuneVaultSecretEngine(ctx context.Context, engine *sysv1beta1.SecretEngine) error {
+	config := mountConfigInput(engine)
+	config.Description = &engine.Spec.Description
+	config.Options = engine.Spec.Options
+
+	if err := r.Vault.TuneMount(ctx, mountPath(engine), config); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	engine.Status.LastAppliedHash = vault.Checksum(config)
+	engine.Status.LastAppliedTime = &now
+	return nil
+}
+
+func mountConfigInput(engine *sysv1beta1.SecretEngine) vaultapi.MountConfigInput {
+	c := engine.Spec.Config
+	return vaultapi.MountConfigInput{
+		DefaultLeaseTTL:           valueOrEmpty(c.DefaultLeaseTTL),
+		MaxLeaseTTL:               valueOrEmpty(c.MaxLeaseTTL),
+		AuditNonHMACRequestKeys:   c.AuditNonHMACRequestKeys,
+		AuditNonHMACResponseKeys:  c.AuditNonHMACResponseKeys,
+		ListingVisibility:         valueOrEmpty(c.ListingVisibility),
+		PassthroughRequestHeaders: c.PassthroughRequestHeaders,
+		AllowedResponseHeaders:    c.AllowedResponseHeaders,
+		PluginVersion:             valueOrEmpty(c.PluginVersion),
+		IdentityTokenKey:          valueOrEmpty(c.IdentityTokenKey),
+	}
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SecretEngineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sysv1beta1.SecretEngine{}).
+		Named("sys-secretengine").
+		Complete(r)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("SecretEngine Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-engine"
+		const enginePath = "secret"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *SecretEngineReconciler
+
+		reconcileOnce := func() error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			return err
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			controllerReconciler = &SecretEngineReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind SecretEngine")
+			engine := &sysv1beta1.SecretEngine{}
+			err := k8sClient.Get(ctx, typeNamespacedName, engine)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &sysv1beta1.SecretEngine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sysv1beta1.SecretEngineSpec{
+						Type:        "kv",
+						Path:        enginePath,
+						Description: "application secrets",
+						Options:     map[string]string{"version": "2"},
+						Config: sysv1beta1.SecretEngineConfig{
+							DefaultLeaseTTL: ptr.To("1h"),
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &sysv1beta1.SecretEngine{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance SecretEngine")
+			vaultServer.ClearFaults()
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+		})
+
+		It("should mount the secrets engine in Vault", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce()).To(Succeed())
+
+			mount, ok := vaultServer.Mount(enginePath)
+			Expect(ok).To(BeTrue())
+			Expect(mount.Type).To(Equal("kv"))
+			Expect(mount.Description).To(Equal("application secrets"))
+			Expect(mount.Options).To(HaveKeyWithValue("version", "2"))
+			Expect(mount.Config.DefaultLeaseTTL).To(Equal(3600))
+
+			engine := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, engine)).To(Succeed())
+			Expect(engine.Finalizers).To(ContainElement(secretEngineFinalizer))
+			Expect(engine.Status.Accessor).To(Equal(mount.Accessor))
+			Expect(meta.IsStatusConditionTrue(engine.Status.Conditions, typeConfiguredSecretEngine)).To(BeTrue())
+		})
+
+		It("should not tune a secrets engine that is in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			tunes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPost && r.Path == "sys/mounts/"+enginePath+"/tune" {
+					tunes++
+				}
+			}
+			Expect(tunes).To(Equal(0))
+		})
+
+		It("should tune the secrets engine in place when the spec changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			mount, _ := vaultServer.Mount(enginePath)
+			accessor := mount.Accessor
+
+			By("Raising the maximum lease TTL")
+			engine := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, engine)).To(Succeed())
+			engine.Spec.Config.MaxLeaseTTL = ptr.To("24h")
+			engine.Spec.Description = "tuned"
+			Expect(k8sClient.Update(ctx, engine)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			mount, _ = vaultServer.Mount(enginePath)
+			Expect(mount.Accessor).To(Equal(accessor))
+			Expect(mount.Description).To(Equal("tuned"))
+			Expect(mount.Config.MaxLeaseTTL).To(Equal(86400))
+		})
+
+		It("should correct drift made directly in Vault", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			By("Tuning the mount behind the operator's back")
+			Expect(vaultServer.Client().TuneMount(ctx, enginePath, vaultapi.MountConfigInput{
+				DefaultLeaseTTL: "5m",
+			})).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			mount, _ := vaultServer.Mount(enginePath)
+			Expect(mount.Config.DefaultLeaseTTL).To(Equal(3600))
+		})
+
+		It("should report a failure to mount the secrets engine", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodPost, Path: "sys/mounts/" + enginePath, Status: http.StatusForbidden})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			engine := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, engine)).To(Succeed())
+			Expect(engine.Status.Accessor).To(BeEmpty())
+			condition := meta.FindStatusCondition(engine.Status.Conditions, typeConfiguredSecretEngine)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToCreate"))
+		})
+
+		It("should unmount the secrets engine when the resource is deleted", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			engine := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, engine)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, engine)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Mount(enginePath)
+			Expect(ok).To(BeFalse())
+			err := k8sClient.Get(ctx, typeNamespacedName, engine)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should adopt and tune the secrets engine already mounted when annotated", func() {
+			Expect(vaultServer.Client().Mount(ctx, enginePath, &vaultapi.MountInput{Type: "kv", Options: map[string]string{"version": "2"}})).To(Succeed())
+			existing, _ := vaultServer.Mount(enginePath)
+
+			resource := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{vault.AdoptAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			engine := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, engine)).To(Succeed())
+			Expect(engine.Status.Accessor).To(Equal(existing.Accessor))
+			mount, _ := vaultServer.Mount(enginePath)
+			Expect(mount.Description).To(Equal("application secrets"))
+			Expect(mount.Config.DefaultLeaseTTL).To(Equal(3600))
+		})
+
+		It("should leave alone the secrets engine already mounted when not annotated", func() {
+			Expect(vaultServer.Client().Mount(ctx, enginePath, &vaultapi.MountInput{Type: "kv", Description: "foreign"})).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			engine := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, engine)).To(Succeed())
+			Expect(engine.Status.Accessor).To(BeEmpty())
+			Expect(meta.FindStatusCondition(engine.Status.Conditions, typeConfiguredSecretEngine).Reason).To(Equal("AlreadyExists"))
+			mount, _ := vaultServer.Mount(enginePath)
+			Expect(mount.Description).To(Equal("foreign"))
+
+			By("deleting the resource")
+			Expect(k8sClient.Delete(ctx, engine)).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.Mount(enginePath)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should report the secrets engine of another type mounted at its path", func() {
+			Expect(vaultServer.Client().Mount(ctx, enginePath, &vaultapi.MountInput{Type: "transit"})).To(Succeed())
+
+			resource := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{vault.AdoptAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			engine := &sysv1beta1.SecretEngine{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, engine)).To(Succeed())
+			Expect(engine.Status.Accessor).To(BeEmpty())
+			Expect(meta.FindStatusCondition(engine.Status.Conditions, typeConfiguredSecretEngine).Reason).To(Equal("AlreadyExists"))
+			mount, _ := vaultServer.Mount(enginePath)
+			Expect(mount.Type).To(Equal("transit"))
+		})
+	})
+})
@@ -0,0 +1,382 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	vaultNamespaceFinalizer = "vaultnamespace.sys.toolkit.vault.hopopops.com/finalizer"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredVaultNamespace = "Configured"
+)
+
+// builtinNamespaceMountTypes are the types of the mounts Vault enables in
+// every namespace, which do not prevent its deletion.
+var builtinNamespaceMountTypes = map[string]bool{
+	"ns_cubbyhole": true,
+	"ns_identity":  true,
+	"ns_system":    true,
+	"ns_token":     true,
+}
+
+// VaultNamespaceReconciler reconciles a VaultNamespace object
+type VaultNamespaceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=vaultnamespaces,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=vaultnamespaces/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sys.toolkit.vault.hopopops.com,resources=vaultnamespaces/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// The namespace is named after the resource and created in its parent, given
+// as a path or resolved from the referenced VaultNamespace. Requests are made
+// in the parent by prefixing their path with it. The namespace is only deleted
+// while it contains no secrets engine or auth method, unless forced.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *VaultNamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the VaultNamespace instance
+	namespace := &sysv1beta1.VaultNamespace{}
+	if err := r.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("VaultNamespace resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get VaultNamespace")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, namespace, &namespace.Status.Conditions, r.Mode, vaultNamespaceFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag VaultNamespace in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// VaultNamespace Deletion
+	isNamespaceMarkedToBeDeleted := namespace.GetDeletionTimestamp() != nil
+	if isNamespaceMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(namespace, vaultNamespaceFinalizer) {
+			if err := r.deleteVaultNamespace(ctx, namespace); err != nil {
+				log.Error(err, "Failed to delete VaultNamespace")
+				vault.SetCondition(&namespace.Status.Conditions, namespace.Generation, metav1.Condition{Type: typeConfiguredVaultNamespace, Status: metav1.ConditionFalse, Reason: "FailedToDelete", Message: fmt.Sprintf("Failed to delete namespace from Vault: %s", err)})
+				events.Failed(r.Recorder, namespace, "FailedToDelete", "Failed to delete namespace from Vault", namespace.Status.Path, err)
+				if err := r.Status().Update(ctx, namespace); err != nil {
+					log.Error(err, "Failed to update VaultNamespace status")
+					return ctrl.Result{}, err
+				}
+
+				return ctrl.Result{}, err
+			}
+			if namespace.Status.Path != "" {
+				events.Deleted(r.Recorder, namespace, namespace.Status.Path)
+			}
+
+			controllerutil.RemoveFinalizer(namespace, vaultNamespaceFinalizer)
+			if err := r.Update(ctx, namespace); err != nil {
+				log.Error(err, "Failed to remove finalizer from VaultNamespace")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// VaultNamespace Initialization
+	if !controllerutil.ContainsFinalizer(namespace, vaultNamespaceFinalizer) {
+		controllerutil.AddFinalizer(namespace, vaultNamespaceFinalizer)
+		vault.SetCondition(&namespace.Status.Conditions, namespace.Generation, metav1.Condition{Type: typeConfiguredVaultNamespace, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, namespace); err != nil {
+			log.Error(err, "Failed to initialize VaultNamespace status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Resolve the parent
+	parent, err := r.resolveParent(ctx, namespace)
+	if err != nil {
+		log.Error(err, "Failed to resolve VaultNamespace references")
+		vault.SetCondition(&namespace.Status.Conditions, namespace.Generation, metav1.Condition{Type: typeConfiguredVaultNamespace, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: fmt.Sprintf("Failed to resolve references: %s", err)})
+		events.Failed(r.Recorder, namespace, "FailedToFetch", "Failed to resolve references", "", err)
+		if err := r.Status().Update(ctx, namespace); err != nil {
+			log.Error(err, "Failed to update VaultNamespace status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create or update
+	current, err := r.fetchVaultNamespace(ctx, parent, namespace.Name)
+	if err != nil {
+		log.Error(err, "Failed to fetch VaultNamespace")
+		vault.SetCondition(&namespace.Status.Conditions, namespace.Generation, metav1.Condition{Type: typeConfiguredVaultNamespace, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch namespace from Vault"})
+		events.Failed(r.Recorder, namespace, "FailedToFetch", "Failed to fetch namespace from Vault", namespacedPath(parent, namespace.Name), err)
+		if err := r.Status().Update(ctx, namespace); err != nil {
+			log.Error(err, "Failed to update VaultNamespace status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if current == nil || current.IsDifferentFromSpec(&namespace.Spec) {
+		existed := current != nil
+		if current, err = r.updateVaultNamespace(ctx, namespace, parent, current); err != nil {
+			log.Error(err, "Failed to update VaultNamespace")
+			vault.SetCondition(&namespace.Status.Conditions, namespace.Generation, metav1.Condition{Type: typeConfiguredVaultNamespace, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push namespace to Vault: %s", err)})
+			events.Failed(r.Recorder, namespace, "FailedToUpdate", "Failed to push namespace to Vault", namespacedPath(parent, namespace.Name), err)
+			if err := r.Status().Update(ctx, namespace); err != nil {
+				log.Error(err, "Failed to update VaultNamespace status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, namespace, namespace.Status.Conditions, existed, "VaultNamespace", namespacedPath(parent, namespace.Name))
+	}
+
+	namespace.Status.ID = current.ID
+	namespace.Status.Path = namespacedPath(parent, namespace.Name)
+	namespace.Status.ObservedGeneration = namespace.Generation
+	namespace.Status.VaultPath = namespacedPath(parent, "sys/namespaces/"+namespace.Name)
+	vault.SetCondition(&namespace.Status.Conditions, namespace.Generation, metav1.Condition{Type: typeConfiguredVaultNamespace, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully pushed namespace to Vault"})
+	if err := r.Status().Update(ctx, namespace); err != nil {
+		log.Error(err, "Failed to update VaultNamespace status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// namespacedPath prefixes a path with the namespace it is relative to, the
+// root namespace being empty.
+func namespacedPath(namespace, p string) string {
+	if namespace == "" {
+		return p
+	}
+	return namespace + "/" + p
+}
+
+// resolveParent returns the path of the namespace to create the namespace in.
+func (r *VaultNamespaceReconciler) resolveParent(ctx context.Context, namespace *sysv1beta1.VaultNamespace) (string, error) {
+	if namespace.Spec.ParentRef == nil {
+		return strings.Trim(namespace.Spec.Parent, "/"), nil
+	}
+
+	parent := &sysv1beta1.VaultNamespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace.Spec.ParentRef.Name, Namespace: namespace.Namespace}, parent); err != nil {
+		return "", fmt.Errorf("failed to get vault namespace %s: %w", namespace.Spec.ParentRef.Name, err)
+	}
+	if parent.Status.Path == "" || !meta.IsStatusConditionTrue(parent.Status.Conditions, typeConfiguredVaultNamespace) {
+		return "", fmt.Errorf("vault namespace %s is not ready", parent.Name)
+	}
+	return parent.Status.Path, nil
+}
+
+func (r *VaultNamespaceReconciler) deleteVaultNamespace(ctx context.Context, namespace *sysv1beta1.VaultNamespace) error {
+	// Never created
+	if namespace.Status.Path == "" {
+		return nil
+	}
+
+	parent := ""
+	if i := strings.LastIndex(namespace.Status.Path, "/"); i >= 0 {
+		parent = namespace.Status.Path[:i]
+	}
+	current, err := r.fetchVaultNamespace(ctx, parent, namespace.Name)
+	if err != nil || current == nil {
+		return err
+	}
+
+	if !namespace.Spec.ForceDeletion {
+		mounts, err := r.fetchVaultNamespaceMounts(ctx, namespace.Status.Path)
+		if err != nil {
+			return err
+		}
+		if len(mounts) > 0 {
+			return fmt.Errorf("namespace still contains %s, set forceDeletion to delete them with it", strings.Join(mounts, ", "))
+		}
+	}
+
+	_, err = r.Vault.Delete(ctx, namespacedPath(parent, "sys/namespaces/"+namespace.Name))
+	return err
+}
+
+func (r *VaultNamespaceReconciler) fetchVaultNamespace(ctx context.Context, parent, name string) (*vault.VaultNamespace, error) {
+	s, err := r.Vault.Read(ctx, namespacedPath(parent, "sys/namespaces/"+name))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var n vault.VaultNamespace
+	if err := json.Unmarshal(jsonBytes, &n); err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// fetchVaultNamespaceMounts returns the secrets engines and auth methods
+// enabled in the namespace, the builtin ones aside.
+func (r *VaultNamespaceReconciler) fetchVaultNamespaceMounts(ctx context.Context, path string) ([]string, error) {
+	var mounts []string
+	for _, p := range []string{"sys/mounts", "sys/auth"} {
+		s, err := r.Vault.Read(ctx, namespacedPath(path, p))
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			continue
+		}
+
+		jsonBytes, err := json.Marshal(s.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		var all map[string]vaultapi.MountOutput
+		if err := json.Unmarshal(jsonBytes, &all); err != nil {
+			return nil, err
+		}
+
+		for mount, m := range all {
+			if builtinNamespaceMountTypes[m.Type] {
+				continue
+			}
+			if p == "sys/auth" {
+				mount = "auth/" + mount
+			}
+			mounts = append(mounts, mount)
+		}
+	}
+	sort.Strings(mounts)
+	return mounts, nil
+}
+
+// updateVaultNamespace creates the namespace, or patches its custom metadata
+// with the keys removed from the spec set to null.
+func (r *VaultNamespaceReconciler) updateVaultNamespace(ctx context.Context, namespace *sysv1beta1.VaultNamespace, parent string, current *vault.VaultNamespace) (*vault.VaultNamespace, error) {
+	p := namespacedPath(parent, "sys/namespaces/"+namespace.Name)
+	metadata := map[string]interface{}{}
+	for k, v := range namespace.Spec.CustomMetadata {
+		metadata[k] = v
+	}
+
+	data := map[string]interface{}{"custom_metadata": metadata}
+	if current == nil {
+		if _, err := r.Vault.Write(ctx, p, data); err != nil {
+			return nil, err
+		}
+	} else {
+		for k := range current.CustomMetadata {
+			if _, ok := metadata[k]; !ok {
+				metadata[k] = nil
+			}
+		}
+		if _, err := r.Vault.JSONMergePatch(ctx, p, data); err != nil {
+			return nil, err
+		}
+	}
+
+	now := metav1.Now()
+	namespace.Status.LastAppliedHash = vault.Checksum(data)
+	namespace.Status.LastAppliedTime = &now
+
+	updated, err := r.fetchVaultNamespace(ctx, parent, namespace.Name)
+	if err == nil && updated == nil {
+		err = fmt.Errorf("namespace %s not found after update", p)
+	}
+	return updated, err
+}
+
+// findNamespacesForParent requeues the namespaces created in a VaultNamespace.
+func (r *VaultNamespaceReconciler) findNamespacesForParent(ctx context.Context, parent client.Object) []reconcile.Request {
+	namespaces := &sysv1beta1.VaultNamespaceList{}
+	if err := r.List(ctx, namespaces, client.InNamespace(parent.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list VaultNamespaces")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, n := range namespaces.Items {
+		if n.Spec.ParentRef != nil && n.Spec.ParentRef.Name == parent.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: n.Name, Namespace: n.Namespace}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VaultNamespaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&sysv1beta1.VaultNamespace{}).
+		Watches(&sysv1beta1.VaultNamespace{}, handler.EnqueueRequestsFromMapFunc(r.findNamespacesForParent)).
+		Named("sys-vaultnamespace").
+		Complete(r)
+}
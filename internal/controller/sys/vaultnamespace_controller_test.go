@@ -0,0 +1,195 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sys
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+)
+
+var _ = Describe("VaultNamespace Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-tenants"
+		const childName = "test-team-a"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		childNamespacedName := types.NamespacedName{
+			Name:      childName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *VaultNamespaceReconciler
+
+		reconcileOnce := func(name types.NamespacedName) error {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: name,
+			})
+			return err
+		}
+
+		getNamespace := func(name types.NamespacedName) *sysv1beta1.VaultNamespace {
+			resource := &sysv1beta1.VaultNamespace{}
+			Expect(k8sClient.Get(ctx, name, resource)).To(Succeed())
+			return resource
+		}
+
+		createChild := func() {
+			child := &sysv1beta1.VaultNamespace{
+				ObjectMeta: metav1.ObjectMeta{Name: childName, Namespace: "default"},
+				Spec: sysv1beta1.VaultNamespaceSpec{
+					ParentRef:      &sysv1beta1.VaultNamespaceReference{Name: resourceName},
+					CustomMetadata: map[string]string{"team": "a"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, child)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			controllerReconciler = &VaultNamespaceReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Vault:  vaultServer.Client(),
+			}
+
+			By("creating the custom resource for the Kind VaultNamespace")
+			namespace := &sysv1beta1.VaultNamespace{}
+			err := k8sClient.Get(ctx, typeNamespacedName, namespace)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &sysv1beta1.VaultNamespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: sysv1beta1.VaultNamespaceSpec{
+						CustomMetadata: map[string]string{"owner": "platform", "tier": "shared"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			vaultServer.ClearFaults()
+			for _, name := range []types.NamespacedName{childNamespacedName, typeNamespacedName} {
+				resource := &sysv1beta1.VaultNamespace{}
+				if err := k8sClient.Get(ctx, name, resource); err == nil {
+					By("Cleanup the specific resource instance VaultNamespace")
+					resource.Spec.ForceDeletion = true
+					Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+					Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+					Expect(reconcileOnce(name)).To(Succeed())
+				}
+			}
+		})
+
+		It("should create the namespace with its custom metadata", func() {
+			By("Reconciling the created resource")
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+
+			metadata, ok := vaultServer.NamespaceMetadata(resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(metadata).To(Equal(map[string]string{"owner": "platform", "tier": "shared"}))
+
+			resource := getNamespace(typeNamespacedName)
+			Expect(resource.Finalizers).To(ContainElement(vaultNamespaceFinalizer))
+			Expect(resource.Status.Path).To(Equal(resourceName))
+			Expect(resource.Status.ID).NotTo(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, typeConfiguredVaultNamespace)).To(BeTrue())
+		})
+
+		It("should update the custom metadata", func() {
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+
+			resource := getNamespace(typeNamespacedName)
+			resource.Spec.CustomMetadata = map[string]string{"owner": "security"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+
+			metadata, _ := vaultServer.NamespaceMetadata(resourceName)
+			Expect(metadata).To(Equal(map[string]string{"owner": "security"}))
+		})
+
+		It("should create nested namespaces in the referenced parent", func() {
+			createChild()
+
+			By("waiting for the parent to be ready")
+			Expect(reconcileOnce(childNamespacedName)).NotTo(Succeed())
+			condition := meta.FindStatusCondition(getNamespace(childNamespacedName).Status.Conditions, typeConfiguredVaultNamespace)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+			Expect(condition.Message).To(ContainSubstring("not ready"))
+
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+			Expect(reconcileOnce(childNamespacedName)).To(Succeed())
+
+			Expect(getNamespace(childNamespacedName).Status.Path).To(Equal(resourceName + "/" + childName))
+			metadata, ok := vaultServer.NamespaceMetadata(resourceName + "/" + childName)
+			Expect(ok).To(BeTrue())
+			Expect(metadata).To(Equal(map[string]string{"team": "a"}))
+		})
+
+		It("should refuse to delete a namespace that still contains mounts unless forced", func() {
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+			_, err := vaultServer.Client().Write(ctx, resourceName+"/sys/mounts/secret", map[string]interface{}{"type": "kv"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Delete(ctx, getNamespace(typeNamespacedName))).To(Succeed())
+			Expect(reconcileOnce(typeNamespacedName)).NotTo(Succeed())
+
+			resource := getNamespace(typeNamespacedName)
+			condition := meta.FindStatusCondition(resource.Status.Conditions, typeConfiguredVaultNamespace)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToDelete"))
+			Expect(condition.Message).To(ContainSubstring("secret/"))
+			_, ok := vaultServer.NamespaceMetadata(resourceName)
+			Expect(ok).To(BeTrue())
+
+			resource.Spec.ForceDeletion = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+
+			_, ok = vaultServer.NamespaceMetadata(resourceName)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should delete the namespace from Vault when the resource is deleted", func() {
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, getNamespace(typeNamespacedName))).To(Succeed())
+			Expect(reconcileOnce(typeNamespacedName)).To(Succeed())
+
+			_, ok := vaultServer.NamespaceMetadata(resourceName)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
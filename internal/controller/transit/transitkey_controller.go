@@ -0,0 +1,335 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	transitv1beta1 "hopopops/vault-operator/api/transit/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/events"
+)
+
+const (
+	transitKeyFinalizer = "transitkey.transit.toolkit.vault.hopopops.com/finalizer"
+
+	// rotateAnnotation triggers a rotation of the key whenever its value
+	// changes.
+	rotateAnnotation = "transit.toolkit.vault.hopopops.com/rotate"
+)
+
+// Definitions to manage status conditions
+const (
+	typeConfiguredTransitKey = "Configured"
+)
+
+// TransitKeyReconciler reconciles a TransitKey object
+type TransitKeyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Vault    vault.Interface
+	Recorder record.EventRecorder
+	Mode     vault.Mode
+}
+
+// +kubebuilder:rbac:groups=transit.toolkit.vault.hopopops.com,resources=transitkeys,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=transit.toolkit.vault.hopopops.com,resources=transitkeys/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=transit.toolkit.vault.hopopops.com,resources=transitkeys/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// Keys are only deleted from Vault when deletionAllowed is set, otherwise they
+// are kept along with everything they encrypted.
+//
+// Observe mode is not supported: the resource is only flagged by its
+// Configured condition, and its finalizer leaves Vault untouched.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *TransitKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Fetch the TransitKey instance
+	key := &transitv1beta1.TransitKey{}
+	if err := r.Get(ctx, req.NamespacedName, key); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("TransitKey resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get TransitKey")
+		return ctrl.Result{}, err
+	}
+
+	// Observe mode is not supported, the resource is only flagged
+	if skip, err := vault.SkipUnsupportedMode(ctx, r.Client, r.Recorder, key, &key.Status.Conditions, r.Mode, transitKeyFinalizer); skip || err != nil {
+		if err != nil {
+			log.Error(err, "Failed to flag TransitKey in observe mode")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// TransitKey Deletion
+	isKeyMarkedToBeDeleted := key.GetDeletionTimestamp() != nil
+	if isKeyMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(key, transitKeyFinalizer) {
+			if key.Spec.DeletionAllowed {
+				if err := r.deleteVaultTransitKey(ctx, key); err != nil {
+					log.Error(err, "Failed to delete TransitKey")
+					return ctrl.Result{}, err
+				}
+				events.Deleted(r.Recorder, key, transitKeyPath(key))
+			} else {
+				log.Info("Keeping TransitKey in Vault since deletionAllowed is not set")
+			}
+
+			controllerutil.RemoveFinalizer(key, transitKeyFinalizer)
+			if err := r.Update(ctx, key); err != nil {
+				log.Error(err, "Failed to remove finalizer from TransitKey")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// TransitKey Initialization
+	if !controllerutil.ContainsFinalizer(key, transitKeyFinalizer) {
+		controllerutil.AddFinalizer(key, transitKeyFinalizer)
+		vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredTransitKey, Status: metav1.ConditionUnknown, Reason: "Reconciling", Message: "Starting reconciliation"})
+		if err := r.Update(ctx, key); err != nil {
+			log.Error(err, "Failed to initialize TransitKey status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	current, err := r.fetchVaultTransitKey(ctx, key)
+	if err != nil {
+		log.Error(err, "Failed to fetch TransitKey")
+		vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredTransitKey, Status: metav1.ConditionFalse, Reason: "FailedToFetch", Message: "Failed to fetch transit key from Vault"})
+		events.Failed(r.Recorder, key, "FailedToFetch", "Failed to fetch transit key from Vault", transitKeyPath(key), err)
+		if err := r.Status().Update(ctx, key); err != nil {
+			log.Error(err, "Failed to update TransitKey status")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Create
+	if current == nil {
+		if current, err = r.createVaultTransitKey(ctx, key); err != nil {
+			log.Error(err, "Failed to create TransitKey")
+			vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredTransitKey, Status: metav1.ConditionFalse, Reason: "FailedToCreate", Message: "Failed to create transit key in Vault"})
+			events.Failed(r.Recorder, key, "FailedToCreate", "Failed to create transit key in Vault", transitKeyPath(key), err)
+			if err := r.Status().Update(ctx, key); err != nil {
+				log.Error(err, "Failed to update TransitKey status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		events.Pushed(r.Recorder, key, key.Status.Conditions, false, "TransitKey", transitKeyPath(key))
+	}
+
+	// On-demand rotation, before the configuration which may require the new
+	// version
+	if request := key.Annotations[rotateAnnotation]; request != "" && request != key.Status.RotationRequest {
+		if current, err = r.rotateVaultTransitKey(ctx, key); err != nil {
+			log.Error(err, "Failed to rotate TransitKey")
+			vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredTransitKey, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: "Failed to rotate transit key"})
+			events.Failed(r.Recorder, key, "FailedToUpdate", "Failed to rotate transit key", transitKeyPath(key)+"/rotate", err)
+			if err := r.Status().Update(ctx, key); err != nil {
+				log.Error(err, "Failed to update TransitKey status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+		log.Info("Rotated TransitKey", "request", request, "version", current.LatestVersion)
+		events.Rotated(r.Recorder, key, transitKeyPath(key)+"/rotate")
+		key.Status.RotationRequest = request
+	}
+
+	// Update
+	if current.IsDifferentFromSpec(&key.Spec) {
+		if err := r.updateVaultTransitKey(ctx, key); err != nil {
+			log.Error(err, "Failed to update TransitKey")
+			vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredTransitKey, Status: metav1.ConditionFalse, Reason: "FailedToUpdate", Message: fmt.Sprintf("Failed to push transit key configuration to Vault: %s", err)})
+			events.Failed(r.Recorder, key, "FailedToUpdate", "Failed to push transit key configuration to Vault", transitKeyPath(key), err)
+			if err := r.Status().Update(ctx, key); err != nil {
+				log.Error(err, "Failed to update TransitKey status")
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		events.Pushed(r.Recorder, key, key.Status.Conditions, true, "TransitKey", transitKeyPath(key)+"/config")
+	}
+
+	key.Status.LatestVersion = current.LatestVersion
+	key.Status.ObservedGeneration = key.Generation
+	key.Status.VaultPath = transitKeyPath(key)
+	vault.SetCondition(&key.Status.Conditions, key.Generation, metav1.Condition{Type: typeConfiguredTransitKey, Status: metav1.ConditionTrue, Reason: "Configured", Message: "Successfully configured transit key in Vault"})
+	if err := r.Status().Update(ctx, key); err != nil {
+		log.Error(err, "Failed to update TransitKey status")
+		return ctrl.Result{}, err
+	}
+
+	// Vault rotates the key on its own, check back for the latest version
+	if period, err := vault.ParseTTL(key.Spec.AutoRotatePeriod); err == nil && period > 0 {
+		return ctrl.Result{RequeueAfter: time.Duration(period) * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func transitKeyPath(key *transitv1beta1.TransitKey) string {
+	return fmt.Sprintf("%s/keys/%s", key.Spec.Mount, key.Name)
+}
+
+func (r *TransitKeyReconciler) fetchVaultTransitKey(ctx context.Context, key *transitv1beta1.TransitKey) (*vault.TransitKey, error) {
+	s, err := r.Vault.Read(ctx, transitKeyPath(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return nil, nil
+	}
+
+	return toTransitKey(s.Data)
+}
+
+func toTransitKey(data map[string]interface{}) (*vault.TransitKey, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var k vault.TransitKey
+	if err := json.Unmarshal(jsonBytes, &k); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// createVaultTransitKey creates the key with the parameters that can only be
+// set at creation. The rest of its configuration is pushed afterwards.
+func (r *TransitKeyReconciler) createVaultTransitKey(ctx context.Context, key *transitv1beta1.TransitKey) (*vault.TransitKey, error) {
+	data := map[string]interface{}{
+		"type":                   key.Spec.Type,
+		"exportable":             key.Spec.Exportable,
+		"allow_plaintext_backup": key.Spec.AllowPlaintextBackup,
+	}
+	if _, err := r.Vault.Write(ctx, transitKeyPath(key), data); err != nil {
+		return nil, err
+	}
+
+	now := metav1.Now()
+	key.Status.LastAppliedHash = vault.Checksum(data)
+	key.Status.LastAppliedTime = &now
+
+	current, err := r.fetchVaultTransitKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("transit key %s not found after its creation", key.Name)
+	}
+	return current, nil
+}
+
+func (r *TransitKeyReconciler) rotateVaultTransitKey(ctx context.Context, key *transitv1beta1.TransitKey) (*vault.TransitKey, error) {
+	s, err := r.Vault.Write(ctx, transitKeyPath(key)+"/rotate", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	if s == nil {
+		return r.fetchVaultTransitKey(ctx, key)
+	}
+	return toTransitKey(s.Data)
+}
+
+func (r *TransitKeyReconciler) updateVaultTransitKey(ctx context.Context, key *transitv1beta1.TransitKey) error {
+	data := map[string]interface{}{
+		"exportable":             key.Spec.Exportable,
+		"allow_plaintext_backup": key.Spec.AllowPlaintextBackup,
+		"deletion_allowed":       key.Spec.DeletionAllowed,
+		"auto_rotate_period":     ttlOrZero(key.Spec.AutoRotatePeriod),
+		"min_decryption_version": max(key.Spec.MinDecryptionVersion, 1),
+		"min_encryption_version": key.Spec.MinEncryptionVersion,
+	}
+	if _, err := r.Vault.Write(ctx, transitKeyPath(key)+"/config", data); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	key.Status.LastAppliedHash = vault.Checksum(data)
+	key.Status.LastAppliedTime = &now
+	return nil
+}
+
+// deleteVaultTransitKey deletes the key, allowing its deletion first in case it
+// was changed behind the operator's back.
+func (r *TransitKeyReconciler) deleteVaultTransitKey(ctx context.Context, key *transitv1beta1.TransitKey) error {
+	current, err := r.fetchVaultTransitKey(ctx, key)
+	if err != nil || current == nil {
+		return err
+	}
+
+	if !current.DeletionAllowed {
+		if _, err := r.Vault.Write(ctx, transitKeyPath(key)+"/config", map[string]interface{}{
+			"deletion_allowed": true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.Vault.Delete(ctx, transitKeyPath(key))
+	return err
+}
+
+// ttlOrZero returns "0" for an unset period, which disables it.
+func ttlOrZero(ttl string) string {
+	if ttl == "" {
+		return "0"
+	}
+	return ttl
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TransitKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&transitv1beta1.TransitKey{}).
+		Named("transit-transitkey").
+		Complete(r)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2025 HopopOps, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	transitv1beta1 "hopopops/vault-operator/api/transit/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+)
+
+var _ = Describe("TransitKey Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-key"
+		const mount = "transit"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var controllerReconciler *TransitKeyReconciler
+		var recorder *record.FakeRecorder
+
+		reconcileWithResult := func() (reconcile.Result, error) {
+			return controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+		}
+		reconcileOnce := func() error {
+			_, err := reconcileWithResult()
+			return err
+		}
+
+		get := func() *transitv1beta1.TransitKey {
+			key := &transitv1beta1.TransitKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, key)).To(Succeed())
+			return key
+		}
+
+		update := func(mutate func(*transitv1beta1.TransitKey)) {
+			key := get()
+			mutate(key)
+			Expect(k8sClient.Update(ctx, key)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			vaultServer.Reset()
+			Expect(vaultServer.Client().Mount(ctx, mount, &vaultapi.MountInput{Type: "transit"})).To(Succeed())
+
+			recorder = record.NewFakeRecorder(10)
+			controllerReconciler = &TransitKeyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Vault:    vaultServer.Client(),
+				Recorder: recorder,
+			}
+
+			By("creating the custom resource for the Kind TransitKey")
+			resource := &transitv1beta1.TransitKey{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err != nil && errors.IsNotFound(err) {
+				resource = &transitv1beta1.TransitKey{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: transitv1beta1.TransitKeySpec{
+						Mount:                mount,
+						Type:                 "aes256-gcm96",
+						DeletionAllowed:      true,
+						MinDecryptionVersion: 1,
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &transitv1beta1.TransitKey{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if err == nil {
+				By("Cleanup the specific resource instance TransitKey")
+				vaultServer.ClearFaults()
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				Expect(reconcileOnce()).To(Succeed())
+			}
+		})
+
+		It("should create the key in Vault", func() {
+			By("Reconciling the created resource")
+			result, err := reconcileWithResult()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			k, ok := vaultServer.TransitKey(mount, resourceName)
+			Expect(ok).To(BeTrue())
+			Expect(k.Type).To(Equal("aes256-gcm96"))
+			Expect(k.DeletionAllowed).To(BeTrue())
+			Expect(k.LatestVersion).To(Equal(1))
+
+			key := get()
+			Expect(key.Finalizers).To(ContainElement(transitKeyFinalizer))
+			Expect(key.Status.LatestVersion).To(Equal(1))
+			Expect(meta.IsStatusConditionTrue(key.Status.Conditions, typeConfiguredTransitKey)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created Created " + mount + "/keys/" + resourceName + " in Vault")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Updated Updated " + mount + "/keys/" + resourceName + "/config in Vault")))
+		})
+
+		It("should not write to Vault when in sync", func() {
+			Expect(reconcileOnce()).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			writes := 0
+			for _, r := range vaultServer.Requests() {
+				if r.Method == http.MethodPut && r.Path == mount+"/keys/"+resourceName+"/config" {
+					writes++
+				}
+			}
+			Expect(writes).To(Equal(1))
+		})
+
+		It("should rotate the key when the annotation changes", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			update(func(key *transitv1beta1.TransitKey) {
+				key.Annotations = map[string]string{rotateAnnotation: "1"}
+				key.Spec.MinDecryptionVersion = 2
+			})
+			Expect(reconcileOnce()).To(Succeed())
+
+			k, _ := vaultServer.TransitKey(mount, resourceName)
+			Expect(k.LatestVersion).To(Equal(2))
+			Expect(k.MinDecryptionVersion).To(Equal(2))
+			key := get()
+			Expect(key.Status.LatestVersion).To(Equal(2))
+			Expect(key.Status.RotationRequest).To(Equal("1"))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Updated")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Updated Rotated " + mount + "/keys/" + resourceName + "/rotate in Vault")))
+
+			By("Not rotating again for the same annotation value")
+			Expect(reconcileOnce()).To(Succeed())
+			k, _ = vaultServer.TransitKey(mount, resourceName)
+			Expect(k.LatestVersion).To(Equal(2))
+		})
+
+		It("should configure automatic rotation", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			update(func(key *transitv1beta1.TransitKey) {
+				key.Spec.AutoRotatePeriod = "720h"
+				key.Spec.Exportable = true
+			})
+			result, err := reconcileWithResult()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(720 * time.Hour))
+
+			k, _ := vaultServer.TransitKey(mount, resourceName)
+			Expect(k.AutoRotatePeriod).To(Equal(2592000))
+			Expect(k.Exportable).To(BeTrue())
+		})
+
+		It("should restore settings modified behind the operator's back", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, err := vaultServer.Client().Write(ctx, mount+"/keys/"+resourceName+"/config", map[string]interface{}{"deletion_allowed": false})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconcileOnce()).To(Succeed())
+			k, _ := vaultServer.TransitKey(mount, resourceName)
+			Expect(k.DeletionAllowed).To(BeTrue())
+		})
+
+		It("should report settings rejected by Vault", func() {
+			update(func(key *transitv1beta1.TransitKey) {
+				key.Spec.MinDecryptionVersion = 3
+			})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredTransitKey)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("FailedToUpdate"))
+		})
+
+		It("should report Vault errors in the status", func() {
+			vaultServer.InjectFault(fake.Fault{Method: http.MethodGet, Path: mount + "/keys/" + resourceName})
+
+			Expect(reconcileOnce()).NotTo(Succeed())
+
+			condition := meta.FindStatusCondition(get().Status.Conditions, typeConfiguredTransitKey)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("FailedToFetch"))
+		})
+
+		It("should delete the key from Vault when allowed", func() {
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, get())).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.TransitKey(mount, resourceName)
+			Expect(ok).To(BeFalse())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Updated")))
+			Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleted " + mount + "/keys/" + resourceName + " from Vault")))
+		})
+
+		It("should keep the key in Vault unless deletion is allowed", func() {
+			update(func(key *transitv1beta1.TransitKey) {
+				key.Spec.DeletionAllowed = false
+			})
+			Expect(reconcileOnce()).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, get())).To(Succeed())
+			Expect(reconcileOnce()).To(Succeed())
+
+			_, ok := vaultServer.TransitKey(mount, resourceName)
+			Expect(ok).To(BeTrue())
+			err := k8sClient.Get(ctx, typeNamespacedName, &transitv1beta1.TransitKey{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,161 @@
+// Package events records the Kubernetes Events the controllers emit on their
+// resources when they change Vault. The messages carry the Vault path of the
+// object but never its content, nor the error returned by Vault, which may
+// echo it.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"hopopops/vault-operator/internal/metrics"
+)
+
+// Reasons of the Normal events. Warning events use the reason of the
+// condition set on the failure, such as FailedToUpdate.
+const (
+	ReasonCreated        = "Created"
+	ReasonUpdated        = "Updated"
+	ReasonDriftCorrected = "DriftCorrected"
+	ReasonDeleted        = "Deleted"
+	ReasonRevoked        = "Revoked"
+)
+
+// Pushed records the write of the object of a resource to Vault at the path,
+// before its Configured condition is updated. The event is Created when the
+// object did not exist, DriftCorrected when it was changed in Vault while the
+// resource kept the generation last pushed, as told by metrics.Drifted, and
+// Updated otherwise. A drift correction is also counted in the metrics under
+// the kind.
+func Pushed(recorder record.EventRecorder, obj client.Object, conditions []metav1.Condition, existed bool, kind, path string) {
+	recorder = recorderOrDiscard(recorder)
+	switch {
+	case !existed:
+		recorder.Eventf(obj, corev1.EventTypeNormal, ReasonCreated, "Created %s in Vault", path)
+	case metrics.Drifted(obj, conditions):
+		metrics.CorrectDrift(kind)
+		recorder.Eventf(obj, corev1.EventTypeNormal, ReasonDriftCorrected, "Overwrote %s, changed in Vault outside of the resource", path)
+	default:
+		recorder.Eventf(obj, corev1.EventTypeNormal, ReasonUpdated, "Updated %s in Vault", path)
+	}
+}
+
+// Reapplied records the write to Vault at the path of an object already
+// matching the spec of its resource, as requested by its reconcile-at
+// annotation.
+func Reapplied(recorder record.EventRecorder, obj client.Object, path string) {
+	recorderOrDiscard(recorder).Eventf(obj, corev1.EventTypeNormal, ReasonUpdated, "Rewrote %s in Vault on request", path)
+}
+
+// Rotated records the rotation of a key or credentials in Vault, requested
+// at the path.
+func Rotated(recorder record.EventRecorder, obj client.Object, path string) {
+	recorderOrDiscard(recorder).Eventf(obj, corev1.EventTypeNormal, ReasonUpdated, "Rotated %s in Vault", path)
+}
+
+// Deleted records the deletion of the object of a resource from Vault.
+func Deleted(recorder record.EventRecorder, obj client.Object, path string) {
+	recorderOrDiscard(recorder).Eventf(obj, corev1.EventTypeNormal, ReasonDeleted, "Deleted %s from Vault", path)
+}
+
+// Revoked records the revocation in Vault of what was issued to a resource,
+// such as a token or a lease, described by what and identified by the path.
+func Revoked(recorder record.EventRecorder, obj client.Object, what, path string) {
+	recorderOrDiscard(recorder).Eventf(obj, corev1.EventTypeNormal, ReasonRevoked, "Revoked %s %s in Vault", what, path)
+}
+
+// Failed records a failure, the reason being the one of the condition set on
+// it. The message is followed by the Vault path, when known, and the class of
+// the error.
+func Failed(recorder record.EventRecorder, obj client.Object, reason, message, path string, err error) {
+	if path != "" {
+		message = fmt.Sprintf("%s at %s", message, path)
+	}
+	recorderOrDiscard(recorder).Eventf(obj, corev1.EventTypeWarning, reason, "%s: %s", message, Classify(err))
+}
+
+// Conflicted records that the object of a resource was not written to Vault
+// at the path, another object the operator does not own being there. The
+// reason is the one of the condition set on it.
+func Conflicted(recorder record.EventRecorder, obj client.Object, reason, message, path string) {
+	recorderOrDiscard(recorder).Eventf(obj, corev1.EventTypeWarning, reason, "%s at %s", message, path)
+}
+
+// UnsupportedMode records that a resource is not reconciled in the mode it is
+// to be reconciled in, the reason being the one of the condition set on it.
+func UnsupportedMode(recorder record.EventRecorder, obj client.Object, reason, message string) {
+	recorderOrDiscard(recorder).Event(obj, corev1.EventTypeWarning, reason, message)
+}
+
+// Classify describes the cause of an error without its details, which may
+// quote the payload of the request.
+func Classify(err error) string {
+	var responseErr *vaultapi.ResponseError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return "unknown error"
+	case errors.As(err, &responseErr):
+		return classifyStatus(responseErr.StatusCode)
+	case apierrors.ReasonForError(err) != metav1.StatusReasonUnknown:
+		return fmt.Sprintf("Kubernetes API error (%s)", apierrors.ReasonForError(err))
+	case errors.Is(err, context.DeadlineExceeded):
+		return "request timed out"
+	case errors.As(err, &netErr):
+		return "Vault is unreachable"
+	default:
+		return "invalid configuration or unexpected response"
+	}
+}
+
+// classifyStatus describes the status code of a response of Vault.
+func classifyStatus(code int) string {
+	var class string
+	switch {
+	case code == http.StatusBadRequest:
+		class = "invalid request"
+	case code == http.StatusForbidden:
+		class = "permission denied"
+	case code == http.StatusNotFound:
+		class = "not found"
+	case code == http.StatusTooManyRequests:
+		class = "rate limited"
+	case code == http.StatusServiceUnavailable:
+		class = "Vault is sealed or in standby"
+	case code >= http.StatusInternalServerError:
+		class = "Vault internal error"
+	default:
+		class = "unexpected response"
+	}
+	return fmt.Sprintf("%s (HTTP %d)", class, code)
+}
+
+// recorderOrDiscard returns the recorder, or one discarding the events when
+// the reconciler was built without one, as for plans.
+func recorderOrDiscard(recorder record.EventRecorder) record.EventRecorder {
+	if recorder == nil {
+		return discard{}
+	}
+	return recorder
+}
+
+// discard is an EventRecorder dropping the events.
+type discard struct{}
+
+func (discard) Event(runtime.Object, string, string, string) {}
+
+func (discard) Eventf(runtime.Object, string, string, string, ...interface{}) {}
+
+func (discard) AnnotatedEventf(runtime.Object, map[string]string, string, string, string, ...interface{}) {
+}
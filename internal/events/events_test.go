@@ -0,0 +1,66 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/events"
+)
+
+var _ = Describe("Events", func() {
+	var recorder *record.FakeRecorder
+	var policy *sysv1beta1.Policy
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		policy = &sysv1beta1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Generation: 2}}
+	})
+
+	configured := func(generation int64) []metav1.Condition {
+		return []metav1.Condition{{Type: "Configured", Status: metav1.ConditionTrue, Reason: "Configured", ObservedGeneration: generation}}
+	}
+
+	It("should tell creations, updates and drift corrections apart", func() {
+		events.Pushed(recorder, policy, nil, false, "Policy", "sys/policies/acl/test")
+		events.Pushed(recorder, policy, configured(1), true, "Policy", "sys/policies/acl/test")
+		events.Pushed(recorder, policy, configured(2), true, "Policy", "sys/policies/acl/test")
+
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Created sys/policies/acl/test in Vault")))
+		Expect(recorder.Events).To(Receive(Equal("Normal Updated Updated sys/policies/acl/test in Vault")))
+		Expect(recorder.Events).To(Receive(Equal("Normal DriftCorrected Overwrote sys/policies/acl/test, changed in Vault outside of the resource")))
+	})
+
+	It("should record failures with the reason of the condition and the class of the error", func() {
+		err := &vaultapi.ResponseError{StatusCode: http.StatusForbidden, Errors: []string{"permission denied on s3cr3t"}}
+		events.Failed(recorder, policy, "FailedToUpdate", "Failed to push policy to Vault", "sys/policies/acl/test", err)
+
+		Expect(recorder.Events).To(Receive(Equal("Warning FailedToUpdate Failed to push policy to Vault at sys/policies/acl/test: permission denied (HTTP 403)")))
+	})
+
+	It("should discard the events without a recorder", func() {
+		Expect(func() { events.Deleted(nil, policy, "sys/policies/acl/test") }).NotTo(Panic())
+	})
+
+	DescribeTable("should classify errors without their details",
+		func(err error, class string) {
+			Expect(events.Classify(err)).To(Equal(class))
+		},
+		Entry("invalid request", &vaultapi.ResponseError{StatusCode: http.StatusBadRequest}, "invalid request (HTTP 400)"),
+		Entry("wrapped response", fmt.Errorf("failed: %w", &vaultapi.ResponseError{StatusCode: http.StatusServiceUnavailable}), "Vault is sealed or in standby (HTTP 503)"),
+		Entry("server error", &vaultapi.ResponseError{StatusCode: http.StatusBadGateway}, "Vault internal error (HTTP 502)"),
+		Entry("Kubernetes error", apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "test"), "Kubernetes API error (NotFound)"),
+		Entry("timeout", context.DeadlineExceeded, "request timed out"),
+		Entry("other", errors.New("password=s3cr3t is invalid"), "invalid configuration or unexpected response"),
+	)
+})
@@ -0,0 +1,288 @@
+// Package export turns the configuration of Vault into resources of the
+// operator, for an existing Vault to be migrated to it.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// builtinPolicies cannot be deleted from Vault, so are not exported.
+var builtinPolicies = map[string]struct{}{"root": {}, "default": {}}
+
+// builtinMounts are enabled by Vault itself, so are not exported.
+var builtinMounts = map[string]struct{}{"cubbyhole": {}, "identity": {}, "system": {}, "token": {}, "ns_cubbyhole": {}, "ns_identity": {}, "ns_system": {}, "ns_token": {}}
+
+// Options tune the resources exported.
+type Options struct {
+	// Namespace is the namespace of the resources, left unset when empty.
+	Namespace string
+	// NamePrefix prefixes the names of the resources whose Vault path is set
+	// in their spec, namely secrets engines and audit devices. The other
+	// resources are named after the object they manage in Vault.
+	NamePrefix string
+	// Adopt annotates the resources for the operator to take over the
+	// objects existing in Vault, rather than failing to create them.
+	Adopt bool
+}
+
+// Skipped is an object of Vault left out of an export.
+type Skipped struct {
+	Kind   string
+	Path   string
+	Reason string
+}
+
+// exporter collects the resources of an export.
+type exporter struct {
+	vault   vault.Interface
+	options Options
+
+	objects []client.Object
+	skipped []Skipped
+	names   map[string]string
+}
+
+// Export reads the policies, password policies, auth methods, secrets
+// engines, audit devices and Kubernetes auth roles of Vault, and returns the
+// resources managing them along with the objects that cannot be exported.
+func Export(ctx context.Context, v vault.Interface, options Options) ([]client.Object, []Skipped, error) {
+	e := &exporter{vault: v, options: options, names: map[string]string{}}
+
+	for _, export := range []func(context.Context) error{
+		e.exportPolicies,
+		e.exportPasswordPolicies,
+		e.exportAuths,
+		e.exportSecretEngines,
+		e.exportAuditDevices,
+	} {
+		if err := export(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return e.objects, e.skipped, nil
+}
+
+// add adds a resource named after the Vault object at the path, skipping it
+// when the name is not valid for Kubernetes or already taken by a resource
+// of the same kind.
+func (e *exporter) add(obj client.Object, kind, name, path string) {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		e.skipped = append(e.skipped, Skipped{Kind: kind, Path: path, Reason: fmt.Sprintf("%q is not a valid resource name: %s", name, strings.Join(errs, ", "))})
+		return
+	}
+	if other, ok := e.names[kind+"/"+name]; ok {
+		e.skipped = append(e.skipped, Skipped{Kind: kind, Path: path, Reason: fmt.Sprintf("resource name %q is already used for %s", name, other)})
+		return
+	}
+	e.names[kind+"/"+name] = path
+
+	obj.SetName(name)
+	obj.SetNamespace(e.options.Namespace)
+	if e.options.Adopt {
+		obj.SetAnnotations(map[string]string{vault.AdoptAnnotation: "true"})
+	}
+	e.objects = append(e.objects, obj)
+}
+
+// list returns the sorted keys under a path, none when the path is missing.
+func (e *exporter) list(ctx context.Context, path string) ([]string, error) {
+	s, err := e.vault.List(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", path, err)
+	}
+	if s == nil {
+		return nil, nil
+	}
+
+	raw, _ := s.Data["keys"].([]interface{})
+	keys := make([]string, 0, len(raw))
+	for _, k := range raw {
+		if key, ok := k.(string); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// read decodes the data at a path, reporting whether it exists.
+func (e *exporter) read(ctx context.Context, path string, out interface{}) (bool, error) {
+	s, err := e.vault.Read(ctx, path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if s == nil {
+		return false, nil
+	}
+
+	jsonBytes, err := json.Marshal(s.Data)
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(jsonBytes, out)
+}
+
+func (e *exporter) exportPolicies(ctx context.Context) error {
+	names, err := e.list(ctx, "sys/policies/acl")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if _, ok := builtinPolicies[name]; ok {
+			continue
+		}
+		rules, err := e.vault.GetPolicy(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to read policy %s: %w", name, err)
+		}
+
+		e.add(&sysv1beta1.Policy{
+			TypeMeta: metav1.TypeMeta{APIVersion: sysv1beta1.GroupVersion.String(), Kind: "Policy"},
+			Spec:     sysv1beta1.PolicySpec{Policy: ptr.To(rules)},
+		}, "Policy", name, "sys/policies/acl/"+name)
+	}
+	return nil
+}
+
+func (e *exporter) exportPasswordPolicies(ctx context.Context) error {
+	names, err := e.list(ctx, "sys/policies/password")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		var policy struct {
+			Policy string `json:"policy"`
+		}
+		if _, err := e.read(ctx, "sys/policies/password/"+name, &policy); err != nil {
+			return err
+		}
+
+		e.add(&sysv1beta1.PasswordPolicy{
+			TypeMeta: metav1.TypeMeta{APIVersion: sysv1beta1.GroupVersion.String(), Kind: "PasswordPolicy"},
+			Spec:     sysv1beta1.PasswordPolicySpec{Policy: policy.Policy},
+		}, "PasswordPolicy", name, "sys/policies/password/"+name)
+	}
+	return nil
+}
+
+// exportAuths exports the auth methods, along with the roles of the
+// Kubernetes ones.
+func (e *exporter) exportAuths(ctx context.Context) error {
+	mounts := map[string]*vaultapi.MountOutput{}
+	if _, err := e.read(ctx, "sys/auth", &mounts); err != nil {
+		return err
+	}
+
+	for _, p := range sortedPaths(mounts) {
+		m := mounts[p]
+		path := strings.TrimSuffix(p, "/")
+		if _, ok := builtinMounts[m.Type]; ok {
+			continue
+		}
+
+		e.add(&sysv1beta1.Auth{
+			TypeMeta: metav1.TypeMeta{APIVersion: sysv1beta1.GroupVersion.String(), Kind: "Auth"},
+			Spec:     sysv1beta1.AuthSpec{Type: ptr.To(m.Type), Description: ptr.To(m.Description)},
+		}, "Auth", path, "sys/auth/"+path)
+
+		if m.Type == "kubernetes" {
+			if err := e.exportKubernetesRoles(ctx, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) exportKubernetesRoles(ctx context.Context, authPath string) error {
+	names, err := e.list(ctx, fmt.Sprintf("auth/%s/role", authPath))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		path := fmt.Sprintf("auth/%s/role/%s", authPath, name)
+		var kr vault.KubernetesRole
+		if ok, err := e.read(ctx, path, &kr); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		e.add(&authv1beta1.KubernetesRole{
+			TypeMeta: metav1.TypeMeta{APIVersion: authv1beta1.GroupVersion.String(), Kind: "KubernetesRole"},
+			Spec:     kr.ToSpec(authPath),
+		}, "KubernetesRole", name, path)
+	}
+	return nil
+}
+
+func (e *exporter) exportSecretEngines(ctx context.Context) error {
+	mounts := map[string]*vault.SecretEngine{}
+	if _, err := e.read(ctx, "sys/mounts", &mounts); err != nil {
+		return err
+	}
+
+	for _, p := range sortedPaths(mounts) {
+		m := mounts[p]
+		path := strings.TrimSuffix(p, "/")
+		if _, ok := builtinMounts[m.Type]; ok {
+			continue
+		}
+
+		e.add(&sysv1beta1.SecretEngine{
+			TypeMeta: metav1.TypeMeta{APIVersion: sysv1beta1.GroupVersion.String(), Kind: "SecretEngine"},
+			Spec:     m.ToSpec(path),
+		}, "SecretEngine", e.pathName(path), "sys/mounts/"+path)
+	}
+	return nil
+}
+
+func (e *exporter) exportAuditDevices(ctx context.Context) error {
+	devices, err := e.vault.ListAudit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list audit devices: %w", err)
+	}
+
+	for _, p := range sortedPaths(devices) {
+		path := strings.TrimSuffix(p, "/")
+		e.add(&sysv1beta1.AuditDevice{
+			TypeMeta: metav1.TypeMeta{APIVersion: sysv1beta1.GroupVersion.String(), Kind: "AuditDevice"},
+			Spec:     (*vault.AuditDevice)(devices[p]).ToSpec(path),
+		}, "AuditDevice", e.pathName(path), "sys/audit/"+path)
+	}
+	return nil
+}
+
+// pathName returns the name of a resource whose Vault path is set in its
+// spec, paths being nested with slashes.
+func (e *exporter) pathName(path string) string {
+	return e.options.NamePrefix + strings.ToLower(strings.NewReplacer("/", "-", "_", "-", ".", "-").Replace(path))
+}
+
+func sortedPaths[T any](m map[string]T) []string {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
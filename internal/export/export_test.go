@@ -0,0 +1,139 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+	authcontroller "hopopops/vault-operator/internal/controller/auth"
+	syscontroller "hopopops/vault-operator/internal/controller/sys"
+	"hopopops/vault-operator/internal/export"
+	"hopopops/vault-operator/internal/plan"
+)
+
+var _ = Describe("Export", func() {
+	var (
+		server *fake.Server
+		client *vault.Vault
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		server = fake.NewServer()
+		client = server.Client()
+		ctx = context.Background()
+
+		Expect(client.PutPolicy(ctx, "app", "path \"secret/data/app\" {\n  capabilities = [\"read\"]\n}\n")).To(Succeed())
+		Expect(client.PutPolicy(ctx, "Invalid_Name", `path "*" {}`)).To(Succeed())
+		_, err := client.Write(ctx, "sys/policies/password/digits", map[string]interface{}{"policy": "length = 12\nrule \"charset\" {\n  charset = \"0123456789\"\n}\n"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(client.EnableAuth(ctx, "kubernetes/", &vaultapi.EnableAuthOptions{Type: "kubernetes", Description: "cluster"})).To(Succeed())
+		_, err = client.Write(ctx, "auth/kubernetes/role/app", map[string]interface{}{
+			"bound_service_account_names":      []string{"app"},
+			"bound_service_account_namespaces": []string{"default"},
+			"token_policies":                   []string{"app"},
+			"token_ttl":                        3600,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(client.Mount(ctx, "teams/kv", &vaultapi.MountInput{Type: "kv", Options: map[string]string{"version": "2"}, Config: vaultapi.MountConfigInput{DefaultLeaseTTL: "1h"}})).To(Succeed())
+		Expect(client.EnableAudit(ctx, "file", &vaultapi.EnableAuditOptions{Type: "file", Options: map[string]string{"file_path": "/var/log/vault.log"}})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should export the configuration as resources", func() {
+		writes := len(server.Requests())
+
+		objs, skipped, err := export.Export(ctx, client, export.Options{Namespace: "vault", NamePrefix: "prod-", Adopt: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Requests()[writes:]).To(HaveEach(HaveField("Method", BeElementOf("GET", "LIST"))))
+
+		Expect(skipped).To(ConsistOf(And(HaveField("Kind", "Policy"), HaveField("Path", "sys/policies/acl/Invalid_Name"))))
+		Expect(objs).To(HaveEach(And(
+			HaveField("ObjectMeta.Namespace", "vault"),
+			HaveField("ObjectMeta.Annotations", HaveKeyWithValue(vault.AdoptAnnotation, "true")),
+		)))
+
+		names := map[string]string{}
+		for _, obj := range objs {
+			names[obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName()] = obj.GetName()
+		}
+		Expect(names).To(HaveKey("Policy/app"))
+		Expect(names).NotTo(HaveKey("Policy/default"))
+		Expect(names).To(HaveKey("PasswordPolicy/digits"))
+		Expect(names).To(HaveKey("Auth/kubernetes"))
+		Expect(names).NotTo(HaveKey("Auth/token"))
+		Expect(names).To(HaveKey("KubernetesRole/app"))
+		Expect(names).To(HaveKey("SecretEngine/prod-teams-kv"))
+		Expect(names).To(HaveKey("AuditDevice/prod-file"))
+
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *authv1beta1.KubernetesRole:
+				Expect(o.Spec.AuthPath).To(Equal("kubernetes"))
+				Expect(o.Spec.BoundServiceAccountNamespaces).To(Equal([]string{"default"}))
+				Expect(o.Spec.TokenPolicies).To(Equal([]string{"app"}))
+				Expect(o.Spec.TokenTTL).To(Equal(3600))
+			case *sysv1beta1.SecretEngine:
+				Expect(o.Spec.Path).To(Equal("teams/kv"))
+				Expect(o.Spec.Options).To(HaveKeyWithValue("version", "2"))
+				Expect(o.Spec.Config.DefaultLeaseTTL).To(HaveValue(Equal("3600s")))
+				Expect((*vault.SecretEngine)(mustGetMount(client, "teams/kv")).IsDifferentFromSpec(&o.Spec)).To(BeFalse())
+			case *sysv1beta1.AuditDevice:
+				Expect(o.Spec.Options).To(HaveKeyWithValue("file_path", "/var/log/vault.log"))
+			}
+		}
+	})
+
+	It("should write manifests planned without changes", func() {
+		objs, _, err := export.Export(ctx, client, export.Options{Namespace: "vault"})
+		Expect(err).NotTo(HaveOccurred())
+
+		var out bytes.Buffer
+		Expect(export.Write(&out, objs)).To(Succeed())
+		Expect(out.String()).NotTo(ContainSubstring("status"))
+		Expect(out.String()).NotTo(ContainSubstring("creationTimestamp"))
+
+		manifest := filepath.Join(GinkgoT().TempDir(), "exported.yaml")
+		Expect(os.WriteFile(manifest, out.Bytes(), 0o600)).To(Succeed())
+
+		scheme := runtime.NewScheme()
+		utilruntime.Must(sysv1beta1.AddToScheme(scheme))
+		utilruntime.Must(authv1beta1.AddToScheme(scheme))
+		loaded, err := plan.Load(scheme, "kustomize", manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(HaveLen(len(objs)))
+
+		p, err := plan.Build(ctx, map[schema.GroupKind]plan.Planner{
+			{Group: sysv1beta1.GroupVersion.Group, Kind: "Policy"}:          &syscontroller.PolicyReconciler{Vault: client},
+			{Group: sysv1beta1.GroupVersion.Group, Kind: "Auth"}:            &syscontroller.AuthReconciler{Vault: client},
+			{Group: authv1beta1.GroupVersion.Group, Kind: "KubernetesRole"}: &authcontroller.KubernetesRoleReconciler{Vault: client},
+		}, loaded, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Changes).To(HaveLen(3))
+		Expect(p.HasChanges()).To(BeFalse())
+	})
+})
+
+func mustGetMount(client *vault.Vault, path string) *vaultapi.MountOutput {
+	m, err := client.GetMount(context.Background(), path)
+	Expect(err).NotTo(HaveOccurred())
+	return m
+}
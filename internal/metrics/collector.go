@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+)
+
+// groupSuffix is the suffix of the API groups of the operator.
+const groupSuffix = ".toolkit.vault.hopopops.com"
+
+// collectTimeout bounds the listing of the resources on a scrape.
+const collectTimeout = 10 * time.Second
+
+var (
+	managedObjectsDesc = prometheus.NewDesc(namespace+"_managed_objects",
+		"Number of resources managed by the operator, by kind and status of their Ready condition.",
+		[]string{"kind", "ready"}, nil)
+
+	tokenExpiryDesc = prometheus.NewDesc(namespace+"_token_expiry_timestamp_seconds",
+		"Time the token issued for a Token resource expires, as a Unix timestamp.",
+		[]string{"namespace", "name"}, nil)
+)
+
+// ResourcesCollector reports the health of the resources of the operator,
+// listed on each scrape from the informers the controllers already watch them
+// with, so that scrapes cost no request to the API server.
+type ResourcesCollector struct {
+	reader client.Reader
+	kinds  []schema.GroupVersionKind
+	scheme *runtime.Scheme
+}
+
+// NewResourcesCollector returns a collector listing the resources of every
+// kind of the operator registered in the scheme with the reader, the cache of
+// the manager outside of tests.
+func NewResourcesCollector(reader client.Reader, scheme *runtime.Scheme) *ResourcesCollector {
+	var kinds []schema.GroupVersionKind
+	for gvk := range scheme.AllKnownTypes() {
+		if strings.HasSuffix(gvk.Group, groupSuffix) && !strings.HasSuffix(gvk.Kind, "List") {
+			kinds = append(kinds, gvk)
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+
+	return &ResourcesCollector{reader: reader, kinds: kinds, scheme: scheme}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ResourcesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedObjectsDesc
+	ch <- tokenExpiryDesc
+}
+
+// Collect implements prometheus.Collector. Kinds that cannot be listed are
+// left out of the scrape.
+func (c *ResourcesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+	log := logf.FromContext(ctx).WithName("metrics")
+
+	for _, gvk := range c.kinds {
+		counts, err := c.countByReadiness(ctx, gvk)
+		if err != nil {
+			log.Error(err, "Failed to list resources", "kind", gvk.Kind)
+			continue
+		}
+		for _, ready := range []string{"true", "false", "unknown"} {
+			ch <- prometheus.MustNewConstMetric(managedObjectsDesc, prometheus.GaugeValue, float64(counts[ready]), gvk.Kind, ready)
+		}
+	}
+
+	tokens := &authv1beta1.TokenList{}
+	if err := c.reader.List(ctx, tokens, client.UnsafeDisableDeepCopy); err != nil {
+		log.Error(err, "Failed to list resources", "kind", "Token")
+		return
+	}
+	for _, token := range tokens.Items {
+		if token.Status.ExpireTime != nil {
+			ch <- prometheus.MustNewConstMetric(tokenExpiryDesc, prometheus.GaugeValue, float64(token.Status.ExpireTime.Unix()), token.Namespace, token.Name)
+		}
+	}
+}
+
+// countByReadiness counts the resources of a kind by status of their
+// Ready condition, lowercased.
+func (c *ResourcesCollector) countByReadiness(ctx context.Context, gvk schema.GroupVersionKind) (map[string]int, error) {
+	obj, err := c.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", gvk.Kind+"List")
+	}
+	// The listed objects are only read, copying them out of the cache is
+	// not needed
+	if err := c.reader.List(ctx, list, client.UnsafeDisableDeepCopy); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	err = meta.EachListItem(list, func(item runtime.Object) error {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return err
+		}
+		conditions, _, _ := unstructured.NestedSlice(u, "status", "conditions")
+
+		ready := "unknown"
+		for _, condition := range conditions {
+			if m, ok := condition.(map[string]interface{}); ok && m["type"] == "Ready" {
+				if status, ok := m["status"].(string); ok {
+					ready = strings.ToLower(status)
+				}
+			}
+		}
+		counts[ready]++
+		return nil
+	})
+	return counts, err
+}
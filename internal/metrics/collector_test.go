@@ -0,0 +1,52 @@
+package metrics_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/metrics"
+)
+
+var _ = Describe("ResourcesCollector", func() {
+	It("should count the resources by readiness and report the token expiry", func() {
+		scheme := runtime.NewScheme()
+		scheme.AddKnownTypes(sysv1beta1.GroupVersion, &sysv1beta1.Policy{}, &sysv1beta1.PolicyList{})
+		scheme.AddKnownTypes(authv1beta1.GroupVersion, &authv1beta1.Token{}, &authv1beta1.TokenList{})
+
+		configured := func(status metav1.ConditionStatus) []metav1.Condition {
+			return []metav1.Condition{{Type: "Ready", Status: status, Reason: "Test", LastTransitionTime: metav1.Now()}}
+		}
+		expireTime := metav1.NewTime(time.Unix(1900000000, 0))
+		reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&sysv1beta1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default"}, Status: sysv1beta1.PolicyStatus{Conditions: configured(metav1.ConditionTrue)}},
+			&sysv1beta1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "default"}, Status: sysv1beta1.PolicyStatus{Conditions: configured(metav1.ConditionFalse)}},
+			&sysv1beta1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"}},
+			&authv1beta1.Token{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}, Status: authv1beta1.TokenStatus{ExpireTime: &expireTime}},
+		).Build()
+
+		collector := metrics.NewResourcesCollector(reader, scheme)
+
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP vault_operator_managed_objects Number of resources managed by the operator, by kind and status of their Ready condition.
+# TYPE vault_operator_managed_objects gauge
+vault_operator_managed_objects{kind="Policy",ready="false"} 1
+vault_operator_managed_objects{kind="Policy",ready="true"} 1
+vault_operator_managed_objects{kind="Policy",ready="unknown"} 1
+vault_operator_managed_objects{kind="Token",ready="false"} 0
+vault_operator_managed_objects{kind="Token",ready="true"} 0
+vault_operator_managed_objects{kind="Token",ready="unknown"} 1
+# HELP vault_operator_token_expiry_timestamp_seconds Time the token issued for a Token resource expires, as a Unix timestamp.
+# TYPE vault_operator_token_expiry_timestamp_seconds gauge
+vault_operator_token_expiry_timestamp_seconds{name="app",namespace="default"} 1.9e+09
+`))).To(Succeed())
+	})
+})
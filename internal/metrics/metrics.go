@@ -0,0 +1,98 @@
+// Package metrics defines the Prometheus metrics of the operator, registered
+// with the controller-runtime registry served on its metrics endpoint.
+package metrics
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "vault_operator"
+
+// typeConfigured is the condition the controllers set once an object is
+// pushed to Vault.
+const typeConfigured = "Configured"
+
+var (
+	// VaultRequestDuration is the latency of the requests to the Vault API, by
+	// operation of vault.Interface and HTTP status code, "ok" on success.
+	VaultRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "vault_request_duration_seconds",
+		Help:      "Latency of the requests to the Vault API, by operation and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "code"})
+
+	// VaultRequestErrors counts the requests to the Vault API that failed, the
+	// code being "error" when no response was received.
+	VaultRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "vault_request_errors_total",
+		Help:      "Number of requests to the Vault API that failed, by operation and status code.",
+	}, []string{"operation", "code"})
+
+	// DriftCorrections counts the objects overwritten in Vault because they
+	// were changed there, not the writes following a change of the spec.
+	DriftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "drift_corrections_total",
+		Help:      "Number of objects overwritten in Vault because they were changed outside of their resource, by kind.",
+	}, []string{"kind"})
+
+	// TokenRenewalFailures counts the failures to renew the token the operator
+	// logs in to Vault with.
+	TokenRenewalFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_renewal_failures_total",
+		Help:      "Number of failures to renew the Vault token of the operator.",
+	})
+
+	tokenExpiry atomic.Int64
+
+	tokenTTL = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "token_ttl_seconds",
+		Help:      "Time left before the Vault token of the operator expires, +Inf when it never does.",
+	}, func() float64 {
+		expiry := tokenExpiry.Load()
+		if expiry == 0 {
+			return math.Inf(1)
+		}
+		return time.Until(time.Unix(expiry, 0)).Seconds()
+	})
+)
+
+// SetTokenExpiry records when the Vault token of the operator expires, after
+// it logs in or renews it, the zero time meaning it never does.
+func SetTokenExpiry(t time.Time) {
+	if t.IsZero() {
+		tokenExpiry.Store(0)
+		return
+	}
+	tokenExpiry.Store(t.Unix())
+}
+
+// CorrectDrift records an object of the kind overwritten in Vault because it
+// was changed there, as told by Drifted.
+func CorrectDrift(kind string) {
+	DriftCorrections.WithLabelValues(kind).Inc()
+}
+
+// Drifted reports whether the resource was already pushed to Vault at its
+// current generation, an object differing from it in Vault then having been
+// changed there. It is checked before the Configured condition is updated.
+func Drifted(obj client.Object, conditions []metav1.Condition) bool {
+	condition := meta.FindStatusCondition(conditions, typeConfigured)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == obj.GetGeneration()
+}
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(VaultRequestDuration, VaultRequestErrors, DriftCorrections, TokenRenewalFailures, tokenTTL)
+}
@@ -0,0 +1,25 @@
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/metrics"
+)
+
+var _ = Describe("Drifted", func() {
+	policy := &sysv1beta1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Generation: 2}}
+
+	configured := func(status metav1.ConditionStatus, generation int64) []metav1.Condition {
+		return []metav1.Condition{{Type: "Configured", Status: status, Reason: "Test", ObservedGeneration: generation}}
+	}
+
+	It("should only report the resources pushed at their current generation", func() {
+		Expect(metrics.Drifted(policy, nil)).To(BeFalse())
+		Expect(metrics.Drifted(policy, configured(metav1.ConditionFalse, 2))).To(BeFalse())
+		Expect(metrics.Drifted(policy, configured(metav1.ConditionTrue, 1))).To(BeFalse())
+		Expect(metrics.Drifted(policy, configured(metav1.ConditionTrue, 2))).To(BeTrue())
+	})
+})
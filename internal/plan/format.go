@@ -0,0 +1,124 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the output format of a plan.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	// FormatMarkdown renders a plan as a GitHub comment.
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat parses the output format of a plan.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatText, FormatJSON, FormatMarkdown:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid format %q, must be %q, %q or %q", s, FormatText, FormatJSON, FormatMarkdown)
+	}
+}
+
+// Write renders the plan in the format. The text and markdown formats leave
+// out the resources Vault already matches.
+func (p *Plan) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	case FormatMarkdown:
+		return p.writeMarkdown(w)
+	default:
+		return p.writeText(w)
+	}
+}
+
+// symbols prefix the changes in the text format.
+var symbols = map[Action]string{
+	ActionCreate: "+",
+	ActionUpdate: "~",
+	ActionDelete: "-",
+}
+
+func (p *Plan) writeText(w io.Writer) error {
+	var b strings.Builder
+	for _, c := range p.Changes {
+		if c.Action == ActionNone {
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s %s %s (%s)\n", symbols[c.Action], c.Action, c.Kind, resourceName(c.Namespace, c.Name), c.Path)
+		for _, line := range strings.Split(c.Diff, "\n") {
+			if line != "" {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	for _, s := range p.Skipped {
+		fmt.Fprintf(&b, "! skip %s %s: %s\n", s.Kind, resourceName(s.Namespace, s.Name), s.Reason)
+	}
+
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(p.summary() + "\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (p *Plan) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("### Vault plan\n\n")
+	b.WriteString(p.summary() + "\n")
+
+	if p.HasChanges() {
+		b.WriteString("\n| Action | Kind | Resource | Vault path |\n|---|---|---|---|\n")
+		for _, c := range p.Changes {
+			if c.Action != ActionNone {
+				fmt.Fprintf(&b, "| %s | %s | `%s` | `%s` |\n", c.Action, c.Kind, resourceName(c.Namespace, c.Name), c.Path)
+			}
+		}
+		for _, c := range p.Changes {
+			if c.Action == ActionNone || c.Diff == "" {
+				continue
+			}
+			// Unindent the line diffs of multi-line fields for GitHub to highlight them
+			diff := strings.ReplaceAll(c.Diff, "\n  ", "\n")
+			fmt.Fprintf(&b, "\n<details><summary>%s %s %s</summary>\n\n```diff\n%s\n```\n\n</details>\n", c.Action, c.Kind, resourceName(c.Namespace, c.Name), diff)
+		}
+	}
+
+	if len(p.Skipped) > 0 {
+		b.WriteString("\nSkipped:\n\n")
+		for _, s := range p.Skipped {
+			fmt.Fprintf(&b, "- %s `%s`: %s\n", s.Kind, resourceName(s.Namespace, s.Name), s.Reason)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// summary counts the changes by action.
+func (p *Plan) summary() string {
+	if !p.HasChanges() {
+		return fmt.Sprintf("No changes, Vault matches the resources (%d unchanged).", p.Count(ActionNone))
+	}
+	return fmt.Sprintf("Plan: %d to create, %d to update, %d to delete, %d unchanged.",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete), p.Count(ActionNone))
+}
+
+func resourceName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
@@ -0,0 +1,163 @@
+package plan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kustomizationFiles are the names kustomize looks for in a directory.
+var kustomizationFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// Load decodes the resources of the manifests at the paths, "-" being the
+// standard input. Directories holding a kustomization are built with the
+// kustomize binary, other directories are walked for YAML and JSON files.
+// Resources of kinds unknown to the scheme are decoded as unstructured.
+func Load(scheme *runtime.Scheme, kustomize string, paths ...string) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var objs []client.Object
+	for _, p := range paths {
+		manifests, err := read(kustomize, p)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range manifests {
+			decoded, err := decode(decoder, m)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", p, err)
+			}
+			objs = append(objs, decoded...)
+		}
+	}
+	return objs, nil
+}
+
+// read returns the content of the manifests at a path.
+func read(kustomize, p string) ([][]byte, error) {
+	if p == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		return [][]byte{b}, err
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		b, err := os.ReadFile(p)
+		return [][]byte{b}, err
+	}
+
+	for _, k := range kustomizationFiles {
+		if _, err := os.Stat(filepath.Join(p, k)); err == nil {
+			b, err := exec.Command(kustomize, "build", p).Output()
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return nil, fmt.Errorf("failed to build %s: %s", p, strings.TrimSpace(string(exitErr.Stderr)))
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to build %s: %w", p, err)
+			}
+			return [][]byte{b}, nil
+		}
+	}
+
+	var manifests [][]byte
+	err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			manifests = append(manifests, b)
+		}
+		return nil
+	})
+	return manifests, err
+}
+
+// decode returns the resources of a multi-document manifest, lists being
+// flattened.
+func decode(decoder runtime.Decoder, manifest []byte) ([]client.Object, error) {
+	var objs []client.Object
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return objs, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		j, err := utilyaml.ToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(j)) == 0 || string(bytes.TrimSpace(j)) == "null" {
+			continue
+		}
+
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(j); err != nil {
+			return nil, err
+		}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range list.Items {
+				b, err := item.MarshalJSON()
+				if err != nil {
+					return nil, err
+				}
+				obj, err := decodeObject(decoder, b)
+				if err != nil {
+					return nil, err
+				}
+				objs = append(objs, obj)
+			}
+			continue
+		}
+
+		obj, err := decodeObject(decoder, j)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+}
+
+// decodeObject decodes a resource into its type, or as unstructured when the
+// kind is unknown to the scheme.
+func decodeObject(decoder runtime.Decoder, j []byte) (client.Object, error) {
+	obj, gvk, err := decoder.Decode(j, nil, nil)
+	if runtime.IsNotRegisteredError(err) {
+		u := &unstructured.Unstructured{}
+		return u, u.UnmarshalJSON(j)
+	} else if err != nil {
+		return nil, err
+	}
+
+	o, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a resource", gvk)
+	}
+	o.GetObjectKind().SetGroupVersionKind(*gvk)
+	return o, nil
+}
@@ -0,0 +1,129 @@
+// Package plan computes the changes applying resources would make in Vault,
+// without writing to it, so that they can be reviewed beforehand.
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"hopopops/vault-operator/internal/connector/vault"
+)
+
+// Action is what applying a resource does in Vault.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionNone   Action = "no-op"
+)
+
+// Change is the change applying, or deleting, a resource makes in Vault.
+type Change struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Path is the Vault path written to or deleted.
+	Path   string `json:"path"`
+	Action Action `json:"action"`
+	// Diff describes how Vault differs from the resource, as returned by
+	// vault.Diff.
+	Diff string `json:"diff,omitempty"`
+}
+
+// Skipped is a resource left out of a plan.
+type Skipped struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// Plan is the changes applying a set of resources makes in Vault.
+type Plan struct {
+	Changes []Change  `json:"changes"`
+	Skipped []Skipped `json:"skipped,omitempty"`
+}
+
+// Count returns the number of changes with the action.
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// HasChanges reports whether applying the resources writes to Vault.
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > p.Count(ActionNone)
+}
+
+// Planner computes the change a resource of a kind makes in Vault, the
+// resource being deleted rather than applied when deleted is set. It only
+// reads from Vault.
+type Planner interface {
+	Plan(ctx context.Context, obj client.Object, deleted bool) (*Change, error)
+}
+
+// Build plans applying the desired resources, and deleting the previous
+// resources that are not desired anymore. Resources of kinds without planner,
+// in observe mode or paused, are skipped.
+func Build(ctx context.Context, planners map[schema.GroupKind]Planner, desired, previous []client.Object) (*Plan, error) {
+	p := &Plan{Changes: []Change{}}
+
+	kept := map[string]struct{}{}
+	for _, obj := range desired {
+		kept[key(obj)] = struct{}{}
+		if err := p.add(ctx, planners, obj, false); err != nil {
+			return nil, err
+		}
+	}
+	for _, obj := range previous {
+		if _, ok := kept[key(obj)]; ok {
+			continue
+		}
+		if err := p.add(ctx, planners, obj, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Plan) add(ctx context.Context, planners map[schema.GroupKind]Planner, obj client.Object, deleted bool) error {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	planner, ok := planners[gvk.GroupKind()]
+	if !ok {
+		p.Skipped = append(p.Skipped, Skipped{Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Reason: "kind is not supported"})
+		return nil
+	}
+	// The finalizer of a resource in observe mode does not delete it either
+	if vault.ResourceMode(vault.ModeEnforce, obj.GetAnnotations()) == vault.ModeObserve {
+		p.Skipped = append(p.Skipped, Skipped{Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Reason: "resource is in observe mode"})
+		return nil
+	}
+	if vault.Paused(obj.GetAnnotations()) {
+		p.Skipped = append(p.Skipped, Skipped{Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Reason: "resource is paused"})
+		return nil
+	}
+
+	change, err := planner.Plan(ctx, obj, deleted)
+	if err != nil {
+		return fmt.Errorf("failed to plan %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+	change.Kind, change.Namespace, change.Name = gvk.Kind, obj.GetNamespace(), obj.GetName()
+	p.Changes = append(p.Changes, *change)
+	return nil
+}
+
+// key identifies a resource across sets of manifests.
+func key(obj client.Object) string {
+	return obj.GetObjectKind().GroupVersionKind().GroupKind().String() + "/" + client.ObjectKeyFromObject(obj).String()
+}
@@ -0,0 +1,189 @@
+package plan_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	authv1beta1 "hopopops/vault-operator/api/auth/v1beta1"
+	sysv1beta1 "hopopops/vault-operator/api/sys/v1beta1"
+	"hopopops/vault-operator/internal/connector/vault/fake"
+	authcontroller "hopopops/vault-operator/internal/controller/auth"
+	syscontroller "hopopops/vault-operator/internal/controller/sys"
+	"hopopops/vault-operator/internal/plan"
+)
+
+const manifests = `apiVersion: sys.toolkit.vault.hopopops.com/v1beta1
+kind: Policy
+metadata:
+  name: app
+  namespace: default
+spec:
+  policy: |
+    path "secret/data/app" {
+      capabilities = ["read"]
+    }
+---
+apiVersion: sys.toolkit.vault.hopopops.com/v1beta1
+kind: Policy
+metadata:
+  name: unchanged
+  namespace: default
+spec:
+  policy: path "*" {}
+---
+apiVersion: sys.toolkit.vault.hopopops.com/v1beta1
+kind: Auth
+metadata:
+  name: kubernetes
+  namespace: default
+spec:
+  type: kubernetes
+  description: cluster
+---
+apiVersion: auth.toolkit.vault.hopopops.com/v1beta1
+kind: KubernetesRole
+metadata:
+  name: observed
+  namespace: default
+  annotations:
+    vault.hopopops.com/mode: observe
+spec:
+  authPath: kubernetes
+  boundServiceAccountNames: ["app"]
+---
+apiVersion: sys.toolkit.vault.hopopops.com/v1beta1
+kind: Policy
+metadata:
+  name: frozen
+  namespace: default
+  annotations:
+    vault.hopopops.com/paused: "true"
+spec:
+  policy: path "*" { capabilities = ["deny"] }
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+`
+
+const previousManifests = `apiVersion: sys.toolkit.vault.hopopops.com/v1beta1
+kind: Policy
+metadata:
+  name: removed
+  namespace: default
+spec:
+  policy: path "*" {}
+`
+
+var _ = Describe("Plan", func() {
+	var (
+		server   *fake.Server
+		scheme   *runtime.Scheme
+		planners map[schema.GroupKind]plan.Planner
+		dir      string
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		server = fake.NewServer()
+		ctx = context.Background()
+
+		scheme = runtime.NewScheme()
+		utilruntime.Must(sysv1beta1.AddToScheme(scheme))
+		utilruntime.Must(authv1beta1.AddToScheme(scheme))
+
+		v := server.Client()
+		planners = map[schema.GroupKind]plan.Planner{
+			{Group: sysv1beta1.GroupVersion.Group, Kind: "Policy"}:          &syscontroller.PolicyReconciler{Vault: v},
+			{Group: sysv1beta1.GroupVersion.Group, Kind: "Auth"}:            &syscontroller.AuthReconciler{Vault: v},
+			{Group: authv1beta1.GroupVersion.Group, Kind: "KubernetesRole"}: &authcontroller.KubernetesRoleReconciler{Vault: v},
+		}
+
+		dir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "resources.yaml"), []byte(manifests), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "previous.yaml"), []byte(previousManifests), 0o600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	build := func() *plan.Plan {
+		desired, err := plan.Load(scheme, "kustomize", filepath.Join(dir, "resources.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		previous, err := plan.Load(scheme, "kustomize", filepath.Join(dir, "previous.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		p, err := plan.Build(ctx, planners, desired, previous)
+		Expect(err).NotTo(HaveOccurred())
+		return p
+	}
+
+	It("should plan the changes without writing to Vault", func() {
+		client := server.Client()
+		Expect(client.PutPolicy(ctx, "app", "path \"secret/data/app\" {\n  capabilities = [\"list\"]\n}\n")).To(Succeed())
+		Expect(client.PutPolicy(ctx, "unchanged", `path "*" {}`)).To(Succeed())
+		Expect(client.PutPolicy(ctx, "removed", `path "*" {}`)).To(Succeed())
+		writes := len(server.Requests())
+
+		p := build()
+
+		Expect(server.Requests()[writes:]).To(HaveEach(HaveField("Method", "GET")))
+		Expect(p.Changes).To(ConsistOf(
+			And(HaveField("Name", "app"), HaveField("Action", plan.ActionUpdate), HaveField("Path", "sys/policies/acl/app"),
+				HaveField("Diff", ContainSubstring("  -  capabilities = [\"list\"]\n  +  capabilities = [\"read\"]"))),
+			And(HaveField("Name", "unchanged"), HaveField("Action", plan.ActionNone)),
+			And(HaveField("Kind", "Auth"), HaveField("Action", plan.ActionCreate), HaveField("Path", "sys/auth/kubernetes")),
+			And(HaveField("Name", "removed"), HaveField("Action", plan.ActionDelete)),
+		))
+		Expect(p.Skipped).To(ConsistOf(
+			And(HaveField("Kind", "KubernetesRole"), HaveField("Reason", "resource is in observe mode")),
+			And(HaveField("Name", "frozen"), HaveField("Reason", "resource is paused")),
+			And(HaveField("Kind", "ConfigMap"), HaveField("Reason", "kind is not supported")),
+		))
+	})
+
+	It("should not plan deleting resources missing from Vault", func() {
+		Expect(server.Client().EnableAuth(ctx, "kubernetes/", &vaultapi.EnableAuthOptions{Type: "kubernetes", Description: "cluster"})).To(Succeed())
+
+		p := build()
+
+		Expect(p.Changes).To(ContainElement(And(HaveField("Kind", "Auth"), HaveField("Action", plan.ActionNone))))
+		Expect(p.Changes).To(ContainElement(And(HaveField("Name", "removed"), HaveField("Action", plan.ActionNone))))
+	})
+
+	It("should render the plan", func() {
+		p := build()
+
+		var text bytes.Buffer
+		Expect(p.Write(&text, plan.FormatText)).To(Succeed())
+		Expect(text.String()).To(ContainSubstring("+ create Policy default/app (sys/policies/acl/app)\n"))
+		Expect(text.String()).To(ContainSubstring("! skip ConfigMap default/settings: kind is not supported\n"))
+		Expect(text.String()).To(HaveSuffix("Plan: 3 to create, 0 to update, 0 to delete, 1 unchanged.\n"))
+
+		var markdown bytes.Buffer
+		Expect(p.Write(&markdown, plan.FormatMarkdown)).To(Succeed())
+		Expect(markdown.String()).To(HavePrefix("### Vault plan\n\nPlan: 3 to create"))
+		Expect(markdown.String()).To(ContainSubstring("| create | Policy | `default/app` | `sys/policies/acl/app` |\n"))
+		Expect(markdown.String()).To(ContainSubstring("<details><summary>create Auth default/kubernetes</summary>\n\n```diff\n"))
+
+		var out bytes.Buffer
+		Expect(p.Write(&out, plan.FormatJSON)).To(Succeed())
+		decoded := &plan.Plan{}
+		Expect(json.Unmarshal(out.Bytes(), decoded)).To(Succeed())
+		Expect(decoded).To(Equal(p))
+	})
+})